@@ -21,9 +21,10 @@ import (
 //  2. Serve it over a local httptest.Server
 //  3. List tools — expect the Count tool to be present
 //  4. Call Count with to=3 and a progressToken
-//  5. Handler returns {"status":"started"} immediately (non-blocking)
+//  5. Handler returns a working task immediately (non-blocking)
 //  6. Background goroutine sends progress notifications over the SSE stream
 //  7. Final notification (progress=1.0) contains the CountResponse JSON
+//  8. tasks-result returns the stored CountResponse for the task
 func TestRegisterCounterServiceMCPHandler(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -93,7 +94,7 @@ func TestRegisterCounterServiceMCPHandler(t *testing.T) {
 	}
 	t.Logf("Tool listed OK: counter_service-count_v1")
 
-	// 2. Call Count(to=3) with progressToken — expect immediate {"status":"started"}.
+	// 2. Call Count(to=3) with progressToken — expect an immediate working task.
 	// Meta must be pre-initialized; SetProgressToken doesn't call SetMeta when nil.
 	countArgs, _ := json.Marshal(map[string]any{"to": 3})
 	callParams := &mcp.CallToolParams{
@@ -108,8 +109,14 @@ func TestRegisterCounterServiceMCPHandler(t *testing.T) {
 		t.Fatalf("CallTool: %v", err)
 	}
 	text := extractText(callResult)
-	if text != `{"status":"started"}` {
-		t.Fatalf("expected immediate {\"status\":\"started\"}, got: %s", text)
+	var created struct {
+		Task runtime.Task `json:"task"`
+	}
+	if err := json.Unmarshal([]byte(text), &created); err != nil {
+		t.Fatalf("expected task JSON, got: %s", text)
+	}
+	if created.Task.TaskID == "" || created.Task.Status != runtime.TaskStatusWorking {
+		t.Fatalf("expected a working task, got: %s", text)
 	}
 	t.Logf("Immediate return OK: %s", text)
 
@@ -157,4 +164,20 @@ func TestRegisterCounterServiceMCPHandler(t *testing.T) {
 
 	t.Logf("Progress notifications received: %d", len(notifs))
 	t.Logf("Final result notification: %s", finalMsg)
+
+	// 6. Verify the result can be fetched from the task store.
+	resultArgs, _ := json.Marshal(map[string]any{"taskId": created.Task.TaskID})
+	taskResult, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      runtime.TaskResultToolName,
+		Arguments: json.RawMessage(resultArgs),
+	})
+	if err != nil {
+		t.Fatalf("tasks-result: %v", err)
+	}
+	if taskResult.IsError {
+		t.Fatalf("tasks-result returned error: %s", extractText(taskResult))
+	}
+	if got := extractText(taskResult); !strings.Contains(got, `"count"`) {
+		t.Fatalf("task result missing 'count' field: %s", got)
+	}
 }
@@ -9,10 +9,11 @@ use rmcp::{ErrorData as McpError, RoleServer, ServerHandler, ServiceExt, model::
 use serde_json::{self, json, Value};
 
 #[allow(dead_code)]
-fn make_tool(name: &str, description: &str, schema_json: &str) -> Tool {
+fn make_tool(name: &str, title: &str, description: &str, schema_json: &str, icons: Value) -> Tool {
     serde_json::from_value(json!({
-        "name": name, "description": description,
+        "name": name, "title": title, "description": description,
         "inputSchema": serde_json::from_str::<Value>(schema_json).unwrap(),
+        "icons": icons,
     })).expect("generated tool schema must be valid")
 }
 
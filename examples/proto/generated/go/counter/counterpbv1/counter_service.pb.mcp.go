@@ -9,10 +9,8 @@ package counterpbv1
 import (
 	"context"
 	"errors"
-	"fmt"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/grpc"
-	"google.golang.org/protobuf/encoding/protojson"
 
 	"github.com/machanirobotics/grpc-mcp-gateway/runtime"
 )
@@ -23,13 +21,13 @@ var CounterService_CountSchemaJSON = `{"description":"Counts from 0 up to the gi
 // MCP tool descriptors. Each pairs a schema with a tool name and description
 // so that LLM clients can discover and invoke the underlying RPCs.
 var (
-	CounterService_CountTool = runtime.MustCreateTool("counter_service-count_v1", `Counts from 0 up to the given number. Sends progress updates as it counts. Use with progressToken in _meta for progress notifications.`, CounterService_CountSchemaJSON)
+	CounterService_CountTool = runtime.ToolDisplay(runtime.MustCreateTool("counter_service-count_v1", `Counts from 0 up to the given number. Sends progress updates as it counts. Use with progressToken in _meta for progress notifications.`, CounterService_CountSchemaJSON), "Count")
 )
 
 // CounterServiceMCPServer is the interface that users implement to handle MCP
 // tool calls backed by CounterService RPCs. Unary RPCs take (ctx, req) and
-// return (resp, error). Server-streaming RPCs (with MCPProgress) take (req,
-// stream) matching the gRPC server interface — any type implementing
+// return (resp, error). Streaming RPCs take (req, stream) or (stream)
+// matching the gRPC server interface — any type implementing
 // CounterServiceServer automatically satisfies this interface.
 type CounterServiceMCPServer interface {
 	Count(req *CountRequest, stream CounterService_CountServer) error
@@ -46,61 +44,61 @@ type CounterServiceMCPClient interface {
 func RegisterCounterServiceMCPHandler(s *mcp.Server, srv CounterServiceMCPServer, opts ...runtime.Option) {
 	cfg := runtime.ApplyOptions(opts...)
 	_ = cfg
-	appResourceURI := runtime.AppResourceURI("CounterService")
 	{
 		tool := runtime.PrepareToolWithExtras(CounterService_CountTool, cfg.ExtraProperties)
-		tool = runtime.SetToolAppMeta(tool, appResourceURI)
-		s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*CountRequest)(nil).ProtoReflect().Descriptor())
+		tool = runtime.SetToolAppMeta(tool, "ui://counterservice/app.html")
+		formatter := cfg.ResultFormatterFor(runtime.ResultFormatDefault)
+		formatter = runtime.StructuredFormatter(formatter)
+		runtime.AddTool(s, cfg, tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var pbReq CountRequest
-			args, ctx := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
-			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(args, &pbReq); err != nil {
+			args, ctx, err := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			if err != nil {
+				return nil, err
+			}
+			if err := runtime.UnmarshalArgs(ctx, args, &pbReq); err != nil {
+				return nil, err
+			}
+			if err := runtime.BindFields(ctx, req, cfg, &pbReq); err != nil {
 				return nil, err
 			}
 			token := req.Params.GetProgressToken()
-			session := req.Session
-			// notifCtx is unbound so progress notifications are not tied to the
-			// tool-call request lifetime.
-			// grpcCtx detaches from the tool-call cancellation so the gRPC
-			// server method can complete its stream after the HTTP response is sent.
-			notifCtx := context.Background()
-			grpcCtx := runtime.WithIncomingProgressToken(context.WithoutCancel(ctx), token)
-			stream := runtime.NewInProcessServerStream[*CountStreamChunk](grpcCtx)
-			errCh := make(chan error, 1)
-			go func() {
-				defer stream.Close()
-				errCh <- srv.Count(&pbReq, stream)
-			}()
-			go func() {
+			// The RPC runs as a task: the call returns the task immediately and the
+			// final Result is stored for retrieval via the tasks-result tool.
+			return runtime.StartTask(ctx, req, cfg, func(taskCtx context.Context, task *runtime.TaskHandle) (*mcp.CallToolResult, error) {
+				grpcCtx := runtime.WithIncomingProgressToken(taskCtx, token)
+				stream := runtime.NewInProcessServerStream[*CountStreamChunk](grpcCtx)
+				errCh := make(chan error, 1)
+				go func() {
+					defer stream.Close()
+					errCh <- srv.Count(&pbReq, stream)
+				}()
 				for {
 					chunk, ok := stream.Recv()
 					if !ok {
 						if err := <-errCh; err != nil {
-							_ = runtime.SendDoneProgress(notifCtx, session, token, fmt.Sprintf(`{"error":%q}`, err.Error()))
+							return runtime.HandleError(err)
 						}
-						return
+						return nil, errors.New("stream ended without result")
 					}
 					switch {
 					case chunk.GetProgress() != nil:
-						_ = runtime.SendProgressFromProto(notifCtx, session, token, chunk.GetProgress())
+						_ = task.Progress(chunk.GetProgress())
 					case chunk.GetResult() != nil:
-						out, err := (protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}).Marshal(chunk.GetResult())
-						if err != nil {
-							_ = runtime.SendDoneProgress(notifCtx, session, token, fmt.Sprintf(`{"error":%q}`, err.Error()))
-						} else {
-							_ = runtime.SendDoneProgress(notifCtx, session, token, string(out))
-						}
-						return
+						return runtime.FormatResult(formatter, chunk.GetResult())
 					}
 				}
-			}()
-			return runtime.TextResult(`{"status":"started"}`), nil
+			})
 		})
 	}
+	runtime.RegisterTaskTools(s, cfg.TaskStoreOrDefault())
+	runtime.RegisterResultResource(s, cfg)
 
 	s.AddResource(&mcp.Resource{
-		URI:      appResourceURI,
-		Name:     "Counter App",
-		MIMEType: "text/html",
+		URI:         "ui://counterservice/app.html",
+		Name:        "Counter App",
+		Description: "A simple counter that streams progress as it counts",
+		MIMEType:    runtime.AppMIMEType,
 	}, runtime.DefaultAppResourceHandler("Counter App", "1.0.0", "A simple counter that streams progress as it counts"))
 }
 
@@ -142,25 +140,53 @@ func ServeCounterServiceMCP(ctx context.Context, srv CounterServiceMCPServer, cf
 func ForwardToCounterServiceMCPClient(s *mcp.Server, client CounterServiceMCPClient, opts ...runtime.Option) {
 	cfg := runtime.ApplyOptions(opts...)
 	_ = cfg
-	appResourceURI := runtime.AppResourceURI("CounterService")
 	{
 		tool := runtime.PrepareToolWithExtras(CounterService_CountTool, cfg.ExtraProperties)
-		tool = runtime.SetToolAppMeta(tool, appResourceURI)
-		s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*CountRequest)(nil).ProtoReflect().Descriptor())
+		tool = runtime.SetToolAppMeta(tool, "ui://counterservice/app.html")
+		formatter := cfg.ResultFormatterFor(runtime.ResultFormatDefault)
+		formatter = runtime.StructuredFormatter(formatter)
+		runtime.AddTool(s, cfg, tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var pbReq CountRequest
-			args, ctx := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
-			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(args, &pbReq); err != nil {
+			args, ctx, err := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			if err != nil {
+				return nil, err
+			}
+			if err := runtime.UnmarshalArgs(ctx, args, &pbReq); err != nil {
+				return nil, err
+			}
+			if err := runtime.BindFields(ctx, req, cfg, &pbReq); err != nil {
 				return nil, err
 			}
 			ctx = runtime.ForwardMetadata(ctx)
-			if token := req.Params.GetProgressToken(); token != nil {
+			token := req.Params.GetProgressToken()
+			if token != nil {
 				ctx = runtime.WithProgressToken(ctx, token)
 			}
+			if cfg.TaskStore != nil {
+				return runtime.StartTask(ctx, req, cfg, func(taskCtx context.Context, task *runtime.TaskHandle) (*mcp.CallToolResult, error) {
+					stream, err := client.Count(taskCtx, &pbReq)
+					if err != nil {
+						return runtime.HandleError(err)
+					}
+					for {
+						chunk, err := stream.Recv()
+						if err != nil {
+							return runtime.HandleError(err)
+						}
+						switch {
+						case chunk.GetProgress() != nil:
+							_ = task.Progress(chunk.GetProgress())
+						case chunk.GetResult() != nil:
+							return runtime.FormatResult(formatter, chunk.GetResult())
+						}
+					}
+				})
+			}
 			stream, err := client.Count(ctx, &pbReq)
 			if err != nil {
 				return runtime.HandleError(err)
 			}
-			token := req.Params.GetProgressToken()
 			for {
 				chunk, err := stream.Recv()
 				if err != nil {
@@ -173,19 +199,20 @@ func ForwardToCounterServiceMCPClient(s *mcp.Server, client CounterServiceMCPCli
 				case chunk.GetProgress() != nil:
 					_ = runtime.SendProgressFromProto(ctx, req.Session, token, chunk.GetProgress())
 				case chunk.GetResult() != nil:
-					out, err := (protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}).Marshal(chunk.GetResult())
-					if err != nil {
-						return nil, err
-					}
-					return runtime.TextResult(string(out)), nil
+					return runtime.FormatResult(formatter, chunk.GetResult())
 				}
 			}
 		})
 	}
+	if cfg.TaskStore != nil {
+		runtime.RegisterTaskTools(s, cfg.TaskStore)
+	}
+	runtime.RegisterResultResource(s, cfg)
 
 	s.AddResource(&mcp.Resource{
-		URI:      appResourceURI,
-		Name:     "Counter App",
-		MIMEType: "text/html",
+		URI:         "ui://counterservice/app.html",
+		Name:        "Counter App",
+		Description: "A simple counter that streams progress as it counts",
+		MIMEType:    runtime.AppMIMEType,
 	}, runtime.DefaultAppResourceHandler("Counter App", "1.0.0", "A simple counter that streams progress as it counts"))
 }
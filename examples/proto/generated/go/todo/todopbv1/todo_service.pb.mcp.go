@@ -11,32 +11,31 @@ import (
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/grpc"
-	"google.golang.org/protobuf/encoding/protojson"
 
 	"github.com/machanirobotics/grpc-mcp-gateway/runtime"
 )
 
 // JSON schemas for each RPC method, used as the inputSchema for MCP tools.
-var TodoService_CreateTodoSchemaJSON = `{"description":"Creates a new todo item under a user. Requires parent (e.g. users/alice), a todo object with title/description/priority, and a unique todo_id.","properties":{"parent":{"description":"Parent resource name (e.g. users/alice). The todo will be created under this user.","type":"string"},"todo":{"properties":{"completed":{"description":"Whether the todo is done.","type":"boolean"},"create_time":{"format":"date-time","type":["string","null"]},"description":{"description":"Optional longer description or notes.","type":"string"},"name":{"description":"Resource name (e.g. users/alice/todos/abc123). Required when updating.","type":"string"},"priority":{"description":"Priority level for a todo item. PRIORITY_UNSPECIFIED: Unspecified; use default priority.. PRIORITY_LOW: Low priority; can be done when convenient.. PRIORITY_MEDIUM: Normal priority; default for most todos.. PRIORITY_HIGH: High priority; should be done soon.. PRIORITY_URGENT: Urgent; do first.","enum":["PRIORITY_UNSPECIFIED","PRIORITY_LOW","PRIORITY_MEDIUM","PRIORITY_HIGH","PRIORITY_URGENT"],"enumDescriptions":{"PRIORITY_HIGH":"High priority; should be done soon.","PRIORITY_LOW":"Low priority; can be done when convenient.","PRIORITY_MEDIUM":"Normal priority; default for most todos.","PRIORITY_UNSPECIFIED":"Unspecified; use default priority.","PRIORITY_URGENT":"Urgent; do first."},"type":"string"},"title":{"description":"Short title for the todo.","type":"string"},"update_time":{"format":"date-time","type":["string","null"]}},"required":[],"type":"object"},"todo_id":{"description":"Unique ID for the todo (e.g. abc123). Becomes the final segment of the resource name.","examples":["abc123","todo-001"],"type":"string"}},"required":["parent","todo","todo_id"],"type":"object"}`
+var TodoService_CreateTodoSchemaJSON = `{"description":"Creates a new todo item under a user. Requires parent (e.g. users/alice), a todo object with title/description/priority, and a unique todo_id.","properties":{"parent":{"description":"Parent resource name (e.g. users/alice). The todo will be created under this user.","type":"string"},"todo":{"description":"The todo item to create.","properties":{"completed":{"description":"Whether the todo is done. Optional.","type":"boolean"},"description":{"description":"Optional longer description or notes.","type":"string"},"priority":{"description":"Priority level for a todo item. PRIORITY_UNSPECIFIED: Unspecified; use default priority.. PRIORITY_LOW: Low priority; can be done when convenient.. PRIORITY_MEDIUM: Normal priority; default for most todos.. PRIORITY_HIGH: High priority; should be done soon.. PRIORITY_URGENT: Urgent; do first. Optional.","enum":["PRIORITY_UNSPECIFIED","PRIORITY_LOW","PRIORITY_MEDIUM","PRIORITY_HIGH","PRIORITY_URGENT"],"enumDescriptions":{"PRIORITY_HIGH":"High priority; should be done soon.","PRIORITY_LOW":"Low priority; can be done when convenient.","PRIORITY_MEDIUM":"Normal priority; default for most todos.","PRIORITY_UNSPECIFIED":"Unspecified; use default priority.","PRIORITY_URGENT":"Urgent; do first."},"type":"string"},"title":{"description":"Short title for the todo. Optional.","type":"string"}},"required":[],"type":"object"},"todo_id":{"description":"Unique ID for the todo (e.g. abc123). Becomes the final segment of the resource name.","examples":["abc123","todo-001"],"type":"string"}},"required":["parent","todo","todo_id"],"type":"object"}`
 var TodoService_DeleteTodoSchemaJSON = `{"description":"Permanently deletes a todo item by its resource name. This action cannot be undone.","properties":{"name":{"description":"Resource name of the todo to delete (e.g. users/alice/todos/abc123).","type":"string"}},"required":["name"],"type":"object"}`
 var TodoService_GetTodoSchemaJSON = `{"description":"Retrieves a single todo item by its resource name (e.g. users/alice/todos/abc123).","properties":{"name":{"description":"Resource name of the todo (e.g. users/alice/todos/abc123).","examples":["users/alice/todos/abc123"],"format":"uri","type":"string"}},"required":["name"],"type":"object"}`
-var TodoService_ListTodosSchemaJSON = `{"description":"Lists all todo items for a user. Supports pagination via page_size and page_token.","properties":{"page_size":{"description":"Max number of todos to return (default 50).","type":"integer"},"page_token":{"description":"Token from previous response for next page.","type":"string"},"parent":{"description":"Parent resource name (e.g. users/alice). Lists todos for this user.","type":"string"}},"required":["parent"],"type":"object"}`
-var TodoService_UpdateTodoSchemaJSON = `{"description":"Updates an existing todo item. Send the todo with its resource name and the fields to update. Use update_mask to specify which fields to modify.","properties":{"todo":{"properties":{"completed":{"description":"Whether the todo is done.","type":"boolean"},"create_time":{"format":"date-time","type":["string","null"]},"description":{"description":"Optional longer description or notes.","type":"string"},"name":{"description":"Resource name (e.g. users/alice/todos/abc123). Required when updating.","type":"string"},"priority":{"description":"Priority level for a todo item. PRIORITY_UNSPECIFIED: Unspecified; use default priority.. PRIORITY_LOW: Low priority; can be done when convenient.. PRIORITY_MEDIUM: Normal priority; default for most todos.. PRIORITY_HIGH: High priority; should be done soon.. PRIORITY_URGENT: Urgent; do first.","enum":["PRIORITY_UNSPECIFIED","PRIORITY_LOW","PRIORITY_MEDIUM","PRIORITY_HIGH","PRIORITY_URGENT"],"enumDescriptions":{"PRIORITY_HIGH":"High priority; should be done soon.","PRIORITY_LOW":"Low priority; can be done when convenient.","PRIORITY_MEDIUM":"Normal priority; default for most todos.","PRIORITY_UNSPECIFIED":"Unspecified; use default priority.","PRIORITY_URGENT":"Urgent; do first."},"type":"string"},"title":{"description":"Short title for the todo.","type":"string"},"update_time":{"format":"date-time","type":["string","null"]}},"required":[],"type":"object"},"update_mask":{"description":"Comma-separated field names to update (e.g. title,completed). Omit to update all provided fields.","type":"string"}},"required":["todo"],"type":"object"}`
+var TodoService_ListTodosSchemaJSON = `{"description":"Lists all todo items for a user. Supports pagination via page_size and page_token.","properties":{"page_size":{"description":"Max number of todos to return (default 50). Optional.","type":"integer"},"page_token":{"description":"Token from previous response for next page. Optional.","type":"string"},"parent":{"description":"Parent resource name (e.g. users/alice). Lists todos for this user.","type":"string"}},"required":["parent"],"type":"object"}`
+var TodoService_UpdateTodoSchemaJSON = `{"description":"Updates an existing todo item. Send the todo with its resource name and the fields to update. Use update_mask to specify which fields to modify.","properties":{"todo":{"description":"The todo item to update.","properties":{"completed":{"description":"Whether the todo is done. Optional.","type":"boolean"},"description":{"description":"Optional longer description or notes.","type":"string"},"name":{"description":"Resource name (e.g. users/alice/todos/abc123). Required when updating. Identifier: the resource name of this resource.","type":"string"},"priority":{"description":"Priority level for a todo item. PRIORITY_UNSPECIFIED: Unspecified; use default priority.. PRIORITY_LOW: Low priority; can be done when convenient.. PRIORITY_MEDIUM: Normal priority; default for most todos.. PRIORITY_HIGH: High priority; should be done soon.. PRIORITY_URGENT: Urgent; do first. Optional.","enum":["PRIORITY_UNSPECIFIED","PRIORITY_LOW","PRIORITY_MEDIUM","PRIORITY_HIGH","PRIORITY_URGENT"],"enumDescriptions":{"PRIORITY_HIGH":"High priority; should be done soon.","PRIORITY_LOW":"Low priority; can be done when convenient.","PRIORITY_MEDIUM":"Normal priority; default for most todos.","PRIORITY_UNSPECIFIED":"Unspecified; use default priority.","PRIORITY_URGENT":"Urgent; do first."},"type":"string"},"title":{"description":"Short title for the todo. Optional.","type":"string"}},"required":["name"],"type":"object"},"update_mask":{"description":"Comma-separated field names to update (e.g. title,completed). Omit to update all provided fields. Optional.","type":"string"}},"required":["todo"],"type":"object"}`
 
 // MCP tool descriptors. Each pairs a schema with a tool name and description
 // so that LLM clients can discover and invoke the underlying RPCs.
 var (
-	TodoService_CreateTodoTool = runtime.MustCreateTool("todo_service-create_todo_v1", `Creates a new todo item under a user. Requires parent (e.g. users/alice), a todo object with title/description/priority, and a unique todo_id.`, TodoService_CreateTodoSchemaJSON)
-	TodoService_DeleteTodoTool = runtime.MustCreateTool("todo_service-delete_todo_v1", `Permanently deletes a todo item by its resource name. This action cannot be undone.`, TodoService_DeleteTodoSchemaJSON)
-	TodoService_GetTodoTool    = runtime.MustCreateTool("todo_service-get_todo_v1", `Retrieves a single todo item by its resource name (e.g. users/alice/todos/abc123).`, TodoService_GetTodoSchemaJSON)
-	TodoService_ListTodosTool  = runtime.MustCreateTool("todo_service-list_todos_v1", `Lists all todo items for a user. Supports pagination via page_size and page_token.`, TodoService_ListTodosSchemaJSON)
-	TodoService_UpdateTodoTool = runtime.MustCreateTool("todo_service-update_todo_v1", `Updates an existing todo item. Send the todo with its resource name and the fields to update. Use update_mask to specify which fields to modify.`, TodoService_UpdateTodoSchemaJSON)
+	TodoService_CreateTodoTool = runtime.ToolDisplay(runtime.MustCreateTool("todo_service-create_todo_v1", `Creates a new todo item under a user. Requires parent (e.g. users/alice), a todo object with title/description/priority, and a unique todo_id.`, TodoService_CreateTodoSchemaJSON), "Create Todo")
+	TodoService_DeleteTodoTool = runtime.ToolDisplay(runtime.MustCreateTool("todo_service-delete_todo_v1", `Permanently deletes a todo item by its resource name. This action cannot be undone.`, TodoService_DeleteTodoSchemaJSON), "Delete Todo")
+	TodoService_GetTodoTool    = runtime.ToolDisplay(runtime.MustCreateTool("todo_service-get_todo_v1", `Retrieves a single todo item by its resource name (e.g. users/alice/todos/abc123).`, TodoService_GetTodoSchemaJSON), "Get Todo")
+	TodoService_ListTodosTool  = runtime.ToolDisplay(runtime.MustCreateTool("todo_service-list_todos_v1", `Lists all todo items for a user. Supports pagination via page_size and page_token.`, TodoService_ListTodosSchemaJSON), "List Todos")
+	TodoService_UpdateTodoTool = runtime.ToolDisplay(runtime.MustCreateTool("todo_service-update_todo_v1", `Updates an existing todo item. Send the todo with its resource name and the fields to update. Use update_mask to specify which fields to modify.`, TodoService_UpdateTodoSchemaJSON), "Update Todo")
 )
 
 // TodoServiceMCPServer is the interface that users implement to handle MCP
 // tool calls backed by TodoService RPCs. Unary RPCs take (ctx, req) and
-// return (resp, error). Server-streaming RPCs (with MCPProgress) take (req,
-// stream) matching the gRPC server interface — any type implementing
+// return (resp, error). Streaming RPCs take (req, stream) or (stream)
+// matching the gRPC server interface — any type implementing
 // TodoServiceServer automatically satisfies this interface.
 type TodoServiceMCPServer interface {
 	CreateTodo(ctx context.Context, req *CreateTodoRequest) (*Todo, error)
@@ -61,11 +60,13 @@ type TodoServiceMCPClient interface {
 func RegisterTodoServiceMCPHandler(s *mcp.Server, srv TodoServiceMCPServer, opts ...runtime.Option) {
 	cfg := runtime.ApplyOptions(opts...)
 	_ = cfg
-	appResourceURI := runtime.AppResourceURI("TodoService")
 	{
 		tool := runtime.PrepareToolWithExtras(TodoService_CreateTodoTool, cfg.ExtraProperties)
-		tool = runtime.SetToolAppMeta(tool, appResourceURI)
-		s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*CreateTodoRequest)(nil).ProtoReflect().Descriptor())
+		tool = runtime.SetToolAppMeta(tool, "ui://todoservice/app.html")
+		formatter := cfg.ResultFormatterFor(runtime.ResultFormatDefault)
+		formatter = runtime.StructuredFormatter(formatter)
+		runtime.AddTool(s, cfg, tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			elicitFields := []runtime.ElicitField{
 				{Name: "confirm", Description: "Confirm creation.", Required: true, Type: "string", EnumValues: []string{"yes", "no"}, ProtoValues: []string{"CONFIRM_ACTION_YES", "CONFIRM_ACTION_NO"}},
 			}
@@ -84,26 +85,29 @@ func RegisterTodoServiceMCPHandler(s *mcp.Server, srv TodoServiceMCPServer, opts
 				return runtime.TextResult("Action cancelled by user."), nil
 			}
 			var pbReq CreateTodoRequest
-			args, ctx := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			args, ctx, err := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			if err != nil {
+				return nil, err
+			}
 			args = runtime.MergeElicitResult(args, elicitResult.Content, elicitFields)
-			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(args, &pbReq); err != nil {
+			if err := runtime.UnmarshalArgs(ctx, args, &pbReq); err != nil {
+				return nil, err
+			}
+			if err := runtime.BindFields(ctx, req, cfg, &pbReq); err != nil {
 				return nil, err
 			}
 			resp, err := srv.CreateTodo(ctx, &pbReq)
 			if err != nil {
 				return runtime.HandleError(err)
 			}
-			out, err := (protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}).Marshal(resp)
-			if err != nil {
-				return nil, err
-			}
-			return runtime.TextResult(string(out)), nil
+			return runtime.FormatResult(formatter, resp)
 		})
 	}
 	{
 		tool := runtime.PrepareToolWithExtras(TodoService_DeleteTodoTool, cfg.ExtraProperties)
-		tool = runtime.SetToolAppMeta(tool, appResourceURI)
-		s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*DeleteTodoRequest)(nil).ProtoReflect().Descriptor())
+		formatter := cfg.ResultFormatterFor(runtime.ResultFormatDefault)
+		runtime.AddTool(s, cfg, tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			elicitFields := []runtime.ElicitField{
 				{Name: "confirm", Description: "Confirm deletion.", Required: true, Type: "string", EnumValues: []string{"yes", "no"}, ProtoValues: []string{"CONFIRM_ACTION_YES", "CONFIRM_ACTION_NO"}},
 			}
@@ -122,66 +126,84 @@ func RegisterTodoServiceMCPHandler(s *mcp.Server, srv TodoServiceMCPServer, opts
 				return runtime.TextResult("Action cancelled by user."), nil
 			}
 			var pbReq DeleteTodoRequest
-			args, ctx := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			args, ctx, err := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			if err != nil {
+				return nil, err
+			}
 			args = runtime.MergeElicitResult(args, elicitResult.Content, elicitFields)
-			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(args, &pbReq); err != nil {
+			if err := runtime.UnmarshalArgs(ctx, args, &pbReq); err != nil {
+				return nil, err
+			}
+			if err := runtime.BindFields(ctx, req, cfg, &pbReq); err != nil {
 				return nil, err
 			}
 			resp, err := srv.DeleteTodo(ctx, &pbReq)
 			if err != nil {
 				return runtime.HandleError(err)
 			}
-			out, err := (protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}).Marshal(resp)
-			if err != nil {
-				return nil, err
-			}
-			return runtime.TextResult(string(out)), nil
+			return runtime.FormatResult(formatter, resp)
 		})
 	}
 	{
 		tool := runtime.PrepareToolWithExtras(TodoService_GetTodoTool, cfg.ExtraProperties)
-		tool = runtime.SetToolAppMeta(tool, appResourceURI)
-		s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*GetTodoRequest)(nil).ProtoReflect().Descriptor())
+		tool = runtime.SetToolAppMeta(tool, "ui://todoservice/app.html")
+		formatter := cfg.ResultFormatterFor(runtime.ResultFormatDefault)
+		formatter = runtime.StructuredFormatter(formatter)
+		runtime.AddTool(s, cfg, tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var pbReq GetTodoRequest
-			args, ctx := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
-			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(args, &pbReq); err != nil {
+			args, ctx, err := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			if err != nil {
+				return nil, err
+			}
+			if err := runtime.UnmarshalArgs(ctx, args, &pbReq); err != nil {
+				return nil, err
+			}
+			if err := runtime.BindFields(ctx, req, cfg, &pbReq); err != nil {
 				return nil, err
 			}
 			resp, err := srv.GetTodo(ctx, &pbReq)
 			if err != nil {
 				return runtime.HandleError(err)
 			}
-			out, err := (protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}).Marshal(resp)
-			if err != nil {
-				return nil, err
-			}
-			return runtime.TextResult(string(out)), nil
+			return runtime.FormatResult(formatter, resp)
 		})
 	}
 	{
 		tool := runtime.PrepareToolWithExtras(TodoService_ListTodosTool, cfg.ExtraProperties)
-		tool = runtime.SetToolAppMeta(tool, appResourceURI)
-		s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*ListTodosRequest)(nil).ProtoReflect().Descriptor())
+		tool = runtime.SetToolAppMeta(tool, "ui://todoservice/app.html")
+		formatter := cfg.ResultFormatterFor(runtime.ResultFormatDefault)
+		formatter = runtime.StructuredFormatter(formatter)
+		runtime.AddTool(s, cfg, tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var pbReq ListTodosRequest
-			args, ctx := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
-			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(args, &pbReq); err != nil {
+			args, ctx, err := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			if err != nil {
+				return nil, err
+			}
+			if err := runtime.UnmarshalArgs(ctx, args, &pbReq); err != nil {
 				return nil, err
 			}
+			if err := runtime.BindFields(ctx, req, cfg, &pbReq); err != nil {
+				return nil, err
+			}
+			if paging := cfg.PaginationFor(nil); paging != nil {
+				return runtime.Paginate(ctx, paging, formatter, &pbReq, "todos", srv.ListTodos)
+			}
 			resp, err := srv.ListTodos(ctx, &pbReq)
 			if err != nil {
 				return runtime.HandleError(err)
 			}
-			out, err := (protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}).Marshal(resp)
-			if err != nil {
-				return nil, err
-			}
-			return runtime.TextResult(string(out)), nil
+			return runtime.FormatResult(formatter, resp)
 		})
 	}
 	{
 		tool := runtime.PrepareToolWithExtras(TodoService_UpdateTodoTool, cfg.ExtraProperties)
-		tool = runtime.SetToolAppMeta(tool, appResourceURI)
-		s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*UpdateTodoRequest)(nil).ProtoReflect().Descriptor())
+		tool = runtime.SetToolAppMeta(tool, "ui://todoservice/app.html")
+		formatter := cfg.ResultFormatterFor(runtime.ResultFormatDefault)
+		formatter = runtime.StructuredFormatter(formatter)
+		runtime.AddTool(s, cfg, tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			elicitFields := []runtime.ElicitField{
 				{Name: "confirm", Description: "Confirm update.", Required: true, Type: "string", EnumValues: []string{"yes", "no"}, ProtoValues: []string{"CONFIRM_ACTION_YES", "CONFIRM_ACTION_NO"}},
 			}
@@ -200,30 +222,34 @@ func RegisterTodoServiceMCPHandler(s *mcp.Server, srv TodoServiceMCPServer, opts
 				return runtime.TextResult("Action cancelled by user."), nil
 			}
 			var pbReq UpdateTodoRequest
-			args, ctx := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			args, ctx, err := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			if err != nil {
+				return nil, err
+			}
 			args = runtime.MergeElicitResult(args, elicitResult.Content, elicitFields)
-			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(args, &pbReq); err != nil {
+			if err := runtime.UnmarshalArgs(ctx, args, &pbReq); err != nil {
+				return nil, err
+			}
+			if err := runtime.BindFields(ctx, req, cfg, &pbReq); err != nil {
 				return nil, err
 			}
 			resp, err := srv.UpdateTodo(ctx, &pbReq)
 			if err != nil {
 				return runtime.HandleError(err)
 			}
-			out, err := (protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}).Marshal(resp)
-			if err != nil {
-				return nil, err
-			}
-			return runtime.TextResult(string(out)), nil
+			return runtime.FormatResult(formatter, resp)
 		})
 	}
+	runtime.RegisterResultResource(s, cfg)
 	s.AddResourceTemplate(&mcp.ResourceTemplate{
 		URITemplate: "todo://users/{user}/todos/{todo}",
 		Name:        "Todo",
-		Description: "Todo resource (users/{user}/todos/{todo})",
+		Description: "A single todo item, read with GetTodo.",
 		MIMEType:    "application/json",
-	}, runtime.DefaultResourceHandler())
+	}, runtime.RPCResourceHandler("todo://users/{user}/todos/{todo}", "application/json", srv.GetTodo))
 	s.AddPrompt(&mcp.Prompt{
 		Name:        "summarize_todos",
+		Title:       "Summarize Todos",
 		Description: "Summarize all pending todo items for a user",
 		Arguments: []*mcp.PromptArgument{
 			{Name: "user", Description: "The user ID to summarize todos for.", Required: true},
@@ -231,6 +257,7 @@ func RegisterTodoServiceMCPHandler(s *mcp.Server, srv TodoServiceMCPServer, opts
 	}, runtime.DefaultPromptHandler("Summarize all pending todo items for a user"))
 	s.AddPrompt(&mcp.Prompt{
 		Name:        "prioritize_todos",
+		Title:       "Prioritize Todos",
 		Description: "Suggest a priority ordering for a user's incomplete todos",
 		Arguments: []*mcp.PromptArgument{
 			{Name: "user", Description: "The user ID whose todos to prioritize.", Required: true},
@@ -239,16 +266,30 @@ func RegisterTodoServiceMCPHandler(s *mcp.Server, srv TodoServiceMCPServer, opts
 	}, runtime.DefaultPromptHandler("Suggest a priority ordering for a user's incomplete todos"))
 
 	s.AddResource(&mcp.Resource{
-		URI:      appResourceURI,
-		Name:     "Todo App",
-		MIMEType: "text/html",
-	}, runtime.DefaultAppResourceHandler("Todo App", "1.0.0", "A simple todo management application"))
+		URI:         "ui://todoservice/app.html",
+		Name:        "Todo App",
+		Description: "A simple todo management application",
+		MIMEType:    runtime.AppMIMEType,
+	}, runtime.AppViewHandler(cfg, &runtime.AppView{
+		Entry:         "todo/ui/todo.html",
+		Assets:        []string{"todo/ui/todo.js", "todo/ui/todo.css"},
+		HTML:          "<!doctype html>\n<html lang=\"en\">\n<head>\n  <meta charset=\"utf-8\">\n  <title>Todo App</title>\n  <style>body {\n  margin: 0;\n  font: 14px/1.4 system-ui, sans-serif;\n  color: #1f2328;\n}\nmain {\n  padding: 12px 16px;\n}\nh1 {\n  margin: 0 0 8px;\n  font-size: 16px;\n}\nul {\n  margin: 0;\n  padding: 0;\n  list-style: none;\n}\nli {\n  padding: 6px 0;\n  border-bottom: 1px solid #d0d7de;\n}\nli.done .title {\n  text-decoration: line-through;\n  color: #656d76;\n}\n.priority {\n  margin-left: 8px;\n  font-size: 12px;\n  color: #656d76;\n}\n#empty {\n  color: #656d76;\n}\n</style>\n</head>\n<body>\n  <main>\n    <h1 id=\"title\">Todos</h1>\n    <ul id=\"todos\"></ul>\n    <p id=\"empty\">Call a todo tool to see its result here.</p>\n  </main>\n  <script>// Renders TodoService tool results. The host passes each result to the view\n// over the MCP Apps postMessage bridge; structuredContent holds the response\n// message as JSON with proto field names.\n(function () {\n  let nextId = 1;\n\n  function send(message) {\n    window.parent.postMessage(Object.assign({ jsonrpc: \"2.0\" }, message), \"*\");\n  }\n\n  function render(result) {\n    const data = (result && result.structuredContent) || {};\n    const todos = data.todos || (data.name ? [data] : []);\n    const list = document.getElementById(\"todos\");\n    list.replaceChildren();\n    for (const todo of todos) {\n      const item = document.createElement(\"li\");\n      if (todo.completed) {\n        item.className = \"done\";\n      }\n      const title = document.createElement(\"span\");\n      title.className = \"title\";\n      title.textContent = todo.title || todo.name;\n      item.append(title);\n      if (todo.priority && todo.priority !== \"PRIORITY_UNSPECIFIED\") {\n        const priority = document.createElement(\"span\");\n        priority.className = \"priority\";\n        priority.textContent = todo.priority.replace(\"PRIORITY_\", \"\").toLowerCase();\n        item.append(priority);\n      }\n      list.append(item);\n    }\n    document.getElementById(\"empty\").hidden = todos.length > 0;\n  }\n\n  window.addEventListener(\"message\", (event) => {\n    const message = event.data;\n    if (!message || message.jsonrpc !== \"2.0\") {\n      return;\n    }\n    if (message.method === \"ui/notifications/tool-result\") {\n      render(message.params);\n    } else if (message.id === 1 && message.result) {\n      send({ method: \"ui/notifications/initialized\" });\n    }\n  });\n\n  send({\n    id: nextId++,\n    method: \"ui/initialize\",\n    params: {\n      appInfo: { name: \"Todo App\", version: \"1.0.0\" },\n      appCapabilities: {},\n      protocolVersion: \"2026-01-26\",\n    },\n  });\n})();\n</script>\n</body>\n</html>\n",
+		PrefersBorder: true,
+	}))
 }
 
 // TodoServiceMCPDefaultBasePath is the default HTTP path prefix for
 // TodoService MCP endpoints, derived from the protobuf package and service name.
 const TodoServiceMCPDefaultBasePath = "/todo/v1/todoservice/mcp"
 
+// TodoServiceMCPServerIdentity is the server identity declared in the
+// TodoService (mcp.protobuf.service) options.
+var TodoServiceMCPServerIdentity = runtime.ServerIdentity{
+	Name:         "TodoService",
+	Title:        "Todo Manager",
+	Instructions: "Todos belong to users (parent users/{user}). Call todo_service-list_todos_v1 to find a todo's resource name before getting, updating or deleting it.",
+}
+
 // TodoServiceCompletionMap returns a map of "promptName:argName" → allowed
 // values, built from enum_values declared in the proto MCP options.
 func TodoServiceCompletionMap() map[string][]string {
@@ -263,6 +304,7 @@ func TodoServiceCompletionMap() map[string][]string {
 func ServeTodoServiceMCP(ctx context.Context, srv TodoServiceMCPServer, cfg *runtime.MCPServerConfig, opts ...runtime.Option) error {
 	// Set the proto-derived path as the generated default
 	cfg.GeneratedBasePath = TodoServiceMCPDefaultBasePath
+	runtime.ApplyServerIdentity(cfg, TodoServiceMCPServerIdentity)
 
 	// Wire completion handler for prompt arguments with enum_values.
 	completionMap := TodoServiceCompletionMap()
@@ -284,11 +326,13 @@ func ServeTodoServiceMCP(ctx context.Context, srv TodoServiceMCPServer, cfg *run
 func ForwardToTodoServiceMCPClient(s *mcp.Server, client TodoServiceMCPClient, opts ...runtime.Option) {
 	cfg := runtime.ApplyOptions(opts...)
 	_ = cfg
-	appResourceURI := runtime.AppResourceURI("TodoService")
 	{
 		tool := runtime.PrepareToolWithExtras(TodoService_CreateTodoTool, cfg.ExtraProperties)
-		tool = runtime.SetToolAppMeta(tool, appResourceURI)
-		s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*CreateTodoRequest)(nil).ProtoReflect().Descriptor())
+		tool = runtime.SetToolAppMeta(tool, "ui://todoservice/app.html")
+		formatter := cfg.ResultFormatterFor(runtime.ResultFormatDefault)
+		formatter = runtime.StructuredFormatter(formatter)
+		runtime.AddTool(s, cfg, tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			elicitFields := []runtime.ElicitField{
 				{Name: "confirm", Description: "Confirm creation.", Required: true, Type: "string", EnumValues: []string{"yes", "no"}, ProtoValues: []string{"CONFIRM_ACTION_YES", "CONFIRM_ACTION_NO"}},
 			}
@@ -307,9 +351,15 @@ func ForwardToTodoServiceMCPClient(s *mcp.Server, client TodoServiceMCPClient, o
 				return runtime.TextResult("Action cancelled by user."), nil
 			}
 			var pbReq CreateTodoRequest
-			args, ctx := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			args, ctx, err := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			if err != nil {
+				return nil, err
+			}
 			args = runtime.MergeElicitResult(args, elicitResult.Content, elicitFields)
-			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(args, &pbReq); err != nil {
+			if err := runtime.UnmarshalArgs(ctx, args, &pbReq); err != nil {
+				return nil, err
+			}
+			if err := runtime.BindFields(ctx, req, cfg, &pbReq); err != nil {
 				return nil, err
 			}
 			ctx = runtime.ForwardMetadata(ctx)
@@ -317,17 +367,14 @@ func ForwardToTodoServiceMCPClient(s *mcp.Server, client TodoServiceMCPClient, o
 			if err != nil {
 				return runtime.HandleError(err)
 			}
-			out, err := (protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}).Marshal(resp)
-			if err != nil {
-				return nil, err
-			}
-			return runtime.TextResult(string(out)), nil
+			return runtime.FormatResult(formatter, resp)
 		})
 	}
 	{
 		tool := runtime.PrepareToolWithExtras(TodoService_DeleteTodoTool, cfg.ExtraProperties)
-		tool = runtime.SetToolAppMeta(tool, appResourceURI)
-		s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*DeleteTodoRequest)(nil).ProtoReflect().Descriptor())
+		formatter := cfg.ResultFormatterFor(runtime.ResultFormatDefault)
+		runtime.AddTool(s, cfg, tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			elicitFields := []runtime.ElicitField{
 				{Name: "confirm", Description: "Confirm deletion.", Required: true, Type: "string", EnumValues: []string{"yes", "no"}, ProtoValues: []string{"CONFIRM_ACTION_YES", "CONFIRM_ACTION_NO"}},
 			}
@@ -346,9 +393,15 @@ func ForwardToTodoServiceMCPClient(s *mcp.Server, client TodoServiceMCPClient, o
 				return runtime.TextResult("Action cancelled by user."), nil
 			}
 			var pbReq DeleteTodoRequest
-			args, ctx := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			args, ctx, err := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			if err != nil {
+				return nil, err
+			}
 			args = runtime.MergeElicitResult(args, elicitResult.Content, elicitFields)
-			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(args, &pbReq); err != nil {
+			if err := runtime.UnmarshalArgs(ctx, args, &pbReq); err != nil {
+				return nil, err
+			}
+			if err := runtime.BindFields(ctx, req, cfg, &pbReq); err != nil {
 				return nil, err
 			}
 			ctx = runtime.ForwardMetadata(ctx)
@@ -356,20 +409,25 @@ func ForwardToTodoServiceMCPClient(s *mcp.Server, client TodoServiceMCPClient, o
 			if err != nil {
 				return runtime.HandleError(err)
 			}
-			out, err := (protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}).Marshal(resp)
-			if err != nil {
-				return nil, err
-			}
-			return runtime.TextResult(string(out)), nil
+			return runtime.FormatResult(formatter, resp)
 		})
 	}
 	{
 		tool := runtime.PrepareToolWithExtras(TodoService_GetTodoTool, cfg.ExtraProperties)
-		tool = runtime.SetToolAppMeta(tool, appResourceURI)
-		s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*GetTodoRequest)(nil).ProtoReflect().Descriptor())
+		tool = runtime.SetToolAppMeta(tool, "ui://todoservice/app.html")
+		formatter := cfg.ResultFormatterFor(runtime.ResultFormatDefault)
+		formatter = runtime.StructuredFormatter(formatter)
+		runtime.AddTool(s, cfg, tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var pbReq GetTodoRequest
-			args, ctx := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
-			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(args, &pbReq); err != nil {
+			args, ctx, err := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			if err != nil {
+				return nil, err
+			}
+			if err := runtime.UnmarshalArgs(ctx, args, &pbReq); err != nil {
+				return nil, err
+			}
+			if err := runtime.BindFields(ctx, req, cfg, &pbReq); err != nil {
 				return nil, err
 			}
 			ctx = runtime.ForwardMetadata(ctx)
@@ -377,38 +435,47 @@ func ForwardToTodoServiceMCPClient(s *mcp.Server, client TodoServiceMCPClient, o
 			if err != nil {
 				return runtime.HandleError(err)
 			}
-			out, err := (protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}).Marshal(resp)
-			if err != nil {
-				return nil, err
-			}
-			return runtime.TextResult(string(out)), nil
+			return runtime.FormatResult(formatter, resp)
 		})
 	}
 	{
 		tool := runtime.PrepareToolWithExtras(TodoService_ListTodosTool, cfg.ExtraProperties)
-		tool = runtime.SetToolAppMeta(tool, appResourceURI)
-		s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*ListTodosRequest)(nil).ProtoReflect().Descriptor())
+		tool = runtime.SetToolAppMeta(tool, "ui://todoservice/app.html")
+		formatter := cfg.ResultFormatterFor(runtime.ResultFormatDefault)
+		formatter = runtime.StructuredFormatter(formatter)
+		runtime.AddTool(s, cfg, tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var pbReq ListTodosRequest
-			args, ctx := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
-			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(args, &pbReq); err != nil {
+			args, ctx, err := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			if err != nil {
+				return nil, err
+			}
+			if err := runtime.UnmarshalArgs(ctx, args, &pbReq); err != nil {
+				return nil, err
+			}
+			if err := runtime.BindFields(ctx, req, cfg, &pbReq); err != nil {
 				return nil, err
 			}
 			ctx = runtime.ForwardMetadata(ctx)
+			if paging := cfg.PaginationFor(nil); paging != nil {
+				return runtime.Paginate(ctx, paging, formatter, &pbReq, "todos", func(ctx context.Context, r *ListTodosRequest) (*ListTodosResponse, error) {
+					return client.ListTodos(ctx, r)
+				})
+			}
 			resp, err := client.ListTodos(ctx, &pbReq)
 			if err != nil {
 				return runtime.HandleError(err)
 			}
-			out, err := (protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}).Marshal(resp)
-			if err != nil {
-				return nil, err
-			}
-			return runtime.TextResult(string(out)), nil
+			return runtime.FormatResult(formatter, resp)
 		})
 	}
 	{
 		tool := runtime.PrepareToolWithExtras(TodoService_UpdateTodoTool, cfg.ExtraProperties)
-		tool = runtime.SetToolAppMeta(tool, appResourceURI)
-		s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*UpdateTodoRequest)(nil).ProtoReflect().Descriptor())
+		tool = runtime.SetToolAppMeta(tool, "ui://todoservice/app.html")
+		formatter := cfg.ResultFormatterFor(runtime.ResultFormatDefault)
+		formatter = runtime.StructuredFormatter(formatter)
+		runtime.AddTool(s, cfg, tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			elicitFields := []runtime.ElicitField{
 				{Name: "confirm", Description: "Confirm update.", Required: true, Type: "string", EnumValues: []string{"yes", "no"}, ProtoValues: []string{"CONFIRM_ACTION_YES", "CONFIRM_ACTION_NO"}},
 			}
@@ -427,9 +494,15 @@ func ForwardToTodoServiceMCPClient(s *mcp.Server, client TodoServiceMCPClient, o
 				return runtime.TextResult("Action cancelled by user."), nil
 			}
 			var pbReq UpdateTodoRequest
-			args, ctx := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			args, ctx, err := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			if err != nil {
+				return nil, err
+			}
 			args = runtime.MergeElicitResult(args, elicitResult.Content, elicitFields)
-			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(args, &pbReq); err != nil {
+			if err := runtime.UnmarshalArgs(ctx, args, &pbReq); err != nil {
+				return nil, err
+			}
+			if err := runtime.BindFields(ctx, req, cfg, &pbReq); err != nil {
 				return nil, err
 			}
 			ctx = runtime.ForwardMetadata(ctx)
@@ -437,21 +510,21 @@ func ForwardToTodoServiceMCPClient(s *mcp.Server, client TodoServiceMCPClient, o
 			if err != nil {
 				return runtime.HandleError(err)
 			}
-			out, err := (protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}).Marshal(resp)
-			if err != nil {
-				return nil, err
-			}
-			return runtime.TextResult(string(out)), nil
+			return runtime.FormatResult(formatter, resp)
 		})
 	}
+	runtime.RegisterResultResource(s, cfg)
 	s.AddResourceTemplate(&mcp.ResourceTemplate{
 		URITemplate: "todo://users/{user}/todos/{todo}",
 		Name:        "Todo",
-		Description: "Todo resource (users/{user}/todos/{todo})",
+		Description: "A single todo item, read with GetTodo.",
 		MIMEType:    "application/json",
-	}, runtime.DefaultResourceHandler())
+	}, runtime.RPCResourceHandler("todo://users/{user}/todos/{todo}", "application/json", func(ctx context.Context, req *GetTodoRequest) (*Todo, error) {
+		return client.GetTodo(runtime.ForwardMetadata(ctx), req)
+	}))
 	s.AddPrompt(&mcp.Prompt{
 		Name:        "summarize_todos",
+		Title:       "Summarize Todos",
 		Description: "Summarize all pending todo items for a user",
 		Arguments: []*mcp.PromptArgument{
 			{Name: "user", Description: "The user ID to summarize todos for.", Required: true},
@@ -459,6 +532,7 @@ func ForwardToTodoServiceMCPClient(s *mcp.Server, client TodoServiceMCPClient, o
 	}, runtime.DefaultPromptHandler("Summarize all pending todo items for a user"))
 	s.AddPrompt(&mcp.Prompt{
 		Name:        "prioritize_todos",
+		Title:       "Prioritize Todos",
 		Description: "Suggest a priority ordering for a user's incomplete todos",
 		Arguments: []*mcp.PromptArgument{
 			{Name: "user", Description: "The user ID whose todos to prioritize.", Required: true},
@@ -467,8 +541,14 @@ func ForwardToTodoServiceMCPClient(s *mcp.Server, client TodoServiceMCPClient, o
 	}, runtime.DefaultPromptHandler("Suggest a priority ordering for a user's incomplete todos"))
 
 	s.AddResource(&mcp.Resource{
-		URI:      appResourceURI,
-		Name:     "Todo App",
-		MIMEType: "text/html",
-	}, runtime.DefaultAppResourceHandler("Todo App", "1.0.0", "A simple todo management application"))
+		URI:         "ui://todoservice/app.html",
+		Name:        "Todo App",
+		Description: "A simple todo management application",
+		MIMEType:    runtime.AppMIMEType,
+	}, runtime.AppViewHandler(cfg, &runtime.AppView{
+		Entry:         "todo/ui/todo.html",
+		Assets:        []string{"todo/ui/todo.js", "todo/ui/todo.css"},
+		HTML:          "<!doctype html>\n<html lang=\"en\">\n<head>\n  <meta charset=\"utf-8\">\n  <title>Todo App</title>\n  <style>body {\n  margin: 0;\n  font: 14px/1.4 system-ui, sans-serif;\n  color: #1f2328;\n}\nmain {\n  padding: 12px 16px;\n}\nh1 {\n  margin: 0 0 8px;\n  font-size: 16px;\n}\nul {\n  margin: 0;\n  padding: 0;\n  list-style: none;\n}\nli {\n  padding: 6px 0;\n  border-bottom: 1px solid #d0d7de;\n}\nli.done .title {\n  text-decoration: line-through;\n  color: #656d76;\n}\n.priority {\n  margin-left: 8px;\n  font-size: 12px;\n  color: #656d76;\n}\n#empty {\n  color: #656d76;\n}\n</style>\n</head>\n<body>\n  <main>\n    <h1 id=\"title\">Todos</h1>\n    <ul id=\"todos\"></ul>\n    <p id=\"empty\">Call a todo tool to see its result here.</p>\n  </main>\n  <script>// Renders TodoService tool results. The host passes each result to the view\n// over the MCP Apps postMessage bridge; structuredContent holds the response\n// message as JSON with proto field names.\n(function () {\n  let nextId = 1;\n\n  function send(message) {\n    window.parent.postMessage(Object.assign({ jsonrpc: \"2.0\" }, message), \"*\");\n  }\n\n  function render(result) {\n    const data = (result && result.structuredContent) || {};\n    const todos = data.todos || (data.name ? [data] : []);\n    const list = document.getElementById(\"todos\");\n    list.replaceChildren();\n    for (const todo of todos) {\n      const item = document.createElement(\"li\");\n      if (todo.completed) {\n        item.className = \"done\";\n      }\n      const title = document.createElement(\"span\");\n      title.className = \"title\";\n      title.textContent = todo.title || todo.name;\n      item.append(title);\n      if (todo.priority && todo.priority !== \"PRIORITY_UNSPECIFIED\") {\n        const priority = document.createElement(\"span\");\n        priority.className = \"priority\";\n        priority.textContent = todo.priority.replace(\"PRIORITY_\", \"\").toLowerCase();\n        item.append(priority);\n      }\n      list.append(item);\n    }\n    document.getElementById(\"empty\").hidden = todos.length > 0;\n  }\n\n  window.addEventListener(\"message\", (event) => {\n    const message = event.data;\n    if (!message || message.jsonrpc !== \"2.0\") {\n      return;\n    }\n    if (message.method === \"ui/notifications/tool-result\") {\n      render(message.params);\n    } else if (message.id === 1 && message.result) {\n      send({ method: \"ui/notifications/initialized\" });\n    }\n  });\n\n  send({\n    id: nextId++,\n    method: \"ui/initialize\",\n    params: {\n      appInfo: { name: \"Todo App\", version: \"1.0.0\" },\n      appCapabilities: {},\n      protocolVersion: \"2026-01-26\",\n    },\n  });\n})();\n</script>\n</body>\n</html>\n",
+		PrefersBorder: true,
+	}))
 }
@@ -7,11 +7,14 @@
 from __future__ import annotations
 
 import json
+import re
 from typing import Any, Iterator, Protocol
+from urllib.parse import unquote
 
 import mcp.types as types
 from mcp.server.fastmcp import FastMCP
 from mcp.server.lowlevel import Server
+from mcp.server.lowlevel.helper_types import ReadResourceContents
 from google.protobuf.json_format import MessageToDict, ParseDict
 
 import counter.v1.counter_pb2
@@ -21,13 +24,59 @@ CounterService_Count_SCHEMA = json.loads(r'''{"description":"Counts from 0 up to
 
 CounterService_Count_TOOL = types.Tool(
     name="counter_service-count_v1",
+    title="Count",
     description="Counts from 0 up to the given number. Sends progress updates as it counts. Use with progressToken in _meta for progress notifications.",
     inputSchema=CounterService_Count_SCHEMA,
 )
 
 
-def _app_resource_uri(service_name: str) -> str:
-    return f"ui://{service_name.lower()}/app.html"
+_APP_MIME_TYPE = "text/html;profile=mcp-app"
+
+
+def _resource_args(uri_template: str, uri: str, bindings: dict[str, str], name_binding: bool) -> dict[str, Any] | None:
+    """Map the variables of uri_template in uri to request fields, or return None if uri does not match."""
+    parts = re.split(r"\{(\+?)([^}]+)\}", uri_template)
+    pattern, names = re.escape(parts[0]), []
+    for i in range(1, len(parts), 3):
+        pattern += ("(.*)" if parts[i] else "([^/]*)") + re.escape(parts[i + 2])
+        names.append(parts[i + 1])
+    match = re.fullmatch(pattern, uri)
+    if match is None:
+        return None
+    args: dict[str, Any] = {}
+    if name_binding:
+        args["name"] = unquote(uri.split("://", 1)[-1])
+    for name, value in zip(names, match.groups()):
+        if name in bindings:
+            *parents, leaf = bindings[name].split(".")
+            target = args
+            for parent in parents:
+                target = target.setdefault(parent, {})
+            target[leaf] = unquote(value)
+    return args
+
+
+def _resource_data(data: bytes, mime_type: str) -> str | bytes:
+    base = mime_type.split(";", 1)[0]
+    if base.startswith("text/") or base in ("application/json", "application/xml") or base.endswith(("+json", "+xml")):
+        return data.decode()
+    return data
+
+
+def _resource_contents(resp: Any, mime_type: str) -> list[ReadResourceContents]:
+    """Render an RPC response as resource contents: HttpBody data, the value of a single string or bytes field, or JSON."""
+    if resp.DESCRIPTOR.full_name == "google.api.HttpBody":
+        content_type = resp.content_type or mime_type
+        return [ReadResourceContents(content=_resource_data(resp.data, content_type), mime_type=content_type)]
+    fields = resp.DESCRIPTOR.fields
+    if len(fields) == 1 and fields[0].label != fields[0].LABEL_REPEATED:
+        value = getattr(resp, fields[0].name)
+        if isinstance(value, bytes):
+            return [ReadResourceContents(content=_resource_data(value, mime_type), mime_type=mime_type)]
+        if isinstance(value, str):
+            return [ReadResourceContents(content=value, mime_type=mime_type)]
+    text = json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True))
+    return [ReadResourceContents(content=text, mime_type="application/json")]
 
 def _default_app_html(app_name: str, version: str, description: str) -> str:
     return (
@@ -41,12 +90,28 @@ def _default_app_html(app_name: str, version: str, description: str) -> str:
         "</body></html>"
     )
 
-def _tool_with_app_meta(tool: types.Tool, app_resource_uri: str) -> types.Tool:
-    meta = {"ui": {"resourceUri": app_resource_uri}}
+def _tool_with_app_meta(tool: types.Tool, resource_uri: str, visibility: list[str] | None = None) -> types.Tool:
+    ui: dict[str, Any] = {"resourceUri": resource_uri}
+    if visibility:
+        ui["visibility"] = visibility
+    meta = {**(tool.meta or {}), "ui": ui}
     if hasattr(tool, "model_copy"):
         return tool.model_copy(update={"meta": meta})
     return tool.copy(deep=True, update={"meta": meta})
 
+
+def _app_view_contents(html: str, ui: dict[str, Any]) -> list[ReadResourceContents]:
+    return [ReadResourceContents(content=html, mime_type=_APP_MIME_TYPE, meta={"ui": ui} if ui else None)]
+
+
+def _tool_result(resp: Any, structured: bool) -> list[types.TextContent] | tuple[list[types.TextContent], dict[str, Any]]:
+    """Render an RPC response as tool result content, also returned as structured content for MCP App views."""
+    data = MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)
+    content = [types.TextContent(type="text", text=json.dumps(data))]
+    if not structured:
+        return content
+    return content, data if isinstance(data, dict) else {"value": data}
+
 class CounterServiceMCPServer(Protocol):
     """Protocol that users implement to handle MCP tool calls backed by CounterService RPCs."""
     def count(self, request: counter.v1.counter_pb2.CountRequest) -> Iterator[counter.v1.counter_service_pb2.CountStreamChunk]: ...
@@ -58,7 +123,7 @@ class CounterServiceMCPClient(Protocol):
 CounterService_MCP_DEFAULT_BASE_PATH = "/counter/v1/counterservice/mcp"
 
 CounterService_TOOLS: list[types.Tool] = [
-    CounterService_Count_TOOL,
+    _tool_with_app_meta(CounterService_Count_TOOL, "ui://counterservice/app.html"),
 ]
 
 def _counter_service_prompts() -> list[types.Prompt]:
@@ -69,7 +134,7 @@ def _counter_service_prompts() -> list[types.Prompt]:
 def _counter_service_resources() -> list[types.Resource | types.ResourceTemplate]:
     """Build the list of resource descriptors for CounterService."""
     return [
-        types.Resource(uri=_app_resource_uri("CounterService"), name="Counter App", mimeType="text/html"),
+        types.Resource(uri="ui://counterservice/app.html", name="Counter App", description="A simple counter that streams progress as it counts", mimeType=_APP_MIME_TYPE),
     ]
 
 def _counter_service_completion_map() -> dict[str, list[str]]:
@@ -79,14 +144,13 @@ def _counter_service_completion_map() -> dict[str, list[str]]:
 
 def register_counter_service_mcp_handler(server: Server, impl: CounterServiceMCPServer) -> None:
     """Register all CounterService tools, prompts, and resources on the given MCP server."""
-    _app_resource_uri_value = _app_resource_uri("CounterService")
 
     @server.list_tools()
     async def handle_list_tools() -> list[types.Tool]:
-        return [_tool_with_app_meta(t, _app_resource_uri_value) for t in CounterService_TOOLS]
+        return CounterService_TOOLS
 
     @server.call_tool()
-    async def handle_call_tool(name: str, arguments: dict[str, Any]) -> list[types.TextContent]:
+    async def handle_call_tool(name: str, arguments: dict[str, Any]) -> list[types.TextContent] | tuple[list[types.TextContent], dict[str, Any]]:
         if name == "counter_service-count_v1":
             req = ParseDict(arguments, counter.v1.counter_pb2.CountRequest())
             _progress_token = None
@@ -110,7 +174,7 @@ def register_counter_service_mcp_handler(server: Server, impl: CounterServiceMCP
                         )
                 elif chunk.HasField("result"):
                     resp = chunk.result
-                    return [types.TextContent(type="text", text=json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)))]
+                    return _tool_result(resp, True)
             raise ValueError("Stream ended without result")
         raise ValueError(f"Unknown tool: {name}")
 
@@ -142,9 +206,9 @@ def register_counter_service_mcp_handler(server: Server, impl: CounterServiceMCP
             return [r for r in _resources if isinstance(r, types.ResourceTemplate)]
 
         @server.read_resource()
-        async def handle_read_resource(uri: str) -> str:
-            if uri == _app_resource_uri_value:
-                return _default_app_html("Counter App", "1.0.0", "A simple counter that streams progress as it counts")
+        async def handle_read_resource(uri: str) -> str | list[ReadResourceContents]:
+            if uri == "ui://counterservice/app.html":
+                return _app_view_contents(_default_app_html("Counter App", "1.0.0", "A simple counter that streams progress as it counts"), {})
             return "{}"
 
     _completion_map = _counter_service_completion_map()
@@ -218,14 +282,13 @@ def serve_counter_service_mcp(
 
 def forward_to_counter_service_mcp_client(server: Server, client: CounterServiceMCPClient) -> None:
     """Register all CounterService tools, prompts, and resources on the MCP server, forwarding each call to a remote gRPC server."""
-    _app_resource_uri_value = _app_resource_uri("CounterService")
 
     @server.list_tools()
     async def handle_list_tools() -> list[types.Tool]:
-        return [_tool_with_app_meta(t, _app_resource_uri_value) for t in CounterService_TOOLS]
+        return CounterService_TOOLS
 
     @server.call_tool()
-    async def handle_call_tool(name: str, arguments: dict[str, Any]) -> list[types.TextContent]:
+    async def handle_call_tool(name: str, arguments: dict[str, Any]) -> list[types.TextContent] | tuple[list[types.TextContent], dict[str, Any]]:
         if name == "counter_service-count_v1":
             req = ParseDict(arguments, counter.v1.counter_pb2.CountRequest())
             _progress_token = None
@@ -249,7 +312,7 @@ def forward_to_counter_service_mcp_client(server: Server, client: CounterService
                         )
                 elif chunk.HasField("result"):
                     resp = chunk.result
-                    return [types.TextContent(type="text", text=json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)))]
+                    return _tool_result(resp, True)
             raise ValueError("Stream ended without result")
         raise ValueError(f"Unknown tool: {name}")
 
@@ -281,9 +344,9 @@ def forward_to_counter_service_mcp_client(server: Server, client: CounterService
             return [r for r in _resources if isinstance(r, types.ResourceTemplate)]
 
         @server.read_resource()
-        async def handle_read_resource(uri: str) -> str:
-            if uri == _app_resource_uri_value:
-                return _default_app_html("Counter App", "1.0.0", "A simple counter that streams progress as it counts")
+        async def handle_read_resource(uri: str) -> str | list[ReadResourceContents]:
+            if uri == "ui://counterservice/app.html":
+                return _app_view_contents(_default_app_html("Counter App", "1.0.0", "A simple counter that streams progress as it counts"), {})
             return "{}"
 
     _completion_map = _counter_service_completion_map()
@@ -7,55 +7,108 @@
 from __future__ import annotations
 
 import json
+import re
 from typing import Any, Iterator, Protocol
+from urllib.parse import unquote
 
 import mcp.types as types
 from mcp.server.fastmcp import FastMCP
 from mcp.server.lowlevel import Server
+from mcp.server.lowlevel.helper_types import ReadResourceContents
 from google.protobuf.json_format import MessageToDict, ParseDict
 
 import google.protobuf.empty_pb2
 import todo.v1.todo_pb2
-TodoService_CreateTodo_SCHEMA = json.loads(r'''{"description":"Creates a new todo item under a user. Requires parent (e.g. users/alice), a todo object with title/description/priority, and a unique todo_id.","properties":{"parent":{"description":"Parent resource name (e.g. users/alice). The todo will be created under this user.","type":"string"},"todo":{"properties":{"completed":{"description":"Whether the todo is done.","type":"boolean"},"create_time":{"format":"date-time","type":["string","null"]},"description":{"description":"Optional longer description or notes.","type":"string"},"name":{"description":"Resource name (e.g. users/alice/todos/abc123). Required when updating.","type":"string"},"priority":{"description":"Priority level for a todo item. PRIORITY_UNSPECIFIED: Unspecified; use default priority.. PRIORITY_LOW: Low priority; can be done when convenient.. PRIORITY_MEDIUM: Normal priority; default for most todos.. PRIORITY_HIGH: High priority; should be done soon.. PRIORITY_URGENT: Urgent; do first.","enum":["PRIORITY_UNSPECIFIED","PRIORITY_LOW","PRIORITY_MEDIUM","PRIORITY_HIGH","PRIORITY_URGENT"],"enumDescriptions":{"PRIORITY_HIGH":"High priority; should be done soon.","PRIORITY_LOW":"Low priority; can be done when convenient.","PRIORITY_MEDIUM":"Normal priority; default for most todos.","PRIORITY_UNSPECIFIED":"Unspecified; use default priority.","PRIORITY_URGENT":"Urgent; do first."},"type":"string"},"title":{"description":"Short title for the todo.","type":"string"},"update_time":{"format":"date-time","type":["string","null"]}},"required":[],"type":"object"},"todo_id":{"description":"Unique ID for the todo (e.g. abc123). Becomes the final segment of the resource name.","examples":["abc123","todo-001"],"type":"string"}},"required":["parent","todo","todo_id"],"type":"object"}''')
+TodoService_CreateTodo_SCHEMA = json.loads(r'''{"description":"Creates a new todo item under a user. Requires parent (e.g. users/alice), a todo object with title/description/priority, and a unique todo_id.","properties":{"parent":{"description":"Parent resource name (e.g. users/alice). The todo will be created under this user.","type":"string"},"todo":{"description":"The todo item to create.","properties":{"completed":{"description":"Whether the todo is done. Optional.","type":"boolean"},"description":{"description":"Optional longer description or notes.","type":"string"},"priority":{"description":"Priority level for a todo item. PRIORITY_UNSPECIFIED: Unspecified; use default priority.. PRIORITY_LOW: Low priority; can be done when convenient.. PRIORITY_MEDIUM: Normal priority; default for most todos.. PRIORITY_HIGH: High priority; should be done soon.. PRIORITY_URGENT: Urgent; do first. Optional.","enum":["PRIORITY_UNSPECIFIED","PRIORITY_LOW","PRIORITY_MEDIUM","PRIORITY_HIGH","PRIORITY_URGENT"],"enumDescriptions":{"PRIORITY_HIGH":"High priority; should be done soon.","PRIORITY_LOW":"Low priority; can be done when convenient.","PRIORITY_MEDIUM":"Normal priority; default for most todos.","PRIORITY_UNSPECIFIED":"Unspecified; use default priority.","PRIORITY_URGENT":"Urgent; do first."},"type":"string"},"title":{"description":"Short title for the todo. Optional.","type":"string"}},"required":[],"type":"object"},"todo_id":{"description":"Unique ID for the todo (e.g. abc123). Becomes the final segment of the resource name.","examples":["abc123","todo-001"],"type":"string"}},"required":["parent","todo","todo_id"],"type":"object"}''')
 TodoService_DeleteTodo_SCHEMA = json.loads(r'''{"description":"Permanently deletes a todo item by its resource name. This action cannot be undone.","properties":{"name":{"description":"Resource name of the todo to delete (e.g. users/alice/todos/abc123).","type":"string"}},"required":["name"],"type":"object"}''')
 TodoService_GetTodo_SCHEMA = json.loads(r'''{"description":"Retrieves a single todo item by its resource name (e.g. users/alice/todos/abc123).","properties":{"name":{"description":"Resource name of the todo (e.g. users/alice/todos/abc123).","examples":["users/alice/todos/abc123"],"format":"uri","type":"string"}},"required":["name"],"type":"object"}''')
-TodoService_ListTodos_SCHEMA = json.loads(r'''{"description":"Lists all todo items for a user. Supports pagination via page_size and page_token.","properties":{"page_size":{"description":"Max number of todos to return (default 50).","type":"integer"},"page_token":{"description":"Token from previous response for next page.","type":"string"},"parent":{"description":"Parent resource name (e.g. users/alice). Lists todos for this user.","type":"string"}},"required":["parent"],"type":"object"}''')
-TodoService_UpdateTodo_SCHEMA = json.loads(r'''{"description":"Updates an existing todo item. Send the todo with its resource name and the fields to update. Use update_mask to specify which fields to modify.","properties":{"todo":{"properties":{"completed":{"description":"Whether the todo is done.","type":"boolean"},"create_time":{"format":"date-time","type":["string","null"]},"description":{"description":"Optional longer description or notes.","type":"string"},"name":{"description":"Resource name (e.g. users/alice/todos/abc123). Required when updating.","type":"string"},"priority":{"description":"Priority level for a todo item. PRIORITY_UNSPECIFIED: Unspecified; use default priority.. PRIORITY_LOW: Low priority; can be done when convenient.. PRIORITY_MEDIUM: Normal priority; default for most todos.. PRIORITY_HIGH: High priority; should be done soon.. PRIORITY_URGENT: Urgent; do first.","enum":["PRIORITY_UNSPECIFIED","PRIORITY_LOW","PRIORITY_MEDIUM","PRIORITY_HIGH","PRIORITY_URGENT"],"enumDescriptions":{"PRIORITY_HIGH":"High priority; should be done soon.","PRIORITY_LOW":"Low priority; can be done when convenient.","PRIORITY_MEDIUM":"Normal priority; default for most todos.","PRIORITY_UNSPECIFIED":"Unspecified; use default priority.","PRIORITY_URGENT":"Urgent; do first."},"type":"string"},"title":{"description":"Short title for the todo.","type":"string"},"update_time":{"format":"date-time","type":["string","null"]}},"required":[],"type":"object"},"update_mask":{"description":"Comma-separated field names to update (e.g. title,completed). Omit to update all provided fields.","type":"string"}},"required":["todo"],"type":"object"}''')
+TodoService_ListTodos_SCHEMA = json.loads(r'''{"description":"Lists all todo items for a user. Supports pagination via page_size and page_token.","properties":{"page_size":{"description":"Max number of todos to return (default 50). Optional.","type":"integer"},"page_token":{"description":"Token from previous response for next page. Optional.","type":"string"},"parent":{"description":"Parent resource name (e.g. users/alice). Lists todos for this user.","type":"string"}},"required":["parent"],"type":"object"}''')
+TodoService_UpdateTodo_SCHEMA = json.loads(r'''{"description":"Updates an existing todo item. Send the todo with its resource name and the fields to update. Use update_mask to specify which fields to modify.","properties":{"todo":{"description":"The todo item to update.","properties":{"completed":{"description":"Whether the todo is done. Optional.","type":"boolean"},"description":{"description":"Optional longer description or notes.","type":"string"},"name":{"description":"Resource name (e.g. users/alice/todos/abc123). Required when updating. Identifier: the resource name of this resource.","type":"string"},"priority":{"description":"Priority level for a todo item. PRIORITY_UNSPECIFIED: Unspecified; use default priority.. PRIORITY_LOW: Low priority; can be done when convenient.. PRIORITY_MEDIUM: Normal priority; default for most todos.. PRIORITY_HIGH: High priority; should be done soon.. PRIORITY_URGENT: Urgent; do first. Optional.","enum":["PRIORITY_UNSPECIFIED","PRIORITY_LOW","PRIORITY_MEDIUM","PRIORITY_HIGH","PRIORITY_URGENT"],"enumDescriptions":{"PRIORITY_HIGH":"High priority; should be done soon.","PRIORITY_LOW":"Low priority; can be done when convenient.","PRIORITY_MEDIUM":"Normal priority; default for most todos.","PRIORITY_UNSPECIFIED":"Unspecified; use default priority.","PRIORITY_URGENT":"Urgent; do first."},"type":"string"},"title":{"description":"Short title for the todo. Optional.","type":"string"}},"required":["name"],"type":"object"},"update_mask":{"description":"Comma-separated field names to update (e.g. title,completed). Omit to update all provided fields. Optional.","type":"string"}},"required":["todo"],"type":"object"}''')
 
 
 TodoService_CreateTodo_TOOL = types.Tool(
     name="todo_service-create_todo_v1",
+    title="Create Todo",
     description="Creates a new todo item under a user. Requires parent (e.g. users/alice), a todo object with title/description/priority, and a unique todo_id.",
     inputSchema=TodoService_CreateTodo_SCHEMA,
 )
 
 TodoService_DeleteTodo_TOOL = types.Tool(
     name="todo_service-delete_todo_v1",
+    title="Delete Todo",
     description="Permanently deletes a todo item by its resource name. This action cannot be undone.",
     inputSchema=TodoService_DeleteTodo_SCHEMA,
 )
 
 TodoService_GetTodo_TOOL = types.Tool(
     name="todo_service-get_todo_v1",
+    title="Get Todo",
     description="Retrieves a single todo item by its resource name (e.g. users/alice/todos/abc123).",
     inputSchema=TodoService_GetTodo_SCHEMA,
 )
 
 TodoService_ListTodos_TOOL = types.Tool(
     name="todo_service-list_todos_v1",
+    title="List Todos",
     description="Lists all todo items for a user. Supports pagination via page_size and page_token.",
     inputSchema=TodoService_ListTodos_SCHEMA,
 )
 
 TodoService_UpdateTodo_TOOL = types.Tool(
     name="todo_service-update_todo_v1",
+    title="Update Todo",
     description="Updates an existing todo item. Send the todo with its resource name and the fields to update. Use update_mask to specify which fields to modify.",
     inputSchema=TodoService_UpdateTodo_SCHEMA,
 )
 
 
-def _app_resource_uri(service_name: str) -> str:
-    return f"ui://{service_name.lower()}/app.html"
+_APP_MIME_TYPE = "text/html;profile=mcp-app"
+
+
+def _resource_args(uri_template: str, uri: str, bindings: dict[str, str], name_binding: bool) -> dict[str, Any] | None:
+    """Map the variables of uri_template in uri to request fields, or return None if uri does not match."""
+    parts = re.split(r"\{(\+?)([^}]+)\}", uri_template)
+    pattern, names = re.escape(parts[0]), []
+    for i in range(1, len(parts), 3):
+        pattern += ("(.*)" if parts[i] else "([^/]*)") + re.escape(parts[i + 2])
+        names.append(parts[i + 1])
+    match = re.fullmatch(pattern, uri)
+    if match is None:
+        return None
+    args: dict[str, Any] = {}
+    if name_binding:
+        args["name"] = unquote(uri.split("://", 1)[-1])
+    for name, value in zip(names, match.groups()):
+        if name in bindings:
+            *parents, leaf = bindings[name].split(".")
+            target = args
+            for parent in parents:
+                target = target.setdefault(parent, {})
+            target[leaf] = unquote(value)
+    return args
+
+
+def _resource_data(data: bytes, mime_type: str) -> str | bytes:
+    base = mime_type.split(";", 1)[0]
+    if base.startswith("text/") or base in ("application/json", "application/xml") or base.endswith(("+json", "+xml")):
+        return data.decode()
+    return data
+
+
+def _resource_contents(resp: Any, mime_type: str) -> list[ReadResourceContents]:
+    """Render an RPC response as resource contents: HttpBody data, the value of a single string or bytes field, or JSON."""
+    if resp.DESCRIPTOR.full_name == "google.api.HttpBody":
+        content_type = resp.content_type or mime_type
+        return [ReadResourceContents(content=_resource_data(resp.data, content_type), mime_type=content_type)]
+    fields = resp.DESCRIPTOR.fields
+    if len(fields) == 1 and fields[0].label != fields[0].LABEL_REPEATED:
+        value = getattr(resp, fields[0].name)
+        if isinstance(value, bytes):
+            return [ReadResourceContents(content=_resource_data(value, mime_type), mime_type=mime_type)]
+        if isinstance(value, str):
+            return [ReadResourceContents(content=value, mime_type=mime_type)]
+    text = json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True))
+    return [ReadResourceContents(content=text, mime_type="application/json")]
 
 def _default_app_html(app_name: str, version: str, description: str) -> str:
     return (
@@ -69,12 +122,28 @@ def _default_app_html(app_name: str, version: str, description: str) -> str:
         "</body></html>"
     )
 
-def _tool_with_app_meta(tool: types.Tool, app_resource_uri: str) -> types.Tool:
-    meta = {"ui": {"resourceUri": app_resource_uri}}
+def _tool_with_app_meta(tool: types.Tool, resource_uri: str, visibility: list[str] | None = None) -> types.Tool:
+    ui: dict[str, Any] = {"resourceUri": resource_uri}
+    if visibility:
+        ui["visibility"] = visibility
+    meta = {**(tool.meta or {}), "ui": ui}
     if hasattr(tool, "model_copy"):
         return tool.model_copy(update={"meta": meta})
     return tool.copy(deep=True, update={"meta": meta})
 
+
+def _app_view_contents(html: str, ui: dict[str, Any]) -> list[ReadResourceContents]:
+    return [ReadResourceContents(content=html, mime_type=_APP_MIME_TYPE, meta={"ui": ui} if ui else None)]
+
+
+def _tool_result(resp: Any, structured: bool) -> list[types.TextContent] | tuple[list[types.TextContent], dict[str, Any]]:
+    """Render an RPC response as tool result content, also returned as structured content for MCP App views."""
+    data = MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)
+    content = [types.TextContent(type="text", text=json.dumps(data))]
+    if not structured:
+        return content
+    return content, data if isinstance(data, dict) else {"value": data}
+
 class TodoServiceMCPServer(Protocol):
     """Protocol that users implement to handle MCP tool calls backed by TodoService RPCs."""
     async def create_todo(self, request: todo.v1.todo_pb2.CreateTodoRequest) -> todo.v1.todo_pb2.Todo: ...
@@ -94,11 +163,11 @@ class TodoServiceMCPClient(Protocol):
 TodoService_MCP_DEFAULT_BASE_PATH = "/todo/v1/todoservice/mcp"
 
 TodoService_TOOLS: list[types.Tool] = [
-    TodoService_CreateTodo_TOOL,
+    _tool_with_app_meta(TodoService_CreateTodo_TOOL, "ui://todoservice/app.html"),
     TodoService_DeleteTodo_TOOL,
-    TodoService_GetTodo_TOOL,
-    TodoService_ListTodos_TOOL,
-    TodoService_UpdateTodo_TOOL,
+    _tool_with_app_meta(TodoService_GetTodo_TOOL, "ui://todoservice/app.html"),
+    _tool_with_app_meta(TodoService_ListTodos_TOOL, "ui://todoservice/app.html"),
+    _tool_with_app_meta(TodoService_UpdateTodo_TOOL, "ui://todoservice/app.html"),
 ]
 
 def _todo_service_prompts() -> list[types.Prompt]:
@@ -106,6 +175,7 @@ def _todo_service_prompts() -> list[types.Prompt]:
     return [
         types.Prompt(
             name="summarize_todos",
+            title="Summarize Todos",
             description="Summarize all pending todo items for a user",
             arguments=[
                 types.PromptArgument(name="user", description="The user ID to summarize todos for.", required=True),
@@ -113,6 +183,7 @@ def _todo_service_prompts() -> list[types.Prompt]:
         ),
         types.Prompt(
             name="prioritize_todos",
+            title="Prioritize Todos",
             description="Suggest a priority ordering for a user's incomplete todos",
             arguments=[
                 types.PromptArgument(name="user", description="The user ID whose todos to prioritize.", required=True),
@@ -124,8 +195,8 @@ def _todo_service_prompts() -> list[types.Prompt]:
 def _todo_service_resources() -> list[types.Resource | types.ResourceTemplate]:
     """Build the list of resource descriptors for TodoService."""
     return [
-        types.ResourceTemplate(uriTemplate="todo://users/{user}/todos/{todo}", name="Todo", description="Todo resource (users/{user}/todos/{todo})", mimeType="application/json"),
-        types.Resource(uri=_app_resource_uri("TodoService"), name="Todo App", mimeType="text/html"),
+        types.ResourceTemplate(uriTemplate="todo://users/{user}/todos/{todo}", name="Todo", description="A single todo item, read with GetTodo.", mimeType="application/json"),
+        types.Resource(uri="ui://todoservice/app.html", name="Todo App", description="A simple todo management application", mimeType=_APP_MIME_TYPE),
     ]
 
 def _todo_service_completion_map() -> dict[str, list[str]]:
@@ -136,14 +207,13 @@ def _todo_service_completion_map() -> dict[str, list[str]]:
 
 def register_todo_service_mcp_handler(server: Server, impl: TodoServiceMCPServer) -> None:
     """Register all TodoService tools, prompts, and resources on the given MCP server."""
-    _app_resource_uri_value = _app_resource_uri("TodoService")
 
     @server.list_tools()
     async def handle_list_tools() -> list[types.Tool]:
-        return [_tool_with_app_meta(t, _app_resource_uri_value) for t in TodoService_TOOLS]
+        return TodoService_TOOLS
 
     @server.call_tool()
-    async def handle_call_tool(name: str, arguments: dict[str, Any]) -> list[types.TextContent]:
+    async def handle_call_tool(name: str, arguments: dict[str, Any]) -> list[types.TextContent] | tuple[list[types.TextContent], dict[str, Any]]:
         if name == "todo_service-create_todo_v1":
             try:
                 _elicit_result = await server.request_context.session.elicit(
@@ -162,7 +232,7 @@ def register_todo_service_mcp_handler(server: Server, impl: TodoServiceMCPServer
                 pass  # Client does not support elicitation; proceed with tool call.
             req = ParseDict(arguments, todo.v1.todo_pb2.CreateTodoRequest())
             resp = await impl.create_todo(req)
-            return [types.TextContent(type="text", text=json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)))]
+            return _tool_result(resp, True)
         if name == "todo_service-delete_todo_v1":
             try:
                 _elicit_result = await server.request_context.session.elicit(
@@ -181,15 +251,15 @@ def register_todo_service_mcp_handler(server: Server, impl: TodoServiceMCPServer
                 pass  # Client does not support elicitation; proceed with tool call.
             req = ParseDict(arguments, todo.v1.todo_pb2.DeleteTodoRequest())
             resp = await impl.delete_todo(req)
-            return [types.TextContent(type="text", text=json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)))]
+            return _tool_result(resp, False)
         if name == "todo_service-get_todo_v1":
             req = ParseDict(arguments, todo.v1.todo_pb2.GetTodoRequest())
             resp = await impl.get_todo(req)
-            return [types.TextContent(type="text", text=json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)))]
+            return _tool_result(resp, True)
         if name == "todo_service-list_todos_v1":
             req = ParseDict(arguments, todo.v1.todo_pb2.ListTodosRequest())
             resp = await impl.list_todos(req)
-            return [types.TextContent(type="text", text=json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)))]
+            return _tool_result(resp, True)
         if name == "todo_service-update_todo_v1":
             try:
                 _elicit_result = await server.request_context.session.elicit(
@@ -208,7 +278,7 @@ def register_todo_service_mcp_handler(server: Server, impl: TodoServiceMCPServer
                 pass  # Client does not support elicitation; proceed with tool call.
             req = ParseDict(arguments, todo.v1.todo_pb2.UpdateTodoRequest())
             resp = await impl.update_todo(req)
-            return [types.TextContent(type="text", text=json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)))]
+            return _tool_result(resp, True)
         raise ValueError(f"Unknown tool: {name}")
 
     _prompts = _todo_service_prompts()
@@ -239,9 +309,118 @@ def register_todo_service_mcp_handler(server: Server, impl: TodoServiceMCPServer
             return [r for r in _resources if isinstance(r, types.ResourceTemplate)]
 
         @server.read_resource()
-        async def handle_read_resource(uri: str) -> str:
-            if uri == _app_resource_uri_value:
-                return _default_app_html("Todo App", "1.0.0", "A simple todo management application")
+        async def handle_read_resource(uri: str) -> str | list[ReadResourceContents]:
+            if uri == "ui://todoservice/app.html":
+                return _app_view_contents("""<!doctype html>
+<html lang=\"en\">
+<head>
+  <meta charset=\"utf-8\">
+  <title>Todo App</title>
+  <style>body {
+  margin: 0;
+  font: 14px/1.4 system-ui, sans-serif;
+  color: #1f2328;
+}
+main {
+  padding: 12px 16px;
+}
+h1 {
+  margin: 0 0 8px;
+  font-size: 16px;
+}
+ul {
+  margin: 0;
+  padding: 0;
+  list-style: none;
+}
+li {
+  padding: 6px 0;
+  border-bottom: 1px solid #d0d7de;
+}
+li.done .title {
+  text-decoration: line-through;
+  color: #656d76;
+}
+.priority {
+  margin-left: 8px;
+  font-size: 12px;
+  color: #656d76;
+}
+#empty {
+  color: #656d76;
+}
+</style>
+</head>
+<body>
+  <main>
+    <h1 id=\"title\">Todos</h1>
+    <ul id=\"todos\"></ul>
+    <p id=\"empty\">Call a todo tool to see its result here.</p>
+  </main>
+  <script>// Renders TodoService tool results. The host passes each result to the view
+// over the MCP Apps postMessage bridge; structuredContent holds the response
+// message as JSON with proto field names.
+(function () {
+  let nextId = 1;
+
+  function send(message) {
+    window.parent.postMessage(Object.assign({ jsonrpc: \"2.0\" }, message), \"*\");
+  }
+
+  function render(result) {
+    const data = (result && result.structuredContent) || {};
+    const todos = data.todos || (data.name ? [data] : []);
+    const list = document.getElementById(\"todos\");
+    list.replaceChildren();
+    for (const todo of todos) {
+      const item = document.createElement(\"li\");
+      if (todo.completed) {
+        item.className = \"done\";
+      }
+      const title = document.createElement(\"span\");
+      title.className = \"title\";
+      title.textContent = todo.title || todo.name;
+      item.append(title);
+      if (todo.priority && todo.priority !== \"PRIORITY_UNSPECIFIED\") {
+        const priority = document.createElement(\"span\");
+        priority.className = \"priority\";
+        priority.textContent = todo.priority.replace(\"PRIORITY_\", \"\").toLowerCase();
+        item.append(priority);
+      }
+      list.append(item);
+    }
+    document.getElementById(\"empty\").hidden = todos.length > 0;
+  }
+
+  window.addEventListener(\"message\", (event) => {
+    const message = event.data;
+    if (!message || message.jsonrpc !== \"2.0\") {
+      return;
+    }
+    if (message.method === \"ui/notifications/tool-result\") {
+      render(message.params);
+    } else if (message.id === 1 && message.result) {
+      send({ method: \"ui/notifications/initialized\" });
+    }
+  });
+
+  send({
+    id: nextId++,
+    method: \"ui/initialize\",
+    params: {
+      appInfo: { name: \"Todo App\", version: \"1.0.0\" },
+      appCapabilities: {},
+      protocolVersion: \"2026-01-26\",
+    },
+  });
+})();
+</script>
+</body>
+</html>""", {"prefersBorder": True})
+            args = _resource_args("todo://users/{user}/todos/{todo}", str(uri), {}, True)
+            if args is not None:
+                resp = await impl.get_todo(ParseDict(args, todo.v1.todo_pb2.GetTodoRequest()))
+                return _resource_contents(resp, "application/json")
             return "{}"
 
     _completion_map = _todo_service_completion_map()
@@ -277,7 +456,12 @@ def serve_todo_service_mcp(
     has_stdio = "stdio" in transports
     http_transports = [t for t in transports if t in ("sse", "streamable-http")]
 
-    mcp_server = FastMCP("TodoService", json_response=True, streamable_http_path="/")
+    mcp_server = FastMCP(
+        "TodoService",
+        instructions="Todos belong to users (parent users/{user}). Call todo_service-list_todos_v1 to find a todo's resource name before getting, updating or deleting it.",
+        json_response=True,
+        streamable_http_path="/",
+    )
     register_todo_service_mcp_handler(mcp_server._mcp_server, impl)
 
     @contextlib.asynccontextmanager
@@ -315,14 +499,13 @@ def serve_todo_service_mcp(
 
 def forward_to_todo_service_mcp_client(server: Server, client: TodoServiceMCPClient) -> None:
     """Register all TodoService tools, prompts, and resources on the MCP server, forwarding each call to a remote gRPC server."""
-    _app_resource_uri_value = _app_resource_uri("TodoService")
 
     @server.list_tools()
     async def handle_list_tools() -> list[types.Tool]:
-        return [_tool_with_app_meta(t, _app_resource_uri_value) for t in TodoService_TOOLS]
+        return TodoService_TOOLS
 
     @server.call_tool()
-    async def handle_call_tool(name: str, arguments: dict[str, Any]) -> list[types.TextContent]:
+    async def handle_call_tool(name: str, arguments: dict[str, Any]) -> list[types.TextContent] | tuple[list[types.TextContent], dict[str, Any]]:
         if name == "todo_service-create_todo_v1":
             try:
                 _elicit_result = await server.request_context.session.elicit(
@@ -341,7 +524,7 @@ def forward_to_todo_service_mcp_client(server: Server, client: TodoServiceMCPCli
                 pass  # Client does not support elicitation; proceed with tool call.
             req = ParseDict(arguments, todo.v1.todo_pb2.CreateTodoRequest())
             resp = await client.create_todo(req)
-            return [types.TextContent(type="text", text=json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)))]
+            return _tool_result(resp, True)
         if name == "todo_service-delete_todo_v1":
             try:
                 _elicit_result = await server.request_context.session.elicit(
@@ -360,15 +543,15 @@ def forward_to_todo_service_mcp_client(server: Server, client: TodoServiceMCPCli
                 pass  # Client does not support elicitation; proceed with tool call.
             req = ParseDict(arguments, todo.v1.todo_pb2.DeleteTodoRequest())
             resp = await client.delete_todo(req)
-            return [types.TextContent(type="text", text=json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)))]
+            return _tool_result(resp, False)
         if name == "todo_service-get_todo_v1":
             req = ParseDict(arguments, todo.v1.todo_pb2.GetTodoRequest())
             resp = await client.get_todo(req)
-            return [types.TextContent(type="text", text=json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)))]
+            return _tool_result(resp, True)
         if name == "todo_service-list_todos_v1":
             req = ParseDict(arguments, todo.v1.todo_pb2.ListTodosRequest())
             resp = await client.list_todos(req)
-            return [types.TextContent(type="text", text=json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)))]
+            return _tool_result(resp, True)
         if name == "todo_service-update_todo_v1":
             try:
                 _elicit_result = await server.request_context.session.elicit(
@@ -387,7 +570,7 @@ def forward_to_todo_service_mcp_client(server: Server, client: TodoServiceMCPCli
                 pass  # Client does not support elicitation; proceed with tool call.
             req = ParseDict(arguments, todo.v1.todo_pb2.UpdateTodoRequest())
             resp = await client.update_todo(req)
-            return [types.TextContent(type="text", text=json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)))]
+            return _tool_result(resp, True)
         raise ValueError(f"Unknown tool: {name}")
 
     _prompts = _todo_service_prompts()
@@ -418,9 +601,118 @@ def forward_to_todo_service_mcp_client(server: Server, client: TodoServiceMCPCli
             return [r for r in _resources if isinstance(r, types.ResourceTemplate)]
 
         @server.read_resource()
-        async def handle_read_resource(uri: str) -> str:
-            if uri == _app_resource_uri_value:
-                return _default_app_html("Todo App", "1.0.0", "A simple todo management application")
+        async def handle_read_resource(uri: str) -> str | list[ReadResourceContents]:
+            if uri == "ui://todoservice/app.html":
+                return _app_view_contents("""<!doctype html>
+<html lang=\"en\">
+<head>
+  <meta charset=\"utf-8\">
+  <title>Todo App</title>
+  <style>body {
+  margin: 0;
+  font: 14px/1.4 system-ui, sans-serif;
+  color: #1f2328;
+}
+main {
+  padding: 12px 16px;
+}
+h1 {
+  margin: 0 0 8px;
+  font-size: 16px;
+}
+ul {
+  margin: 0;
+  padding: 0;
+  list-style: none;
+}
+li {
+  padding: 6px 0;
+  border-bottom: 1px solid #d0d7de;
+}
+li.done .title {
+  text-decoration: line-through;
+  color: #656d76;
+}
+.priority {
+  margin-left: 8px;
+  font-size: 12px;
+  color: #656d76;
+}
+#empty {
+  color: #656d76;
+}
+</style>
+</head>
+<body>
+  <main>
+    <h1 id=\"title\">Todos</h1>
+    <ul id=\"todos\"></ul>
+    <p id=\"empty\">Call a todo tool to see its result here.</p>
+  </main>
+  <script>// Renders TodoService tool results. The host passes each result to the view
+// over the MCP Apps postMessage bridge; structuredContent holds the response
+// message as JSON with proto field names.
+(function () {
+  let nextId = 1;
+
+  function send(message) {
+    window.parent.postMessage(Object.assign({ jsonrpc: \"2.0\" }, message), \"*\");
+  }
+
+  function render(result) {
+    const data = (result && result.structuredContent) || {};
+    const todos = data.todos || (data.name ? [data] : []);
+    const list = document.getElementById(\"todos\");
+    list.replaceChildren();
+    for (const todo of todos) {
+      const item = document.createElement(\"li\");
+      if (todo.completed) {
+        item.className = \"done\";
+      }
+      const title = document.createElement(\"span\");
+      title.className = \"title\";
+      title.textContent = todo.title || todo.name;
+      item.append(title);
+      if (todo.priority && todo.priority !== \"PRIORITY_UNSPECIFIED\") {
+        const priority = document.createElement(\"span\");
+        priority.className = \"priority\";
+        priority.textContent = todo.priority.replace(\"PRIORITY_\", \"\").toLowerCase();
+        item.append(priority);
+      }
+      list.append(item);
+    }
+    document.getElementById(\"empty\").hidden = todos.length > 0;
+  }
+
+  window.addEventListener(\"message\", (event) => {
+    const message = event.data;
+    if (!message || message.jsonrpc !== \"2.0\") {
+      return;
+    }
+    if (message.method === \"ui/notifications/tool-result\") {
+      render(message.params);
+    } else if (message.id === 1 && message.result) {
+      send({ method: \"ui/notifications/initialized\" });
+    }
+  });
+
+  send({
+    id: nextId++,
+    method: \"ui/initialize\",
+    params: {
+      appInfo: { name: \"Todo App\", version: \"1.0.0\" },
+      appCapabilities: {},
+      protocolVersion: \"2026-01-26\",
+    },
+  });
+})();
+</script>
+</body>
+</html>""", {"prefersBorder": True})
+            args = _resource_args("todo://users/{user}/todos/{todo}", str(uri), {}, True)
+            if args is not None:
+                resp = await client.get_todo(ParseDict(args, todo.v1.todo_pb2.GetTodoRequest()))
+                return _resource_contents(resp, "application/json")
             return "{}"
 
     _completion_map = _todo_service_completion_map()
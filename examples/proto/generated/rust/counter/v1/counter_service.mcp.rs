@@ -9,23 +9,120 @@ use async_trait::async_trait;
 use rmcp::{ErrorData as McpError, RoleServer, ServerHandler, ServiceExt, model::*, service::RequestContext};
 use serde_json::{self, json, Value};
 
-fn make_tool(name: &str, description: &str, schema_json: &str) -> Tool {
+fn make_tool(name: &str, title: &str, description: &str, schema_json: &str, icons: Value) -> Tool {
     serde_json::from_value(json!({
-        "name": name, "description": description,
+        "name": name, "title": title, "description": description,
         "inputSchema": serde_json::from_str::<Value>(schema_json).unwrap(),
+        "icons": icons,
     })).expect("generated tool schema must be valid")
 }
 
-fn make_tool_with_app_meta(name: &str, description: &str, schema_json: &str, app_resource_uri: &str) -> Tool {
+fn make_tool_with_app_meta(name: &str, title: &str, description: &str, schema_json: &str, icons: Value, resource_uri: &str, visibility: &[&str]) -> Tool {
+    let mut ui = json!({ "resourceUri": resource_uri });
+    if !visibility.is_empty() {
+        ui["visibility"] = json!(visibility);
+    }
     serde_json::from_value(json!({
-        "name": name, "description": description,
+        "name": name, "title": title, "description": description,
         "inputSchema": serde_json::from_str::<Value>(schema_json).unwrap(),
-        "_meta": { "ui": { "resourceUri": app_resource_uri } }
+        "icons": icons,
+        "_meta": { "ui": ui }
     })).expect("generated tool schema must be valid")
 }
 
-fn app_resource_uri(service_name: &str) -> String {
-    format!("ui://{}/app.html", service_name.to_lowercase())
+const APP_MIME_TYPE: &str = "text/html;profile=mcp-app";
+
+fn app_view_contents(html: String, uri: String, ui: Value) -> ResourceContents {
+    let mut contents = json!({ "uri": uri, "mimeType": APP_MIME_TYPE, "text": html });
+    if ui.as_object().is_some_and(|ui| !ui.is_empty()) {
+        contents["_meta"] = json!({ "ui": ui });
+    }
+    serde_json::from_value(contents).expect("generated app view contents must be valid")
+}
+
+/// Renders an RPC response as a tool result, also carried as structured
+/// content for MCP App views.
+fn tool_result(result: Value, structured: bool) -> std::result::Result<CallToolResult, McpError> {
+    let text = serde_json::to_string(&result)
+        .map_err(|e| McpError::internal_error(format!("serialize response: {e}"), None))?;
+    let mut res = CallToolResult::success(vec![Content::text(text)]);
+    if structured {
+        res.structured_content = Some(if result.is_object() { result } else { json!({ "value": result }) });
+    }
+    Ok(res)
+}
+
+fn percent_decode(s: &str) -> String {
+    let mut out = Vec::with_capacity(s.len());
+    let mut i = 0;
+    while i < s.len() {
+        let byte = s.as_bytes()[i];
+        if byte == b'%' {
+            if let Some(b) = s.get(i + 1..i + 3).and_then(|h| u8::from_str_radix(h, 16).ok()) {
+                out.push(b);
+                i += 3;
+                continue;
+            }
+        }
+        out.push(byte);
+        i += 1;
+    }
+    String::from_utf8_lossy(&out).into_owned()
+}
+
+/// Maps the variables of `uri_template` in `uri` to request fields, or returns
+/// None if `uri` does not match the template.
+fn resource_args(uri_template: &str, uri: &str, bindings: &[(&str, &str)], name_binding: bool) -> Option<Value> {
+    let mut args = serde_json::Map::new();
+    if name_binding {
+        let name = uri.split_once("://").map_or(uri, |(_, path)| path);
+        args.insert("name".to_string(), Value::String(percent_decode(name)));
+    }
+    let (mut template, mut rest) = (uri_template, uri);
+    while let Some(start) = template.find('{') {
+        rest = rest.strip_prefix(&template[..start])?;
+        let end = start + template[start..].find('}')?;
+        let var = &template[start + 1..end];
+        template = &template[end + 1..];
+        let (var, reserved) = var.strip_prefix('+').map_or((var, false), |v| (v, true));
+        let literal = &template[..template.find('{').unwrap_or(template.len())];
+        let len = if literal.is_empty() { rest.len() } else { rest.find(literal)? };
+        let value = &rest[..len];
+        if !reserved && value.contains('/') {
+            return None;
+        }
+        rest = &rest[len..];
+        if let Some((_, field)) = bindings.iter().find(|(v, _)| *v == var) {
+            let mut target = &mut args;
+            let mut parts = field.split('.').peekable();
+            while let Some(part) = parts.next() {
+                if parts.peek().is_none() {
+                    target.insert(part.to_string(), Value::String(percent_decode(value)));
+                } else {
+                    target = target.entry(part.to_string()).or_insert_with(|| json!({})).as_object_mut()?;
+                }
+            }
+        }
+    }
+    (rest == template).then_some(Value::Object(args))
+}
+
+/// Renders an RPC result as resource contents: HttpBody data, the value of the
+/// response's only string or bytes field, or JSON.
+fn resource_contents(result: Value, uri: String, mime_type: &str, content: &str, field: &str) -> ResourceContents {
+    let blob = |data: &Value, mime_type: &str, uri: String| -> ResourceContents {
+        serde_json::from_value(json!({ "uri": uri, "mimeType": mime_type, "blob": data.as_str().unwrap_or_default() }))
+            .expect("generated blob resource must be valid")
+    };
+    match content {
+        "http_body" => {
+            let content_type = result["content_type"].as_str().filter(|s| !s.is_empty()).unwrap_or(mime_type);
+            blob(&result["data"], content_type, uri)
+        }
+        "bytes" => blob(&result[field], mime_type, uri),
+        "string" => ResourceContents::text(result[field].as_str().unwrap_or_default(), uri).with_mime_type(mime_type),
+        _ => ResourceContents::text(result.to_string(), uri).with_mime_type("application/json"),
+    }
 }
 
 fn default_app_html(app_name: &str, version: &str, description: &str) -> String {
@@ -48,24 +145,21 @@ impl<T: CounterServiceMcpServer> CounterServiceMcpHandler<T> {
     pub fn new(svc: T) -> Self { Self { inner: Arc::new(svc) } }
 
     fn tools() -> Vec<Tool> {
-        let app_uri = app_resource_uri("CounterService");
         vec![
         ]
     }
 
     fn all_tools() -> Vec<Tool> {
-        let app_uri = app_resource_uri("CounterService");
         vec![
-            make_tool_with_app_meta("counter_service-count_v1", "Counts from 0 up to the given number. Sends progress updates as it counts. Use with progressToken in _meta for progress notifications.", COUNTER_SERVICE__COUNT_SCHEMA_JSON, &app_uri),
+            make_tool_with_app_meta("counter_service-count_v1", "Count", "Counts from 0 up to the given number. Sends progress updates as it counts. Use with progressToken in _meta for progress notifications.", COUNTER_SERVICE__COUNT_SCHEMA_JSON, Value::Null, "ui://counterservice/app.html", &[]),
         ]
     }
 
     fn resources() -> Vec<Resource> {
         vec![
             serde_json::from_value(json!({
-                "uri": app_resource_uri("CounterService"),
-                "name": "Counter App",
-                "mimeType": "text/html"
+                "uri": "ui://counterservice/app.html", "name": "Counter App", "description": "A simple counter that streams progress as it counts",
+                "mimeType": APP_MIME_TYPE
             })).expect("generated app resource must be valid"),
         ]
     }
@@ -107,12 +201,9 @@ impl<T: CounterServiceMcpServer> ServerHandler for CounterServiceMcpHandler<T> {
     }
 
     async fn read_resource(&self, request: ReadResourceRequestParams, _: RequestContext<RoleServer>) -> std::result::Result<ReadResourceResult, McpError> {
-        let app_uri = app_resource_uri("CounterService");
-        if request.uri == app_uri {
+        if request.uri == "ui://counterservice/app.html" {
             let html = default_app_html("Counter App", "1.0.0", "A simple counter that streams progress as it counts");
-            return Ok(ReadResourceResult::new(vec![
-                ResourceContents::text(html, request.uri).with_mime_type("text/html")
-            ]));
+            return Ok(ReadResourceResult::new(vec![app_view_contents(html, request.uri, json!({}))]));
         }
         Ok(ReadResourceResult::new(vec![ResourceContents::text("{}", request.uri)]))
     }
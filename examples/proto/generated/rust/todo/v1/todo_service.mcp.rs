@@ -9,34 +9,131 @@ use async_trait::async_trait;
 use rmcp::{ErrorData as McpError, RoleServer, ServerHandler, ServiceExt, model::*, service::RequestContext};
 use serde_json::{self, json, Value};
 
-fn make_tool(name: &str, description: &str, schema_json: &str) -> Tool {
+fn make_tool(name: &str, title: &str, description: &str, schema_json: &str, icons: Value) -> Tool {
     serde_json::from_value(json!({
-        "name": name, "description": description,
+        "name": name, "title": title, "description": description,
         "inputSchema": serde_json::from_str::<Value>(schema_json).unwrap(),
+        "icons": icons,
     })).expect("generated tool schema must be valid")
 }
 
-fn make_tool_with_app_meta(name: &str, description: &str, schema_json: &str, app_resource_uri: &str) -> Tool {
+fn make_tool_with_app_meta(name: &str, title: &str, description: &str, schema_json: &str, icons: Value, resource_uri: &str, visibility: &[&str]) -> Tool {
+    let mut ui = json!({ "resourceUri": resource_uri });
+    if !visibility.is_empty() {
+        ui["visibility"] = json!(visibility);
+    }
     serde_json::from_value(json!({
-        "name": name, "description": description,
+        "name": name, "title": title, "description": description,
         "inputSchema": serde_json::from_str::<Value>(schema_json).unwrap(),
-        "_meta": { "ui": { "resourceUri": app_resource_uri } }
+        "icons": icons,
+        "_meta": { "ui": ui }
     })).expect("generated tool schema must be valid")
 }
 
-fn app_resource_uri(service_name: &str) -> String {
-    format!("ui://{}/app.html", service_name.to_lowercase())
+const APP_MIME_TYPE: &str = "text/html;profile=mcp-app";
+
+fn app_view_contents(html: String, uri: String, ui: Value) -> ResourceContents {
+    let mut contents = json!({ "uri": uri, "mimeType": APP_MIME_TYPE, "text": html });
+    if ui.as_object().is_some_and(|ui| !ui.is_empty()) {
+        contents["_meta"] = json!({ "ui": ui });
+    }
+    serde_json::from_value(contents).expect("generated app view contents must be valid")
+}
+
+/// Renders an RPC response as a tool result, also carried as structured
+/// content for MCP App views.
+fn tool_result(result: Value, structured: bool) -> std::result::Result<CallToolResult, McpError> {
+    let text = serde_json::to_string(&result)
+        .map_err(|e| McpError::internal_error(format!("serialize response: {e}"), None))?;
+    let mut res = CallToolResult::success(vec![Content::text(text)]);
+    if structured {
+        res.structured_content = Some(if result.is_object() { result } else { json!({ "value": result }) });
+    }
+    Ok(res)
+}
+
+fn percent_decode(s: &str) -> String {
+    let mut out = Vec::with_capacity(s.len());
+    let mut i = 0;
+    while i < s.len() {
+        let byte = s.as_bytes()[i];
+        if byte == b'%' {
+            if let Some(b) = s.get(i + 1..i + 3).and_then(|h| u8::from_str_radix(h, 16).ok()) {
+                out.push(b);
+                i += 3;
+                continue;
+            }
+        }
+        out.push(byte);
+        i += 1;
+    }
+    String::from_utf8_lossy(&out).into_owned()
+}
+
+/// Maps the variables of `uri_template` in `uri` to request fields, or returns
+/// None if `uri` does not match the template.
+fn resource_args(uri_template: &str, uri: &str, bindings: &[(&str, &str)], name_binding: bool) -> Option<Value> {
+    let mut args = serde_json::Map::new();
+    if name_binding {
+        let name = uri.split_once("://").map_or(uri, |(_, path)| path);
+        args.insert("name".to_string(), Value::String(percent_decode(name)));
+    }
+    let (mut template, mut rest) = (uri_template, uri);
+    while let Some(start) = template.find('{') {
+        rest = rest.strip_prefix(&template[..start])?;
+        let end = start + template[start..].find('}')?;
+        let var = &template[start + 1..end];
+        template = &template[end + 1..];
+        let (var, reserved) = var.strip_prefix('+').map_or((var, false), |v| (v, true));
+        let literal = &template[..template.find('{').unwrap_or(template.len())];
+        let len = if literal.is_empty() { rest.len() } else { rest.find(literal)? };
+        let value = &rest[..len];
+        if !reserved && value.contains('/') {
+            return None;
+        }
+        rest = &rest[len..];
+        if let Some((_, field)) = bindings.iter().find(|(v, _)| *v == var) {
+            let mut target = &mut args;
+            let mut parts = field.split('.').peekable();
+            while let Some(part) = parts.next() {
+                if parts.peek().is_none() {
+                    target.insert(part.to_string(), Value::String(percent_decode(value)));
+                } else {
+                    target = target.entry(part.to_string()).or_insert_with(|| json!({})).as_object_mut()?;
+                }
+            }
+        }
+    }
+    (rest == template).then_some(Value::Object(args))
+}
+
+/// Renders an RPC result as resource contents: HttpBody data, the value of the
+/// response's only string or bytes field, or JSON.
+fn resource_contents(result: Value, uri: String, mime_type: &str, content: &str, field: &str) -> ResourceContents {
+    let blob = |data: &Value, mime_type: &str, uri: String| -> ResourceContents {
+        serde_json::from_value(json!({ "uri": uri, "mimeType": mime_type, "blob": data.as_str().unwrap_or_default() }))
+            .expect("generated blob resource must be valid")
+    };
+    match content {
+        "http_body" => {
+            let content_type = result["content_type"].as_str().filter(|s| !s.is_empty()).unwrap_or(mime_type);
+            blob(&result["data"], content_type, uri)
+        }
+        "bytes" => blob(&result[field], mime_type, uri),
+        "string" => ResourceContents::text(result[field].as_str().unwrap_or_default(), uri).with_mime_type(mime_type),
+        _ => ResourceContents::text(result.to_string(), uri).with_mime_type("application/json"),
+    }
 }
 
 fn default_app_html(app_name: &str, version: &str, description: &str) -> String {
     format!("<!DOCTYPE html><html lang=\"en\"><head><meta charset=\"utf-8\"><title>{app_name}</title></head><body><h1>{app_name}</h1><p>v{version}</p><p>{description}</p><p>This is a generated MCP App placeholder. Replace this resource with your own UI.</p></body></html>")
 }
 
-const TODO_SERVICE__CREATE_TODO_SCHEMA_JSON: &str = r##"{"description":"Creates a new todo item under a user. Requires parent (e.g. users/alice), a todo object with title/description/priority, and a unique todo_id.","properties":{"parent":{"description":"Parent resource name (e.g. users/alice). The todo will be created under this user.","type":"string"},"todo":{"properties":{"completed":{"description":"Whether the todo is done.","type":"boolean"},"create_time":{"format":"date-time","type":["string","null"]},"description":{"description":"Optional longer description or notes.","type":"string"},"name":{"description":"Resource name (e.g. users/alice/todos/abc123). Required when updating.","type":"string"},"priority":{"description":"Priority level for a todo item. PRIORITY_UNSPECIFIED: Unspecified; use default priority.. PRIORITY_LOW: Low priority; can be done when convenient.. PRIORITY_MEDIUM: Normal priority; default for most todos.. PRIORITY_HIGH: High priority; should be done soon.. PRIORITY_URGENT: Urgent; do first.","enum":["PRIORITY_UNSPECIFIED","PRIORITY_LOW","PRIORITY_MEDIUM","PRIORITY_HIGH","PRIORITY_URGENT"],"enumDescriptions":{"PRIORITY_HIGH":"High priority; should be done soon.","PRIORITY_LOW":"Low priority; can be done when convenient.","PRIORITY_MEDIUM":"Normal priority; default for most todos.","PRIORITY_UNSPECIFIED":"Unspecified; use default priority.","PRIORITY_URGENT":"Urgent; do first."},"type":"string"},"title":{"description":"Short title for the todo.","type":"string"},"update_time":{"format":"date-time","type":["string","null"]}},"required":[],"type":"object"},"todo_id":{"description":"Unique ID for the todo (e.g. abc123). Becomes the final segment of the resource name.","examples":["abc123","todo-001"],"type":"string"}},"required":["parent","todo","todo_id"],"type":"object"}"##;
+const TODO_SERVICE__CREATE_TODO_SCHEMA_JSON: &str = r##"{"description":"Creates a new todo item under a user. Requires parent (e.g. users/alice), a todo object with title/description/priority, and a unique todo_id.","properties":{"parent":{"description":"Parent resource name (e.g. users/alice). The todo will be created under this user.","type":"string"},"todo":{"description":"The todo item to create.","properties":{"completed":{"description":"Whether the todo is done. Optional.","type":"boolean"},"description":{"description":"Optional longer description or notes.","type":"string"},"priority":{"description":"Priority level for a todo item. PRIORITY_UNSPECIFIED: Unspecified; use default priority.. PRIORITY_LOW: Low priority; can be done when convenient.. PRIORITY_MEDIUM: Normal priority; default for most todos.. PRIORITY_HIGH: High priority; should be done soon.. PRIORITY_URGENT: Urgent; do first. Optional.","enum":["PRIORITY_UNSPECIFIED","PRIORITY_LOW","PRIORITY_MEDIUM","PRIORITY_HIGH","PRIORITY_URGENT"],"enumDescriptions":{"PRIORITY_HIGH":"High priority; should be done soon.","PRIORITY_LOW":"Low priority; can be done when convenient.","PRIORITY_MEDIUM":"Normal priority; default for most todos.","PRIORITY_UNSPECIFIED":"Unspecified; use default priority.","PRIORITY_URGENT":"Urgent; do first."},"type":"string"},"title":{"description":"Short title for the todo. Optional.","type":"string"}},"required":[],"type":"object"},"todo_id":{"description":"Unique ID for the todo (e.g. abc123). Becomes the final segment of the resource name.","examples":["abc123","todo-001"],"type":"string"}},"required":["parent","todo","todo_id"],"type":"object"}"##;
 const TODO_SERVICE__DELETE_TODO_SCHEMA_JSON: &str = r##"{"description":"Permanently deletes a todo item by its resource name. This action cannot be undone.","properties":{"name":{"description":"Resource name of the todo to delete (e.g. users/alice/todos/abc123).","type":"string"}},"required":["name"],"type":"object"}"##;
 const TODO_SERVICE__GET_TODO_SCHEMA_JSON: &str = r##"{"description":"Retrieves a single todo item by its resource name (e.g. users/alice/todos/abc123).","properties":{"name":{"description":"Resource name of the todo (e.g. users/alice/todos/abc123).","examples":["users/alice/todos/abc123"],"format":"uri","type":"string"}},"required":["name"],"type":"object"}"##;
-const TODO_SERVICE__LIST_TODOS_SCHEMA_JSON: &str = r##"{"description":"Lists all todo items for a user. Supports pagination via page_size and page_token.","properties":{"page_size":{"description":"Max number of todos to return (default 50).","type":"integer"},"page_token":{"description":"Token from previous response for next page.","type":"string"},"parent":{"description":"Parent resource name (e.g. users/alice). Lists todos for this user.","type":"string"}},"required":["parent"],"type":"object"}"##;
-const TODO_SERVICE__UPDATE_TODO_SCHEMA_JSON: &str = r##"{"description":"Updates an existing todo item. Send the todo with its resource name and the fields to update. Use update_mask to specify which fields to modify.","properties":{"todo":{"properties":{"completed":{"description":"Whether the todo is done.","type":"boolean"},"create_time":{"format":"date-time","type":["string","null"]},"description":{"description":"Optional longer description or notes.","type":"string"},"name":{"description":"Resource name (e.g. users/alice/todos/abc123). Required when updating.","type":"string"},"priority":{"description":"Priority level for a todo item. PRIORITY_UNSPECIFIED: Unspecified; use default priority.. PRIORITY_LOW: Low priority; can be done when convenient.. PRIORITY_MEDIUM: Normal priority; default for most todos.. PRIORITY_HIGH: High priority; should be done soon.. PRIORITY_URGENT: Urgent; do first.","enum":["PRIORITY_UNSPECIFIED","PRIORITY_LOW","PRIORITY_MEDIUM","PRIORITY_HIGH","PRIORITY_URGENT"],"enumDescriptions":{"PRIORITY_HIGH":"High priority; should be done soon.","PRIORITY_LOW":"Low priority; can be done when convenient.","PRIORITY_MEDIUM":"Normal priority; default for most todos.","PRIORITY_UNSPECIFIED":"Unspecified; use default priority.","PRIORITY_URGENT":"Urgent; do first."},"type":"string"},"title":{"description":"Short title for the todo.","type":"string"},"update_time":{"format":"date-time","type":["string","null"]}},"required":[],"type":"object"},"update_mask":{"description":"Comma-separated field names to update (e.g. title,completed). Omit to update all provided fields.","type":"string"}},"required":["todo"],"type":"object"}"##;
+const TODO_SERVICE__LIST_TODOS_SCHEMA_JSON: &str = r##"{"description":"Lists all todo items for a user. Supports pagination via page_size and page_token.","properties":{"page_size":{"description":"Max number of todos to return (default 50). Optional.","type":"integer"},"page_token":{"description":"Token from previous response for next page. Optional.","type":"string"},"parent":{"description":"Parent resource name (e.g. users/alice). Lists todos for this user.","type":"string"}},"required":["parent"],"type":"object"}"##;
+const TODO_SERVICE__UPDATE_TODO_SCHEMA_JSON: &str = r##"{"description":"Updates an existing todo item. Send the todo with its resource name and the fields to update. Use update_mask to specify which fields to modify.","properties":{"todo":{"description":"The todo item to update.","properties":{"completed":{"description":"Whether the todo is done. Optional.","type":"boolean"},"description":{"description":"Optional longer description or notes.","type":"string"},"name":{"description":"Resource name (e.g. users/alice/todos/abc123). Required when updating. Identifier: the resource name of this resource.","type":"string"},"priority":{"description":"Priority level for a todo item. PRIORITY_UNSPECIFIED: Unspecified; use default priority.. PRIORITY_LOW: Low priority; can be done when convenient.. PRIORITY_MEDIUM: Normal priority; default for most todos.. PRIORITY_HIGH: High priority; should be done soon.. PRIORITY_URGENT: Urgent; do first. Optional.","enum":["PRIORITY_UNSPECIFIED","PRIORITY_LOW","PRIORITY_MEDIUM","PRIORITY_HIGH","PRIORITY_URGENT"],"enumDescriptions":{"PRIORITY_HIGH":"High priority; should be done soon.","PRIORITY_LOW":"Low priority; can be done when convenient.","PRIORITY_MEDIUM":"Normal priority; default for most todos.","PRIORITY_UNSPECIFIED":"Unspecified; use default priority.","PRIORITY_URGENT":"Urgent; do first."},"type":"string"},"title":{"description":"Short title for the todo. Optional.","type":"string"}},"required":["name"],"type":"object"},"update_mask":{"description":"Comma-separated field names to update (e.g. title,completed). Omit to update all provided fields. Optional.","type":"string"}},"required":["todo"],"type":"object"}"##;
 
 #[async_trait]
 pub trait TodoServiceMcpServer: Send + Sync + 'static {
@@ -57,24 +154,22 @@ impl<T: TodoServiceMcpServer> TodoServiceMcpHandler<T> {
     pub fn new(svc: T) -> Self { Self { inner: Arc::new(svc) } }
 
     fn tools() -> Vec<Tool> {
-        let app_uri = app_resource_uri("TodoService");
         vec![
-            make_tool_with_app_meta("todo_service-create_todo_v1", "Creates a new todo item under a user. Requires parent (e.g. users/alice), a todo object with title/description/priority, and a unique todo_id.", TODO_SERVICE__CREATE_TODO_SCHEMA_JSON, &app_uri),
-            make_tool_with_app_meta("todo_service-delete_todo_v1", "Permanently deletes a todo item by its resource name. This action cannot be undone.", TODO_SERVICE__DELETE_TODO_SCHEMA_JSON, &app_uri),
-            make_tool_with_app_meta("todo_service-get_todo_v1", "Retrieves a single todo item by its resource name (e.g. users/alice/todos/abc123).", TODO_SERVICE__GET_TODO_SCHEMA_JSON, &app_uri),
-            make_tool_with_app_meta("todo_service-list_todos_v1", "Lists all todo items for a user. Supports pagination via page_size and page_token.", TODO_SERVICE__LIST_TODOS_SCHEMA_JSON, &app_uri),
-            make_tool_with_app_meta("todo_service-update_todo_v1", "Updates an existing todo item. Send the todo with its resource name and the fields to update. Use update_mask to specify which fields to modify.", TODO_SERVICE__UPDATE_TODO_SCHEMA_JSON, &app_uri),
+            make_tool_with_app_meta("todo_service-create_todo_v1", "Create Todo", "Creates a new todo item under a user. Requires parent (e.g. users/alice), a todo object with title/description/priority, and a unique todo_id.", TODO_SERVICE__CREATE_TODO_SCHEMA_JSON, Value::Null, "ui://todoservice/app.html", &[]),
+            make_tool("todo_service-delete_todo_v1", "Delete Todo", "Permanently deletes a todo item by its resource name. This action cannot be undone.", TODO_SERVICE__DELETE_TODO_SCHEMA_JSON, Value::Null),
+            make_tool_with_app_meta("todo_service-get_todo_v1", "Get Todo", "Retrieves a single todo item by its resource name (e.g. users/alice/todos/abc123).", TODO_SERVICE__GET_TODO_SCHEMA_JSON, Value::Null, "ui://todoservice/app.html", &[]),
+            make_tool_with_app_meta("todo_service-list_todos_v1", "List Todos", "Lists all todo items for a user. Supports pagination via page_size and page_token.", TODO_SERVICE__LIST_TODOS_SCHEMA_JSON, Value::Null, "ui://todoservice/app.html", &[]),
+            make_tool_with_app_meta("todo_service-update_todo_v1", "Update Todo", "Updates an existing todo item. Send the todo with its resource name and the fields to update. Use update_mask to specify which fields to modify.", TODO_SERVICE__UPDATE_TODO_SCHEMA_JSON, Value::Null, "ui://todoservice/app.html", &[]),
         ]
     }
 
     fn all_tools() -> Vec<Tool> {
-        let app_uri = app_resource_uri("TodoService");
         vec![
-            make_tool_with_app_meta("todo_service-create_todo_v1", "Creates a new todo item under a user. Requires parent (e.g. users/alice), a todo object with title/description/priority, and a unique todo_id.", TODO_SERVICE__CREATE_TODO_SCHEMA_JSON, &app_uri),
-            make_tool_with_app_meta("todo_service-delete_todo_v1", "Permanently deletes a todo item by its resource name. This action cannot be undone.", TODO_SERVICE__DELETE_TODO_SCHEMA_JSON, &app_uri),
-            make_tool_with_app_meta("todo_service-get_todo_v1", "Retrieves a single todo item by its resource name (e.g. users/alice/todos/abc123).", TODO_SERVICE__GET_TODO_SCHEMA_JSON, &app_uri),
-            make_tool_with_app_meta("todo_service-list_todos_v1", "Lists all todo items for a user. Supports pagination via page_size and page_token.", TODO_SERVICE__LIST_TODOS_SCHEMA_JSON, &app_uri),
-            make_tool_with_app_meta("todo_service-update_todo_v1", "Updates an existing todo item. Send the todo with its resource name and the fields to update. Use update_mask to specify which fields to modify.", TODO_SERVICE__UPDATE_TODO_SCHEMA_JSON, &app_uri),
+            make_tool_with_app_meta("todo_service-create_todo_v1", "Create Todo", "Creates a new todo item under a user. Requires parent (e.g. users/alice), a todo object with title/description/priority, and a unique todo_id.", TODO_SERVICE__CREATE_TODO_SCHEMA_JSON, Value::Null, "ui://todoservice/app.html", &[]),
+            make_tool("todo_service-delete_todo_v1", "Delete Todo", "Permanently deletes a todo item by its resource name. This action cannot be undone.", TODO_SERVICE__DELETE_TODO_SCHEMA_JSON, Value::Null),
+            make_tool_with_app_meta("todo_service-get_todo_v1", "Get Todo", "Retrieves a single todo item by its resource name (e.g. users/alice/todos/abc123).", TODO_SERVICE__GET_TODO_SCHEMA_JSON, Value::Null, "ui://todoservice/app.html", &[]),
+            make_tool_with_app_meta("todo_service-list_todos_v1", "List Todos", "Lists all todo items for a user. Supports pagination via page_size and page_token.", TODO_SERVICE__LIST_TODOS_SCHEMA_JSON, Value::Null, "ui://todoservice/app.html", &[]),
+            make_tool_with_app_meta("todo_service-update_todo_v1", "Update Todo", "Updates an existing todo item. Send the todo with its resource name and the fields to update. Use update_mask to specify which fields to modify.", TODO_SERVICE__UPDATE_TODO_SCHEMA_JSON, Value::Null, "ui://todoservice/app.html", &[]),
         ]
     }
 
@@ -82,6 +177,7 @@ impl<T: TodoServiceMcpServer> TodoServiceMcpHandler<T> {
         vec![
             serde_json::from_value(json!({
                 "name": "summarize_todos",
+                "title": "Summarize Todos",
                 "description": "Summarize all pending todo items for a user",
                 "arguments": [
                     {"name": "user", "description": "The user ID to summarize todos for.", "required": true},
@@ -89,6 +185,7 @@ impl<T: TodoServiceMcpServer> TodoServiceMcpHandler<T> {
             })).expect("generated prompt must be valid"),
             serde_json::from_value(json!({
                 "name": "prioritize_todos",
+                "title": "Prioritize Todos",
                 "description": "Suggest a priority ordering for a user's incomplete todos",
                 "arguments": [
                     {"name": "user", "description": "The user ID whose todos to prioritize.", "required": true},
@@ -110,9 +207,8 @@ impl<T: TodoServiceMcpServer> TodoServiceMcpHandler<T> {
     fn resources() -> Vec<Resource> {
         vec![
             serde_json::from_value(json!({
-                "uri": app_resource_uri("TodoService"),
-                "name": "Todo App",
-                "mimeType": "text/html"
+                "uri": "ui://todoservice/app.html", "name": "Todo App", "description": "A simple todo management application",
+                "mimeType": APP_MIME_TYPE
             })).expect("generated app resource must be valid"),
         ]
     }
@@ -121,7 +217,7 @@ impl<T: TodoServiceMcpServer> TodoServiceMcpHandler<T> {
         vec![
             serde_json::from_value(json!({
                 "uriTemplate": "todo://users/{user}/todos/{todo}", "name": "Todo",
-                "description": "Todo resource (users/{user}/todos/{todo})", "mimeType": "application/json"
+                "description": "A single todo item, read with GetTodo.", "mimeType": "application/json"
             })).expect("generated resource template must be valid"),
         ]
     }
@@ -137,7 +233,12 @@ impl<T: TodoServiceMcpServer> ServerHandler for TodoServiceMcpHandler<T> {
                 .enable_resources()
                 .build(),
         )
-        .with_server_info(Implementation::new("TodoService", "0.1.0"))
+        .with_server_info(serde_json::from_value(json!({
+            "name": "TodoService",
+            "version": "0.1.0",
+            "title": "Todo Manager",
+        })).expect("generated server info must be valid"))
+        .with_instructions("Todos belong to users (parent users/{user}). Call todo_service-list_todos_v1 to find a todo's resource name before getting, updating or deleting it.")
     }
 
     async fn list_tools(&self, _: Option<PaginatedRequestParams>, _: RequestContext<RoleServer>) -> std::result::Result<ListToolsResult, McpError> {
@@ -170,9 +271,7 @@ impl<T: TodoServiceMcpServer> ServerHandler for TodoServiceMcpHandler<T> {
                     }
                 }
                 let result = self.inner.create_todo(args).await?;
-                let text = serde_json::to_string(&result)
-                    .map_err(|e| McpError::internal_error(format!("serialize response: {e}"), None))?;
-                Ok(CallToolResult::success(vec![Content::text(text)]))
+                tool_result(result, true)
             }
             "todo_service-delete_todo_v1" => {
                 if let Ok(schema) = ElicitationSchema::from_json_schema(
@@ -197,21 +296,15 @@ impl<T: TodoServiceMcpServer> ServerHandler for TodoServiceMcpHandler<T> {
                     }
                 }
                 let result = self.inner.delete_todo(args).await?;
-                let text = serde_json::to_string(&result)
-                    .map_err(|e| McpError::internal_error(format!("serialize response: {e}"), None))?;
-                Ok(CallToolResult::success(vec![Content::text(text)]))
+                tool_result(result, false)
             }
             "todo_service-get_todo_v1" => {
                 let result = self.inner.get_todo(args).await?;
-                let text = serde_json::to_string(&result)
-                    .map_err(|e| McpError::internal_error(format!("serialize response: {e}"), None))?;
-                Ok(CallToolResult::success(vec![Content::text(text)]))
+                tool_result(result, true)
             }
             "todo_service-list_todos_v1" => {
                 let result = self.inner.list_todos(args).await?;
-                let text = serde_json::to_string(&result)
-                    .map_err(|e| McpError::internal_error(format!("serialize response: {e}"), None))?;
-                Ok(CallToolResult::success(vec![Content::text(text)]))
+                tool_result(result, true)
             }
             "todo_service-update_todo_v1" => {
                 if let Ok(schema) = ElicitationSchema::from_json_schema(
@@ -236,9 +329,7 @@ impl<T: TodoServiceMcpServer> ServerHandler for TodoServiceMcpHandler<T> {
                     }
                 }
                 let result = self.inner.update_todo(args).await?;
-                let text = serde_json::to_string(&result)
-                    .map_err(|e| McpError::internal_error(format!("serialize response: {e}"), None))?;
-                Ok(CallToolResult::success(vec![Content::text(text)]))
+                tool_result(result, true)
             }
             _ => Err(McpError::internal_error(format!("unknown tool: {}", request.name), None)),
         }
@@ -295,11 +386,120 @@ impl<T: TodoServiceMcpServer> ServerHandler for TodoServiceMcpHandler<T> {
     }
 
     async fn read_resource(&self, request: ReadResourceRequestParams, _: RequestContext<RoleServer>) -> std::result::Result<ReadResourceResult, McpError> {
-        let app_uri = app_resource_uri("TodoService");
-        if request.uri == app_uri {
-            let html = default_app_html("Todo App", "1.0.0", "A simple todo management application");
+        if request.uri == "ui://todoservice/app.html" {
+            let html = "<!doctype html>
+<html lang=\"en\">
+<head>
+  <meta charset=\"utf-8\">
+  <title>Todo App</title>
+  <style>body {
+  margin: 0;
+  font: 14px/1.4 system-ui, sans-serif;
+  color: #1f2328;
+}
+main {
+  padding: 12px 16px;
+}
+h1 {
+  margin: 0 0 8px;
+  font-size: 16px;
+}
+ul {
+  margin: 0;
+  padding: 0;
+  list-style: none;
+}
+li {
+  padding: 6px 0;
+  border-bottom: 1px solid #d0d7de;
+}
+li.done .title {
+  text-decoration: line-through;
+  color: #656d76;
+}
+.priority {
+  margin-left: 8px;
+  font-size: 12px;
+  color: #656d76;
+}
+#empty {
+  color: #656d76;
+}
+</style>
+</head>
+<body>
+  <main>
+    <h1 id=\"title\">Todos</h1>
+    <ul id=\"todos\"></ul>
+    <p id=\"empty\">Call a todo tool to see its result here.</p>
+  </main>
+  <script>// Renders TodoService tool results. The host passes each result to the view
+// over the MCP Apps postMessage bridge; structuredContent holds the response
+// message as JSON with proto field names.
+(function () {
+  let nextId = 1;
+
+  function send(message) {
+    window.parent.postMessage(Object.assign({ jsonrpc: \"2.0\" }, message), \"*\");
+  }
+
+  function render(result) {
+    const data = (result && result.structuredContent) || {};
+    const todos = data.todos || (data.name ? [data] : []);
+    const list = document.getElementById(\"todos\");
+    list.replaceChildren();
+    for (const todo of todos) {
+      const item = document.createElement(\"li\");
+      if (todo.completed) {
+        item.className = \"done\";
+      }
+      const title = document.createElement(\"span\");
+      title.className = \"title\";
+      title.textContent = todo.title || todo.name;
+      item.append(title);
+      if (todo.priority && todo.priority !== \"PRIORITY_UNSPECIFIED\") {
+        const priority = document.createElement(\"span\");
+        priority.className = \"priority\";
+        priority.textContent = todo.priority.replace(\"PRIORITY_\", \"\").toLowerCase();
+        item.append(priority);
+      }
+      list.append(item);
+    }
+    document.getElementById(\"empty\").hidden = todos.length > 0;
+  }
+
+  window.addEventListener(\"message\", (event) => {
+    const message = event.data;
+    if (!message || message.jsonrpc !== \"2.0\") {
+      return;
+    }
+    if (message.method === \"ui/notifications/tool-result\") {
+      render(message.params);
+    } else if (message.id === 1 && message.result) {
+      send({ method: \"ui/notifications/initialized\" });
+    }
+  });
+
+  send({
+    id: nextId++,
+    method: \"ui/initialize\",
+    params: {
+      appInfo: { name: \"Todo App\", version: \"1.0.0\" },
+      appCapabilities: {},
+      protocolVersion: \"2026-01-26\",
+    },
+  });
+})();
+</script>
+</body>
+</html>
+".to_string();
+            return Ok(ReadResourceResult::new(vec![app_view_contents(html, request.uri, json!({"prefersBorder": true}))]));
+        }
+        if let Some(args) = resource_args("todo://users/{user}/todos/{todo}", &request.uri, &[], true) {
+            let result = self.inner.get_todo(args).await?;
             return Ok(ReadResourceResult::new(vec![
-                ResourceContents::text(html, request.uri).with_mime_type("text/html")
+                resource_contents(result, request.uri, "application/json", "", "")
             ]));
         }
         Ok(ReadResourceResult::new(vec![ResourceContents::text("{}", request.uri)]))
@@ -0,0 +1,57 @@
+// Renders TodoService tool results. The host passes each result to the view
+// over the MCP Apps postMessage bridge; structuredContent holds the response
+// message as JSON with proto field names.
+(function () {
+  let nextId = 1;
+
+  function send(message) {
+    window.parent.postMessage(Object.assign({ jsonrpc: "2.0" }, message), "*");
+  }
+
+  function render(result) {
+    const data = (result && result.structuredContent) || {};
+    const todos = data.todos || (data.name ? [data] : []);
+    const list = document.getElementById("todos");
+    list.replaceChildren();
+    for (const todo of todos) {
+      const item = document.createElement("li");
+      if (todo.completed) {
+        item.className = "done";
+      }
+      const title = document.createElement("span");
+      title.className = "title";
+      title.textContent = todo.title || todo.name;
+      item.append(title);
+      if (todo.priority && todo.priority !== "PRIORITY_UNSPECIFIED") {
+        const priority = document.createElement("span");
+        priority.className = "priority";
+        priority.textContent = todo.priority.replace("PRIORITY_", "").toLowerCase();
+        item.append(priority);
+      }
+      list.append(item);
+    }
+    document.getElementById("empty").hidden = todos.length > 0;
+  }
+
+  window.addEventListener("message", (event) => {
+    const message = event.data;
+    if (!message || message.jsonrpc !== "2.0") {
+      return;
+    }
+    if (message.method === "ui/notifications/tool-result") {
+      render(message.params);
+    } else if (message.id === 1 && message.result) {
+      send({ method: "ui/notifications/initialized" });
+    }
+  });
+
+  send({
+    id: nextId++,
+    method: "ui/initialize",
+    params: {
+      appInfo: { name: "Todo App", version: "1.0.0" },
+      appCapabilities: {},
+      protocolVersion: "2026-01-26",
+    },
+  });
+})();
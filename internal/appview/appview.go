@@ -0,0 +1,83 @@
+// Package appview bundles MCP App views into single HTML documents, shared
+// by the code generator and the runtime.
+package appview
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/fs"
+	"mime"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Bundle reads entry from fsys and inlines assets into it. The entry refers
+// to each asset by its path relative to the entry's directory, optionally
+// prefixed with "./": scripts (<script src>) and stylesheets (<link href>)
+// are inlined as elements, other files become data URIs in src and href
+// attributes. Paths are slash-separated and relative to fsys.
+func Bundle(fsys fs.FS, entry string, assets []string) (string, error) {
+	b, err := fs.ReadFile(fsys, entry)
+	if err != nil {
+		return "", err
+	}
+	html := string(b)
+	for _, asset := range assets {
+		data, err := fs.ReadFile(fsys, asset)
+		if err != nil {
+			return "", err
+		}
+		ref := relative(path.Dir(entry), asset)
+		out, ok := inline(html, ref, asset, data)
+		if !ok {
+			return "", fmt.Errorf("%s: asset %s is not referenced as %q", entry, asset, ref)
+		}
+		html = out
+	}
+	return html, nil
+}
+
+func inline(html, ref, asset string, data []byte) (string, bool) {
+	q := `["'](?:\./)?` + regexp.QuoteMeta(ref) + `["']`
+	found := false
+	replace := func(re *regexp.Regexp, f func(m []string) string) {
+		html = re.ReplaceAllStringFunc(html, func(s string) string {
+			found = true
+			return f(re.FindStringSubmatch(s))
+		})
+	}
+	switch path.Ext(asset) {
+	case ".js", ".mjs":
+		re := regexp.MustCompile(`<script\b([^>]*?)\s+src=` + q + `([^>]*)>\s*</script>`)
+		body := strings.ReplaceAll(string(data), "</script", `<\/script`)
+		replace(re, func(m []string) string { return "<script" + m[1] + m[2] + ">" + body + "</script>" })
+	case ".css":
+		re := regexp.MustCompile(`<link\b[^>]*?\s+href=` + q + `[^>]*>`)
+		body := strings.ReplaceAll(string(data), "</style", `<\/style`)
+		replace(re, func([]string) string { return "<style>" + body + "</style>" })
+	default:
+		re := regexp.MustCompile(`\b(src|href)=` + q)
+		mimeType := mime.TypeByExtension(path.Ext(asset))
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+		uri := "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+		replace(re, func(m []string) string { return m[1] + `="` + uri + `"` })
+	}
+	return html, found
+}
+
+// relative returns target relative to dir; both are slash-separated paths.
+func relative(dir, target string) string {
+	if dir == "." {
+		return target
+	}
+	d := strings.Split(dir, "/")
+	t := strings.Split(target, "/")
+	i := 0
+	for i < len(d) && i < len(t)-1 && d[i] == t[i] {
+		i++
+	}
+	return strings.Repeat("../", len(d)-i) + strings.Join(t[i:], "/")
+}
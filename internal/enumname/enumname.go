@@ -0,0 +1,33 @@
+// Package enumname derives the friendly names of protobuf enum values shared
+// by the code generator and the runtime.
+package enumname
+
+import "strings"
+
+// Friendly strips the enum type prefix and lowercases the result.
+// E.g. "CONFIRM_ACTION_YES" with enum name "ConfirmAction" → "yes".
+func Friendly(valueName, enumName string) string {
+	// Convert CamelCase enum name to UPPER_SNAKE prefix.
+	// E.g. "ConfirmAction" → "CONFIRM_ACTION_"
+	prefix := UpperSnake(enumName) + "_"
+	if strings.HasPrefix(valueName, prefix) {
+		return strings.ToLower(valueName[len(prefix):])
+	}
+	return strings.ToLower(valueName)
+}
+
+// UpperSnake converts CamelCase to UPPER_SNAKE_CASE.
+func UpperSnake(s string) string {
+	var result strings.Builder
+	for i, r := range s {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			result.WriteByte('_')
+		}
+		if r >= 'a' && r <= 'z' {
+			result.WriteByte(byte(r - 32))
+		} else {
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
@@ -0,0 +1,21 @@
+// Package mimetype maps MCPMimeType values to media type strings for the
+// code generator and the runtime.
+package mimetype
+
+import (
+	"strings"
+
+	"github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppb"
+)
+
+// String returns the media type for an MCPMimeType value, e.g. "image/png"
+// for MCP_MIME_TYPE_IMAGE_PNG, or "" for UNSPECIFIED.
+func String(t mcppb.MCPMimeType) string {
+	if t == mcppb.MCPMimeType_MCP_MIME_TYPE_UNSPECIFIED {
+		return ""
+	}
+	// Enum names encode the media type: MCP_MIME_TYPE_<TYPE>_<SUBTYPE>.
+	name := strings.ToLower(strings.TrimPrefix(t.String(), "MCP_MIME_TYPE_"))
+	typ, sub, _ := strings.Cut(name, "_")
+	return typ + "/" + strings.ReplaceAll(sub, "_", "-")
+}
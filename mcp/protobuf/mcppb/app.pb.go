@@ -22,7 +22,69 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// MCPApp describes application-level metadata for an MCP server.
+// MCPAppVisibility names a caller of an app-linked tool.
+type MCPAppVisibility int32
+
+const (
+	MCPAppVisibility_MCP_APP_VISIBILITY_UNSPECIFIED MCPAppVisibility = 0
+	// The model, through the host.
+	MCPAppVisibility_MCP_APP_VISIBILITY_MODEL MCPAppVisibility = 1
+	// The app view, through the host bridge.
+	MCPAppVisibility_MCP_APP_VISIBILITY_APP MCPAppVisibility = 2
+)
+
+// Enum value maps for MCPAppVisibility.
+var (
+	MCPAppVisibility_name = map[int32]string{
+		0: "MCP_APP_VISIBILITY_UNSPECIFIED",
+		1: "MCP_APP_VISIBILITY_MODEL",
+		2: "MCP_APP_VISIBILITY_APP",
+	}
+	MCPAppVisibility_value = map[string]int32{
+		"MCP_APP_VISIBILITY_UNSPECIFIED": 0,
+		"MCP_APP_VISIBILITY_MODEL":       1,
+		"MCP_APP_VISIBILITY_APP":         2,
+	}
+)
+
+func (x MCPAppVisibility) Enum() *MCPAppVisibility {
+	p := new(MCPAppVisibility)
+	*p = x
+	return p
+}
+
+func (x MCPAppVisibility) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MCPAppVisibility) Descriptor() protoreflect.EnumDescriptor {
+	return file_mcp_protobuf_app_proto_enumTypes[0].Descriptor()
+}
+
+func (MCPAppVisibility) Type() protoreflect.EnumType {
+	return &file_mcp_protobuf_app_proto_enumTypes[0]
+}
+
+func (x MCPAppVisibility) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MCPAppVisibility.Descriptor instead.
+func (MCPAppVisibility) EnumDescriptor() ([]byte, []int) {
+	return file_mcp_protobuf_app_proto_rawDescGZIP(), []int{0}
+}
+
+// MCPApp describes the MCP App of a service: an HTML view that hosts render
+// next to tool results. Without an entry, a placeholder page is served.
+//
+// Example:
+//
+//	app: {
+//	  name: "Todo App"
+//	  entry: "ui/todo.html"
+//	  assets: "ui/todo.js"
+//	  csp: { connect_domains: "https://api.example.com" }
+//	}
 type MCPApp struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The display name of the MCP application.
@@ -30,7 +92,24 @@ type MCPApp struct {
 	// The version of the MCP application (e.g. "1.0.0").
 	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
 	// A human-readable description of the MCP application.
-	Description   string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// Path of the view's HTML entry point, e.g. "ui/todo.html". The generator
+	// embeds the file, resolved against the app_root plugin option. Go servers
+	// can read it from an fs.FS instead with runtime.WithAppFS.
+	Entry string `protobuf:"bytes,4,opt,name=entry,proto3" json:"entry,omitempty"`
+	// Scripts, stylesheets and images the entry references by relative path,
+	// e.g. "ui/todo.js". They are inlined into the served HTML, since hosts
+	// load the view as a single document.
+	Assets []string `protobuf:"bytes,5,rep,name=assets,proto3" json:"assets,omitempty"`
+	// Origins the view loads from or connects to.
+	Csp *MCPAppCSP `protobuf:"bytes,6,opt,name=csp,proto3" json:"csp,omitempty"`
+	// Dedicated sandbox origin requested for the view.
+	Domain string `protobuf:"bytes,7,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Whether the host should draw a border around the view.
+	PrefersBorder bool `protobuf:"varint,8,opt,name=prefers_border,json=prefersBorder,proto3" json:"prefers_border,omitempty"`
+	// When true, only tools that set app.enabled in (mcp.protobuf.tool) are
+	// linked to the view; by default all tools are.
+	OptIn         bool `protobuf:"varint,9,opt,name=opt_in,json=optIn,proto3" json:"opt_in,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -86,17 +165,234 @@ func (x *MCPApp) GetDescription() string {
 	return ""
 }
 
+func (x *MCPApp) GetEntry() string {
+	if x != nil {
+		return x.Entry
+	}
+	return ""
+}
+
+func (x *MCPApp) GetAssets() []string {
+	if x != nil {
+		return x.Assets
+	}
+	return nil
+}
+
+func (x *MCPApp) GetCsp() *MCPAppCSP {
+	if x != nil {
+		return x.Csp
+	}
+	return nil
+}
+
+func (x *MCPApp) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *MCPApp) GetPrefersBorder() bool {
+	if x != nil {
+		return x.PrefersBorder
+	}
+	return false
+}
+
+func (x *MCPApp) GetOptIn() bool {
+	if x != nil {
+		return x.OptIn
+	}
+	return false
+}
+
+// MCPAppCSP lists the origins an app view needs. Hosts add them to the
+// Content Security Policy of the view's sandbox.
+type MCPAppCSP struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Origins for fetch, XHR and WebSocket connections.
+	ConnectDomains []string `protobuf:"bytes,1,rep,name=connect_domains,json=connectDomains,proto3" json:"connect_domains,omitempty"`
+	// Origins for scripts, stylesheets, images and fonts.
+	ResourceDomains []string `protobuf:"bytes,2,rep,name=resource_domains,json=resourceDomains,proto3" json:"resource_domains,omitempty"`
+	// Origins for nested frames.
+	FrameDomains []string `protobuf:"bytes,3,rep,name=frame_domains,json=frameDomains,proto3" json:"frame_domains,omitempty"`
+	// Allowed document base URIs.
+	BaseUriDomains []string `protobuf:"bytes,4,rep,name=base_uri_domains,json=baseUriDomains,proto3" json:"base_uri_domains,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MCPAppCSP) Reset() {
+	*x = MCPAppCSP{}
+	mi := &file_mcp_protobuf_app_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MCPAppCSP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MCPAppCSP) ProtoMessage() {}
+
+func (x *MCPAppCSP) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_protobuf_app_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MCPAppCSP.ProtoReflect.Descriptor instead.
+func (*MCPAppCSP) Descriptor() ([]byte, []int) {
+	return file_mcp_protobuf_app_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MCPAppCSP) GetConnectDomains() []string {
+	if x != nil {
+		return x.ConnectDomains
+	}
+	return nil
+}
+
+func (x *MCPAppCSP) GetResourceDomains() []string {
+	if x != nil {
+		return x.ResourceDomains
+	}
+	return nil
+}
+
+func (x *MCPAppCSP) GetFrameDomains() []string {
+	if x != nil {
+		return x.FrameDomains
+	}
+	return nil
+}
+
+func (x *MCPAppCSP) GetBaseUriDomains() []string {
+	if x != nil {
+		return x.BaseUriDomains
+	}
+	return nil
+}
+
+// MCPToolApp links a tool to an MCP App view.
+type MCPToolApp struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Links (true) or unlinks (false) the service app; unset follows
+	// MCPApp.opt_in.
+	Enabled *bool `protobuf:"varint,1,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	// HTML entry point of a view for this tool only, served as
+	// ui://<service>/<tool>.html. Resolved like MCPApp.entry; the CSP, domain
+	// and border of the service app apply.
+	Entry string `protobuf:"bytes,2,opt,name=entry,proto3" json:"entry,omitempty"`
+	// Files inlined into entry, as for MCPApp.assets.
+	Assets []string `protobuf:"bytes,3,rep,name=assets,proto3" json:"assets,omitempty"`
+	// Who may call the tool; by default both the model and the view.
+	Visibility    []MCPAppVisibility `protobuf:"varint,4,rep,packed,name=visibility,proto3,enum=mcp.protobuf.MCPAppVisibility" json:"visibility,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MCPToolApp) Reset() {
+	*x = MCPToolApp{}
+	mi := &file_mcp_protobuf_app_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MCPToolApp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MCPToolApp) ProtoMessage() {}
+
+func (x *MCPToolApp) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_protobuf_app_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MCPToolApp.ProtoReflect.Descriptor instead.
+func (*MCPToolApp) Descriptor() ([]byte, []int) {
+	return file_mcp_protobuf_app_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MCPToolApp) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *MCPToolApp) GetEntry() string {
+	if x != nil {
+		return x.Entry
+	}
+	return ""
+}
+
+func (x *MCPToolApp) GetAssets() []string {
+	if x != nil {
+		return x.Assets
+	}
+	return nil
+}
+
+func (x *MCPToolApp) GetVisibility() []MCPAppVisibility {
+	if x != nil {
+		return x.Visibility
+	}
+	return nil
+}
+
 var File_mcp_protobuf_app_proto protoreflect.FileDescriptor
 
 const file_mcp_protobuf_app_proto_rawDesc = "" +
 	"\n" +
-	"\x16mcp/protobuf/app.proto\x12\fmcp.protobuf\x1a\x19google/api/resource.proto\"~\n" +
+	"\x16mcp/protobuf/app.proto\x12\fmcp.protobuf\x1a\x19google/api/resource.proto\"\xad\x02\n" +
 	"\x06MCPApp\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
 	"\aversion\x18\x02 \x01(\tR\aversion\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription:$\xeaA!\n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05entry\x18\x04 \x01(\tR\x05entry\x12\x16\n" +
+	"\x06assets\x18\x05 \x03(\tR\x06assets\x12)\n" +
+	"\x03csp\x18\x06 \x01(\v2\x17.mcp.protobuf.MCPAppCSPR\x03csp\x12\x16\n" +
+	"\x06domain\x18\a \x01(\tR\x06domain\x12%\n" +
+	"\x0eprefers_border\x18\b \x01(\bR\rprefersBorder\x12\x15\n" +
+	"\x06opt_in\x18\t \x01(\bR\x05optIn:$\xeaA!\n" +
 	"\x13mcp.protobuf/MCPApp\x12\n" +
-	"apps/{app}B^\n" +
+	"apps/{app}\"\xae\x01\n" +
+	"\tMCPAppCSP\x12'\n" +
+	"\x0fconnect_domains\x18\x01 \x03(\tR\x0econnectDomains\x12)\n" +
+	"\x10resource_domains\x18\x02 \x03(\tR\x0fresourceDomains\x12#\n" +
+	"\rframe_domains\x18\x03 \x03(\tR\fframeDomains\x12(\n" +
+	"\x10base_uri_domains\x18\x04 \x03(\tR\x0ebaseUriDomains\"\xa5\x01\n" +
+	"\n" +
+	"MCPToolApp\x12\x1d\n" +
+	"\aenabled\x18\x01 \x01(\bH\x00R\aenabled\x88\x01\x01\x12\x14\n" +
+	"\x05entry\x18\x02 \x01(\tR\x05entry\x12\x16\n" +
+	"\x06assets\x18\x03 \x03(\tR\x06assets\x12>\n" +
+	"\n" +
+	"visibility\x18\x04 \x03(\x0e2\x1e.mcp.protobuf.MCPAppVisibilityR\n" +
+	"visibilityB\n" +
+	"\n" +
+	"\b_enabled*p\n" +
+	"\x10MCPAppVisibility\x12\"\n" +
+	"\x1eMCP_APP_VISIBILITY_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18MCP_APP_VISIBILITY_MODEL\x10\x01\x12\x1a\n" +
+	"\x16MCP_APP_VISIBILITY_APP\x10\x02B^\n" +
 	"\x10com.mcp.protobufB\bAppProtoP\x01Z>github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppbb\x06proto3"
 
 var (
@@ -111,16 +407,22 @@ func file_mcp_protobuf_app_proto_rawDescGZIP() []byte {
 	return file_mcp_protobuf_app_proto_rawDescData
 }
 
-var file_mcp_protobuf_app_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_mcp_protobuf_app_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_mcp_protobuf_app_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_mcp_protobuf_app_proto_goTypes = []any{
-	(*MCPApp)(nil), // 0: mcp.protobuf.MCPApp
+	(MCPAppVisibility)(0), // 0: mcp.protobuf.MCPAppVisibility
+	(*MCPApp)(nil),        // 1: mcp.protobuf.MCPApp
+	(*MCPAppCSP)(nil),     // 2: mcp.protobuf.MCPAppCSP
+	(*MCPToolApp)(nil),    // 3: mcp.protobuf.MCPToolApp
 }
 var file_mcp_protobuf_app_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: mcp.protobuf.MCPApp.csp:type_name -> mcp.protobuf.MCPAppCSP
+	0, // 1: mcp.protobuf.MCPToolApp.visibility:type_name -> mcp.protobuf.MCPAppVisibility
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_mcp_protobuf_app_proto_init() }
@@ -128,18 +430,20 @@ func file_mcp_protobuf_app_proto_init() {
 	if File_mcp_protobuf_app_proto != nil {
 		return
 	}
+	file_mcp_protobuf_app_proto_msgTypes[2].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_mcp_protobuf_app_proto_rawDesc), len(file_mcp_protobuf_app_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   1,
+			NumEnums:      1,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_mcp_protobuf_app_proto_goTypes,
 		DependencyIndexes: file_mcp_protobuf_app_proto_depIdxs,
+		EnumInfos:         file_mcp_protobuf_app_proto_enumTypes,
 		MessageInfos:      file_mcp_protobuf_app_proto_msgTypes,
 	}.Build()
 	File_mcp_protobuf_app_proto = out.File
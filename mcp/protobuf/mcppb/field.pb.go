@@ -34,7 +34,17 @@ type MCPFieldOptions struct {
 	Deprecated bool `protobuf:"varint,3,opt,name=deprecated,proto3" json:"deprecated,omitempty"`
 	// JSON Schema format override (e.g. "uri", "email", "uuid").
 	// When set, overrides auto-detected format (e.g. from buf.validate).
-	Format        string `protobuf:"bytes,4,opt,name=format,proto3" json:"format,omitempty"`
+	Format string `protobuf:"bytes,4,opt,name=format,proto3" json:"format,omitempty"`
+	// Media type of a bytes field. In tool results, set bytes fields with an
+	// image/* type are returned as image content, audio/* as audio content and
+	// anything else as an embedded blob resource, and are removed from the
+	// JSON text.
+	MimeType MCPMimeType `protobuf:"varint,5,opt,name=mime_type,json=mimeType,proto3,enum=mcp.protobuf.MCPMimeType" json:"mime_type,omitempty"`
+	// Binds the field to a value from the request context instead of the
+	// tool arguments. A bound field is left out of the input schema and set on
+	// the request after the arguments are decoded, so the model cannot choose
+	// it. Only singular scalar fields can be bound.
+	Bind          *MCPFieldBinding `protobuf:"bytes,6,opt,name=bind,proto3" json:"bind,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -97,18 +107,173 @@ func (x *MCPFieldOptions) GetFormat() string {
 	return ""
 }
 
+func (x *MCPFieldOptions) GetMimeType() MCPMimeType {
+	if x != nil {
+		return x.MimeType
+	}
+	return MCPMimeType_MCP_MIME_TYPE_UNSPECIFIED
+}
+
+func (x *MCPFieldOptions) GetBind() *MCPFieldBinding {
+	if x != nil {
+		return x.Bind
+	}
+	return nil
+}
+
+// MCPFieldBinding names the context source of a bound field.
+// Used as: option (mcp.protobuf.field) = { bind: { claim: "sub", template: "users/{value}" } };
+// The tool call fails when the source has no value.
+type MCPFieldBinding struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Source:
+	//
+	//	*MCPFieldBinding_Claim
+	//	*MCPFieldBinding_Header
+	//	*MCPFieldBinding_Extra
+	//	*MCPFieldBinding_Value
+	Source isMCPFieldBinding_Source `protobuf_oneof:"source"`
+	// Template the value is substituted into, e.g. "users/{value}".
+	// Defaults to the value itself.
+	Template      string `protobuf:"bytes,5,opt,name=template,proto3" json:"template,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MCPFieldBinding) Reset() {
+	*x = MCPFieldBinding{}
+	mi := &file_mcp_protobuf_field_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MCPFieldBinding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MCPFieldBinding) ProtoMessage() {}
+
+func (x *MCPFieldBinding) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_protobuf_field_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MCPFieldBinding.ProtoReflect.Descriptor instead.
+func (*MCPFieldBinding) Descriptor() ([]byte, []int) {
+	return file_mcp_protobuf_field_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MCPFieldBinding) GetSource() isMCPFieldBinding_Source {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *MCPFieldBinding) GetClaim() string {
+	if x != nil {
+		if x, ok := x.Source.(*MCPFieldBinding_Claim); ok {
+			return x.Claim
+		}
+	}
+	return ""
+}
+
+func (x *MCPFieldBinding) GetHeader() string {
+	if x != nil {
+		if x, ok := x.Source.(*MCPFieldBinding_Header); ok {
+			return x.Header
+		}
+	}
+	return ""
+}
+
+func (x *MCPFieldBinding) GetExtra() string {
+	if x != nil {
+		if x, ok := x.Source.(*MCPFieldBinding_Extra); ok {
+			return x.Extra
+		}
+	}
+	return ""
+}
+
+func (x *MCPFieldBinding) GetValue() string {
+	if x != nil {
+		if x, ok := x.Source.(*MCPFieldBinding_Value); ok {
+			return x.Value
+		}
+	}
+	return ""
+}
+
+func (x *MCPFieldBinding) GetTemplate() string {
+	if x != nil {
+		return x.Template
+	}
+	return ""
+}
+
+type isMCPFieldBinding_Source interface {
+	isMCPFieldBinding_Source()
+}
+
+type MCPFieldBinding_Claim struct {
+	// Claim of the caller's verified token, e.g. "sub" or "tenant".
+	Claim string `protobuf:"bytes,1,opt,name=claim,proto3,oneof"`
+}
+
+type MCPFieldBinding_Header struct {
+	// gRPC metadata key of an HTTP header captured by HeadersMiddleware,
+	// e.g. "x-tenant-id".
+	Header string `protobuf:"bytes,2,opt,name=header,proto3,oneof"`
+}
+
+type MCPFieldBinding_Extra struct {
+	// Name of an extra property set with WithExtraProperties.
+	Extra string `protobuf:"bytes,3,opt,name=extra,proto3,oneof"`
+}
+
+type MCPFieldBinding_Value struct {
+	// Constant value.
+	Value string `protobuf:"bytes,4,opt,name=value,proto3,oneof"`
+}
+
+func (*MCPFieldBinding_Claim) isMCPFieldBinding_Source() {}
+
+func (*MCPFieldBinding_Header) isMCPFieldBinding_Source() {}
+
+func (*MCPFieldBinding_Extra) isMCPFieldBinding_Source() {}
+
+func (*MCPFieldBinding_Value) isMCPFieldBinding_Source() {}
+
 var File_mcp_protobuf_field_proto protoreflect.FileDescriptor
 
 const file_mcp_protobuf_field_proto_rawDesc = "" +
 	"\n" +
-	"\x18mcp/protobuf/field.proto\x12\fmcp.protobuf\"\x87\x01\n" +
+	"\x18mcp/protobuf/field.proto\x12\fmcp.protobuf\x1a\x1cmcp/protobuf/mime_type.proto\"\xf2\x01\n" +
 	"\x0fMCPFieldOptions\x12 \n" +
 	"\vdescription\x18\x01 \x01(\tR\vdescription\x12\x1a\n" +
 	"\bexamples\x18\x02 \x03(\tR\bexamples\x12\x1e\n" +
 	"\n" +
 	"deprecated\x18\x03 \x01(\bR\n" +
 	"deprecated\x12\x16\n" +
-	"\x06format\x18\x04 \x01(\tR\x06formatB`\n" +
+	"\x06format\x18\x04 \x01(\tR\x06format\x126\n" +
+	"\tmime_type\x18\x05 \x01(\x0e2\x19.mcp.protobuf.MCPMimeTypeR\bmimeType\x121\n" +
+	"\x04bind\x18\x06 \x01(\v2\x1d.mcp.protobuf.MCPFieldBindingR\x04bind\"\x99\x01\n" +
+	"\x0fMCPFieldBinding\x12\x16\n" +
+	"\x05claim\x18\x01 \x01(\tH\x00R\x05claim\x12\x18\n" +
+	"\x06header\x18\x02 \x01(\tH\x00R\x06header\x12\x16\n" +
+	"\x05extra\x18\x03 \x01(\tH\x00R\x05extra\x12\x16\n" +
+	"\x05value\x18\x04 \x01(\tH\x00R\x05value\x12\x1a\n" +
+	"\btemplate\x18\x05 \x01(\tR\btemplateB\b\n" +
+	"\x06sourceB`\n" +
 	"\x10com.mcp.protobufB\n" +
 	"FieldProtoP\x01Z>github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppbb\x06proto3"
 
@@ -124,16 +289,20 @@ func file_mcp_protobuf_field_proto_rawDescGZIP() []byte {
 	return file_mcp_protobuf_field_proto_rawDescData
 }
 
-var file_mcp_protobuf_field_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_mcp_protobuf_field_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_mcp_protobuf_field_proto_goTypes = []any{
 	(*MCPFieldOptions)(nil), // 0: mcp.protobuf.MCPFieldOptions
+	(*MCPFieldBinding)(nil), // 1: mcp.protobuf.MCPFieldBinding
+	(MCPMimeType)(0),        // 2: mcp.protobuf.MCPMimeType
 }
 var file_mcp_protobuf_field_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: mcp.protobuf.MCPFieldOptions.mime_type:type_name -> mcp.protobuf.MCPMimeType
+	1, // 1: mcp.protobuf.MCPFieldOptions.bind:type_name -> mcp.protobuf.MCPFieldBinding
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_mcp_protobuf_field_proto_init() }
@@ -141,13 +310,20 @@ func file_mcp_protobuf_field_proto_init() {
 	if File_mcp_protobuf_field_proto != nil {
 		return
 	}
+	file_mcp_protobuf_mime_type_proto_init()
+	file_mcp_protobuf_field_proto_msgTypes[1].OneofWrappers = []any{
+		(*MCPFieldBinding_Claim)(nil),
+		(*MCPFieldBinding_Header)(nil),
+		(*MCPFieldBinding_Extra)(nil),
+		(*MCPFieldBinding_Value)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_mcp_protobuf_field_proto_rawDesc), len(file_mcp_protobuf_field_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
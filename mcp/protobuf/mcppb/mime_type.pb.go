@@ -45,21 +45,36 @@ const (
 	MCPMimeType_MCP_MIME_TYPE_IMAGE_PNG MCPMimeType = 8
 	// image/jpeg
 	MCPMimeType_MCP_MIME_TYPE_IMAGE_JPEG MCPMimeType = 9
+	// image/gif
+	MCPMimeType_MCP_MIME_TYPE_IMAGE_GIF MCPMimeType = 10
+	// image/webp
+	MCPMimeType_MCP_MIME_TYPE_IMAGE_WEBP MCPMimeType = 11
+	// audio/wav
+	MCPMimeType_MCP_MIME_TYPE_AUDIO_WAV MCPMimeType = 12
+	// audio/mpeg
+	MCPMimeType_MCP_MIME_TYPE_AUDIO_MPEG MCPMimeType = 13
+	// audio/ogg
+	MCPMimeType_MCP_MIME_TYPE_AUDIO_OGG MCPMimeType = 14
 )
 
 // Enum value maps for MCPMimeType.
 var (
 	MCPMimeType_name = map[int32]string{
-		0: "MCP_MIME_TYPE_UNSPECIFIED",
-		1: "MCP_MIME_TYPE_APPLICATION_JSON",
-		2: "MCP_MIME_TYPE_TEXT_PLAIN",
-		3: "MCP_MIME_TYPE_TEXT_HTML",
-		4: "MCP_MIME_TYPE_TEXT_MARKDOWN",
-		5: "MCP_MIME_TYPE_APPLICATION_XML",
-		6: "MCP_MIME_TYPE_APPLICATION_OCTET_STREAM",
-		7: "MCP_MIME_TYPE_APPLICATION_PDF",
-		8: "MCP_MIME_TYPE_IMAGE_PNG",
-		9: "MCP_MIME_TYPE_IMAGE_JPEG",
+		0:  "MCP_MIME_TYPE_UNSPECIFIED",
+		1:  "MCP_MIME_TYPE_APPLICATION_JSON",
+		2:  "MCP_MIME_TYPE_TEXT_PLAIN",
+		3:  "MCP_MIME_TYPE_TEXT_HTML",
+		4:  "MCP_MIME_TYPE_TEXT_MARKDOWN",
+		5:  "MCP_MIME_TYPE_APPLICATION_XML",
+		6:  "MCP_MIME_TYPE_APPLICATION_OCTET_STREAM",
+		7:  "MCP_MIME_TYPE_APPLICATION_PDF",
+		8:  "MCP_MIME_TYPE_IMAGE_PNG",
+		9:  "MCP_MIME_TYPE_IMAGE_JPEG",
+		10: "MCP_MIME_TYPE_IMAGE_GIF",
+		11: "MCP_MIME_TYPE_IMAGE_WEBP",
+		12: "MCP_MIME_TYPE_AUDIO_WAV",
+		13: "MCP_MIME_TYPE_AUDIO_MPEG",
+		14: "MCP_MIME_TYPE_AUDIO_OGG",
 	}
 	MCPMimeType_value = map[string]int32{
 		"MCP_MIME_TYPE_UNSPECIFIED":              0,
@@ -72,6 +87,11 @@ var (
 		"MCP_MIME_TYPE_APPLICATION_PDF":          7,
 		"MCP_MIME_TYPE_IMAGE_PNG":                8,
 		"MCP_MIME_TYPE_IMAGE_JPEG":               9,
+		"MCP_MIME_TYPE_IMAGE_GIF":                10,
+		"MCP_MIME_TYPE_IMAGE_WEBP":               11,
+		"MCP_MIME_TYPE_AUDIO_WAV":                12,
+		"MCP_MIME_TYPE_AUDIO_MPEG":               13,
+		"MCP_MIME_TYPE_AUDIO_OGG":                14,
 	}
 )
 
@@ -106,7 +126,7 @@ var File_mcp_protobuf_mime_type_proto protoreflect.FileDescriptor
 
 const file_mcp_protobuf_mime_type_proto_rawDesc = "" +
 	"\n" +
-	"\x1cmcp/protobuf/mime_type.proto\x12\fmcp.protobuf*\xd9\x02\n" +
+	"\x1cmcp/protobuf/mime_type.proto\x12\fmcp.protobuf*\xec\x03\n" +
 	"\vMCPMimeType\x12\x1d\n" +
 	"\x19MCP_MIME_TYPE_UNSPECIFIED\x10\x00\x12\"\n" +
 	"\x1eMCP_MIME_TYPE_APPLICATION_JSON\x10\x01\x12\x1c\n" +
@@ -117,7 +137,13 @@ const file_mcp_protobuf_mime_type_proto_rawDesc = "" +
 	"&MCP_MIME_TYPE_APPLICATION_OCTET_STREAM\x10\x06\x12!\n" +
 	"\x1dMCP_MIME_TYPE_APPLICATION_PDF\x10\a\x12\x1b\n" +
 	"\x17MCP_MIME_TYPE_IMAGE_PNG\x10\b\x12\x1c\n" +
-	"\x18MCP_MIME_TYPE_IMAGE_JPEG\x10\tBc\n" +
+	"\x18MCP_MIME_TYPE_IMAGE_JPEG\x10\t\x12\x1b\n" +
+	"\x17MCP_MIME_TYPE_IMAGE_GIF\x10\n" +
+	"\x12\x1c\n" +
+	"\x18MCP_MIME_TYPE_IMAGE_WEBP\x10\v\x12\x1b\n" +
+	"\x17MCP_MIME_TYPE_AUDIO_WAV\x10\f\x12\x1c\n" +
+	"\x18MCP_MIME_TYPE_AUDIO_MPEG\x10\r\x12\x1b\n" +
+	"\x17MCP_MIME_TYPE_AUDIO_OGG\x10\x0eBc\n" +
 	"\x10com.mcp.protobufB\rMimeTypeProtoP\x01Z>github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppbb\x06proto3"
 
 var (
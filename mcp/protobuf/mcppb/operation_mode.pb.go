@@ -0,0 +1,137 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: mcp/protobuf/operation_mode.proto
+
+package mcppb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// MCPOperationMode selects how an RPC returning google.longrunning.Operation
+// (with google.longrunning.operation_info) is exposed as an MCP tool.
+type MCPOperationMode int32
+
+const (
+	// Default; treated as MCP_OPERATION_MODE_BLOCKING.
+	MCPOperationMode_MCP_OPERATION_MODE_UNSPECIFIED MCPOperationMode = 0
+	// The tool polls GetOperation, reporting MCP progress, until the operation
+	// is done and returns the unpacked response_type as the result.
+	MCPOperationMode_MCP_OPERATION_MODE_BLOCKING MCPOperationMode = 1
+	// The tool returns the operation immediately. Clients follow it with the
+	// companion get_operation and cancel_operation tools.
+	MCPOperationMode_MCP_OPERATION_MODE_TOOLS MCPOperationMode = 2
+)
+
+// Enum value maps for MCPOperationMode.
+var (
+	MCPOperationMode_name = map[int32]string{
+		0: "MCP_OPERATION_MODE_UNSPECIFIED",
+		1: "MCP_OPERATION_MODE_BLOCKING",
+		2: "MCP_OPERATION_MODE_TOOLS",
+	}
+	MCPOperationMode_value = map[string]int32{
+		"MCP_OPERATION_MODE_UNSPECIFIED": 0,
+		"MCP_OPERATION_MODE_BLOCKING":    1,
+		"MCP_OPERATION_MODE_TOOLS":       2,
+	}
+)
+
+func (x MCPOperationMode) Enum() *MCPOperationMode {
+	p := new(MCPOperationMode)
+	*p = x
+	return p
+}
+
+func (x MCPOperationMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MCPOperationMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_mcp_protobuf_operation_mode_proto_enumTypes[0].Descriptor()
+}
+
+func (MCPOperationMode) Type() protoreflect.EnumType {
+	return &file_mcp_protobuf_operation_mode_proto_enumTypes[0]
+}
+
+func (x MCPOperationMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MCPOperationMode.Descriptor instead.
+func (MCPOperationMode) EnumDescriptor() ([]byte, []int) {
+	return file_mcp_protobuf_operation_mode_proto_rawDescGZIP(), []int{0}
+}
+
+var File_mcp_protobuf_operation_mode_proto protoreflect.FileDescriptor
+
+const file_mcp_protobuf_operation_mode_proto_rawDesc = "" +
+	"\n" +
+	"!mcp/protobuf/operation_mode.proto\x12\fmcp.protobuf*u\n" +
+	"\x10MCPOperationMode\x12\"\n" +
+	"\x1eMCP_OPERATION_MODE_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bMCP_OPERATION_MODE_BLOCKING\x10\x01\x12\x1c\n" +
+	"\x18MCP_OPERATION_MODE_TOOLS\x10\x02Bh\n" +
+	"\x10com.mcp.protobufB\x12OperationModeProtoP\x01Z>github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppbb\x06proto3"
+
+var (
+	file_mcp_protobuf_operation_mode_proto_rawDescOnce sync.Once
+	file_mcp_protobuf_operation_mode_proto_rawDescData []byte
+)
+
+func file_mcp_protobuf_operation_mode_proto_rawDescGZIP() []byte {
+	file_mcp_protobuf_operation_mode_proto_rawDescOnce.Do(func() {
+		file_mcp_protobuf_operation_mode_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_mcp_protobuf_operation_mode_proto_rawDesc), len(file_mcp_protobuf_operation_mode_proto_rawDesc)))
+	})
+	return file_mcp_protobuf_operation_mode_proto_rawDescData
+}
+
+var file_mcp_protobuf_operation_mode_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_mcp_protobuf_operation_mode_proto_goTypes = []any{
+	(MCPOperationMode)(0), // 0: mcp.protobuf.MCPOperationMode
+}
+var file_mcp_protobuf_operation_mode_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_mcp_protobuf_operation_mode_proto_init() }
+func file_mcp_protobuf_operation_mode_proto_init() {
+	if File_mcp_protobuf_operation_mode_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_mcp_protobuf_operation_mode_proto_rawDesc), len(file_mcp_protobuf_operation_mode_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_mcp_protobuf_operation_mode_proto_goTypes,
+		DependencyIndexes: file_mcp_protobuf_operation_mode_proto_depIdxs,
+		EnumInfos:         file_mcp_protobuf_operation_mode_proto_enumTypes,
+	}.Build()
+	File_mcp_protobuf_operation_mode_proto = out.File
+	file_mcp_protobuf_operation_mode_proto_goTypes = nil
+	file_mcp_protobuf_operation_mode_proto_depIdxs = nil
+}
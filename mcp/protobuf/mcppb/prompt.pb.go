@@ -43,7 +43,11 @@ type MCPPrompt struct {
 	// Human-readable description of what this prompt does.
 	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
 	// Fully-qualified proto message name whose fields define the prompt arguments.
-	Schema        string `protobuf:"bytes,3,opt,name=schema,proto3" json:"schema,omitempty"`
+	Schema string `protobuf:"bytes,3,opt,name=schema,proto3" json:"schema,omitempty"`
+	// Human-readable display name; defaults to the prompt name in title case.
+	Title string `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	// Icons clients may display for the prompt.
+	Icons         []*MCPIcon `protobuf:"bytes,5,rep,name=icons,proto3" json:"icons,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -99,6 +103,20 @@ func (x *MCPPrompt) GetSchema() string {
 	return ""
 }
 
+func (x *MCPPrompt) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *MCPPrompt) GetIcons() []*MCPIcon {
+	if x != nil {
+		return x.Icons
+	}
+	return nil
+}
+
 // MCPToolOptions configures an individual RPC method as an MCP tool.
 // Used as: option (mcp.protobuf.tool) = { ... };
 type MCPToolOptions struct {
@@ -111,7 +129,26 @@ type MCPToolOptions struct {
 	// progressToken in params._meta; the server will send progress updates during
 	// execution. Requires a server-streaming RPC whose response has a oneof with
 	// mcp.protobuf.MCPProgress and the result type.
-	Progress      *bool `protobuf:"varint,3,opt,name=progress,proto3,oneof" json:"progress,omitempty"`
+	Progress *bool `protobuf:"varint,3,opt,name=progress,proto3,oneof" json:"progress,omitempty"`
+	// How an RPC returning google.longrunning.Operation is exposed. Only used
+	// when the method declares google.longrunning.operation_info.
+	OperationMode MCPOperationMode `protobuf:"varint,4,opt,name=operation_mode,json=operationMode,proto3,enum=mcp.protobuf.MCPOperationMode" json:"operation_mode,omitempty"`
+	// Expose a plain server-streaming RPC (without the MCPProgress oneof) by
+	// collecting its messages into a JSON array. Streaming RPCs without this
+	// option or the MCPProgress convention are not exposed as tools.
+	StreamCollect *MCPStreamCollect `protobuf:"bytes,5,opt,name=stream_collect,json=streamCollect,proto3" json:"stream_collect,omitempty"`
+	// Fetch AIP-158 list pages automatically. Only used for unary methods with
+	// page_size / page_token / next_page_token fields.
+	Pagination *MCPPagination `protobuf:"bytes,6,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	// How the response is rendered as text. Overrides runtime.WithResultFormatter.
+	ResultFormat MCPResultFormat `protobuf:"varint,7,opt,name=result_format,json=resultFormat,proto3,enum=mcp.protobuf.MCPResultFormat" json:"result_format,omitempty"`
+	// Human-readable display name shown in client UIs instead of the tool
+	// name; defaults to the RPC name split into words ("Create Todo").
+	Title string `protobuf:"bytes,8,opt,name=title,proto3" json:"title,omitempty"`
+	// Icons clients may display for the tool.
+	Icons []*MCPIcon `protobuf:"bytes,9,rep,name=icons,proto3" json:"icons,omitempty"`
+	// MCP App view linked to the tool's results.
+	App           *MCPToolApp `protobuf:"bytes,10,opt,name=app,proto3" json:"app,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -167,19 +204,80 @@ func (x *MCPToolOptions) GetProgress() bool {
 	return false
 }
 
+func (x *MCPToolOptions) GetOperationMode() MCPOperationMode {
+	if x != nil {
+		return x.OperationMode
+	}
+	return MCPOperationMode_MCP_OPERATION_MODE_UNSPECIFIED
+}
+
+func (x *MCPToolOptions) GetStreamCollect() *MCPStreamCollect {
+	if x != nil {
+		return x.StreamCollect
+	}
+	return nil
+}
+
+func (x *MCPToolOptions) GetPagination() *MCPPagination {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+func (x *MCPToolOptions) GetResultFormat() MCPResultFormat {
+	if x != nil {
+		return x.ResultFormat
+	}
+	return MCPResultFormat_MCP_RESULT_FORMAT_UNSPECIFIED
+}
+
+func (x *MCPToolOptions) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *MCPToolOptions) GetIcons() []*MCPIcon {
+	if x != nil {
+		return x.Icons
+	}
+	return nil
+}
+
+func (x *MCPToolOptions) GetApp() *MCPToolApp {
+	if x != nil {
+		return x.App
+	}
+	return nil
+}
+
 var File_mcp_protobuf_prompt_proto protoreflect.FileDescriptor
 
 const file_mcp_protobuf_prompt_proto_rawDesc = "" +
 	"\n" +
-	"\x19mcp/protobuf/prompt.proto\x12\fmcp.protobuf\"Y\n" +
+	"\x19mcp/protobuf/prompt.proto\x12\fmcp.protobuf\x1a\x16mcp/protobuf/app.proto\x1a\x17mcp/protobuf/icon.proto\x1a!mcp/protobuf/operation_mode.proto\x1a\x1dmcp/protobuf/pagination.proto\x1a mcp/protobuf/result_format.proto\x1a\x19mcp/protobuf/stream.proto\"\x9c\x01\n" +
 	"\tMCPPrompt\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x16\n" +
-	"\x06schema\x18\x03 \x01(\tR\x06schema\"t\n" +
+	"\x06schema\x18\x03 \x01(\tR\x06schema\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12+\n" +
+	"\x05icons\x18\x05 \x03(\v2\x15.mcp.protobuf.MCPIconR\x05icons\"\xf2\x03\n" +
 	"\x0eMCPToolOptions\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1f\n" +
-	"\bprogress\x18\x03 \x01(\bH\x00R\bprogress\x88\x01\x01B\v\n" +
+	"\bprogress\x18\x03 \x01(\bH\x00R\bprogress\x88\x01\x01\x12E\n" +
+	"\x0eoperation_mode\x18\x04 \x01(\x0e2\x1e.mcp.protobuf.MCPOperationModeR\roperationMode\x12E\n" +
+	"\x0estream_collect\x18\x05 \x01(\v2\x1e.mcp.protobuf.MCPStreamCollectR\rstreamCollect\x12;\n" +
+	"\n" +
+	"pagination\x18\x06 \x01(\v2\x1b.mcp.protobuf.MCPPaginationR\n" +
+	"pagination\x12B\n" +
+	"\rresult_format\x18\a \x01(\x0e2\x1d.mcp.protobuf.MCPResultFormatR\fresultFormat\x12\x14\n" +
+	"\x05title\x18\b \x01(\tR\x05title\x12+\n" +
+	"\x05icons\x18\t \x03(\v2\x15.mcp.protobuf.MCPIconR\x05icons\x12*\n" +
+	"\x03app\x18\n" +
+	" \x01(\v2\x18.mcp.protobuf.MCPToolAppR\x03appB\v\n" +
 	"\t_progressBa\n" +
 	"\x10com.mcp.protobufB\vPromptProtoP\x01Z>github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppbb\x06proto3"
 
@@ -197,15 +295,28 @@ func file_mcp_protobuf_prompt_proto_rawDescGZIP() []byte {
 
 var file_mcp_protobuf_prompt_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_mcp_protobuf_prompt_proto_goTypes = []any{
-	(*MCPPrompt)(nil),      // 0: mcp.protobuf.MCPPrompt
-	(*MCPToolOptions)(nil), // 1: mcp.protobuf.MCPToolOptions
+	(*MCPPrompt)(nil),        // 0: mcp.protobuf.MCPPrompt
+	(*MCPToolOptions)(nil),   // 1: mcp.protobuf.MCPToolOptions
+	(*MCPIcon)(nil),          // 2: mcp.protobuf.MCPIcon
+	(MCPOperationMode)(0),    // 3: mcp.protobuf.MCPOperationMode
+	(*MCPStreamCollect)(nil), // 4: mcp.protobuf.MCPStreamCollect
+	(*MCPPagination)(nil),    // 5: mcp.protobuf.MCPPagination
+	(MCPResultFormat)(0),     // 6: mcp.protobuf.MCPResultFormat
+	(*MCPToolApp)(nil),       // 7: mcp.protobuf.MCPToolApp
 }
 var file_mcp_protobuf_prompt_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: mcp.protobuf.MCPPrompt.icons:type_name -> mcp.protobuf.MCPIcon
+	3, // 1: mcp.protobuf.MCPToolOptions.operation_mode:type_name -> mcp.protobuf.MCPOperationMode
+	4, // 2: mcp.protobuf.MCPToolOptions.stream_collect:type_name -> mcp.protobuf.MCPStreamCollect
+	5, // 3: mcp.protobuf.MCPToolOptions.pagination:type_name -> mcp.protobuf.MCPPagination
+	6, // 4: mcp.protobuf.MCPToolOptions.result_format:type_name -> mcp.protobuf.MCPResultFormat
+	2, // 5: mcp.protobuf.MCPToolOptions.icons:type_name -> mcp.protobuf.MCPIcon
+	7, // 6: mcp.protobuf.MCPToolOptions.app:type_name -> mcp.protobuf.MCPToolApp
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_mcp_protobuf_prompt_proto_init() }
@@ -213,6 +324,12 @@ func file_mcp_protobuf_prompt_proto_init() {
 	if File_mcp_protobuf_prompt_proto != nil {
 		return
 	}
+	file_mcp_protobuf_app_proto_init()
+	file_mcp_protobuf_icon_proto_init()
+	file_mcp_protobuf_operation_mode_proto_init()
+	file_mcp_protobuf_pagination_proto_init()
+	file_mcp_protobuf_result_format_proto_init()
+	file_mcp_protobuf_stream_proto_init()
 	file_mcp_protobuf_prompt_proto_msgTypes[1].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -22,6 +22,21 @@ const (
 )
 
 // MCPResource describes a data source exposed by an MCP server.
+//
+// Declare resources in the service options. A resource bound to an RPC
+// serves the RPC's response; unbound resources serve an empty JSON object
+// until replaced by a custom handler.
+//
+// Example:
+//
+//	option (mcp.protobuf.service) = {
+//	  resources: {
+//	    pattern: "todo://users/{user}/todos/{todo}"
+//	    name: "todo"
+//	    description: "A single todo item."
+//	    rpc: "GetTodo"
+//	  }
+//	};
 type MCPResource struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Static URI for a fixed resource (mutually exclusive with pattern).
@@ -33,7 +48,16 @@ type MCPResource struct {
 	// Description of what this resource provides.
 	Description string `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
 	// The MIME type of the resource content.
-	MimeType      MCPMimeType `protobuf:"varint,5,opt,name=mime_type,json=mimeType,proto3,enum=mcp.protobuf.MCPMimeType" json:"mime_type,omitempty"`
+	MimeType MCPMimeType `protobuf:"varint,5,opt,name=mime_type,json=mimeType,proto3,enum=mcp.protobuf.MCPMimeType" json:"mime_type,omitempty"`
+	// Human-readable display name for client UIs; defaults to name.
+	Title string `protobuf:"bytes,6,opt,name=title,proto3" json:"title,omitempty"`
+	// Icons clients may display for the resource.
+	Icons []*MCPIcon `protobuf:"bytes,7,rep,name=icons,proto3" json:"icons,omitempty"`
+	// Name of a unary RPC of the same service that supplies the content, e.g.
+	// "GetTodo". Pattern variables set the request fields they name (dotted
+	// for nested fields). When no variable names a field, the request's name
+	// field receives the URI without its scheme, as for AIP resource names.
+	Rpc           string `protobuf:"bytes,8,opt,name=rpc,proto3" json:"rpc,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -103,17 +127,41 @@ func (x *MCPResource) GetMimeType() MCPMimeType {
 	return MCPMimeType_MCP_MIME_TYPE_UNSPECIFIED
 }
 
+func (x *MCPResource) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *MCPResource) GetIcons() []*MCPIcon {
+	if x != nil {
+		return x.Icons
+	}
+	return nil
+}
+
+func (x *MCPResource) GetRpc() string {
+	if x != nil {
+		return x.Rpc
+	}
+	return ""
+}
+
 var File_mcp_protobuf_resource_proto protoreflect.FileDescriptor
 
 const file_mcp_protobuf_resource_proto_rawDesc = "" +
 	"\n" +
-	"\x1bmcp/protobuf/resource.proto\x12\fmcp.protobuf\x1a\x1cmcp/protobuf/mime_type.proto\"\xa7\x01\n" +
+	"\x1bmcp/protobuf/resource.proto\x12\fmcp.protobuf\x1a\x17mcp/protobuf/icon.proto\x1a\x1cmcp/protobuf/mime_type.proto\"\xfc\x01\n" +
 	"\vMCPResource\x12\x10\n" +
 	"\x03uri\x18\x01 \x01(\tR\x03uri\x12\x18\n" +
 	"\apattern\x18\x02 \x01(\tR\apattern\x12\x12\n" +
 	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x04 \x01(\tR\vdescription\x126\n" +
-	"\tmime_type\x18\x05 \x01(\x0e2\x19.mcp.protobuf.MCPMimeTypeR\bmimeTypeBc\n" +
+	"\tmime_type\x18\x05 \x01(\x0e2\x19.mcp.protobuf.MCPMimeTypeR\bmimeType\x12\x14\n" +
+	"\x05title\x18\x06 \x01(\tR\x05title\x12+\n" +
+	"\x05icons\x18\a \x03(\v2\x15.mcp.protobuf.MCPIconR\x05icons\x12\x10\n" +
+	"\x03rpc\x18\b \x01(\tR\x03rpcBc\n" +
 	"\x10com.mcp.protobufB\rResourceProtoP\x01Z>github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppbb\x06proto3"
 
 var (
@@ -132,14 +180,16 @@ var file_mcp_protobuf_resource_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
 var file_mcp_protobuf_resource_proto_goTypes = []any{
 	(*MCPResource)(nil), // 0: mcp.protobuf.MCPResource
 	(MCPMimeType)(0),    // 1: mcp.protobuf.MCPMimeType
+	(*MCPIcon)(nil),     // 2: mcp.protobuf.MCPIcon
 }
 var file_mcp_protobuf_resource_proto_depIdxs = []int32{
 	1, // 0: mcp.protobuf.MCPResource.mime_type:type_name -> mcp.protobuf.MCPMimeType
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2, // 1: mcp.protobuf.MCPResource.icons:type_name -> mcp.protobuf.MCPIcon
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_mcp_protobuf_resource_proto_init() }
@@ -147,6 +197,7 @@ func file_mcp_protobuf_resource_proto_init() {
 	if File_mcp_protobuf_resource_proto != nil {
 		return
 	}
+	file_mcp_protobuf_icon_proto_init()
 	file_mcp_protobuf_mime_type_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -0,0 +1,148 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: mcp/protobuf/result_format.proto
+
+package mcppb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// MCPResultFormat selects how a tool renders its response message as text.
+//
+// Example:
+//
+//	option (mcp.protobuf.tool) = { result_format: MCP_RESULT_FORMAT_MARKDOWN };
+type MCPResultFormat int32
+
+const (
+	// Default; uses the runtime's configured formatter, or JSON.
+	MCPResultFormat_MCP_RESULT_FORMAT_UNSPECIFIED MCPResultFormat = 0
+	// protojson with proto field names and default values.
+	MCPResultFormat_MCP_RESULT_FORMAT_JSON MCPResultFormat = 1
+	// protojson with proto field names, without default values or whitespace.
+	MCPResultFormat_MCP_RESULT_FORMAT_COMPACT_JSON MCPResultFormat = 2
+	// YAML, without default values.
+	MCPResultFormat_MCP_RESULT_FORMAT_YAML MCPResultFormat = 3
+	// Markdown: fields as a list and repeated messages as tables.
+	MCPResultFormat_MCP_RESULT_FORMAT_MARKDOWN MCPResultFormat = 4
+)
+
+// Enum value maps for MCPResultFormat.
+var (
+	MCPResultFormat_name = map[int32]string{
+		0: "MCP_RESULT_FORMAT_UNSPECIFIED",
+		1: "MCP_RESULT_FORMAT_JSON",
+		2: "MCP_RESULT_FORMAT_COMPACT_JSON",
+		3: "MCP_RESULT_FORMAT_YAML",
+		4: "MCP_RESULT_FORMAT_MARKDOWN",
+	}
+	MCPResultFormat_value = map[string]int32{
+		"MCP_RESULT_FORMAT_UNSPECIFIED":  0,
+		"MCP_RESULT_FORMAT_JSON":         1,
+		"MCP_RESULT_FORMAT_COMPACT_JSON": 2,
+		"MCP_RESULT_FORMAT_YAML":         3,
+		"MCP_RESULT_FORMAT_MARKDOWN":     4,
+	}
+)
+
+func (x MCPResultFormat) Enum() *MCPResultFormat {
+	p := new(MCPResultFormat)
+	*p = x
+	return p
+}
+
+func (x MCPResultFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MCPResultFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_mcp_protobuf_result_format_proto_enumTypes[0].Descriptor()
+}
+
+func (MCPResultFormat) Type() protoreflect.EnumType {
+	return &file_mcp_protobuf_result_format_proto_enumTypes[0]
+}
+
+func (x MCPResultFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MCPResultFormat.Descriptor instead.
+func (MCPResultFormat) EnumDescriptor() ([]byte, []int) {
+	return file_mcp_protobuf_result_format_proto_rawDescGZIP(), []int{0}
+}
+
+var File_mcp_protobuf_result_format_proto protoreflect.FileDescriptor
+
+const file_mcp_protobuf_result_format_proto_rawDesc = "" +
+	"\n" +
+	" mcp/protobuf/result_format.proto\x12\fmcp.protobuf*\xb0\x01\n" +
+	"\x0fMCPResultFormat\x12!\n" +
+	"\x1dMCP_RESULT_FORMAT_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16MCP_RESULT_FORMAT_JSON\x10\x01\x12\"\n" +
+	"\x1eMCP_RESULT_FORMAT_COMPACT_JSON\x10\x02\x12\x1a\n" +
+	"\x16MCP_RESULT_FORMAT_YAML\x10\x03\x12\x1e\n" +
+	"\x1aMCP_RESULT_FORMAT_MARKDOWN\x10\x04Bg\n" +
+	"\x10com.mcp.protobufB\x11ResultFormatProtoP\x01Z>github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppbb\x06proto3"
+
+var (
+	file_mcp_protobuf_result_format_proto_rawDescOnce sync.Once
+	file_mcp_protobuf_result_format_proto_rawDescData []byte
+)
+
+func file_mcp_protobuf_result_format_proto_rawDescGZIP() []byte {
+	file_mcp_protobuf_result_format_proto_rawDescOnce.Do(func() {
+		file_mcp_protobuf_result_format_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_mcp_protobuf_result_format_proto_rawDesc), len(file_mcp_protobuf_result_format_proto_rawDesc)))
+	})
+	return file_mcp_protobuf_result_format_proto_rawDescData
+}
+
+var file_mcp_protobuf_result_format_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_mcp_protobuf_result_format_proto_goTypes = []any{
+	(MCPResultFormat)(0), // 0: mcp.protobuf.MCPResultFormat
+}
+var file_mcp_protobuf_result_format_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_mcp_protobuf_result_format_proto_init() }
+func file_mcp_protobuf_result_format_proto_init() {
+	if File_mcp_protobuf_result_format_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_mcp_protobuf_result_format_proto_rawDesc), len(file_mcp_protobuf_result_format_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_mcp_protobuf_result_format_proto_goTypes,
+		DependencyIndexes: file_mcp_protobuf_result_format_proto_depIdxs,
+		EnumInfos:         file_mcp_protobuf_result_format_proto_enumTypes,
+	}.Build()
+	File_mcp_protobuf_result_format_proto = out.File
+	file_mcp_protobuf_result_format_proto_goTypes = nil
+	file_mcp_protobuf_result_format_proto_depIdxs = nil
+}
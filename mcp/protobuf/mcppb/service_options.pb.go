@@ -22,10 +22,39 @@ const (
 )
 
 // MCPServiceOptions configures MCP behaviour for an entire gRPC service.
+//
+// name, title, version, instructions, website_url and icons form the server
+// identity reported in the MCP initialize result. Values set in the runtime
+// server config take precedence.
+//
+// Example:
+//
+//	option (mcp.protobuf.service) = {
+//	  name: "todo"
+//	  title: "Todo Manager"
+//	  version: "1.2.0"
+//	  instructions: "Call list_tasks before update_task to find task names."
+//	};
 type MCPServiceOptions struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// App-level metadata surfaced to MCP clients.
-	App           *MCPApp `protobuf:"bytes,1,opt,name=app,proto3" json:"app,omitempty"`
+	App *MCPApp `protobuf:"bytes,1,opt,name=app,proto3" json:"app,omitempty"`
+	// Server name; defaults to the service name.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Human-readable server name for display.
+	Title string `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	// Server version, e.g. "1.2.0".
+	Version string `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	// Instructions describing how to use the server and its tools. Clients
+	// may add them to the model's system prompt.
+	Instructions string `protobuf:"bytes,5,opt,name=instructions,proto3" json:"instructions,omitempty"`
+	// URL of the server's website or documentation.
+	WebsiteUrl string `protobuf:"bytes,6,opt,name=website_url,json=websiteUrl,proto3" json:"website_url,omitempty"`
+	// Icons clients may display for the server.
+	Icons []*MCPIcon `protobuf:"bytes,7,rep,name=icons,proto3" json:"icons,omitempty"`
+	// Resources exposed by the server, in addition to those derived from
+	// google.api.resource annotations on response messages.
+	Resources     []*MCPResource `protobuf:"bytes,8,rep,name=resources,proto3" json:"resources,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -67,13 +96,70 @@ func (x *MCPServiceOptions) GetApp() *MCPApp {
 	return nil
 }
 
+func (x *MCPServiceOptions) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MCPServiceOptions) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *MCPServiceOptions) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *MCPServiceOptions) GetInstructions() string {
+	if x != nil {
+		return x.Instructions
+	}
+	return ""
+}
+
+func (x *MCPServiceOptions) GetWebsiteUrl() string {
+	if x != nil {
+		return x.WebsiteUrl
+	}
+	return ""
+}
+
+func (x *MCPServiceOptions) GetIcons() []*MCPIcon {
+	if x != nil {
+		return x.Icons
+	}
+	return nil
+}
+
+func (x *MCPServiceOptions) GetResources() []*MCPResource {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
 var File_mcp_protobuf_service_options_proto protoreflect.FileDescriptor
 
 const file_mcp_protobuf_service_options_proto_rawDesc = "" +
 	"\n" +
-	"\"mcp/protobuf/service_options.proto\x12\fmcp.protobuf\x1a\x16mcp/protobuf/app.proto\";\n" +
+	"\"mcp/protobuf/service_options.proto\x12\fmcp.protobuf\x1a\x16mcp/protobuf/app.proto\x1a\x17mcp/protobuf/icon.proto\x1a\x1bmcp/protobuf/resource.proto\"\xaa\x02\n" +
 	"\x11MCPServiceOptions\x12&\n" +
-	"\x03app\x18\x01 \x01(\v2\x14.mcp.protobuf.MCPAppR\x03appBi\n" +
+	"\x03app\x18\x01 \x01(\v2\x14.mcp.protobuf.MCPAppR\x03app\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x12\"\n" +
+	"\finstructions\x18\x05 \x01(\tR\finstructions\x12\x1f\n" +
+	"\vwebsite_url\x18\x06 \x01(\tR\n" +
+	"websiteUrl\x12+\n" +
+	"\x05icons\x18\a \x03(\v2\x15.mcp.protobuf.MCPIconR\x05icons\x127\n" +
+	"\tresources\x18\b \x03(\v2\x19.mcp.protobuf.MCPResourceR\tresourcesBi\n" +
 	"\x10com.mcp.protobufB\x13ServiceOptionsProtoP\x01Z>github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppbb\x06proto3"
 
 var (
@@ -92,14 +178,18 @@ var file_mcp_protobuf_service_options_proto_msgTypes = make([]protoimpl.MessageI
 var file_mcp_protobuf_service_options_proto_goTypes = []any{
 	(*MCPServiceOptions)(nil), // 0: mcp.protobuf.MCPServiceOptions
 	(*MCPApp)(nil),            // 1: mcp.protobuf.MCPApp
+	(*MCPIcon)(nil),           // 2: mcp.protobuf.MCPIcon
+	(*MCPResource)(nil),       // 3: mcp.protobuf.MCPResource
 }
 var file_mcp_protobuf_service_options_proto_depIdxs = []int32{
 	1, // 0: mcp.protobuf.MCPServiceOptions.app:type_name -> mcp.protobuf.MCPApp
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2, // 1: mcp.protobuf.MCPServiceOptions.icons:type_name -> mcp.protobuf.MCPIcon
+	3, // 2: mcp.protobuf.MCPServiceOptions.resources:type_name -> mcp.protobuf.MCPResource
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_mcp_protobuf_service_options_proto_init() }
@@ -108,6 +198,8 @@ func file_mcp_protobuf_service_options_proto_init() {
 		return
 	}
 	file_mcp_protobuf_app_proto_init()
+	file_mcp_protobuf_icon_proto_init()
+	file_mcp_protobuf_resource_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
@@ -0,0 +1,164 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: mcp/protobuf/stream.proto
+
+package mcppb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// MCPStreamCollect exposes a server-streaming RPC that does not follow the
+// MCPProgress convention as a tool. The stream is read until it ends or a
+// budget is exhausted, and the messages are returned as a JSON array. When a
+// budget stops the stream early the result carries a truncation marker.
+//
+// Example:
+//
+//	option (mcp.protobuf.tool) = {
+//	  stream_collect: { max_items: 50, max_duration_ms: 10000 }
+//	};
+type MCPStreamCollect struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maximum number of messages to collect (default 100).
+	MaxItems uint32 `protobuf:"varint,1,opt,name=max_items,json=maxItems,proto3" json:"max_items,omitempty"`
+	// Maximum size in bytes of the collected JSON array (default 1 MiB).
+	MaxBytes uint64 `protobuf:"varint,2,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	// Maximum time in milliseconds to read the stream (default: no limit).
+	MaxDurationMs uint32 `protobuf:"varint,3,opt,name=max_duration_ms,json=maxDurationMs,proto3" json:"max_duration_ms,omitempty"`
+	// When true, each received message is also sent as a progress notification.
+	Progress      bool `protobuf:"varint,4,opt,name=progress,proto3" json:"progress,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MCPStreamCollect) Reset() {
+	*x = MCPStreamCollect{}
+	mi := &file_mcp_protobuf_stream_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MCPStreamCollect) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MCPStreamCollect) ProtoMessage() {}
+
+func (x *MCPStreamCollect) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_protobuf_stream_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MCPStreamCollect.ProtoReflect.Descriptor instead.
+func (*MCPStreamCollect) Descriptor() ([]byte, []int) {
+	return file_mcp_protobuf_stream_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MCPStreamCollect) GetMaxItems() uint32 {
+	if x != nil {
+		return x.MaxItems
+	}
+	return 0
+}
+
+func (x *MCPStreamCollect) GetMaxBytes() uint64 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
+func (x *MCPStreamCollect) GetMaxDurationMs() uint32 {
+	if x != nil {
+		return x.MaxDurationMs
+	}
+	return 0
+}
+
+func (x *MCPStreamCollect) GetProgress() bool {
+	if x != nil {
+		return x.Progress
+	}
+	return false
+}
+
+var File_mcp_protobuf_stream_proto protoreflect.FileDescriptor
+
+const file_mcp_protobuf_stream_proto_rawDesc = "" +
+	"\n" +
+	"\x19mcp/protobuf/stream.proto\x12\fmcp.protobuf\"\x90\x01\n" +
+	"\x10MCPStreamCollect\x12\x1b\n" +
+	"\tmax_items\x18\x01 \x01(\rR\bmaxItems\x12\x1b\n" +
+	"\tmax_bytes\x18\x02 \x01(\x04R\bmaxBytes\x12&\n" +
+	"\x0fmax_duration_ms\x18\x03 \x01(\rR\rmaxDurationMs\x12\x1a\n" +
+	"\bprogress\x18\x04 \x01(\bR\bprogressBa\n" +
+	"\x10com.mcp.protobufB\vStreamProtoP\x01Z>github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppbb\x06proto3"
+
+var (
+	file_mcp_protobuf_stream_proto_rawDescOnce sync.Once
+	file_mcp_protobuf_stream_proto_rawDescData []byte
+)
+
+func file_mcp_protobuf_stream_proto_rawDescGZIP() []byte {
+	file_mcp_protobuf_stream_proto_rawDescOnce.Do(func() {
+		file_mcp_protobuf_stream_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_mcp_protobuf_stream_proto_rawDesc), len(file_mcp_protobuf_stream_proto_rawDesc)))
+	})
+	return file_mcp_protobuf_stream_proto_rawDescData
+}
+
+var file_mcp_protobuf_stream_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_mcp_protobuf_stream_proto_goTypes = []any{
+	(*MCPStreamCollect)(nil), // 0: mcp.protobuf.MCPStreamCollect
+}
+var file_mcp_protobuf_stream_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_mcp_protobuf_stream_proto_init() }
+func file_mcp_protobuf_stream_proto_init() {
+	if File_mcp_protobuf_stream_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_mcp_protobuf_stream_proto_rawDesc), len(file_mcp_protobuf_stream_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_mcp_protobuf_stream_proto_goTypes,
+		DependencyIndexes: file_mcp_protobuf_stream_proto_depIdxs,
+		MessageInfos:      file_mcp_protobuf_stream_proto_msgTypes,
+	}.Build()
+	File_mcp_protobuf_stream_proto = out.File
+	file_mcp_protobuf_stream_proto_goTypes = nil
+	file_mcp_protobuf_stream_proto_depIdxs = nil
+}
@@ -45,25 +45,69 @@ func main() {
 		"",
 		"(Go only) Sub-package suffix for generated files (empty = same package as .pb.go files).",
 	)
+	schemaInline := flags.Bool(
+		"schema_inline",
+		false,
+		"Inline nested messages in tool input schemas instead of using $defs/$ref.",
+	)
+	schemaStrict := flags.Bool(
+		"schema_strict",
+		false,
+		"(Go only) Emit strict tool input schemas for OpenAI-compatible clients (all properties required, maps as key/value arrays).",
+	)
+	schemaFriendlyEnums := flags.Bool(
+		"schema_friendly_enums",
+		false,
+		"(Go only) List enum values in tool input schemas by friendly name (e.g. \"high\" for PRIORITY_HIGH), without UNSPECIFIED.",
+	)
+	schemaInt64 := flags.String(
+		"schema_int64",
+		generator.Int64String,
+		"JSON type of 64-bit integer fields in tool, elicitation and prompt schemas (string, integer).",
+	)
+	schemaOneofVariants := flags.Bool(
+		"schema_oneof_variants",
+		false,
+		"(Go only) Also describe each oneof group as a discriminated oneOf with one variant per member.",
+	)
+	schemaMaxDepth := flags.Int(
+		"schema_max_depth",
+		generator.DefaultSchemaMaxDepth,
+		"Message nesting depth after which inlined schemas are cut off.",
+	)
+
+	appRoot := flags.String(
+		"app_root",
+		".",
+		"Directory that MCP App entry and asset paths are resolved against.",
+	)
 
 	generator.PluginVersion = resolveVersion()
 
 	protogen.Options{
 		ParamFunc: flags.Set,
 	}.Run(func(gen *protogen.Plugin) error {
+		schema := generator.SchemaOptions{Inline: *schemaInline, MaxDepth: *schemaMaxDepth, Strict: *schemaStrict, FriendlyEnums: *schemaFriendlyEnums, Int64: *schemaInt64, OneofVariants: *schemaOneofVariants}
+		if schema.Int64 != generator.Int64String && schema.Int64 != generator.Int64Integer {
+			return fmt.Errorf("schema_int64 must be %q or %q, got %q", generator.Int64String, generator.Int64Integer, schema.Int64)
+		}
 		if *lang == "all" {
 			for _, f := range gen.Files {
 				if !f.Generate {
 					continue
 				}
-				if err := generator.GenerateAll(f, gen, *packageSuffix); err != nil {
+				if err := generator.GenerateAll(f, gen, generator.GenerateOptions{
+					PackageSuffix: *packageSuffix,
+					Schema:        schema,
+					AppRoot:       *appRoot,
+				}); err != nil {
 					return err
 				}
 			}
-			return generator.GenerateCppBatch(gen)
+			return generator.GenerateCppBatch(gen, schema)
 		}
 		if *lang == "cpp" {
-			return generator.GenerateCppBatch(gen)
+			return generator.GenerateCppBatch(gen, schema)
 		}
 		for _, f := range gen.Files {
 			if !f.Generate {
@@ -72,6 +116,8 @@ func main() {
 			if err := generator.GenerateFile(f, gen, generator.GenerateOptions{
 				Lang:          generator.Language(*lang),
 				PackageSuffix: *packageSuffix,
+				Schema:        schema,
+				AppRoot:       *appRoot,
 			}); err != nil {
 				return err
 			}
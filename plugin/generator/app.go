@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/machanirobotics/grpc-mcp-gateway/internal/appview"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// ToolAppInfo links a tool to the MCP App view that renders its results.
+type ToolAppInfo struct {
+	ResourceURI string
+	Visibility  []string // "model", "app"; empty for both
+}
+
+// appViewURI returns the URI of a view of the service svcName, e.g.
+// "ui://todoservice/app.html" for the service app.
+func appViewURI(svcName, view string) string {
+	return "ui://" + strings.ToLower(svcName) + "/" + view + ".html"
+}
+
+// resolveAppViews links the tools of svc in toolMeta to MCP App views and
+// records the views in the returned options. The HTML of views with an
+// entry is read from root with the assets inlined. svcOpt may be nil, and
+// is returned unchanged when no view is declared.
+func resolveAppViews(svc *protogen.Service, svcOpt *MCPServiceOpts, toolMeta map[string]ToolMeta, root string) (*MCPServiceOpts, error) {
+	svcName := string(svc.Desc.Name())
+	var app *MCPAppOpts
+	if svcOpt != nil {
+		app = svcOpt.App
+	}
+	var views []AppViewOpts
+	addView := func(view AppViewOpts) error {
+		if view.Entry != "" {
+			if root == "" {
+				root = "."
+			}
+			html, err := appview.Bundle(os.DirFS(root), view.Entry, view.Assets)
+			if err != nil {
+				return fmt.Errorf("%s: app view %s: %w", svc.Desc.FullName(), view.URI, err)
+			}
+			view.HTML = html
+		}
+		views = append(views, view)
+		return nil
+	}
+
+	appURI := appViewURI(svcName, "app")
+	if app != nil {
+		if err := addView(AppViewOpts{
+			URI:           appURI,
+			Name:          app.Name,
+			Description:   app.Description,
+			Version:       app.Version,
+			Entry:         app.Entry,
+			Assets:        app.Assets,
+			CSP:           app.CSP,
+			Domain:        app.Domain,
+			PrefersBorder: app.PrefersBorder,
+		}); err != nil {
+			return svcOpt, err
+		}
+	}
+
+	for _, meth := range svc.Methods {
+		key := svcName + "_" + meth.GoName
+		meta, ok := toolMeta[key]
+		if !ok {
+			continue
+		}
+		var toolApp *MCPToolAppOpts
+		if opts := ExtractMethodOptions(meth); opts != nil {
+			toolApp = opts.App
+		}
+		linked := app != nil && !app.OptIn
+		if toolApp != nil && toolApp.Enabled != nil {
+			linked = *toolApp.Enabled
+		} else if toolApp != nil && toolApp.Entry != "" {
+			linked = true
+		}
+		if !linked {
+			continue
+		}
+		info := &ToolAppInfo{ResourceURI: appURI}
+		if toolApp != nil {
+			info.Visibility = toolApp.Visibility
+		}
+		switch {
+		case toolApp != nil && toolApp.Entry != "":
+			info.ResourceURI = appViewURI(svcName, meta.Name)
+			view := AppViewOpts{
+				URI:         info.ResourceURI,
+				Name:        meta.Title,
+				Description: fmt.Sprintf("MCP App view of the %s tool.", meta.Name),
+				Entry:       toolApp.Entry,
+				Assets:      toolApp.Assets,
+			}
+			if app != nil {
+				view.Version = app.Version
+				view.CSP, view.Domain, view.PrefersBorder = app.CSP, app.Domain, app.PrefersBorder
+			}
+			if err := addView(view); err != nil {
+				return svcOpt, err
+			}
+		case app == nil:
+			return svcOpt, fmt.Errorf("%s: tool %s enables an MCP App, but the service declares no app and the tool no entry",
+				svc.Desc.FullName(), meta.Name)
+		}
+		meta.App = info
+		toolMeta[key] = meta
+	}
+
+	if len(views) == 0 {
+		return svcOpt, nil
+	}
+	if svcOpt == nil {
+		svcOpt = &MCPServiceOpts{}
+	}
+	svcOpt.AppViews = views
+	return svcOpt, nil
+}
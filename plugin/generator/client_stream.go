@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// streamRequestsProperty and bidiStreamURIPrefix mirror the runtime constants.
+const (
+	streamRequestsProperty = "requests"
+	bidiStreamURIPrefix    = "streams://"
+)
+
+// ClientStreamInfo describes a client-streaming or bidirectional RPC.
+// Client-streaming RPCs become a single tool taking an array of requests;
+// bidirectional RPCs become an open tool and a send tool (SendKey).
+type ClientStreamInfo struct {
+	StreamClientType string // Go type for gRPC client stream (e.g. "RobotService_CommandClient")
+	StreamServerType string // Go type for gRPC server stream (e.g. "RobotService_CommandServer")
+	Bidi             bool   // true when the server also streams
+	SendKey          string // SchemaJSON/ToolMeta key of the bidi send tool
+}
+
+// DetectClientStream returns ClientStreamInfo if the method is client-streaming.
+func DetectClientStream(meth *protogen.Method) *ClientStreamInfo {
+	if !meth.Desc.IsStreamingClient() {
+		return nil
+	}
+	svcName := string(meth.Parent.Desc.Name())
+	info := &ClientStreamInfo{
+		StreamClientType: svcName + "_" + meth.GoName + "Client",
+		StreamServerType: svcName + "_" + meth.GoName + "Server",
+		Bidi:             meth.Desc.IsStreamingServer(),
+	}
+	if info.Bidi {
+		info.SendKey = svcName + "_" + meth.GoName + "Send"
+	}
+	return info
+}
+
+// streamRequestsSchema wraps a request message schema into a tool input
+// schema whose "requests" property is an array of those messages.
+func streamRequestsSchema(itemSchema map[string]any, desc string, required bool) map[string]any {
+	delete(itemSchema, "description")
+	props := map[string]any{
+		streamRequestsProperty: map[string]any{
+			"type":        "array",
+			"description": "Request messages, sent in order on the stream.",
+			"items":       itemSchema,
+		},
+	}
+	req := []string{}
+	if required {
+		req = append(req, streamRequestsProperty)
+	}
+	result := map[string]any{"type": "object", "properties": props, "required": req}
+	if desc != "" {
+		result["description"] = desc
+	}
+	hoistDefs(result, itemSchema)
+	return result
+}
+
+// bidiSendSchema is the input schema of the send tool of a bidirectional RPC.
+func bidiSendSchema(itemSchema map[string]any, openTool string) map[string]any {
+	delete(itemSchema, "description")
+	result := map[string]any{
+		"type":        "object",
+		"description": fmt.Sprintf("Sends request messages on a stream opened by %s.", openTool),
+		"properties": map[string]any{
+			"stream_id": map[string]any{
+				"type":        "string",
+				"description": fmt.Sprintf("Stream ID returned by %s.", openTool),
+			},
+			streamRequestsProperty: map[string]any{
+				"type":        "array",
+				"description": "Request messages, sent in order on the stream.",
+				"items":       itemSchema,
+			},
+			"close_send": map[string]any{
+				"type":        "boolean",
+				"description": "Half-close the stream after sending; the server may keep sending.",
+			},
+		},
+		"required": []string{"stream_id"},
+	}
+	hoistDefs(result, itemSchema)
+	return result
+}
@@ -9,7 +9,6 @@ import (
 
 	"github.com/machanirobotics/grpc-mcp-gateway/plugin/generator/templates"
 	"google.golang.org/protobuf/compiler/protogen"
-	"google.golang.org/protobuf/types/pluginpb"
 )
 
 // CppMethodInfo carries C++-specific identifiers for a single RPC method.
@@ -48,13 +47,14 @@ type CppTplParams struct {
 
 // CppFileGenerator produces the C++ MCP adapter and Rust bridge/handler files.
 type CppFileGenerator struct {
-	f   *protogen.File
-	gen *protogen.Plugin
+	f          *protogen.File
+	gen        *protogen.Plugin
+	schemaOpts SchemaOptions
 }
 
 // NewCppFileGenerator creates a CppFileGenerator for the given protobuf file.
 func NewCppFileGenerator(f *protogen.File, gen *protogen.Plugin) *CppFileGenerator {
-	gen.SupportedFeatures |= uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+	declareSupportedFeatures(gen)
 	return &CppFileGenerator{f: f, gen: gen}
 }
 
@@ -77,6 +77,7 @@ func (g *CppFileGenerator) Generate(emitShared bool) {
 		"rsEscape":           rsStringEscape,
 		"cppEscape":          cppStringEscape,
 		"escapeQuotes":       func(s string) string { return strings.ReplaceAll(s, `"`, `\"`) },
+		"jsonIcons":          jsonIcons,
 	}
 
 	genFile := func(outPath, tplName string) {
@@ -138,12 +139,12 @@ func (g *CppFileGenerator) buildCppParams(dir, stem string) CppTplParams {
 					toolName = methOpts.ToolName
 				}
 				if methOpts.Prompt != nil && methOpts.Prompt.Schema != "" {
-					for _, sf := range ResolveSchemaFields(g.gen, methOpts.Prompt.Schema) {
+					for _, sf := range ResolveSchemaFields(g.gen, methOpts.Prompt.Schema, g.schemaOpts) {
 						methOpts.Prompt.Arguments = append(methOpts.Prompt.Arguments, MCPPromptArgOpts(sf))
 					}
 				}
 				if methOpts.Elicitation != nil && methOpts.Elicitation.Schema != "" {
-					for _, sf := range ResolveSchemaFields(g.gen, methOpts.Elicitation.Schema) {
+					for _, sf := range ResolveSchemaFields(g.gen, methOpts.Elicitation.Schema, g.schemaOpts) {
 						methOpts.Elicitation.Fields = append(methOpts.Elicitation.Fields, MCPElicitFieldOpts(sf))
 					}
 				}
@@ -155,15 +156,18 @@ func (g *CppFileGenerator) buildCppParams(dir, stem string) CppTplParams {
 			}
 
 			// Standard schema (root description = tool description, per MCP inputSchema convention)
-			stdSchema := messageSchema(meth.Input.Desc, false, desc)
+			stdSchema := inputSchema(meth.Desc, g.schemaOpts, desc)
 			stdBytes, err := json.Marshal(stdSchema)
 			if err != nil {
 				panic(fmt.Sprintf("marshal standard schema: %v", err))
 			}
 			schemaJSON[key] = string(stdBytes)
+			toolTitle, toolIcons := toolDisplay(meth, methOpts)
 			toolMeta[key] = ToolMeta{
 				Name:        toolName,
+				Title:       toolTitle,
 				Description: desc,
+				Icons:       toolIcons,
 			}
 
 			methods[meth.GoName] = CppMethodInfo{
@@ -182,12 +186,15 @@ func (g *CppFileGenerator) buildCppParams(dir, stem string) CppTplParams {
 		services[svcName] = methods
 		serviceBasePaths[svcName] = "/" + strings.ToLower(strings.ReplaceAll(string(svc.Desc.FullName()), ".", "/")) + "/mcp"
 		svcOpt := ExtractServiceOptions(svc)
-		apiResources := ExtractGoogleAPIResources(svc)
-		if len(apiResources) > 0 {
+		resources, err := ExtractServiceResources(svc)
+		if err != nil {
+			g.gen.Error(err)
+		}
+		if len(resources) > 0 {
 			if svcOpt == nil {
 				svcOpt = &MCPServiceOpts{}
 			}
-			svcOpt.Resources = apiResources
+			svcOpt.Resources = resources
 		}
 		serviceOpts[svcName] = svcOpt
 	}
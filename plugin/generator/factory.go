@@ -3,8 +3,11 @@ package generator
 import (
 	"fmt"
 	"sort"
+	"strings"
 
+	"github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppb"
 	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // PluginVersion is set by the protoc-gen-mcp binary before generation.
@@ -34,24 +37,81 @@ type GenerateOptions struct {
 	// CppEmitShared, when Lang is Cpp, controls whether to emit shared files
 	// (rust/*, Makefile, main.cc). Nil defaults to true.
 	CppEmitShared *bool
+	// Schema controls how tool input schemas are built.
+	Schema SchemaOptions
+	// AppRoot is the directory MCP App entry and asset paths are resolved
+	// against. Empty means the working directory.
+	AppRoot string
+}
+
+// goOnlyMethodOptions are method options and conventions that only the Go
+// generator implements. The other generators would turn such a method into
+// a tool that behaves differently, so GenerateFile rejects them instead.
+var goOnlyMethodOptions = []struct {
+	name string
+	used func(meth protoreflect.MethodDescriptor) bool
+}{
+	{"google.longrunning.operation_info", isOperationMethod},
+	{"(mcp.protobuf.tool).stream_collect", func(meth protoreflect.MethodDescriptor) bool {
+		return toolOptions(meth).GetStreamCollect() != nil
+	}},
+	{"(mcp.protobuf.tool).pagination", func(meth protoreflect.MethodDescriptor) bool {
+		return toolOptions(meth).GetPagination() != nil
+	}},
+	{"(mcp.protobuf.tool).result_format", func(meth protoreflect.MethodDescriptor) bool {
+		return toolOptions(meth).GetResultFormat() != mcppb.MCPResultFormat_MCP_RESULT_FORMAT_UNSPECIFIED
+	}},
+	{"(mcp.protobuf.field).mime_type", func(meth protoreflect.MethodDescriptor) bool {
+		return mediaField(meth.Output()) != nil
+	}},
 }
 
 // GenerateFile dispatches code generation for a single protobuf file to the
 // appropriate language-specific generator.
 func GenerateFile(f *protogen.File, gen *protogen.Plugin, opts GenerateOptions) error {
+	if params := opts.Schema.goOnlyParams(); opts.Lang != Go && len(params) > 0 {
+		return fmt.Errorf("%s: only supported with lang=go, got lang=%s", strings.Join(params, ", "), opts.Lang)
+	}
+	if opts.Lang != Go {
+		for _, svc := range f.Services {
+			for _, meth := range svc.Methods {
+				for _, o := range goOnlyMethodOptions {
+					if o.used(meth.Desc) {
+						return fmt.Errorf("%s: %s is only supported with lang=go, got lang=%s", meth.Desc.FullName(), o.name, opts.Lang)
+					}
+				}
+				// Only the Go runtime sets bound fields; elsewhere they would
+				// just disappear from the schema.
+				if fd := boundField(meth.Desc.Input()); fd != nil {
+					return fmt.Errorf("%s: (mcp.protobuf.field).bind is only supported with lang=go, got lang=%s", fd.FullName(), opts.Lang)
+				}
+			}
+		}
+	}
 	switch opts.Lang {
 	case Go:
-		NewFileGenerator(f, gen).Generate(opts.PackageSuffix)
+		g := NewFileGenerator(f, gen)
+		g.schemaOpts = opts.Schema
+		g.appRoot = opts.AppRoot
+		g.Generate(opts.PackageSuffix)
 	case Python:
-		NewPythonFileGenerator(f, gen).Generate()
+		g := NewPythonFileGenerator(f, gen)
+		g.schemaOpts = opts.Schema
+		g.appRoot = opts.AppRoot
+		g.Generate()
 	case Rust:
-		NewRustFileGenerator(f, gen).Generate()
+		g := NewRustFileGenerator(f, gen)
+		g.schemaOpts = opts.Schema
+		g.appRoot = opts.AppRoot
+		g.Generate()
 	case Cpp:
 		emitShared := true
 		if opts.CppEmitShared != nil {
 			emitShared = *opts.CppEmitShared
 		}
-		NewCppFileGenerator(f, gen).Generate(emitShared)
+		g := NewCppFileGenerator(f, gen)
+		g.schemaOpts = opts.Schema
+		g.Generate(emitShared)
 	default:
 		return fmt.Errorf("unsupported language: %q (supported: %s)", opts.Lang, supportedList())
 	}
@@ -59,16 +119,15 @@ func GenerateFile(f *protogen.File, gen *protogen.Plugin, opts GenerateOptions)
 }
 
 // GenerateAll runs code generation for every target language on a single
-// protobuf file. Cpp is excluded; use GenerateCppBatch for C++.
-func GenerateAll(f *protogen.File, gen *protogen.Plugin, packageSuffix string) error {
+// protobuf file. Cpp is excluded; use GenerateCppBatch for C++. opts.Lang is
+// ignored.
+func GenerateAll(f *protogen.File, gen *protogen.Plugin, opts GenerateOptions) error {
 	for _, lang := range SupportedLanguages() {
 		if lang == Cpp {
 			continue
 		}
-		if err := GenerateFile(f, gen, GenerateOptions{
-			Lang:          lang,
-			PackageSuffix: packageSuffix,
-		}); err != nil {
+		opts.Lang = lang
+		if err := GenerateFile(f, gen, opts); err != nil {
 			return err
 		}
 	}
@@ -77,7 +136,7 @@ func GenerateAll(f *protogen.File, gen *protogen.Plugin, packageSuffix string) e
 
 // GenerateCppBatch runs C++ generation for all files with services, emitting
 // shared files only for the first file to avoid duplicates.
-func GenerateCppBatch(gen *protogen.Plugin) error {
+func GenerateCppBatch(gen *protogen.Plugin, schema SchemaOptions) error {
 	var files []*protogen.File
 	for _, f := range gen.Files {
 		if !f.Generate || len(f.Services) == 0 {
@@ -93,6 +152,7 @@ func GenerateCppBatch(gen *protogen.Plugin) error {
 		if err := GenerateFile(f, gen, GenerateOptions{
 			Lang:          Cpp,
 			CppEmitShared: &emitShared,
+			Schema:        schema,
 		}); err != nil {
 			return err
 		}
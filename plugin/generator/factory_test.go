@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+const goOnlyTestFile = `
+name: "go_only_test.proto"
+package: "goonlytest"
+syntax: "proto3"
+dependency: "google/longrunning/operations.proto"
+message_type {
+  name: "Request"
+  field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "name" }
+}
+message_type {
+  name: "Snapshot"
+  field { name: "image" number: 1 type: TYPE_BYTES label: LABEL_OPTIONAL json_name: "image"
+    options { [mcp.protobuf.field] { mime_type: MCP_MIME_TYPE_IMAGE_PNG } } }
+}
+service {
+  name: "Service"
+  method { name: "Plain" input_type: ".goonlytest.Request" output_type: ".goonlytest.Request" }
+  method { name: "Run" input_type: ".goonlytest.Request" output_type: ".google.longrunning.Operation"
+    options { [google.longrunning.operation_info] { response_type: "Request" } } }
+  method { name: "Watch" input_type: ".goonlytest.Request" output_type: ".goonlytest.Request" server_streaming: true
+    options { [mcp.protobuf.tool] { stream_collect { max_items: 10 } } } }
+  method { name: "List" input_type: ".goonlytest.Request" output_type: ".goonlytest.Request"
+    options { [mcp.protobuf.tool] { pagination { max_items: 50 } } } }
+  method { name: "Show" input_type: ".goonlytest.Request" output_type: ".goonlytest.Request"
+    options { [mcp.protobuf.tool] { result_format: MCP_RESULT_FORMAT_YAML } } }
+  method { name: "Capture" input_type: ".goonlytest.Request" output_type: ".goonlytest.Snapshot" }
+}
+`
+
+func TestGoOnlyMethodOptions(t *testing.T) {
+	want := map[string]string{
+		"Plain":   "",
+		"Run":     "google.longrunning.operation_info",
+		"Watch":   "(mcp.protobuf.tool).stream_collect",
+		"List":    "(mcp.protobuf.tool).pagination",
+		"Show":    "(mcp.protobuf.tool).result_format",
+		"Capture": "(mcp.protobuf.field).mime_type",
+	}
+	methods := testFile(t, goOnlyTestFile).Services().Get(0).Methods()
+	for i := 0; i < methods.Len(); i++ {
+		meth := methods.Get(i)
+		var used []string
+		for _, o := range goOnlyMethodOptions {
+			if o.used(meth) {
+				used = append(used, o.name)
+			}
+		}
+		if got := strings.Join(used, ","); got != want[string(meth.Name())] {
+			t.Errorf("%s uses %q, want %q", meth.Name(), got, want[string(meth.Name())])
+		}
+	}
+}
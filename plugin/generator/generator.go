@@ -13,7 +13,6 @@ import (
 	"text/template"
 
 	"google.golang.org/protobuf/compiler/protogen"
-	"google.golang.org/protobuf/types/pluginpb"
 )
 
 const generatedFilenameExtension = ".pb.mcp.go"
@@ -21,7 +20,10 @@ const generatedFilenameExtension = ".pb.mcp.go"
 // ToolMeta holds the MCP tool name and description for a single RPC method.
 type ToolMeta struct {
 	Name        string
+	Title       string
 	Description string
+	Icons       []MCPIconOpts
+	App         *ToolAppInfo // nil when the tool is not linked to an MCP App view
 }
 
 // MethodInfo carries the Go type identifiers needed by the code template.
@@ -30,21 +32,27 @@ type MethodInfo struct {
 	ResponseType   string
 	MethodOpts     *MCPMethodOpts
 	StreamProgress *StreamProgressInfo // Non-nil when server-streaming with MCPProgress
+	StreamCollect  *StreamCollectInfo  // Non-nil when server-streaming in collect mode
+	ClientStream   *ClientStreamInfo   // Non-nil when client-streaming or bidirectional
+	Operation      *OperationInfo      // Non-nil when returning google.longrunning.Operation with operation_info
+	Pagination     *PaginationInfo     // Non-nil for AIP-158 List methods
+	ResultFormat   string              // runtime.ResultFormat constant suffix ("Default" unless set)
 }
 
 // TplParams is the top-level data fed into the code template.
 type TplParams struct {
-	Version           string
-	SourcePath        string
-	GoPackage         string
-	ExtraImports      []string          // e.g. `emptypb "google.golang.org/.../emptypb"`
-	SchemaJSON        map[string]string // key: ServiceName_MethodName -> schema JSON
-	ToolMeta          map[string]ToolMeta
-	Services          map[string]map[string]MethodInfo
-	ServiceBasePaths  map[string]string          // key: ServiceName -> default base path e.g. "/todo/v1/TodoService"
-	ServiceOpts       map[string]*MCPServiceOpts  // key: ServiceName
-	HasStreamProgress bool                        // true if any method uses server streaming with progress
-	HasAnyMethods     bool                        // true if any service has any methods (needed for grpc/protojson imports)
+	Version                 string
+	SourcePath              string
+	GoPackage               string
+	ExtraImports            []string          // e.g. `emptypb "google.golang.org/.../emptypb"`
+	SchemaJSON              map[string]string // key: ServiceName_MethodName -> schema JSON
+	ToolMeta                map[string]ToolMeta
+	Services                map[string]map[string]MethodInfo
+	ServiceBasePaths        map[string]string          // key: ServiceName -> default base path e.g. "/todo/v1/TodoService"
+	ServiceOpts             map[string]*MCPServiceOpts // key: ServiceName
+	HasStreamProgress       bool                       // true if any method uses server streaming with progress
+	HasStreamCollectTimeout bool                       // true if any collect-mode method sets max_duration_ms (needs "time")
+	HasAnyMethods           bool                       // true if any service has any methods (needed for the grpc import)
 }
 
 // FileGenerator produces a single *.pb.mcp.go file from a protobuf file.
@@ -53,11 +61,13 @@ type FileGenerator struct {
 	gen           *protogen.Plugin
 	gf            *protogen.GeneratedFile
 	genImportPath protogen.GoImportPath
+	schemaOpts    SchemaOptions
+	appRoot       string
 }
 
 // NewFileGenerator creates a FileGenerator for the given protobuf file.
 func NewFileGenerator(f *protogen.File, gen *protogen.Plugin) *FileGenerator {
-	gen.SupportedFeatures |= uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+	declareSupportedFeatures(gen)
 	return &FileGenerator{f: f, gen: gen}
 }
 
@@ -97,6 +107,7 @@ func (g *FileGenerator) Generate(packageSuffix string) {
 	funcMap := template.FuncMap{
 		"backtick":     func() string { return "`" },
 		"escapeQuotes": func(s string) string { return strings.ReplaceAll(s, `"`, `\"`) },
+		"goIcons":      goIconsLiteral,
 		// safeRawString wraps s in a backtick raw-string literal.
 		// If s itself contains a backtick (e.g. from Markdown code spans in proto
 		// comments), it splits on backticks and emits a concatenation expression so
@@ -166,13 +177,31 @@ func (g *FileGenerator) buildParams() TplParams {
 		methods := make(map[string]MethodInfo)
 
 		for _, meth := range svc.Methods {
-			// Skip client-streaming; support unary and server-streaming (progress) RPCs.
-			if meth.Desc.IsStreamingClient() {
+			methOpts := ExtractMethodOptions(meth)
+			clientStream := DetectClientStream(meth)
+			var streamProgress *StreamProgressInfo
+			var streamCollect *StreamCollectInfo
+			if clientStream == nil {
+				streamProgress = DetectProgressStream(meth, resolveType)
+			}
+			if clientStream == nil && streamProgress == nil {
+				streamCollect = DetectStreamCollect(meth, methOpts, resolveType)
+			}
+			if meth.Desc.IsStreamingServer() && clientStream == nil && streamProgress == nil && streamCollect == nil {
+				continue // Server-streaming needs the MCPProgress convention or stream_collect
+			}
+			operation, err := DetectOperation(g.gen, meth, resolveType)
+			if err != nil {
+				g.gen.Error(err)
 				continue
 			}
-			streamProgress := DetectProgressStream(meth, resolveType)
-			if meth.Desc.IsStreamingServer() && streamProgress == nil {
-				continue // Server-streaming without MCPProgress convention is not supported
+			resultFormat := "Default"
+			if methOpts != nil && methOpts.ResultFormat != "" {
+				resultFormat = methOpts.ResultFormat
+			}
+			var pagination *PaginationInfo
+			if operation == nil {
+				pagination = DetectPagination(meth, methOpts)
 			}
 
 			key := string(svc.Desc.Name()) + "_" + meth.GoName
@@ -180,7 +209,6 @@ func (g *FileGenerator) buildParams() TplParams {
 			toolDesc := CleanComment(string(meth.Comments.Leading))
 
 			// Apply method-level option overrides.
-			methOpts := ExtractMethodOptions(meth)
 			if methOpts != nil {
 				if methOpts.ToolName != "" {
 					toolName = methOpts.ToolName
@@ -190,20 +218,51 @@ func (g *FileGenerator) buildParams() TplParams {
 				}
 				// Resolve prompt schema → populate Arguments from proto message fields.
 				if methOpts.Prompt != nil && methOpts.Prompt.Schema != "" {
-					for _, sf := range ResolveSchemaFields(g.gen, methOpts.Prompt.Schema) {
+					for _, sf := range ResolveSchemaFields(g.gen, methOpts.Prompt.Schema, g.schemaOpts) {
 						methOpts.Prompt.Arguments = append(methOpts.Prompt.Arguments, MCPPromptArgOpts(sf))
 					}
 				}
 				// Resolve elicitation schema → populate Fields from proto message fields.
 				if methOpts.Elicitation != nil && methOpts.Elicitation.Schema != "" {
-					for _, sf := range ResolveSchemaFields(g.gen, methOpts.Elicitation.Schema) {
+					for _, sf := range ResolveSchemaFields(g.gen, methOpts.Elicitation.Schema, g.schemaOpts) {
 						methOpts.Elicitation.Fields = append(methOpts.Elicitation.Fields, MCPElicitFieldOpts(sf))
 					}
 				}
 			}
 
+			toolTitle, toolIcons := toolDisplay(meth, methOpts)
+
 			// Standard schema (root description = tool description, per MCP inputSchema convention)
-			stdSchema := messageSchema(meth.Input.Desc, false, toolDesc)
+			stdSchema := inputSchema(meth.Desc, g.schemaOpts, toolDesc)
+			if clientStream != nil {
+				// Client-streaming: the tool takes the request messages as an array.
+				// Bidi: the open tool's requests are optional initial messages and a
+				// companion send tool writes more on the open stream.
+				if clientStream.Bidi {
+					sendName := BuildToolName(string(meth.Desc.FullName()) + "Send")
+					if methOpts != nil && methOpts.ToolName != "" {
+						sendName = methOpts.ToolName + "_send"
+					}
+					sendBytes, err := json.Marshal(bidiSendSchema(inputSchema(meth.Desc, g.schemaOpts, ""), toolName))
+					if err != nil {
+						panic(fmt.Sprintf("marshal bidi send schema: %v", err))
+					}
+					schemaJSON[clientStream.SendKey] = string(sendBytes)
+					toolMeta[clientStream.SendKey] = ToolMeta{
+						Name:        sendName,
+						Title:       "Send to " + toolTitle,
+						Description: fmt.Sprintf("Sends request messages on a stream opened by %s.", toolName),
+						Icons:       toolIcons,
+					}
+					toolDesc = strings.TrimSpace(toolDesc + fmt.Sprintf(
+						"\n\nOpens a bidirectional stream. Send more messages with %s; received messages are published on the %s{stream_id} resource.",
+						sendName, bidiStreamURIPrefix))
+				}
+				stdSchema = streamRequestsSchema(stdSchema, toolDesc, !clientStream.Bidi)
+			}
+			if pagination != nil && pagination.Opts != nil && pagination.Opts.Cursor {
+				applyCursorSchema(stdSchema)
+			}
 			stdBytes, err := json.Marshal(stdSchema)
 			if err != nil {
 				panic(fmt.Sprintf("marshal standard schema: %v", err))
@@ -212,18 +271,28 @@ func (g *FileGenerator) buildParams() TplParams {
 
 			toolMeta[key] = ToolMeta{
 				Name:        toolName,
+				Title:       toolTitle,
 				Description: toolDesc,
+				Icons:       toolIcons,
 			}
 
 			responseType := resolveType(meth.Output.GoIdent)
 			if streamProgress != nil {
 				responseType = streamProgress.ResultType
 			}
+			if streamCollect != nil {
+				responseType = streamCollect.StreamChunkType
+			}
 			methods[meth.GoName] = MethodInfo{
 				RequestType:    resolveType(meth.Input.GoIdent),
 				ResponseType:   responseType,
 				MethodOpts:     methOpts,
 				StreamProgress: streamProgress,
+				StreamCollect:  streamCollect,
+				ClientStream:   clientStream,
+				Operation:      operation,
+				Pagination:     pagination,
+				ResultFormat:   resultFormat,
 			}
 		}
 
@@ -233,12 +302,18 @@ func (g *FileGenerator) buildParams() TplParams {
 
 		// Extract explicit MCP service options + auto-detect google.api.resource.
 		svcOpt := ExtractServiceOptions(svc)
-		apiResources := ExtractGoogleAPIResources(svc)
-		if len(apiResources) > 0 {
+		resources, err := ExtractServiceResources(svc)
+		if err != nil {
+			g.gen.Error(err)
+		}
+		if len(resources) > 0 {
 			if svcOpt == nil {
 				svcOpt = &MCPServiceOpts{}
 			}
-			svcOpt.Resources = apiResources
+			svcOpt.Resources = resources
+		}
+		if svcOpt, err = resolveAppViews(svc, svcOpt, toolMeta, g.appRoot); err != nil {
+			g.gen.Error(err)
 		}
 		serviceOpts[svcName] = svcOpt
 	}
@@ -266,17 +341,27 @@ func (g *FileGenerator) buildParams() TplParams {
 		}
 	}
 
+	hasStreamCollectTimeout := false
+	for _, methods := range services {
+		for _, info := range methods {
+			if info.StreamCollect != nil && info.StreamCollect.MaxDurationMs > 0 {
+				hasStreamCollectTimeout = true
+			}
+		}
+	}
+
 	return TplParams{
-		Version:           PluginVersion,
-		SourcePath:        g.f.Desc.Path(),
-		GoPackage:         string(g.f.GoPackageName),
-		ExtraImports:      extraImports,
-		SchemaJSON:        schemaJSON,
-		ToolMeta:          toolMeta,
-		Services:          services,
-		ServiceBasePaths:  serviceBasePaths,
-		ServiceOpts:       serviceOpts,
-		HasStreamProgress: hasStreamProgress,
-		HasAnyMethods:     hasAnyMethods,
+		Version:                 PluginVersion,
+		SourcePath:              g.f.Desc.Path(),
+		GoPackage:               string(g.f.GoPackageName),
+		ExtraImports:            extraImports,
+		SchemaJSON:              schemaJSON,
+		ToolMeta:                toolMeta,
+		Services:                services,
+		ServiceBasePaths:        serviceBasePaths,
+		ServiceOpts:             serviceOpts,
+		HasStreamProgress:       hasStreamProgress,
+		HasStreamCollectTimeout: hasStreamCollectTimeout,
+		HasAnyMethods:           hasAnyMethods,
 	}
 }
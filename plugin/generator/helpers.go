@@ -1,11 +1,26 @@
 package generator
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
 )
 
+// declareSupportedFeatures tells protoc that the plugin handles proto3
+// optional fields and Editions files up to Edition 2024.
+func declareSupportedFeatures(gen *protogen.Plugin) {
+	gen.SupportedFeatures |= uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL) |
+		uint64(pluginpb.CodeGeneratorResponse_FEATURE_SUPPORTS_EDITIONS)
+	gen.SupportedEditionsMinimum = descriptorpb.Edition_EDITION_PROTO2
+	gen.SupportedEditionsMaximum = descriptorpb.Edition_EDITION_2024
+}
+
 // toSnakeCase converts a CamelCase string to snake_case.
 func toSnakeCase(s string) string {
 	var result []rune
@@ -82,3 +97,80 @@ func protoPyType(msg *protogen.Message) string {
 	module := protoPyModule(msg)
 	return module + "." + string(msg.Desc.Name())
 }
+
+// goIconsLiteral renders icons as a Go []mcp.Icon composite literal.
+func goIconsLiteral(icons []MCPIconOpts) string {
+	var items []string
+	for _, icon := range icons {
+		fields := []string{"Source: " + strconv.Quote(icon.Src)}
+		if icon.MimeType != "" {
+			fields = append(fields, "MIMEType: "+strconv.Quote(icon.MimeType))
+		}
+		if len(icon.Sizes) > 0 {
+			var sizes []string
+			for _, size := range icon.Sizes {
+				sizes = append(sizes, strconv.Quote(size))
+			}
+			fields = append(fields, "Sizes: []string{"+strings.Join(sizes, ", ")+"}")
+		}
+		if icon.Theme != "" {
+			fields = append(fields, "Theme: "+strconv.Quote(icon.Theme))
+		}
+		items = append(items, "{"+strings.Join(fields, ", ")+"}")
+	}
+	return "[]mcp.Icon{" + strings.Join(items, ", ") + "}"
+}
+
+// pyIconsLiteral renders icons as a Python list of mcp.types.Icon.
+func pyIconsLiteral(icons []MCPIconOpts) string {
+	var items []string
+	for _, icon := range icons {
+		args := []string{"src=" + pyStringLiteral(icon.Src)}
+		if icon.MimeType != "" {
+			args = append(args, "mimeType="+pyStringLiteral(icon.MimeType))
+		}
+		if len(icon.Sizes) > 0 {
+			var sizes []string
+			for _, size := range icon.Sizes {
+				sizes = append(sizes, pyStringLiteral(size))
+			}
+			args = append(args, "sizes=["+strings.Join(sizes, ", ")+"]")
+		}
+		if icon.Theme != "" {
+			args = append(args, "theme="+pyStringLiteral(icon.Theme))
+		}
+		items = append(items, "types.Icon("+strings.Join(args, ", ")+")")
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// pyListLiteral renders ss as a Python list of string literals.
+func pyListLiteral(ss []string) string {
+	items := make([]string, len(ss))
+	for i, s := range ss {
+		items[i] = pyStringLiteral(s)
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// jsonIcons renders icons as a JSON array in MCP wire format, for use inside
+// Rust json! macros. HTML characters are left unescaped because Rust string
+// literals have no \u003c escapes.
+func jsonIcons(icons []MCPIconOpts) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(icons); err != nil {
+		panic(fmt.Sprintf("marshal icons: %v", err))
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// jsonList renders ss as a JSON array of strings.
+func jsonList(ss []string) string {
+	b, err := json.Marshal(ss)
+	if err != nil {
+		panic(fmt.Sprintf("marshal list: %v", err))
+	}
+	return string(b)
+}
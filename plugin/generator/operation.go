@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppb"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const operationFQN = "google.longrunning.Operation"
+
+// Operation modes, mirroring mcp.protobuf.MCPOperationMode.
+const (
+	OperationModeBlocking = "blocking"
+	OperationModeTools    = "tools"
+)
+
+// OperationInfo describes a unary RPC that returns google.longrunning.Operation
+// and declares google.longrunning.operation_info.
+type OperationInfo struct {
+	Mode         string // OperationModeBlocking or OperationModeTools
+	ResponseType string // Go type for operation_info.response_type (resolved)
+}
+
+// DetectOperation returns OperationInfo if the method is unary, returns
+// google.longrunning.Operation and carries operation_info. response_type may
+// be unqualified, in which case it is resolved relative to the method's proto
+// package.
+func DetectOperation(gen *protogen.Plugin, meth *protogen.Method, resolveType func(protogen.GoIdent) string) (*OperationInfo, error) {
+	if !isOperationMethod(meth.Desc) {
+		return nil, nil
+	}
+	opts := meth.Desc.Options()
+	info, ok := proto.GetExtension(opts, longrunningpb.E_OperationInfo).(*longrunningpb.OperationInfo)
+	if !ok || info == nil || info.GetResponseType() == "" {
+		return nil, fmt.Errorf("%s: operation_info.response_type is required", meth.Desc.FullName())
+	}
+
+	pkg := string(meth.Desc.ParentFile().Package())
+	resp := lookupMessage(gen, qualifyTypeName(info.GetResponseType(), pkg))
+	if resp == nil {
+		return nil, fmt.Errorf("%s: operation_info.response_type %q not found", meth.Desc.FullName(), info.GetResponseType())
+	}
+	result := &OperationInfo{
+		Mode:         OperationModeBlocking,
+		ResponseType: resolveType(resp.GoIdent),
+	}
+	if toolExt, ok := proto.GetExtension(opts, mcppb.E_Tool).(*mcppb.MCPToolOptions); ok && toolExt != nil {
+		if toolExt.GetOperationMode() == mcppb.MCPOperationMode_MCP_OPERATION_MODE_TOOLS {
+			result.Mode = OperationModeTools
+		}
+	}
+	return result, nil
+}
+
+// isOperationMethod reports whether meth is a unary RPC that returns
+// google.longrunning.Operation and carries operation_info.
+func isOperationMethod(meth protoreflect.MethodDescriptor) bool {
+	if meth.IsStreamingClient() || meth.IsStreamingServer() {
+		return false
+	}
+	if string(meth.Output().FullName()) != operationFQN {
+		return false
+	}
+	opts := meth.Options()
+	return opts != nil && proto.HasExtension(opts, longrunningpb.E_OperationInfo)
+}
+
+// qualifyTypeName prefixes an unqualified message name with pkg.
+func qualifyTypeName(name, pkg string) string {
+	if strings.Contains(name, ".") || pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
+
+// lookupMessage finds a message by fully-qualified name across all files in the plugin.
+func lookupMessage(gen *protogen.Plugin, fqn string) *protogen.Message {
+	for _, f := range gen.Files {
+		if m := findMessage(f.Messages, fqn); m != nil {
+			return m
+		}
+	}
+	return nil
+}
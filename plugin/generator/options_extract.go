@@ -1,9 +1,15 @@
 package generator
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/machanirobotics/grpc-mcp-gateway/internal/mimetype"
 	"github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppb"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // ExtractServiceOptions reads the mcp.protobuf.service extension from a service descriptor.
@@ -19,14 +25,198 @@ func ExtractServiceOptions(svc *protogen.Service) *MCPServiceOpts {
 	result := &MCPServiceOpts{}
 	if ext.App != nil {
 		result.App = &MCPAppOpts{
-			Name:        ext.App.GetName(),
-			Version:     ext.App.GetVersion(),
-			Description: ext.App.GetDescription(),
+			Name:          ext.App.GetName(),
+			Version:       ext.App.GetVersion(),
+			Description:   ext.App.GetDescription(),
+			Entry:         ext.App.GetEntry(),
+			Assets:        ext.App.GetAssets(),
+			Domain:        ext.App.GetDomain(),
+			PrefersBorder: ext.App.GetPrefersBorder(),
+			OptIn:         ext.App.GetOptIn(),
+		}
+		if csp := ext.App.GetCsp(); csp != nil {
+			result.App.CSP = &MCPAppCSPOpts{
+				ConnectDomains:  csp.GetConnectDomains(),
+				ResourceDomains: csp.GetResourceDomains(),
+				FrameDomains:    csp.GetFrameDomains(),
+				BaseURIDomains:  csp.GetBaseUriDomains(),
+			}
+		}
+	}
+	if ext.GetName() != "" || ext.GetTitle() != "" || ext.GetVersion() != "" || ext.GetInstructions() != "" ||
+		ext.GetWebsiteUrl() != "" || len(ext.GetIcons()) > 0 {
+		result.Server = &MCPServerOpts{
+			Name:         ext.GetName(),
+			Title:        ext.GetTitle(),
+			Version:      ext.GetVersion(),
+			Instructions: ext.GetInstructions(),
+			WebsiteURL:   ext.GetWebsiteUrl(),
+			Icons:        extractIcons(ext.GetIcons()),
+		}
+		if result.Server.Name == "" {
+			result.Server.Name = string(svc.Desc.Name())
 		}
 	}
 	return result
 }
 
+// iconThemes maps MCPIconTheme values to MCP icon theme strings.
+var iconThemes = map[mcppb.MCPIconTheme]string{
+	mcppb.MCPIconTheme_MCP_ICON_THEME_LIGHT: "light",
+	mcppb.MCPIconTheme_MCP_ICON_THEME_DARK:  "dark",
+}
+
+// extractIcons converts MCPIcon messages for templates, skipping icons
+// without a src.
+func extractIcons(icons []*mcppb.MCPIcon) []MCPIconOpts {
+	var out []MCPIconOpts
+	for _, icon := range icons {
+		if icon.GetSrc() == "" {
+			continue
+		}
+		out = append(out, MCPIconOpts{
+			Src:      icon.GetSrc(),
+			MimeType: icon.GetMimeType(),
+			Sizes:    icon.GetSizes(),
+			Theme:    iconThemes[icon.GetTheme()],
+		})
+	}
+	return out
+}
+
+// ExtractServiceResources returns the resources declared in the
+// mcp.protobuf.service options of svc, followed by those derived from
+// google.api.resource annotations whose URI template is not declared.
+func ExtractServiceResources(svc *protogen.Service) ([]MCPResourceOpts, error) {
+	var resources []MCPResourceOpts
+	declared := make(map[string]bool)
+	if opts := svc.Desc.Options(); opts != nil {
+		ext, _ := proto.GetExtension(opts, mcppb.E_Service).(*mcppb.MCPServiceOptions)
+		for _, res := range ext.GetResources() {
+			r, err := extractResource(svc, res)
+			if err != nil {
+				return nil, err
+			}
+			declared[r.URITemplate] = true
+			resources = append(resources, r)
+		}
+	}
+	for _, r := range ExtractGoogleAPIResources(svc) {
+		if !declared[r.URITemplate] {
+			resources = append(resources, r)
+		}
+	}
+	return resources, nil
+}
+
+// uriTemplateVar matches a URI template variable; the optional "+" marks
+// reserved expansion, which may span path segments.
+var uriTemplateVar = regexp.MustCompile(`\{\+?([^}]+)\}`)
+
+func extractResource(svc *protogen.Service, res *mcppb.MCPResource) (MCPResourceOpts, error) {
+	r := MCPResourceOpts{
+		URI:         res.GetUri(),
+		URITemplate: res.GetPattern(),
+		Name:        res.GetName(),
+		Title:       res.GetTitle(),
+		Description: res.GetDescription(),
+		MimeType:    mimetype.String(res.GetMimeType()),
+		Icons:       extractIcons(res.GetIcons()),
+	}
+	if r.Name == "" {
+		r.Name = r.URI
+		if r.Name == "" {
+			r.Name = r.URITemplate
+		}
+	}
+	if (r.URI == "") == (r.URITemplate == "") {
+		return r, fmt.Errorf("%s: resource %q must set exactly one of uri and pattern", svc.Desc.FullName(), r.Name)
+	}
+	if r.MimeType == "" {
+		r.MimeType = "application/json"
+	}
+	if res.GetRpc() == "" {
+		return r, nil
+	}
+
+	var meth *protogen.Method
+	for _, m := range svc.Methods {
+		if string(m.Desc.Name()) == res.GetRpc() {
+			meth = m
+		}
+	}
+	if meth == nil || meth.Desc.IsStreamingClient() || meth.Desc.IsStreamingServer() {
+		return r, fmt.Errorf("%s: resource %q: rpc %q is not a unary method of the service", svc.Desc.FullName(), r.Name, res.GetRpc())
+	}
+	r.RPC = meth.GoName
+	out := meth.Output.Desc
+	if fields := out.Fields(); out.FullName() == "google.api.HttpBody" {
+		r.Content = "http_body"
+	} else if fields.Len() == 1 && !fields.Get(0).IsList() && !fields.Get(0).IsMap() {
+		switch fields.Get(0).Kind() {
+		case protoreflect.StringKind:
+			r.Content, r.ContentField = "string", string(fields.Get(0).Name())
+		case protoreflect.BytesKind:
+			r.Content, r.ContentField = "bytes", string(fields.Get(0).Name())
+		}
+	}
+
+	var unbound []string
+	for _, m := range uriTemplateVar.FindAllStringSubmatch(r.URITemplate, -1) {
+		if field, ok := resolveFieldPath(meth.Input.Desc, m[1]); ok {
+			r.Bindings = append(r.Bindings, MCPResourceBindingOpts{Var: m[1], Field: field})
+		} else {
+			unbound = append(unbound, m[1])
+		}
+	}
+	if len(unbound) == 0 {
+		return r, nil
+	}
+	name := meth.Input.Desc.Fields().ByName("name")
+	if len(r.Bindings) > 0 || name == nil || name.Kind() != protoreflect.StringKind || name.IsList() {
+		return r, fmt.Errorf("%s: resource %q: pattern variables {%s} do not name fields of %s",
+			svc.Desc.FullName(), r.Name, strings.Join(unbound, "}, {"), meth.Input.Desc.FullName())
+	}
+	r.NameBinding = true
+	return r, nil
+}
+
+// resolveFieldPath resolves a dotted path of proto or JSON field names in md
+// to its proto field path.
+func resolveFieldPath(md protoreflect.MessageDescriptor, path string) (string, bool) {
+	var names []string
+	for _, part := range strings.Split(path, ".") {
+		if md == nil {
+			return "", false
+		}
+		fd := md.Fields().ByName(protoreflect.Name(part))
+		if fd == nil {
+			fd = md.Fields().ByJSONName(part)
+		}
+		if fd == nil || fd.IsList() || fd.IsMap() {
+			return "", false
+		}
+		names = append(names, string(fd.Name()))
+		md = fd.Message()
+	}
+	return strings.Join(names, "."), md == nil
+}
+
+// resultFormatNames maps MCPResultFormat values to runtime.ResultFormat
+// constant suffixes.
+var resultFormatNames = map[mcppb.MCPResultFormat]string{
+	mcppb.MCPResultFormat_MCP_RESULT_FORMAT_JSON:         "JSON",
+	mcppb.MCPResultFormat_MCP_RESULT_FORMAT_COMPACT_JSON: "CompactJSON",
+	mcppb.MCPResultFormat_MCP_RESULT_FORMAT_YAML:         "YAML",
+	mcppb.MCPResultFormat_MCP_RESULT_FORMAT_MARKDOWN:     "Markdown",
+}
+
+// toolOptions returns the (mcp.protobuf.tool) options of meth, or nil.
+func toolOptions(meth protoreflect.MethodDescriptor) *mcppb.MCPToolOptions {
+	opts, _ := proto.GetExtension(meth.Options(), mcppb.E_Tool).(*mcppb.MCPToolOptions)
+	return opts
+}
+
 // ExtractMethodOptions reads mcp.protobuf.tool, mcp.protobuf.prompt, and mcp.protobuf.elicitation
 // extensions from a method descriptor and merges them into a single MCPMethodOpts.
 func ExtractMethodOptions(meth *protogen.Method) *MCPMethodOpts {
@@ -38,11 +228,42 @@ func ExtractMethodOptions(meth *protogen.Method) *MCPMethodOpts {
 	result := &MCPMethodOpts{}
 	hasAnything := false
 
-	// mcp.protobuf.tool — name/description overrides
+	// mcp.protobuf.tool — name/description overrides, stream collection and pagination
 	toolExt, ok := proto.GetExtension(opts, mcppb.E_Tool).(*mcppb.MCPToolOptions)
 	if ok && toolExt != nil {
 		result.ToolName = toolExt.GetName()
 		result.ToolDescription = toolExt.GetDescription()
+		result.ToolTitle = toolExt.GetTitle()
+		result.ToolIcons = extractIcons(toolExt.GetIcons())
+		if sc := toolExt.GetStreamCollect(); sc != nil {
+			result.StreamCollect = &MCPStreamCollectOpts{
+				MaxItems:      sc.GetMaxItems(),
+				MaxBytes:      sc.GetMaxBytes(),
+				MaxDurationMs: sc.GetMaxDurationMs(),
+				Progress:      sc.GetProgress(),
+			}
+		}
+		result.ResultFormat = resultFormatNames[toolExt.GetResultFormat()]
+		if pg := toolExt.GetPagination(); pg != nil {
+			result.Pagination = &MCPPaginationOpts{
+				Enabled:  pg.GetEnabled() || pg.GetCursor(),
+				MaxItems: pg.GetMaxItems(),
+				MaxBytes: pg.GetMaxBytes(),
+				Cursor:   pg.GetCursor(),
+			}
+		}
+		if app := toolExt.GetApp(); app != nil {
+			result.App = &MCPToolAppOpts{
+				Enabled: app.Enabled,
+				Entry:   app.GetEntry(),
+				Assets:  app.GetAssets(),
+			}
+			for _, v := range app.GetVisibility() {
+				if name, ok := appVisibilityNames[v]; ok {
+					result.App.Visibility = append(result.App.Visibility, name)
+				}
+			}
+		}
 		hasAnything = true
 	}
 
@@ -53,6 +274,11 @@ func ExtractMethodOptions(meth *protogen.Method) *MCPMethodOpts {
 			Name:        promptExt.GetName(),
 			Description: promptExt.GetDescription(),
 			Schema:      promptExt.GetSchema(),
+			Title:       promptExt.GetTitle(),
+			Icons:       extractIcons(promptExt.GetIcons()),
+		}
+		if result.Prompt.Title == "" {
+			result.Prompt.Title = HumanizeName(result.Prompt.Name)
 		}
 		hasAnything = true
 	}
@@ -72,3 +298,23 @@ func ExtractMethodOptions(meth *protogen.Method) *MCPMethodOpts {
 	}
 	return result
 }
+
+// appVisibilityNames maps MCPAppVisibility values to MCP Apps visibility
+// strings.
+var appVisibilityNames = map[mcppb.MCPAppVisibility]string{
+	mcppb.MCPAppVisibility_MCP_APP_VISIBILITY_MODEL: "model",
+	mcppb.MCPAppVisibility_MCP_APP_VISIBILITY_APP:   "app",
+}
+
+// toolDisplay returns the title and icons of the tool for meth: the
+// mcp.protobuf.tool title, or the RPC name split into words.
+func toolDisplay(meth *protogen.Method, opts *MCPMethodOpts) (string, []MCPIconOpts) {
+	if opts == nil {
+		return HumanizeName(meth.GoName), nil
+	}
+	title := opts.ToolTitle
+	if title == "" {
+		title = HumanizeName(meth.GoName)
+	}
+	return title, opts.ToolIcons
+}
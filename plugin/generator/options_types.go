@@ -3,30 +3,111 @@ package generator
 // MCPServiceOpts is the language-neutral view of MCPServiceOptions for templates.
 type MCPServiceOpts struct {
 	App       *MCPAppOpts
+	Server    *MCPServerOpts // nil when no server identity field is set
 	Resources []MCPResourceOpts
+	AppViews  []AppViewOpts // the service app view first, then per-tool views
+}
+
+// MCPServerOpts mirrors the server identity fields of MCPServiceOptions.
+type MCPServerOpts struct {
+	Name         string // service name when unset
+	Title        string
+	Version      string
+	Instructions string
+	WebsiteURL   string
+	Icons        []MCPIconOpts
+}
+
+// MCPIconOpts mirrors MCPIcon for templates. The JSON tags match the MCP
+// Icon wire format.
+type MCPIconOpts struct {
+	Src      string   `json:"src"`
+	MimeType string   `json:"mimeType,omitempty"`
+	Sizes    []string `json:"sizes,omitempty"`
+	Theme    string   `json:"theme,omitempty"` // "light", "dark" or ""
 }
 
 // MCPMethodOpts is the language-neutral view of per-RPC MCP options for templates.
 type MCPMethodOpts struct {
 	ToolName        string
 	ToolDescription string
+	ToolTitle       string
+	ToolIcons       []MCPIconOpts
 	Prompt          *MCPPromptOpts
 	Elicitation     *MCPElicitationOpts
+	StreamCollect   *MCPStreamCollectOpts
+	Pagination      *MCPPaginationOpts
+	ResultFormat    string // runtime.ResultFormat constant suffix, e.g. "Markdown"; "" for default
+	App             *MCPToolAppOpts
+}
+
+// MCPStreamCollectOpts mirrors MCPStreamCollect for templates.
+type MCPStreamCollectOpts struct {
+	MaxItems      uint32
+	MaxBytes      uint64
+	MaxDurationMs uint32
+	Progress      bool
+}
+
+// MCPPaginationOpts mirrors MCPPagination for templates.
+type MCPPaginationOpts struct {
+	Enabled  bool
+	MaxItems uint32
+	MaxBytes uint64
+	Cursor   bool
 }
 
 // MCPAppOpts mirrors MCPApp for templates.
 type MCPAppOpts struct {
-	Name        string
-	Version     string
-	Description string
+	Name          string
+	Version       string
+	Description   string
+	Entry         string
+	Assets        []string
+	CSP           *MCPAppCSPOpts
+	Domain        string
+	PrefersBorder bool
+	OptIn         bool
+}
+
+// MCPAppCSPOpts mirrors MCPAppCSP for templates.
+type MCPAppCSPOpts struct {
+	ConnectDomains  []string
+	ResourceDomains []string
+	FrameDomains    []string
+	BaseURIDomains  []string
+}
+
+// MCPToolAppOpts mirrors MCPToolApp for templates.
+type MCPToolAppOpts struct {
+	Enabled    *bool // nil follows MCPAppOpts.OptIn
+	Entry      string
+	Assets     []string
+	Visibility []string // "model", "app"
+}
+
+// AppViewOpts is an MCP App view served as a resource.
+type AppViewOpts struct {
+	URI           string
+	Name          string
+	Description   string
+	Version       string
+	Entry         string // empty for the placeholder page
+	Assets        []string
+	HTML          string // entry with its assets inlined
+	CSP           *MCPAppCSPOpts
+	Domain        string
+	PrefersBorder bool
 }
 
 // MCPPromptOpts mirrors MCPPrompt for templates.
 // Arguments are derived from the proto message referenced by Schema.
 type MCPPromptOpts struct {
 	Name        string
+	Title       string // prompt name in title case when unset
 	Description string
 	Schema      string
+	Icons       []MCPIconOpts
 	Arguments   []MCPPromptArgOpts
 }
 
@@ -45,8 +126,24 @@ type MCPResourceOpts struct {
 	URI         string
 	URITemplate string
 	Name        string
+	Title       string
 	Description string
 	MimeType    string
+	Icons       []MCPIconOpts
+	RPC         string                   // Go name of the RPC supplying the content, or ""
+	Bindings    []MCPResourceBindingOpts // URI template variables set on the RPC request
+	NameBinding bool                     // URI without its scheme is set on the request's name field
+	// Content is "http_body" for a google.api.HttpBody response, or "string"
+	// or "bytes" when the response's only field, ContentField, supplies the
+	// content; empty when the response is rendered as JSON.
+	Content      string
+	ContentField string
+}
+
+// MCPResourceBindingOpts maps a URI template variable to a request field.
+type MCPResourceBindingOpts struct {
+	Var   string // template variable, e.g. "todo"
+	Field string // dotted proto field path, e.g. "todo_id"
 }
 
 // MCPElicitationOpts mirrors MCPElicitation for templates.
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// paginationCursorProperty mirrors runtime.PaginationCursorProperty.
+const paginationCursorProperty = "cursor"
+
+// PaginationInfo describes an AIP-158 List method whose tool can fetch pages
+// automatically (mcp.protobuf.MCPPagination or runtime.WithAutoPagination).
+type PaginationInfo struct {
+	ItemsField string             // proto name of the repeated field in the response
+	Opts       *MCPPaginationOpts // nil when the method sets no pagination option
+}
+
+// DetectPagination returns PaginationInfo if the method is a unary AIP-158
+// List method: the request has page_size and page_token, and the response has
+// next_page_token and exactly one repeated (non-map) field.
+func DetectPagination(meth *protogen.Method, opts *MCPMethodOpts) *PaginationInfo {
+	if meth.Desc.IsStreamingClient() || meth.Desc.IsStreamingServer() {
+		return nil
+	}
+	in, out := meth.Input.Desc.Fields(), meth.Output.Desc.Fields()
+	if !hasScalarField(in, "page_size", protoreflect.Int32Kind) ||
+		!hasScalarField(in, "page_token", protoreflect.StringKind) ||
+		!hasScalarField(out, "next_page_token", protoreflect.StringKind) {
+		return nil
+	}
+	items := ""
+	for i := 0; i < out.Len(); i++ {
+		fd := out.Get(i)
+		if fd.IsList() {
+			if items != "" {
+				return nil // ambiguous: more than one repeated field
+			}
+			items = string(fd.Name())
+		}
+	}
+	if items == "" {
+		return nil
+	}
+	info := &PaginationInfo{ItemsField: items}
+	if opts != nil {
+		info.Opts = opts.Pagination
+	}
+	return info
+}
+
+func hasScalarField(fields protoreflect.FieldDescriptors, name string, kind protoreflect.Kind) bool {
+	fd := fields.ByName(protoreflect.Name(name))
+	return fd != nil && fd.Kind() == kind && fd.Cardinality() != protoreflect.Repeated
+}
+
+// applyCursorSchema replaces the page_token property of a List request schema
+// with an MCP-style cursor argument.
+func applyCursorSchema(schema map[string]any) {
+	props, ok := schema["properties"].(map[string]any)
+	if !ok {
+		return
+	}
+	delete(props, "page_token")
+	props[paginationCursorProperty] = map[string]any{
+		"type":        "string",
+		"description": "Opaque cursor from the nextCursor of a previous result. Omit to start from the beginning.",
+	}
+	if req, ok := schema["required"].([]string); ok {
+		out := req[:0]
+		for _, r := range req {
+			if r != "page_token" {
+				out = append(out, r)
+			}
+		}
+		schema["required"] = out
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppb"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
-	"google.golang.org/protobuf/types/pluginpb"
 )
 
 // PyMethodInfo carries Python-specific type identifiers for a single RPC method.
@@ -47,13 +46,15 @@ type PyTplParams struct {
 
 // PythonFileGenerator produces a single *_pb2_mcp.py file from a protobuf file.
 type PythonFileGenerator struct {
-	f   *protogen.File
-	gen *protogen.Plugin
+	f          *protogen.File
+	gen        *protogen.Plugin
+	schemaOpts SchemaOptions
+	appRoot    string
 }
 
 // NewPythonFileGenerator creates a PythonFileGenerator for the given protobuf file.
 func NewPythonFileGenerator(f *protogen.File, gen *protogen.Plugin) *PythonFileGenerator {
-	gen.SupportedFeatures |= uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+	declareSupportedFeatures(gen)
 	return &PythonFileGenerator{f: f, gen: gen}
 }
 
@@ -76,6 +77,8 @@ func (g *PythonFileGenerator) Generate() {
 		"pyString":     pyStringLiteral,
 		"lower":        strings.ToLower,
 		"escapeQuotes": func(s string) string { return strings.ReplaceAll(s, `"`, `\"`) },
+		"pyIcons":      pyIconsLiteral,
+		"pyList":       pyListLiteral,
 	}
 
 	tpl, err := template.New("pygen").Funcs(funcMap).Parse(codeTemplates[LangPython])
@@ -127,13 +130,13 @@ func (g *PythonFileGenerator) buildPyParams() PyTplParams {
 				}
 				// Resolve prompt schema → populate Arguments from proto message fields.
 				if methOpts.Prompt != nil && methOpts.Prompt.Schema != "" {
-					for _, sf := range ResolveSchemaFields(g.gen, methOpts.Prompt.Schema) {
+					for _, sf := range ResolveSchemaFields(g.gen, methOpts.Prompt.Schema, g.schemaOpts) {
 						methOpts.Prompt.Arguments = append(methOpts.Prompt.Arguments, MCPPromptArgOpts(sf))
 					}
 				}
 				// Resolve elicitation schema → populate Fields from proto message fields.
 				if methOpts.Elicitation != nil && methOpts.Elicitation.Schema != "" {
-					for _, sf := range ResolveSchemaFields(g.gen, methOpts.Elicitation.Schema) {
+					for _, sf := range ResolveSchemaFields(g.gen, methOpts.Elicitation.Schema, g.schemaOpts) {
 						methOpts.Elicitation.Fields = append(methOpts.Elicitation.Fields, MCPElicitFieldOpts(sf))
 					}
 				}
@@ -145,16 +148,19 @@ func (g *PythonFileGenerator) buildPyParams() PyTplParams {
 			}
 
 			// Standard schema (root description = tool description, per MCP inputSchema convention)
-			stdSchema := messageSchema(meth.Input.Desc, false, toolDesc)
+			stdSchema := inputSchema(meth.Desc, g.schemaOpts, toolDesc)
 			stdBytes, err := json.Marshal(stdSchema)
 			if err != nil {
 				panic(fmt.Sprintf("marshal standard schema: %v", err))
 			}
 			schemaJSON[key] = string(stdBytes)
 
+			toolTitle, toolIcons := toolDisplay(meth, methOpts)
 			toolMeta[key] = ToolMeta{
 				Name:        toolName,
+				Title:       toolTitle,
 				Description: toolDesc,
+				Icons:       toolIcons,
 			}
 
 			// Build Python import paths and type references.
@@ -187,12 +193,18 @@ func (g *PythonFileGenerator) buildPyParams() PyTplParams {
 		services[svcName] = methods
 		serviceBasePaths[svcName] = "/" + strings.ToLower(strings.ReplaceAll(string(svc.Desc.FullName()), ".", "/")) + "/mcp"
 		svcOpt := ExtractServiceOptions(svc)
-		apiResources := ExtractGoogleAPIResources(svc)
-		if len(apiResources) > 0 {
+		resources, err := ExtractServiceResources(svc)
+		if err != nil {
+			g.gen.Error(err)
+		}
+		if len(resources) > 0 {
 			if svcOpt == nil {
 				svcOpt = &MCPServiceOpts{}
 			}
-			svcOpt.Resources = apiResources
+			svcOpt.Resources = resources
+		}
+		if svcOpt, err = resolveAppViews(svc, svcOpt, toolMeta, g.appRoot); err != nil {
+			g.gen.Error(err)
 		}
 		serviceOpts[svcName] = svcOpt
 	}
@@ -213,7 +225,7 @@ func (g *PythonFileGenerator) buildPyParams() PyTplParams {
 	// Collect elicitation schema constants from all messages in this file
 	// that carry at least one (mcp.protobuf.field) annotation.
 	elicitSeen := make(map[string]bool)
-	elicitSchemas := collectElicitSchemas(g.gen, g.f.Messages, elicitSeen)
+	elicitSchemas := collectElicitSchemas(g.gen, g.f.Messages, elicitSeen, g.schemaOpts)
 	sort.Slice(elicitSchemas, func(i, j int) bool {
 		return elicitSchemas[i].Name < elicitSchemas[j].Name
 	})
@@ -250,7 +262,7 @@ func hasElicitMessages(msgs []*protogen.Message) bool {
 // collectElicitSchemas recursively walks msgs and returns an ElicitationSchemaConst for every
 // message that has at least one (mcp.protobuf.field) annotated field.
 // seen is used to deduplicate by fully-qualified message name.
-func collectElicitSchemas(gen *protogen.Plugin, msgs []*protogen.Message, seen map[string]bool) []ElicitationSchemaConst {
+func collectElicitSchemas(gen *protogen.Plugin, msgs []*protogen.Message, seen map[string]bool, opts SchemaOptions) []ElicitationSchemaConst {
 	var result []ElicitationSchemaConst
 	for _, m := range msgs {
 		fqn := string(m.Desc.FullName())
@@ -264,7 +276,7 @@ func collectElicitSchemas(gen *protogen.Plugin, msgs []*protogen.Message, seen m
 			}
 			if hasMCP {
 				seen[fqn] = true
-				fields := ResolveSchemaFields(gen, fqn)
+				fields := ResolveSchemaFields(gen, fqn, opts)
 				if len(fields) > 0 {
 					result = append(result, ElicitationSchemaConst{
 						Name:       string(m.Desc.Name()),
@@ -273,7 +285,7 @@ func collectElicitSchemas(gen *protogen.Plugin, msgs []*protogen.Message, seen m
 				}
 			}
 		}
-		result = append(result, collectElicitSchemas(gen, m.Messages, seen)...)
+		result = append(result, collectElicitSchemas(gen, m.Messages, seen, opts)...)
 	}
 	return result
 }
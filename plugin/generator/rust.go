@@ -8,7 +8,6 @@ import (
 	"text/template"
 
 	"google.golang.org/protobuf/compiler/protogen"
-	"google.golang.org/protobuf/types/pluginpb"
 )
 
 // RsMethodInfo carries Rust-specific identifiers for a single RPC method.
@@ -37,13 +36,15 @@ type RsTplParams struct {
 
 // RustFileGenerator produces a single *_mcp.rs file from a protobuf file.
 type RustFileGenerator struct {
-	f   *protogen.File
-	gen *protogen.Plugin
+	f          *protogen.File
+	gen        *protogen.Plugin
+	schemaOpts SchemaOptions
+	appRoot    string
 }
 
 // NewRustFileGenerator creates a RustFileGenerator for the given protobuf file.
 func NewRustFileGenerator(f *protogen.File, gen *protogen.Plugin) *RustFileGenerator {
-	gen.SupportedFeatures |= uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+	declareSupportedFeatures(gen)
 	return &RustFileGenerator{f: f, gen: gen}
 }
 
@@ -70,6 +71,8 @@ func (g *RustFileGenerator) Generate() {
 		"lower":              strings.ToLower,
 		"rsEscape":           rsStringEscape,
 		"escapeQuotes":       func(s string) string { return strings.ReplaceAll(s, `"`, `\"`) },
+		"jsonIcons":          jsonIcons,
+		"jsonList":           jsonList,
 	}
 
 	tpl, err := template.New("rsgen").Funcs(funcMap).Parse(codeTemplates[LangRust])
@@ -118,13 +121,13 @@ func (g *RustFileGenerator) buildRsParams() RsTplParams {
 				}
 				// Resolve prompt schema → populate Arguments from proto message fields.
 				if methOpts.Prompt != nil && methOpts.Prompt.Schema != "" {
-					for _, sf := range ResolveSchemaFields(g.gen, methOpts.Prompt.Schema) {
+					for _, sf := range ResolveSchemaFields(g.gen, methOpts.Prompt.Schema, g.schemaOpts) {
 						methOpts.Prompt.Arguments = append(methOpts.Prompt.Arguments, MCPPromptArgOpts(sf))
 					}
 				}
 				// Resolve elicitation schema → populate Fields from proto message fields.
 				if methOpts.Elicitation != nil && methOpts.Elicitation.Schema != "" {
-					for _, sf := range ResolveSchemaFields(g.gen, methOpts.Elicitation.Schema) {
+					for _, sf := range ResolveSchemaFields(g.gen, methOpts.Elicitation.Schema, g.schemaOpts) {
 						methOpts.Elicitation.Fields = append(methOpts.Elicitation.Fields, MCPElicitFieldOpts(sf))
 					}
 				}
@@ -136,15 +139,18 @@ func (g *RustFileGenerator) buildRsParams() RsTplParams {
 			}
 
 			// Standard schema (root description = tool description, per MCP inputSchema convention)
-			stdSchema := messageSchema(meth.Input.Desc, false, desc)
+			stdSchema := inputSchema(meth.Desc, g.schemaOpts, desc)
 			stdBytes, err := json.Marshal(stdSchema)
 			if err != nil {
 				panic(fmt.Sprintf("marshal standard schema: %v", err))
 			}
 			schemaJSON[key] = string(stdBytes)
+			toolTitle, toolIcons := toolDisplay(meth, methOpts)
 			toolMeta[key] = ToolMeta{
 				Name:        toolName,
+				Title:       toolTitle,
 				Description: desc,
+				Icons:       toolIcons,
 			}
 
 			reqType := string(meth.Input.Desc.Name())
@@ -172,12 +178,18 @@ func (g *RustFileGenerator) buildRsParams() RsTplParams {
 		services[svcName] = methods
 		serviceBasePaths[svcName] = "/" + strings.ToLower(strings.ReplaceAll(string(svc.Desc.FullName()), ".", "/")) + "/mcp"
 		svcOpt := ExtractServiceOptions(svc)
-		apiResources := ExtractGoogleAPIResources(svc)
-		if len(apiResources) > 0 {
+		resources, err := ExtractServiceResources(svc)
+		if err != nil {
+			g.gen.Error(err)
+		}
+		if len(resources) > 0 {
 			if svcOpt == nil {
 				svcOpt = &MCPServiceOpts{}
 			}
-			svcOpt.Resources = apiResources
+			svcOpt.Resources = resources
+		}
+		if svcOpt, err = resolveAppViews(svc, svcOpt, toolMeta, g.appRoot); err != nil {
+			g.gen.Error(err)
 		}
 		serviceOpts[svcName] = svcOpt
 	}
@@ -1,10 +1,13 @@
 package generator
 
 import (
+	"encoding/base64"
 	"fmt"
+	"math"
 	"strings"
 
-	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"github.com/machanirobotics/grpc-mcp-gateway/internal/enumname"
+	"github.com/machanirobotics/grpc-mcp-gateway/internal/mimetype"
 	"github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppb"
 	"google.golang.org/genproto/googleapis/api/annotations"
 	"google.golang.org/protobuf/proto"
@@ -26,111 +29,188 @@ func kindToType(kind protoreflect.Kind) string {
 	}
 }
 
-// isFieldRequired checks whether a field has REQUIRED google.api.field_behavior.
+// is64BitInt reports whether kind is a 64-bit integer kind, which protojson
+// encodes as a string.
+func is64BitInt(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return true
+	}
+	return false
+}
+
+// maxSafeInteger is the largest integer a JSON number holds exactly in
+// IEEE 754 double precision.
+const maxSafeInteger = 1<<53 - 1
+
+// isFieldRequired checks whether a field has REQUIRED google.api.field_behavior
+// or buf.validate.field.required.
 func isFieldRequired(fd protoreflect.FieldDescriptor) bool {
+	return fd.Cardinality() == protoreflect.Required ||
+		hasFieldBehavior(fd, annotations.FieldBehavior_REQUIRED) || isValidateRequired(fd)
+}
+
+// hasExplicitPresence reports whether a singular scalar or enum field tracks
+// presence (proto3 optional, proto2 optional, or editions EXPLICIT), so
+// leaving it out differs from sending its zero value.
+func hasExplicitPresence(fd protoreflect.FieldDescriptor) bool {
+	return fd.HasPresence() && !fd.IsList() && fd.Message() == nil && fd.Cardinality() != protoreflect.Required
+}
+
+// hasFieldBehavior reports whether fd is annotated with the given google.api.field_behavior.
+func hasFieldBehavior(fd protoreflect.FieldDescriptor, want annotations.FieldBehavior) bool {
 	if !proto.HasExtension(fd.Options(), annotations.E_FieldBehavior) {
 		return false
 	}
 	behaviors := proto.GetExtension(fd.Options(), annotations.E_FieldBehavior).([]annotations.FieldBehavior)
 	for _, b := range behaviors {
-		if b == annotations.FieldBehavior_REQUIRED {
+		if b == want {
 			return true
 		}
 	}
 	return false
 }
 
-// extractValidateConstraints reads buf.validate.field rules and returns JSON Schema constraints.
-func extractValidateConstraints(fd protoreflect.FieldDescriptor) map[string]any {
-	constraints := make(map[string]any)
-	if !proto.HasExtension(fd.Options(), validate.E_Field) {
-		return constraints
+// skipInputField reports whether fd is left out of the input schema: OUTPUT_ONLY
+// and bound fields always, and IDENTIFIER fields of create requests
+// (AIP-203), where the server assigns the resource name.
+func (b *schemaBuilder) skipInputField(fd protoreflect.FieldDescriptor) bool {
+	if hasFieldBehavior(fd, annotations.FieldBehavior_OUTPUT_ONLY) || isBoundField(fd) {
+		return true
 	}
-	rules := proto.GetExtension(fd.Options(), validate.E_Field).(*validate.FieldRules)
-	if rules == nil {
-		return constraints
+	return b.standard == standardCreate && hasFieldBehavior(fd, annotations.FieldBehavior_IDENTIFIER)
+}
+
+// isInputFieldRequired reports whether fd goes in the required list: REQUIRED
+// fields, and IDENTIFIER fields of update requests (AIP-203).
+func (b *schemaBuilder) isInputFieldRequired(fd protoreflect.FieldDescriptor) bool {
+	if isFieldRequired(fd) {
+		return true
 	}
+	return b.standard == standardUpdate && hasFieldBehavior(fd, annotations.FieldBehavior_IDENTIFIER)
+}
 
-	if sr := rules.GetString(); sr != nil {
-		if sr.GetUuid() {
-			constraints["format"] = "uuid"
-		}
-		if sr.GetEmail() {
-			constraints["format"] = "email"
-		}
-		if p := sr.GetPattern(); p != "" {
-			constraints["pattern"] = p
+// isBoundField reports whether fd is set from the request context through
+// the (mcp.protobuf.field).bind option rather than by the model.
+func isBoundField(fd protoreflect.FieldDescriptor) bool {
+	opts, ok := proto.GetExtension(fd.Options(), mcppb.E_Field).(*mcppb.MCPFieldOptions)
+	return ok && opts.GetBind() != nil
+}
+
+// boundField returns the first field of md, or of a message reachable from
+// it, that has the (mcp.protobuf.field).bind option, or nil.
+func boundField(md protoreflect.MessageDescriptor) protoreflect.FieldDescriptor {
+	return findField(md, isBoundField, map[protoreflect.FullName]bool{})
+}
+
+// mediaField returns the first bytes field of md, or of a message reachable
+// from it, that has a mime_type in (mcp.protobuf.field), or nil.
+func mediaField(md protoreflect.MessageDescriptor) protoreflect.FieldDescriptor {
+	return findField(md, func(fd protoreflect.FieldDescriptor) bool {
+		opts, ok := proto.GetExtension(fd.Options(), mcppb.E_Field).(*mcppb.MCPFieldOptions)
+		return ok && fd.Kind() == protoreflect.BytesKind && mimetype.String(opts.GetMimeType()) != ""
+	}, map[protoreflect.FullName]bool{})
+}
+
+// findField returns the first field matching pred in md or in a message
+// reachable from it, skipping messages in seen.
+func findField(md protoreflect.MessageDescriptor, pred func(protoreflect.FieldDescriptor) bool, seen map[protoreflect.FullName]bool) protoreflect.FieldDescriptor {
+	if seen[md.FullName()] {
+		return nil
+	}
+	seen[md.FullName()] = true
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if pred(fd) {
+			return fd
 		}
-		if sr.HasMinLen() {
-			constraints["minLength"] = int(sr.GetMinLen())
+		if fd.IsMap() {
+			fd = fd.MapValue()
 		}
-		if sr.HasMaxLen() {
-			constraints["maxLength"] = int(sr.GetMaxLen())
+		if fd.Message() != nil {
+			if found := findField(fd.Message(), pred, seen); found != nil {
+				return found
+			}
 		}
 	}
+	return nil
+}
 
-	applyIntRange := func(hasGt bool, gt int, hasGte bool, gte int, hasLt bool, lt int, hasLte bool, lte int) {
-		if hasGt {
-			constraints["minimum"] = gt + 1
-		} else if hasGte {
-			constraints["minimum"] = gte
-		}
-		if hasLt {
-			constraints["maximum"] = lt - 1
-		} else if hasLte {
-			constraints["maximum"] = lte
-		}
+// applyFieldBehaviorNotes appends IDENTIFIER, IMMUTABLE and OPTIONAL notes to
+// the field description.
+func applyFieldBehaviorNotes(fd protoreflect.FieldDescriptor, schema map[string]any) {
+	if hasFieldBehavior(fd, annotations.FieldBehavior_IDENTIFIER) {
+		appendDescription(schema, "Identifier: the resource name of this resource.")
 	}
-	if r := rules.GetInt32(); r != nil {
-		applyIntRange(r.HasGt(), int(r.GetGt()), r.HasGte(), int(r.GetGte()), r.HasLt(), int(r.GetLt()), r.HasLte(), int(r.GetLte()))
+	if hasFieldBehavior(fd, annotations.FieldBehavior_IMMUTABLE) {
+		appendDescription(schema, "Immutable: can only be set when the resource is created.")
 	}
-	if r := rules.GetInt64(); r != nil {
-		applyIntRange(r.HasGt(), int(r.GetGt()), r.HasGte(), int(r.GetGte()), r.HasLt(), int(r.GetLt()), r.HasLte(), int(r.GetLte()))
+	desc, _ := schema["description"].(string)
+	if hasFieldBehavior(fd, annotations.FieldBehavior_OPTIONAL) && !strings.HasPrefix(strings.ToLower(desc), "optional") {
+		appendDescription(schema, "Optional.")
 	}
+}
 
-	return constraints
+// appendDescription adds a sentence to the schema's description.
+func appendDescription(schema map[string]any, note string) {
+	desc, _ := schema["description"].(string)
+	desc = strings.TrimSpace(desc)
+	if desc == "" {
+		schema["description"] = note
+		return
+	}
+	if !strings.HasSuffix(desc, ".") {
+		desc += "."
+	}
+	schema["description"] = desc + " " + note
 }
 
 // messageSchema converts a protobuf message descriptor into a JSON Schema map.
 // If schemaDesc is non-empty, it is set as the root-level description (per MCP inputSchema convention).
-func messageSchema(md protoreflect.MessageDescriptor, openAI bool, schemaDesc string) map[string]any {
+func (b *schemaBuilder) messageSchema(md protoreflect.MessageDescriptor, schemaDesc string) map[string]any {
+	openAI := b.openAI
 	required, props := []string{}, map[string]any{}
-	oneOfGroups := map[string][]map[string]any{}
 	for i := 0; i < md.Fields().Len(); i++ {
 		fd := md.Fields().Get(i)
 		name := string(fd.Name())
+		if b.skipInputField(fd) {
+			continue
+		}
 		if oo := fd.ContainingOneof(); oo != nil && !oo.IsSynthetic() {
-			if !openAI {
-				key := string(oo.Name())
-				oneOfGroups[key] = append(oneOfGroups[key], map[string]any{
-					"properties": map[string]any{name: fieldSchema(fd, openAI)}, "required": []string{name},
-				})
-			} else {
-				s := fieldSchema(fd, openAI)
-				if t, ok := s["type"].(string); ok {
-					s["type"] = []string{t, "null"}
-				}
-				s["description"] = fmt.Sprintf("Note: Part of the '%s' oneof group. Only one field in this group can be set. Setting multiple fields WILL result in an error.", oo.Name())
-				props[name] = s
+			// Oneof members stay regular properties so every client sees
+			// them; the group rule is spelled out in each description.
+			s := b.fieldSchema(fd)
+			appendDescription(s, b.oneofNote(oo))
+			if openAI {
+				makeNullable(s)
 				required = append(required, name)
 			}
+			props[name] = s
 		} else {
-			props[name] = fieldSchema(fd, openAI)
-			if isFieldRequired(fd) || openAI {
+			props[name] = b.fieldSchema(fd)
+			if openAI && hasExplicitPresence(fd) {
+				// Strict mode requires every property, so optional ones take null.
+				makeNullable(props[name].(map[string]any))
+			}
+			if b.isInputFieldRequired(fd) || openAI {
 				required = append(required, name)
 			}
 		}
 	}
 	result := map[string]any{"type": "object", "properties": props, "required": required}
+	if schemaDesc == "" {
+		schemaDesc = descriptorComment(md)
+	}
 	if schemaDesc != "" {
 		result["description"] = schemaDesc
 	}
-	if len(oneOfGroups) > 0 {
-		var anyOf []map[string]any
-		for _, entries := range oneOfGroups {
-			anyOf = append(anyOf, map[string]any{"oneOf": entries, "$comment": "Protobuf oneOf group."})
-		}
-		result["anyOf"] = anyOf
+	for _, note := range messageValidateNotes(md) {
+		appendDescription(result, note)
+	}
+	if b.opts.OneofVariants && !openAI {
+		b.applyOneofVariants(md, result)
 	}
 	if openAI {
 		result["additionalProperties"] = false
@@ -141,6 +221,70 @@ func messageSchema(md protoreflect.MessageDescriptor, openAI bool, schemaDesc st
 	return result
 }
 
+// oneofMembers returns the names of the members of oo that are input fields.
+func (b *schemaBuilder) oneofMembers(oo protoreflect.OneofDescriptor) []string {
+	var names []string
+	for i := 0; i < oo.Fields().Len(); i++ {
+		if fd := oo.Fields().Get(i); !b.skipInputField(fd) {
+			names = append(names, string(fd.Name()))
+		}
+	}
+	return names
+}
+
+// oneofNote describes the oneof group of a member field.
+func (b *schemaBuilder) oneofNote(oo protoreflect.OneofDescriptor) string {
+	verb := "at most"
+	if isOneofRequired(oo) {
+		verb = "exactly"
+	}
+	group := fmt.Sprintf("'%s'", oo.Name())
+	if c := descriptorComment(oo); c != "" {
+		group += " (" + strings.TrimSuffix(c, ".") + ")"
+	}
+	note := fmt.Sprintf("Part of oneof %s: set %s one of %s.", group, verb, strings.Join(b.oneofMembers(oo), ", "))
+	if b.openAI {
+		note += " Set the others to null."
+	}
+	return note
+}
+
+// applyOneofVariants adds a discriminated oneOf per oneof group of md: one
+// variant, titled after the member, requiring each member, plus a "none"
+// variant when the group may be left unset. Several groups are combined
+// with allOf.
+func (b *schemaBuilder) applyOneofVariants(md protoreflect.MessageDescriptor, schema map[string]any) {
+	var groups []any
+	for i := 0; i < md.Oneofs().Len(); i++ {
+		oo := md.Oneofs().Get(i)
+		if oo.IsSynthetic() {
+			continue
+		}
+		members := b.oneofMembers(oo)
+		if len(members) == 0 {
+			continue
+		}
+		var variants, present []any
+		for _, name := range members {
+			variants = append(variants, map[string]any{"title": name, "required": []string{name}})
+			present = append(present, map[string]any{"required": []string{name}})
+		}
+		if !isOneofRequired(oo) {
+			variants = append(variants, map[string]any{"title": "none", "not": map[string]any{"anyOf": present}})
+		}
+		groups = append(groups, map[string]any{"oneOf": variants, "$comment": fmt.Sprintf("Protobuf oneof '%s'.", oo.Name())})
+	}
+	switch len(groups) {
+	case 0:
+	case 1:
+		for k, v := range groups[0].(map[string]any) {
+			schema[k] = v
+		}
+	default:
+		schema["allOf"] = groups
+	}
+}
+
 // getFieldDescription returns the field description from (mcp.protobuf.field) if set,
 // otherwise the fallback (e.g. from the leading comment).
 func getFieldDescription(fd protoreflect.FieldDescriptor, fallback string) string {
@@ -153,12 +297,25 @@ func getFieldDescription(fd protoreflect.FieldDescriptor, fallback string) strin
 	return fallback
 }
 
+// descriptorComment returns the cleaned leading and trailing proto comments
+// of a field, message, enum or enum value, or "" without source info.
+func descriptorComment(d protoreflect.Descriptor) string {
+	file := d.ParentFile()
+	if file == nil {
+		return ""
+	}
+	loc := file.SourceLocations().ByDescriptor(d)
+	return CleanComment(loc.LeadingComments + "\n" + loc.TrailingComments)
+}
+
 // applyMCPFieldOptions applies (mcp.protobuf.field) options to the schema.
 // Format from MCPFieldOptions overrides any from buf.validate.
-// For enum fields with (mcp.protobuf.enum) or (mcp.protobuf.enum_value), enum descriptions take precedence over field description.
+// For enum fields, descriptions from (mcp.protobuf.enum), (mcp.protobuf.enum_value)
+// or enum comments take precedence over the field's description.
 func applyMCPFieldOptions(fd protoreflect.FieldDescriptor, schema map[string]any, descFallback string) {
+	hasEnumDesc := fd.Kind() == protoreflect.EnumKind && schema["description"] != nil && schema["description"] != ""
 	if !proto.HasExtension(fd.Options(), mcppb.E_Field) {
-		if descFallback != "" {
+		if descFallback != "" && !hasEnumDesc {
 			schema["description"] = descFallback
 		}
 		return
@@ -168,7 +325,7 @@ func applyMCPFieldOptions(fd protoreflect.FieldDescriptor, schema map[string]any
 		return
 	}
 	// For enum fields, prefer enum-level and enum-value descriptions over field description
-	if fd.Kind() == protoreflect.EnumKind && schema["description"] != nil && schema["description"] != "" {
+	if hasEnumDesc {
 		// Enum descriptions already set by enumSchema; skip field description
 	} else if opts.Description != "" {
 		schema["description"] = opts.Description
@@ -188,28 +345,63 @@ func applyMCPFieldOptions(fd protoreflect.FieldDescriptor, schema map[string]any
 	if opts.Format != "" {
 		schema["format"] = opts.Format
 	}
+	if mt := mimetype.String(opts.GetMimeType()); mt != "" && fd.Kind() == protoreflect.BytesKind {
+		schema["contentMediaType"] = mt
+	}
 }
 
 // fieldSchema converts a single protobuf field descriptor to a JSON Schema map.
-func fieldSchema(fd protoreflect.FieldDescriptor, openAI bool) map[string]any {
+func (b *schemaBuilder) fieldSchema(fd protoreflect.FieldDescriptor) map[string]any {
+	rules := validateRules(fd)
 	if fd.IsMap() {
-		return mapSchema(fd, openAI)
+		schema := b.mapSchema(fd)
+		if desc := getFieldDescription(fd, descriptorComment(fd)); desc != "" {
+			schema["description"] = desc
+		}
+		mapConstraints(rules.GetMap(), schema)
+		_, notes := b.extractValidateConstraints(fd, rules)
+		for _, note := range notes {
+			appendDescription(schema, note)
+		}
+		return schema
 	}
 	var schema map[string]any
 	switch fd.Kind() {
 	case protoreflect.MessageKind:
-		schema = messageFieldSchema(fd, openAI)
+		schema = b.messageFieldSchema(fd)
 	case protoreflect.EnumKind:
-		schema = enumSchema(fd)
+		schema = b.enumSchema(fd)
 	default:
-		schema = scalarSchema(fd, openAI)
+		schema = b.scalarSchema(fd)
+	}
+	itemRules := rules
+	if fd.IsList() {
+		itemRules = rules.GetRepeated().GetItems()
 	}
-	for k, v := range extractValidateConstraints(fd) {
+	constraints, notes := b.extractValidateConstraints(fd, itemRules)
+	b.friendlyEnumConstraints(fd, constraints)
+	for k, v := range constraints {
 		schema[k] = v
 	}
-	applyMCPFieldOptions(fd, schema, "")
+	applyMCPFieldOptions(fd, schema, descriptorComment(fd))
+	// Keep type guidance when the field has its own description.
+	if note := b.typeNote(fd); note != "" && schema["description"] != note {
+		appendDescription(schema, note)
+	}
+	applyFieldBehaviorNotes(fd, schema)
+	b.applyDefault(fd, schema)
+	if fd.IsList() {
+		// Field-level CEL rules apply to the whole list.
+		_, listNotes := b.extractValidateConstraints(fd, rules)
+		notes = append(notes, listNotes...)
+	}
+	for _, note := range notes {
+		appendDescription(schema, note)
+	}
 	if fd.IsList() {
-		return map[string]any{"type": "array", "items": schema}
+		array := map[string]any{"type": "array", "items": schema}
+		repeatedConstraints(rules.GetRepeated(), array)
+		return array
 	}
 	return schema
 }
@@ -220,6 +412,8 @@ type enumDescriptions struct {
 	values   map[string]string // value name -> description
 }
 
+// getEnumDescriptions reads (mcp.protobuf.enum) and (mcp.protobuf.enum_value)
+// descriptions, falling back to the enum and value comments.
 func getEnumDescriptions(ed protoreflect.EnumDescriptor) enumDescriptions {
 	out := enumDescriptions{values: make(map[string]string)}
 	if proto.HasExtension(ed.Options(), mcppb.E_Enum) {
@@ -228,6 +422,9 @@ func getEnumDescriptions(ed protoreflect.EnumDescriptor) enumDescriptions {
 			out.enumDesc = opts.Description
 		}
 	}
+	if out.enumDesc == "" {
+		out.enumDesc = descriptorComment(ed)
+	}
 	vals := ed.Values()
 	for i := 0; i < vals.Len(); i++ {
 		vd := vals.Get(i)
@@ -235,15 +432,25 @@ func getEnumDescriptions(ed protoreflect.EnumDescriptor) enumDescriptions {
 			opts := proto.GetExtension(vd.Options(), mcppb.E_EnumValue).(*mcppb.MCPEnumValueOptions)
 			if opts != nil && opts.Description != "" {
 				out.values[string(vd.Name())] = opts.Description
+				continue
 			}
 		}
+		if c := descriptorComment(vd); c != "" {
+			out.values[string(vd.Name())] = c
+		}
 	}
 	return out
 }
 
-// enumSchema returns a JSON Schema for a protobuf enum field.
-func enumSchema(fd protoreflect.FieldDescriptor) map[string]any {
+// enumSchema returns a JSON Schema for a protobuf enum field. With
+// FriendlyEnums set, values are listed by friendly name (see
+// enumname.Friendly) without the UNSPECIFIED value, as oneOf const entries
+// carrying each value's description; strict schemas use a plain enum list.
+func (b *schemaBuilder) enumSchema(fd protoreflect.FieldDescriptor) map[string]any {
 	ed := fd.Enum()
+	if b.opts.FriendlyEnums {
+		return friendlyEnumSchema(ed, b.openAI)
+	}
 	vals := make([]string, ed.Values().Len())
 	for i := range vals {
 		vals[i] = string(ed.Values().Get(i).Name())
@@ -268,14 +475,207 @@ func enumSchema(fd protoreflect.FieldDescriptor) map[string]any {
 	return schema
 }
 
+func friendlyEnumSchema(ed protoreflect.EnumDescriptor, openAI bool) map[string]any {
+	descs := getEnumDescriptions(ed)
+	schema := map[string]any{"type": "string"}
+	var (
+		names   []string
+		options []any
+		parts   []string
+	)
+	if descs.enumDesc != "" {
+		parts = append(parts, strings.TrimSuffix(descs.enumDesc, "."))
+	}
+	for i := 0; i < ed.Values().Len(); i++ {
+		vd := ed.Values().Get(i)
+		if isUnspecifiedEnumValue(vd) {
+			continue
+		}
+		name := friendlyEnumName(vd)
+		names = append(names, name)
+		option := map[string]any{"const": name}
+		if d, ok := descs.values[string(vd.Name())]; ok {
+			option["description"] = d
+			parts = append(parts, fmt.Sprintf("%s: %s", name, strings.TrimSuffix(d, ".")))
+		}
+		options = append(options, option)
+	}
+	if openAI {
+		// Strict mode does not allow oneOf; keep value descriptions in the
+		// field description instead.
+		schema["enum"] = names
+		if len(parts) > 0 {
+			schema["description"] = strings.Join(parts, ". ")
+		}
+		return schema
+	}
+	schema["oneOf"] = options
+	if descs.enumDesc != "" {
+		schema["description"] = descs.enumDesc
+	}
+	return schema
+}
+
+// isUnspecifiedEnumValue reports whether vd is the zero "unset" value of its
+// enum, which friendly schemas leave out.
+func isUnspecifiedEnumValue(vd protoreflect.EnumValueDescriptor) bool {
+	ed := vd.Parent().(protoreflect.EnumDescriptor)
+	// Closed (proto2) enums have no implicit zero value, so every declared
+	// value is a real choice.
+	return !ed.IsClosed() && vd.Number() == 0 && strings.HasSuffix(string(vd.Name()), "_UNSPECIFIED")
+}
+
+func friendlyEnumName(vd protoreflect.EnumValueDescriptor) string {
+	return enumname.Friendly(string(vd.Name()), string(vd.Parent().Name()))
+}
+
+// friendlyEnumConstraints rewrites enum names in buf.validate constraints of
+// fd to friendly names.
+func (b *schemaBuilder) friendlyEnumConstraints(fd protoreflect.FieldDescriptor, constraints map[string]any) {
+	if !b.opts.FriendlyEnums || fd.Enum() == nil {
+		return
+	}
+	friendly := func(name string) string {
+		if vd := fd.Enum().Values().ByName(protoreflect.Name(name)); vd != nil {
+			return friendlyEnumName(vd)
+		}
+		return name
+	}
+	if c, ok := constraints["const"].(string); ok {
+		constraints["const"] = friendly(c)
+	}
+	if names, ok := constraints["enum"].([]string); ok {
+		out := make([]string, 0, len(names))
+		for _, n := range names {
+			if vd := fd.Enum().Values().ByName(protoreflect.Name(n)); vd != nil && isUnspecifiedEnumValue(vd) {
+				continue
+			}
+			out = append(out, friendly(n))
+		}
+		constraints["enum"] = out
+	}
+}
+
+// makeNullable lets a strict-mode schema also accept null, which must then
+// be listed among its enum values too.
+func makeNullable(s map[string]any) {
+	t, ok := s["type"].(string)
+	if !ok {
+		return
+	}
+	s["type"] = []string{t, "null"}
+	if vals, ok := s["enum"].([]string); ok {
+		withNull := make([]any, 0, len(vals)+1)
+		for _, v := range vals {
+			withNull = append(withNull, v)
+		}
+		s["enum"] = append(withNull, nil)
+	}
+}
+
+// applyDefault sets the JSON Schema default of a singular field with an
+// explicit default ([default = ...] in proto2, or editions fields with
+// explicit presence) or of a closed enum, whose default is its first value.
+// Strict schemas have no "default" keyword, so the default is noted in the
+// description instead.
+func (b *schemaBuilder) applyDefault(fd protoreflect.FieldDescriptor, schema map[string]any) {
+	if fd.IsList() || fd.IsMap() || fd.Message() != nil {
+		return
+	}
+	var def any
+	switch {
+	case fd.HasDefault() && fd.Kind() == protoreflect.EnumKind:
+		def = b.enumValueName(fd.DefaultEnumValue())
+	case fd.HasDefault():
+		def = jsonDefault(fd, b.opts)
+	case fd.Kind() == protoreflect.EnumKind && fd.Enum().IsClosed() && fd.Enum().Values().Len() > 0:
+		def = b.enumValueName(fd.Enum().Values().Get(0))
+	default:
+		return
+	}
+	if b.openAI {
+		appendDescription(schema, fmt.Sprintf("Default: %v.", def))
+		return
+	}
+	schema["default"] = def
+}
+
+// enumValueName returns the schema name of vd: its friendly name with
+// FriendlyEnums, otherwise its proto name.
+func (b *schemaBuilder) enumValueName(vd protoreflect.EnumValueDescriptor) string {
+	if b.opts.FriendlyEnums {
+		return friendlyEnumName(vd)
+	}
+	return string(vd.Name())
+}
+
+// jsonDefault returns the explicit default of a scalar field in its JSON
+// form: protojson, except that 64-bit integers are numbers when opts types
+// them as JSON integers.
+func jsonDefault(fd protoreflect.FieldDescriptor, opts SchemaOptions) any {
+	v := fd.Default()
+	switch fd.Kind() {
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if opts.int64AsInteger() {
+			return v.Interface()
+		}
+		return v.String() // 64-bit integers are JSON strings
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		switch f := v.Float(); {
+		case math.IsNaN(f):
+			return "NaN"
+		case math.IsInf(f, 1):
+			return "Infinity"
+		case math.IsInf(f, -1):
+			return "-Infinity"
+		}
+	}
+	return v.Interface()
+}
+
+// typeNote returns guidance on how values of fd's type are encoded: the
+// google.type description, or the precision caveat of 64-bit integers typed
+// as JSON integers.
+func (b *schemaBuilder) typeNote(fd protoreflect.FieldDescriptor) string {
+	if fd.Message() != nil {
+		return googleTypeNote(string(fd.Message().FullName()))
+	}
+	if is64BitInt(fd.Kind()) && b.opts.int64AsInteger() {
+		return fmt.Sprintf("64-bit integer. Clients may round JSON numbers beyond ±%d; decimal strings are accepted as well.", maxSafeInteger)
+	}
+	return ""
+}
+
 // scalarSchema returns a JSON Schema for a protobuf scalar field.
-func scalarSchema(fd protoreflect.FieldDescriptor, openAI bool) map[string]any {
-	s := map[string]any{"type": kindToType(fd.Kind())}
-	if fd.Kind() == protoreflect.BytesKind {
+func (b *schemaBuilder) scalarSchema(fd protoreflect.FieldDescriptor) map[string]any {
+	s := map[string]any{"type": b.opts.kindToType(fd.Kind())}
+	switch {
+	case fd.Kind() == protoreflect.BytesKind:
 		s["contentEncoding"] = "base64"
-		if !openAI {
+		if !b.openAI {
 			s["format"] = "byte"
 		}
+	case is64BitInt(fd.Kind()):
+		unsigned := fd.Kind() == protoreflect.Uint64Kind || fd.Kind() == protoreflect.Fixed64Kind
+		if !b.opts.int64AsInteger() {
+			s["pattern"] = `^-?(0|[1-9]\d*)$`
+			if unsigned {
+				s["pattern"] = `^(0|[1-9]\d*)$`
+			}
+			break
+		}
+		if unsigned {
+			s["minimum"] = 0
+		}
+		if !b.openAI {
+			s["format"] = "int64"
+			if unsigned {
+				s["format"] = "uint64"
+			}
+		}
 	}
 	return s
 }
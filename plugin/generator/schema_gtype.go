@@ -0,0 +1,198 @@
+package generator
+
+// schema_gtype.go contains JSON Schemas for the google.type common types
+// (Date, Money, LatLng, ...), which are plain messages on the wire but have
+// well-defined meanings a model needs spelled out.
+
+import "sort"
+
+// googleTypeSchema describes one google.type message.
+type googleTypeSchema struct {
+	desc     string
+	example  map[string]any
+	props    func(openAI bool) map[string]any
+	required []string
+}
+
+// googleTypeSchemas maps google.type full names to their schemas. The types
+// are also listed in wellKnownSchemaTypes so they are never hoisted to $defs.
+var googleTypeSchemas = map[string]googleTypeSchema{
+	"google.type.Date": {
+		desc:    "Calendar date (google.type.Date). Set year, month and day; year 0 means a date without a year, day 0 a year and month only.",
+		example: map[string]any{"year": 2026, "month": 10, "day": 17},
+		props: func(bool) map[string]any {
+			return map[string]any{
+				"year":  intRange(0, 9999, "Year, or 0 for a date without a year."),
+				"month": intRange(0, 12, "Month of the year (1-12), or 0 for a year only."),
+				"day":   intRange(0, 31, "Day of the month (1-31), or 0 for a year and month only."),
+			}
+		},
+	},
+	"google.type.TimeOfDay": {
+		desc:    "Time of day without date or time zone (google.type.TimeOfDay), 24-hour clock.",
+		example: map[string]any{"hours": 14, "minutes": 30},
+		props: func(bool) map[string]any {
+			return map[string]any{
+				"hours":   intRange(0, 24, "Hours (0-23; 24 may be used for closing time)."),
+				"minutes": intRange(0, 59, "Minutes (0-59)."),
+				"seconds": intRange(0, 60, "Seconds (0-59; 60 for leap seconds)."),
+				"nanos":   intRange(0, 999999999, "Fractions of a second in nanoseconds."),
+			}
+		},
+	},
+	"google.type.Money": {
+		desc:     "Amount of money (google.type.Money). units holds the whole units and nanos the fraction in billionths, with the same sign: 12.50 USD is units \"12\" and nanos 500000000.",
+		example:  map[string]any{"currency_code": "USD", "units": "12", "nanos": 500000000},
+		required: []string{"currency_code"},
+		props: func(bool) map[string]any {
+			return map[string]any{
+				"currency_code": map[string]any{"type": "string", "pattern": "^[A-Z]{3}$", "description": "ISO 4217 currency code, e.g. USD."},
+				"units":         map[string]any{"type": "string", "pattern": `^-?(0|[1-9]\d*)$`, "description": "Whole units of the amount (64-bit integer as a string)."},
+				"nanos":         intRange(-999999999, 999999999, "Nano units of the amount; same sign as units."),
+			}
+		},
+	},
+	"google.type.LatLng": {
+		desc:    "Latitude/longitude pair in degrees, WGS84 (google.type.LatLng).",
+		example: map[string]any{"latitude": 37.4220, "longitude": -122.0841},
+		props: func(bool) map[string]any {
+			return map[string]any{
+				"latitude":  numberRange(-90, 90, "Latitude in degrees."),
+				"longitude": numberRange(-180, 180, "Longitude in degrees."),
+			}
+		},
+	},
+	"google.type.Color": {
+		desc:    "RGBA color (google.type.Color) with components from 0 to 1. #FF8000 is red 1, green 0.5, blue 0.",
+		example: map[string]any{"red": 1, "green": 0.5, "blue": 0},
+		props: func(bool) map[string]any {
+			return map[string]any{
+				"red":   numberRange(0, 1, "Red component."),
+				"green": numberRange(0, 1, "Green component."),
+				"blue":  numberRange(0, 1, "Blue component."),
+				"alpha": numberRange(0, 1, "Opacity; omit for a solid color."),
+			}
+		},
+	},
+	"google.type.PostalAddress": {
+		desc:     "Postal address (google.type.PostalAddress). region_code is required; put street lines in address_lines.",
+		example:  map[string]any{"region_code": "US", "postal_code": "94043", "administrative_area": "CA", "locality": "Mountain View", "address_lines": []string{"1600 Amphitheatre Pkwy"}},
+		required: []string{"region_code"},
+		props: func(bool) map[string]any {
+			str := func(desc string) map[string]any { return map[string]any{"type": "string", "description": desc} }
+			lines := func(desc string) map[string]any {
+				return map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": desc}
+			}
+			return map[string]any{
+				"region_code":         map[string]any{"type": "string", "pattern": "^[A-Z]{2}$", "description": "CLDR region code of the country, e.g. US or CH."},
+				"language_code":       str("BCP-47 language code of the address contents, e.g. en or zh-Hant."),
+				"postal_code":         str("Postal code."),
+				"sorting_code":        str("Additional country-specific sorting code."),
+				"administrative_area": str("State, province or region."),
+				"locality":            str("City or town."),
+				"sublocality":         str("Neighborhood, suburb or district."),
+				"address_lines":       lines("Street address lines, most specific first."),
+				"recipients":          lines("Recipients at the address."),
+				"organization":        str("Organization at the address."),
+			}
+		},
+	},
+	"google.type.Interval": {
+		desc:    "Time interval (google.type.Interval): start inclusive, end exclusive, as RFC 3339 timestamps. Omit start or end for an open interval.",
+		example: map[string]any{"start_time": "2026-10-01T00:00:00Z", "end_time": "2026-11-01T00:00:00Z"},
+		props: func(bool) map[string]any {
+			return map[string]any{
+				"start_time": map[string]any{"type": "string", "format": "date-time", "description": "Inclusive start."},
+				"end_time":   map[string]any{"type": "string", "format": "date-time", "description": "Exclusive end."},
+			}
+		},
+	},
+	"google.type.DateTime": {
+		desc:    "Civil date and time (google.type.DateTime) with either a UTC offset or an IANA time zone; set neither for local time.",
+		example: map[string]any{"year": 2026, "month": 10, "day": 17, "hours": 14, "minutes": 30, "time_zone": map[string]any{"id": "Europe/Paris"}},
+		props: func(openAI bool) map[string]any {
+			zone := googleTypeObject(openAI, "IANA time zone.", nil, map[string]any{
+				"id":      map[string]any{"type": "string", "description": "IANA time zone ID, e.g. America/New_York."},
+				"version": map[string]any{"type": "string", "description": "Optional time zone database version, e.g. 2019a."},
+			}, []string{"id"})
+			return map[string]any{
+				"year":       intRange(0, 9999, "Year, or 0 for a date-time without a year."),
+				"month":      intRange(1, 12, "Month of the year."),
+				"day":        intRange(1, 31, "Day of the month."),
+				"hours":      intRange(0, 23, "Hours."),
+				"minutes":    intRange(0, 59, "Minutes."),
+				"seconds":    intRange(0, 60, "Seconds."),
+				"nanos":      intRange(0, 999999999, "Fractions of a second in nanoseconds."),
+				"utc_offset": map[string]any{"type": "string", "pattern": `^-?[0-9]+(\.[0-9]+)?s$`, "description": "UTC offset as a duration, e.g. \"-18000s\" for UTC-5."},
+				"time_zone":  zone,
+			}
+		},
+	},
+	"google.type.Decimal": {
+		desc:     "Arbitrary-precision decimal number (google.type.Decimal) as a string, e.g. \"12.50\" or \"-1.5e-3\".",
+		example:  map[string]any{"value": "12.50"},
+		required: []string{"value"},
+		props: func(bool) map[string]any {
+			return map[string]any{
+				"value": map[string]any{"type": "string", "pattern": `^[+-]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][+-]?[0-9]+)?$`, "description": "Decimal value."},
+			}
+		},
+	},
+}
+
+// googleTypeFieldSchema returns the schema of a google.type message, or nil
+// if name is not one.
+func googleTypeFieldSchema(name string, openAI bool) map[string]any {
+	t, ok := googleTypeSchemas[name]
+	if !ok {
+		return nil
+	}
+	return googleTypeObject(openAI, t.desc, t.example, t.props(openAI), t.required)
+}
+
+// googleTypeObject builds an object schema. In strict mode every property is
+// required and the ones that were optional accept null.
+func googleTypeObject(openAI bool, desc string, example map[string]any, props map[string]any, required []string) map[string]any {
+	s := map[string]any{"type": "object", "description": desc, "properties": props}
+	if example != nil {
+		s["examples"] = []any{example}
+	}
+	if openAI {
+		isRequired := map[string]bool{}
+		for _, r := range required {
+			isRequired[r] = true
+		}
+		all := make([]string, 0, len(props))
+		for name, p := range props {
+			all = append(all, name)
+			if ps := p.(map[string]any); !isRequired[name] {
+				if t, ok := ps["type"].(string); ok {
+					ps["type"] = []string{t, "null"}
+				}
+			}
+		}
+		sort.Strings(all)
+		s["required"] = all
+		s["additionalProperties"] = false
+		s["type"] = []string{"object", "null"}
+		return s
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// googleTypeNote returns the description of a google.type message, kept
+// alongside a field's own description.
+func googleTypeNote(name string) string {
+	return googleTypeSchemas[name].desc
+}
+
+func intRange(min, max int, desc string) map[string]any {
+	return map[string]any{"type": "integer", "minimum": min, "maximum": max, "description": desc}
+}
+
+func numberRange(min, max float64, desc string) map[string]any {
+	return map[string]any{"type": "number", "minimum": min, "maximum": max, "description": desc}
+}
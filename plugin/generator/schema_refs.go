@@ -0,0 +1,198 @@
+package generator
+
+// schema_refs.go decides which message types become shared $defs entries
+// and builds the root input schema for a tool.
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// DefaultSchemaMaxDepth is the default message nesting depth after which
+// inlined schemas are cut off.
+const DefaultSchemaMaxDepth = 8
+
+// SchemaOptions controls how input schemas are built.
+type SchemaOptions struct {
+	// Inline expands every nested message in place instead of emitting $defs
+	// and $ref, for clients that cannot resolve references. Recursive types
+	// are expanded up to MaxDepth.
+	Inline bool
+	// MaxDepth is the message nesting depth after which a nested message is
+	// emitted as a plain object. Zero means DefaultSchemaMaxDepth.
+	MaxDepth int
+	// Strict emits schemas for OpenAI-compatible strict mode: every property
+	// required, optional values nullable, additionalProperties false, maps as
+	// key/value arrays and Struct values as JSON-encoded strings.
+	Strict bool
+	// FriendlyEnums lists enum values by friendly lowercase name without the
+	// type prefix, as in elicitation forms, and leaves out UNSPECIFIED.
+	FriendlyEnums bool
+	// Int64 is the JSON type of 64-bit integer fields: Int64String (the
+	// default, as protojson encodes them) or Int64Integer.
+	Int64 string
+	// OneofVariants adds a discriminated oneOf (one variant per member) for
+	// each oneof group, on top of listing the members in properties. Strict
+	// schemas, which do not allow oneOf, ignore it.
+	OneofVariants bool
+}
+
+// JSON types for 64-bit integer fields, see SchemaOptions.Int64.
+const (
+	Int64String  = "string"
+	Int64Integer = "integer"
+)
+
+func (o SchemaOptions) maxDepth() int {
+	if o.MaxDepth <= 0 {
+		return DefaultSchemaMaxDepth
+	}
+	return o.MaxDepth
+}
+
+// goOnlyParams returns the plugin parameters set in o that only the Go
+// runtime supports: it normalizes the arguments their schemas describe back
+// into protojson, which the other runtimes do not.
+func (o SchemaOptions) goOnlyParams() []string {
+	var params []string
+	if o.Strict {
+		params = append(params, "schema_strict")
+	}
+	if o.FriendlyEnums {
+		params = append(params, "schema_friendly_enums")
+	}
+	if o.OneofVariants {
+		params = append(params, "schema_oneof_variants")
+	}
+	return params
+}
+
+// int64AsInteger reports whether 64-bit integers are typed as JSON integers.
+func (o SchemaOptions) int64AsInteger() bool {
+	return o.Int64 == Int64Integer
+}
+
+// kindToType maps a protobuf scalar kind to its JSON Schema type. Tool,
+// elicitation and prompt schemas all use it, so a field has the same type
+// everywhere.
+func (o SchemaOptions) kindToType(kind protoreflect.Kind) string {
+	if is64BitInt(kind) && o.int64AsInteger() {
+		return "integer"
+	}
+	return kindToType(kind)
+}
+
+// Standard methods (AIP-131..135) whose field behaviors change the input schema.
+const (
+	standardCreate = "Create"
+	standardUpdate = "Update"
+)
+
+// schemaBuilder builds the JSON Schema of one tool input. Message types that
+// are recursive or used by more than one field are emitted once under $defs
+// and referenced with $ref, unless opts.Inline is set.
+type schemaBuilder struct {
+	opts     SchemaOptions
+	openAI   bool
+	standard string // standardCreate, standardUpdate or ""
+	shared   map[protoreflect.FullName]bool
+	defs     map[string]any
+	depth    int
+}
+
+// inputSchema returns the tool input schema for the request message of meth,
+// with desc as the root description and any shared message types under $defs.
+func inputSchema(meth protoreflect.MethodDescriptor, opts SchemaOptions, desc string) map[string]any {
+	md := meth.Input()
+	b := &schemaBuilder{opts: opts, openAI: opts.Strict, defs: map[string]any{}}
+	for _, std := range []string{standardCreate, standardUpdate} {
+		if strings.HasPrefix(string(meth.Name()), std) {
+			b.standard = std
+		}
+	}
+	if !opts.Inline {
+		b.shared = sharedMessages(md)
+	}
+	schema := b.messageSchema(md, desc)
+	// Tool input schemas must be objects, not nullable.
+	schema["type"] = "object"
+	if len(b.defs) > 0 {
+		schema["$defs"] = b.defs
+	}
+	return schema
+}
+
+// messageRef returns the schema of a message-typed field: a $ref to its
+// $defs entry for shared types, otherwise the inlined message schema.
+func (b *schemaBuilder) messageRef(md protoreflect.MessageDescriptor) map[string]any {
+	name := string(md.FullName())
+	if b.shared[md.FullName()] {
+		if _, ok := b.defs[name]; !ok {
+			b.defs[name] = nil // reserve the entry so recursive fields stop here
+			depth := b.depth
+			b.depth = 0
+			b.defs[name] = b.messageSchema(md, "")
+			b.depth = depth
+		}
+		return map[string]any{"$ref": "#/$defs/" + name}
+	}
+	if b.depth >= b.opts.maxDepth() {
+		return map[string]any{
+			"type":        "object",
+			"description": fmt.Sprintf("%s (schema depth limit reached; fields not listed).", md.Name()),
+		}
+	}
+	b.depth++
+	defer func() { b.depth-- }()
+	return b.messageSchema(md, "")
+}
+
+// sharedMessages walks the message types reachable from root and returns
+// those that are recursive or referenced by more than one field.
+func sharedMessages(root protoreflect.MessageDescriptor) map[protoreflect.FullName]bool {
+	uses := map[protoreflect.FullName]int{}
+	shared := map[protoreflect.FullName]bool{}
+	visited := map[protoreflect.FullName]bool{}
+	onStack := map[protoreflect.FullName]bool{}
+	var walk func(md protoreflect.MessageDescriptor)
+	walk = func(md protoreflect.MessageDescriptor) {
+		visited[md.FullName()] = true
+		onStack[md.FullName()] = true
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			if hasFieldBehavior(fd, annotations.FieldBehavior_OUTPUT_ONLY) {
+				continue
+			}
+			if fd.IsMap() {
+				fd = fd.MapValue()
+			}
+			nested := fd.Message()
+			if nested == nil || wellKnownSchemaTypes[string(nested.FullName())] {
+				continue
+			}
+			name := nested.FullName()
+			uses[name]++
+			if uses[name] > 1 || onStack[name] {
+				shared[name] = true
+			}
+			if !visited[name] {
+				walk(nested)
+			}
+		}
+		onStack[md.FullName()] = false
+	}
+	walk(root)
+	return shared
+}
+
+// hoistDefs moves the $defs of a schema embedded in dst up to dst's root.
+func hoistDefs(dst, embedded map[string]any) {
+	if defs, ok := embedded["$defs"]; ok {
+		delete(embedded, "$defs")
+		dst["$defs"] = defs
+	}
+}
@@ -0,0 +1,632 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	_ "google.golang.org/protobuf/types/known/structpb"
+)
+
+// testFile builds a FileDescriptorProto in text format. Imports resolve
+// against the global registry, so buf/validate/validate.proto and
+// mcp/protobuf/*.proto work.
+func testFile(t *testing.T, file string) protoreflect.FileDescriptor {
+	t.Helper()
+	var fdp descriptorpb.FileDescriptorProto
+	if err := prototext.Unmarshal([]byte(file), &fdp); err != nil {
+		t.Fatal(err)
+	}
+	fd, err := protodesc.NewFile(&fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd
+}
+
+// testMethod returns the first method of the first service of testFile.
+func testMethod(t *testing.T, file string) protoreflect.MethodDescriptor {
+	t.Helper()
+	return testFile(t, file).Services().Get(0).Methods().Get(0)
+}
+
+// schemaProperty returns the property at a dotted path of schema,
+// following nested properties.
+func schemaProperty(t *testing.T, schema map[string]any, path string) map[string]any {
+	t.Helper()
+	for _, name := range strings.Split(path, ".") {
+		props, _ := schema["properties"].(map[string]any)
+		next, ok := props[name].(map[string]any)
+		if !ok {
+			t.Fatalf("no property %s in %s", name, mustJSON(schema))
+		}
+		schema = next
+	}
+	return schema
+}
+
+func mustJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+const validateTestFile = `
+name: "validate_test.proto"
+package: "validatetest"
+syntax: "proto3"
+dependency: "buf/validate/validate.proto"
+message_type {
+  name: "Request"
+  field { name: "count" number: 1 type: TYPE_INT32 label: LABEL_OPTIONAL json_name: "count"
+    options { [buf.validate.field] { int32 { gt: 0 lte: 100 } } } }
+  field { name: "size" number: 2 type: TYPE_INT64 label: LABEL_OPTIONAL json_name: "size"
+    options { [buf.validate.field] { int64 { gte: 5 lt: 10 } } } }
+  field { name: "ids" number: 3 type: TYPE_UINT64 label: LABEL_OPTIONAL json_name: "ids"
+    options { [buf.validate.field] { uint64 { in: [1, 2] } } } }
+  field { name: "email" number: 4 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "email"
+    options { [buf.validate.field] { string { email: true max_len: 64 } } } }
+}
+service { name: "Service" method { name: "Do" input_type: ".validatetest.Request" output_type: ".validatetest.Request" } }
+`
+
+func TestInputSchema_Protovalidate(t *testing.T) {
+	meth := testMethod(t, validateTestFile)
+	tests := []struct {
+		name     string
+		opts     SchemaOptions
+		field    string
+		want     map[string]any
+		absent   []string
+		wantNote string
+	}{
+		{
+			name:  "int32 bounds",
+			field: "count",
+			want:  map[string]any{"type": "integer", "minimum": float64(1), "maximum": float64(100)},
+		},
+		{
+			name:     "int64 bounds in string mode are notes",
+			field:    "size",
+			want:     map[string]any{"type": "string"},
+			absent:   []string{"minimum", "maximum", "exclusiveMaximum"},
+			wantNote: "Must be at least 5. Must be less than 10.",
+		},
+		{
+			name:  "int64 bounds in integer mode",
+			opts:  SchemaOptions{Int64: Int64Integer},
+			field: "size",
+			want:  map[string]any{"type": "integer", "minimum": float64(5), "maximum": float64(9)},
+		},
+		{
+			name:  "uint64 in as strings",
+			field: "ids",
+			want:  map[string]any{"type": "string", "enum": []any{"1", "2"}},
+		},
+		{
+			name:  "string rules",
+			field: "email",
+			want:  map[string]any{"format": "email", "maxLength": float64(64)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Round-trip through JSON so numbers compare as float64.
+			var schema map[string]any
+			if err := json.Unmarshal([]byte(mustJSON(inputSchema(meth, tt.opts, ""))), &schema); err != nil {
+				t.Fatal(err)
+			}
+			prop := schemaProperty(t, schema, tt.field)
+			for k, v := range tt.want {
+				if mustJSON(prop[k]) != mustJSON(v) {
+					t.Errorf("%s = %v, want %v", k, prop[k], v)
+				}
+			}
+			for _, k := range tt.absent {
+				if _, ok := prop[k]; ok {
+					t.Errorf("%s set in %s", k, mustJSON(prop))
+				}
+			}
+			if desc, _ := prop["description"].(string); !strings.Contains(desc, tt.wantNote) {
+				t.Errorf("description = %q, want it to contain %q", desc, tt.wantNote)
+			}
+		})
+	}
+}
+
+const strictTestFile = `
+name: "strict_test.proto"
+package: "stricttest"
+syntax: "proto3"
+dependency: "google/protobuf/struct.proto"
+message_type {
+  name: "Request"
+  field { name: "note" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "note" proto3_optional: true oneof_index: 0 }
+  field { name: "labels" number: 2 type: TYPE_MESSAGE label: LABEL_REPEATED json_name: "labels" type_name: ".stricttest.Request.LabelsEntry" }
+  field { name: "data" number: 3 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "data" type_name: ".google.protobuf.Struct" }
+  nested_type { name: "LabelsEntry" options { map_entry: true }
+    field { name: "key" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "key" }
+    field { name: "value" number: 2 type: TYPE_INT32 label: LABEL_OPTIONAL json_name: "value" } }
+  oneof_decl { name: "_note" }
+}
+service { name: "Service" method { name: "Do" input_type: ".stricttest.Request" output_type: ".stricttest.Request" } }
+`
+
+func TestInputSchema_Strict(t *testing.T) {
+	meth := testMethod(t, strictTestFile)
+	tests := []struct {
+		path string
+		key  string
+		want string
+	}{
+		{"", "required", `["note","labels","data"]`},
+		{"", "additionalProperties", `false`},
+		{"note", "type", `["string","null"]`},
+		{"labels", "type", `"array"`},
+		{"data", "type", `"string"`},
+	}
+	loose := inputSchema(meth, SchemaOptions{}, "")
+	strict := inputSchema(meth, SchemaOptions{Strict: true}, "")
+	for _, tt := range tests {
+		schema := strict
+		if tt.path != "" {
+			schema = schemaProperty(t, strict, tt.path)
+		}
+		if got := mustJSON(schema[tt.key]); got != tt.want {
+			t.Errorf("%s.%s = %s, want %s", tt.path, tt.key, got, tt.want)
+		}
+	}
+	if _, ok := loose["additionalProperties"]; ok {
+		t.Errorf("non-strict schema sets additionalProperties: %s", mustJSON(loose))
+	}
+}
+
+const enumTestFile = `
+name: "enum_test.proto"
+package: "enumtest"
+syntax: "proto3"
+enum_type {
+  name: "Priority"
+  value { name: "PRIORITY_UNSPECIFIED" number: 0 }
+  value { name: "PRIORITY_HIGH" number: 1 }
+  value { name: "PRIORITY_LOW" number: 2 }
+}
+message_type {
+  name: "Request"
+  field { name: "priority" number: 1 type: TYPE_ENUM label: LABEL_OPTIONAL json_name: "priority" type_name: ".enumtest.Priority" }
+}
+service { name: "Service" method { name: "Do" input_type: ".enumtest.Request" output_type: ".enumtest.Request" } }
+`
+
+func TestInputSchema_FriendlyEnums(t *testing.T) {
+	meth := testMethod(t, enumTestFile)
+	tests := []struct {
+		name string
+		opts SchemaOptions
+		key  string
+		want string
+	}{
+		{"proto names", SchemaOptions{}, "enum", `["PRIORITY_UNSPECIFIED","PRIORITY_HIGH","PRIORITY_LOW"]`},
+		{"friendly names", SchemaOptions{FriendlyEnums: true}, "oneOf", `[{"const":"high"},{"const":"low"}]`},
+		{"friendly names under strict", SchemaOptions{FriendlyEnums: true, Strict: true}, "enum", `["high","low"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prop := schemaProperty(t, inputSchema(meth, tt.opts, ""), "priority")
+			if got := mustJSON(prop[tt.key]); got != tt.want {
+				t.Errorf("%s = %s, want %s", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+const int64TestFile = `
+name: "int64_test.proto"
+package: "int64test"
+syntax: "proto2"
+message_type {
+  name: "Request"
+  field { name: "limit" number: 1 type: TYPE_INT64 label: LABEL_OPTIONAL json_name: "limit" default_value: "42" }
+  field { name: "mask" number: 2 type: TYPE_FIXED64 label: LABEL_OPTIONAL json_name: "mask" }
+}
+service { name: "Service" method { name: "Do" input_type: ".int64test.Request" output_type: ".int64test.Request" } }
+`
+
+func TestInputSchema_Int64(t *testing.T) {
+	meth := testMethod(t, int64TestFile)
+	tests := []struct {
+		name  string
+		opts  SchemaOptions
+		field string
+		key   string
+		want  string
+	}{
+		{"string type", SchemaOptions{}, "limit", "type", `"string"`},
+		{"string default", SchemaOptions{}, "limit", "default", `"42"`},
+		{"integer type", SchemaOptions{Int64: Int64Integer}, "limit", "type", `"integer"`},
+		{"integer default", SchemaOptions{Int64: Int64Integer}, "limit", "default", `42`},
+		{"unsigned pattern", SchemaOptions{}, "mask", "pattern", `"^(0|[1-9]\\d*)$"`},
+		{"unsigned minimum", SchemaOptions{Int64: Int64Integer}, "mask", "minimum", `0`},
+		{"strict default note", SchemaOptions{Int64: Int64Integer, Strict: true}, "limit", "default", `null`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prop := schemaProperty(t, inputSchema(meth, tt.opts, ""), tt.field)
+			if got := mustJSON(prop[tt.key]); got != tt.want {
+				t.Errorf("%s = %s, want %s", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaOptions_GoOnlyParams(t *testing.T) {
+	tests := []struct {
+		opts SchemaOptions
+		want string
+	}{
+		{SchemaOptions{}, ""},
+		{SchemaOptions{Inline: true, Int64: Int64Integer}, ""},
+		{SchemaOptions{Strict: true}, "schema_strict"},
+		{SchemaOptions{Strict: true, FriendlyEnums: true}, "schema_strict,schema_friendly_enums"},
+		{SchemaOptions{OneofVariants: true}, "schema_oneof_variants"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(tt.opts.goOnlyParams(), ","); got != tt.want {
+			t.Errorf("goOnlyParams(%+v) = %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+}
+
+const oneofTestFile = `
+name: "oneof_test.proto"
+package: "oneoftest"
+syntax: "proto3"
+message_type {
+  name: "Request"
+  field { name: "id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "id" oneof_index: 0 }
+  field { name: "name" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "name" oneof_index: 0 }
+  field { name: "note" number: 3 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "note" proto3_optional: true oneof_index: 1 }
+  oneof_decl { name: "key" }
+  oneof_decl { name: "_note" }
+}
+service { name: "Service" method { name: "Do" input_type: ".oneoftest.Request" output_type: ".oneoftest.Request" } }
+`
+
+func TestInputSchema_OneofVariants(t *testing.T) {
+	meth := testMethod(t, oneofTestFile)
+	tests := []struct {
+		name string
+		opts SchemaOptions
+		want string
+	}{
+		{"disabled", SchemaOptions{}, `null`},
+		{"variants", SchemaOptions{OneofVariants: true},
+			`[{"required":["id"],"title":"id"},{"required":["name"],"title":"name"},` +
+				`{"not":{"anyOf":[{"required":["id"]},{"required":["name"]}]},"title":"none"}]`},
+		{"left out under strict", SchemaOptions{OneofVariants: true, Strict: true}, `null`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mustJSON(inputSchema(meth, tt.opts, "")["oneOf"]); got != tt.want {
+				t.Errorf("oneOf = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+const defsTestFile = `
+name: "defs_test.proto"
+package: "defstest"
+syntax: "proto3"
+message_type {
+  name: "Ref"
+  field { name: "id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "id" }
+  field { name: "tenant" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "tenant"
+    options { [mcp.protobuf.field] { bind { header: "x-tenant-id" } } } }
+}
+message_type {
+  name: "Node"
+  field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "name" }
+  field { name: "children" number: 2 type: TYPE_MESSAGE label: LABEL_REPEATED json_name: "children" type_name: ".defstest.Node" }
+}
+message_type {
+  name: "Request"
+  field { name: "owner" number: 1 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "owner" type_name: ".defstest.Ref" }
+  field { name: "backup" number: 2 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "backup" type_name: ".defstest.Ref" }
+  field { name: "tree" number: 3 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "tree" type_name: ".defstest.Node" }
+}
+service { name: "Service" method { name: "Do" input_type: ".defstest.Request" output_type: ".defstest.Request" } }
+`
+
+func TestInputSchema_Defs(t *testing.T) {
+	meth := testMethod(t, defsTestFile)
+	tests := []struct {
+		name string
+		opts SchemaOptions
+		path string
+		want string
+	}{
+		{"shared type", SchemaOptions{}, "owner", `{"$ref":"#/$defs/defstest.Ref"}`},
+		{"recursive type", SchemaOptions{}, "tree", `{"$ref":"#/$defs/defstest.Node"}`},
+		{"inline", SchemaOptions{Inline: true}, "backup.id", `{"type":"string"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prop := schemaProperty(t, inputSchema(meth, tt.opts, ""), tt.path)
+			if got := mustJSON(prop); got != tt.want {
+				t.Errorf("%s = %s, want %s", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	schema := inputSchema(meth, SchemaOptions{}, "")
+	defs, _ := schema["$defs"].(map[string]any)
+	if len(defs) != 2 {
+		t.Fatalf("$defs = %s, want defstest.Ref and defstest.Node", mustJSON(defs))
+	}
+	ref := defs["defstest.Ref"].(map[string]any)
+	schemaProperty(t, ref, "id")
+	if props := ref["properties"].(map[string]any); props["tenant"] != nil {
+		t.Errorf("bound field kept in $defs: %s", mustJSON(ref))
+	}
+	if got := mustJSON(schemaProperty(t, defs["defstest.Node"].(map[string]any), "children")["items"]); got != `{"$ref":"#/$defs/defstest.Node"}` {
+		t.Errorf("Node.children items = %s", got)
+	}
+	if _, ok := inputSchema(meth, SchemaOptions{Inline: true}, "")["$defs"]; ok {
+		t.Error("inline schema has $defs")
+	}
+}
+
+func TestBoundField(t *testing.T) {
+	meth := testMethod(t, defsTestFile)
+	if fd := boundField(meth.Input()); fd == nil || fd.FullName() != "defstest.Ref.tenant" {
+		t.Errorf("boundField(Request) = %v, want defstest.Ref.tenant", fd)
+	}
+	node := meth.Input().Fields().ByName("tree").Message()
+	if fd := boundField(node); fd != nil {
+		t.Errorf("boundField(Node) = %s, want none", fd.FullName())
+	}
+}
+
+const defaultsTestFile = `
+name: "defaults_test.proto"
+package: "defaultstest"
+syntax: "proto2"
+enum_type {
+  name: "Mode"
+  value { name: "MODE_UNSPECIFIED" number: 0 }
+  value { name: "MODE_FAST" number: 1 }
+}
+message_type {
+  name: "Request"
+  field { name: "id" number: 1 type: TYPE_STRING label: LABEL_REQUIRED json_name: "id" }
+  field { name: "nan" number: 2 type: TYPE_DOUBLE label: LABEL_OPTIONAL json_name: "nan" default_value: "nan" }
+  field { name: "inf" number: 3 type: TYPE_FLOAT label: LABEL_OPTIONAL json_name: "inf" default_value: "inf" }
+  field { name: "neg_inf" number: 4 type: TYPE_DOUBLE label: LABEL_OPTIONAL json_name: "negInf" default_value: "-inf" }
+  field { name: "magic" number: 5 type: TYPE_BYTES label: LABEL_OPTIONAL json_name: "magic" default_value: "hi" }
+  field { name: "mode" number: 6 type: TYPE_ENUM label: LABEL_OPTIONAL json_name: "mode" type_name: ".defaultstest.Mode" }
+}
+service { name: "Service" method { name: "Do" input_type: ".defaultstest.Request" output_type: ".defaultstest.Request" } }
+`
+
+const editionsTestFile = `
+name: "editions_test.proto"
+package: "editionstest"
+syntax: "editions"
+edition: EDITION_2023
+message_type {
+  name: "Request"
+  field { name: "note" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "note" }
+  field { name: "title" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "title"
+    options { features { field_presence: IMPLICIT } } }
+}
+service { name: "Service" method { name: "Do" input_type: ".editionstest.Request" output_type: ".editionstest.Request" } }
+`
+
+func TestInputSchema_DefaultsAndPresence(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		opts SchemaOptions
+		path string
+		key  string
+		want string
+	}{
+		{"proto2 required", defaultsTestFile, SchemaOptions{}, "", "required", `["id"]`},
+		{"proto2 required under strict is not nullable", defaultsTestFile, SchemaOptions{Strict: true}, "id", "type", `"string"`},
+		{"proto2 optional under strict is nullable", defaultsTestFile, SchemaOptions{Strict: true}, "magic", "type", `["string","null"]`},
+		{"NaN default", defaultsTestFile, SchemaOptions{}, "nan", "default", `"NaN"`},
+		{"Infinity default", defaultsTestFile, SchemaOptions{}, "inf", "default", `"Infinity"`},
+		{"-Infinity default", defaultsTestFile, SchemaOptions{}, "neg_inf", "default", `"-Infinity"`},
+		{"bytes default", defaultsTestFile, SchemaOptions{}, "magic", "default", `"aGk="`},
+		{"closed enum defaults to its first value", defaultsTestFile, SchemaOptions{}, "mode", "default", `"MODE_UNSPECIFIED"`},
+		{"closed enum keeps its zero value", defaultsTestFile, SchemaOptions{FriendlyEnums: true}, "mode", "oneOf", `[{"const":"unspecified"},{"const":"fast"}]`},
+		{"editions explicit presence under strict", editionsTestFile, SchemaOptions{Strict: true}, "note", "type", `["string","null"]`},
+		{"editions implicit presence under strict", editionsTestFile, SchemaOptions{Strict: true}, "title", "type", `"string"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := inputSchema(testMethod(t, tt.file), tt.opts, "")
+			if tt.path != "" {
+				schema = schemaProperty(t, schema, tt.path)
+			}
+			if got := mustJSON(schema[tt.key]); got != tt.want {
+				t.Errorf("%s = %s, want %s", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+const fieldBehaviorTestFile = `
+name: "field_behavior_test.proto"
+package: "fieldbehaviortest"
+syntax: "proto3"
+dependency: "google/api/field_behavior.proto"
+message_type {
+  name: "Book"
+  field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "name"
+    options { [google.api.field_behavior]: IDENTIFIER } }
+  field { name: "create_time" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "createTime"
+    options { [google.api.field_behavior]: OUTPUT_ONLY } }
+  field { name: "isbn" number: 3 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "isbn"
+    options { [google.api.field_behavior]: IMMUTABLE } }
+  field { name: "notes" number: 4 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "notes"
+    options { [google.api.field_behavior]: OPTIONAL } }
+}
+message_type {
+  name: "BookRequest"
+  field { name: "book" number: 1 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "book" type_name: ".fieldbehaviortest.Book" }
+}
+service {
+  name: "Service"
+  method { name: "CreateBook" input_type: ".fieldbehaviortest.BookRequest" output_type: ".fieldbehaviortest.Book" }
+  method { name: "UpdateBook" input_type: ".fieldbehaviortest.BookRequest" output_type: ".fieldbehaviortest.Book" }
+}
+`
+
+func TestInputSchema_FieldBehavior(t *testing.T) {
+	methods := testFile(t, fieldBehaviorTestFile).Services().Get(0).Methods()
+	book := func(method string) map[string]any {
+		return schemaProperty(t, inputSchema(methods.ByName(protoreflect.Name(method)), SchemaOptions{}, ""), "book")
+	}
+	create, update := book("CreateBook"), book("UpdateBook")
+
+	for name, schema := range map[string]map[string]any{"CreateBook": create, "UpdateBook": update} {
+		if props := schema["properties"].(map[string]any); props["create_time"] != nil {
+			t.Errorf("%s: OUTPUT_ONLY field kept: %s", name, mustJSON(schema))
+		}
+	}
+	if props := create["properties"].(map[string]any); props["name"] != nil {
+		t.Errorf("CreateBook: IDENTIFIER field kept: %s", mustJSON(create))
+	}
+	if got := mustJSON(update["required"]); got != `["name"]` {
+		t.Errorf("UpdateBook: required = %s, want [\"name\"]", got)
+	}
+
+	notes := []struct{ field, want string }{
+		{"name", "Identifier: the resource name of this resource."},
+		{"isbn", "Immutable: can only be set when the resource is created."},
+		{"notes", "Optional."},
+	}
+	for _, tt := range notes {
+		if desc, _ := schemaProperty(t, update, tt.field)["description"].(string); !strings.Contains(desc, tt.want) {
+			t.Errorf("%s description = %q, want it to contain %q", tt.field, desc, tt.want)
+		}
+	}
+}
+
+const depthTestFile = `
+name: "depth_test.proto"
+package: "depthtest"
+syntax: "proto3"
+message_type {
+  name: "Level3"
+  field { name: "x" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "x" }
+}
+message_type {
+  name: "Level2"
+  field { name: "c" number: 1 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "c" type_name: ".depthtest.Level3" }
+}
+message_type {
+  name: "Level1"
+  field { name: "b" number: 1 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "b" type_name: ".depthtest.Level2" }
+}
+message_type {
+  name: "Request"
+  field { name: "a" number: 1 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "a" type_name: ".depthtest.Level1" }
+}
+service { name: "Service" method { name: "Do" input_type: ".depthtest.Request" output_type: ".depthtest.Request" } }
+`
+
+func TestInputSchema_MaxDepth(t *testing.T) {
+	const cutOff = "schema depth limit reached"
+	tests := []struct {
+		name    string
+		file    string
+		opts    SchemaOptions
+		path    string
+		wantCut bool
+	}{
+		{"default depth", depthTestFile, SchemaOptions{}, "a.b.c", false},
+		{"within max depth", depthTestFile, SchemaOptions{MaxDepth: 2}, "a.b", false},
+		{"beyond max depth", depthTestFile, SchemaOptions{MaxDepth: 2}, "a.b.c", true},
+		{"inline shared type", defsTestFile, SchemaOptions{Inline: true, MaxDepth: 1}, "owner", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prop := schemaProperty(t, inputSchema(testMethod(t, tt.file), tt.opts, ""), tt.path)
+			desc, _ := prop["description"].(string)
+			if got := strings.Contains(desc, cutOff); got != tt.wantCut {
+				t.Errorf("%s cut off = %v, want %v: %s", tt.path, got, tt.wantCut, mustJSON(prop))
+			}
+			if _, ok := prop["$ref"]; ok {
+				t.Errorf("%s is a $ref without shared types: %s", tt.path, mustJSON(prop))
+			}
+		})
+	}
+
+	// An inlined recursive type expands until MaxDepth, then stops.
+	tree := schemaProperty(t, inputSchema(testMethod(t, defsTestFile), SchemaOptions{Inline: true, MaxDepth: 2}, ""), "tree")
+	child := schemaProperty(t, tree, "children")["items"].(map[string]any)
+	grandchild := schemaProperty(t, child, "children")["items"].(map[string]any)
+	if desc, _ := grandchild["description"].(string); !strings.Contains(desc, cutOff) {
+		t.Errorf("Node nested past MaxDepth = %s, want it cut off", mustJSON(grandchild))
+	}
+}
+
+const commentsTestFile = `
+name: "comments_test.proto"
+package: "commentstest"
+syntax: "proto3"
+enum_type {
+  name: "Color"
+  value { name: "COLOR_UNSPECIFIED" number: 0 }
+  value { name: "COLOR_RED" number: 1 }
+}
+message_type {
+  name: "Inner"
+  field { name: "label" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "label" }
+}
+message_type {
+  name: "Request"
+  field { name: "inner" number: 1 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "inner" type_name: ".commentstest.Inner" }
+  field { name: "color" number: 2 type: TYPE_ENUM label: LABEL_OPTIONAL json_name: "color" type_name: ".commentstest.Color" }
+}
+service { name: "Service" method { name: "Do" input_type: ".commentstest.Request" output_type: ".commentstest.Request" } }
+source_code_info {
+  location { path: [5, 0, 2, 1] span: [3, 2, 16] leading_comments: " Like a fire truck.\n" }
+  location { path: [4, 0] span: [6, 0, 8, 1] leading_comments: " A nested message.\n" }
+  location { path: [4, 0, 2, 0] span: [7, 2, 19] trailing_comments: " Printed on the label.\n" }
+  location { path: [4, 1] span: [10, 0, 13, 1] leading_comments: " The request.\n" }
+}
+`
+
+func TestInputSchema_Comments(t *testing.T) {
+	schema := inputSchema(testMethod(t, commentsTestFile), SchemaOptions{}, "")
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"message", "", "The request."},
+		{"nested message", "inner", "A nested message."},
+		{"nested field", "inner.label", "Printed on the label."},
+		{"enum value", "color", "COLOR_RED: Like a fire truck."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prop := schema
+			if tt.path != "" {
+				prop = schemaProperty(t, schema, tt.path)
+			}
+			if desc, _ := prop["description"].(string); !strings.Contains(desc, tt.want) {
+				t.Errorf("description = %q, want it to contain %q", desc, tt.want)
+			}
+		})
+	}
+}
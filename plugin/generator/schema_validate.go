@@ -0,0 +1,517 @@
+package generator
+
+// schema_validate.go converts buf.validate (protovalidate) rules into JSON
+// Schema keywords. Rules without a JSON Schema equivalent, such as CEL
+// expressions and timestamp bounds, are rendered as description notes.
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// validateRules returns the buf.validate.field rules of fd, or nil.
+func validateRules(fd protoreflect.FieldDescriptor) *validate.FieldRules {
+	if !proto.HasExtension(fd.Options(), validate.E_Field) {
+		return nil
+	}
+	rules, _ := proto.GetExtension(fd.Options(), validate.E_Field).(*validate.FieldRules)
+	return rules
+}
+
+// isOneofRequired reports whether oo has buf.validate.oneof.required set.
+func isOneofRequired(oo protoreflect.OneofDescriptor) bool {
+	if !proto.HasExtension(oo.Options(), validate.E_Oneof) {
+		return false
+	}
+	rules, _ := proto.GetExtension(oo.Options(), validate.E_Oneof).(*validate.OneofRules)
+	return rules.GetRequired()
+}
+
+// isValidateRequired reports whether fd has buf.validate.field.required set.
+func isValidateRequired(fd protoreflect.FieldDescriptor) bool {
+	return validateRules(fd).GetRequired()
+}
+
+// extractValidateConstraints converts the rules for a single value of fd into
+// JSON Schema constraints and description notes. For repeated fields, pass
+// the repeated.items rules.
+func (b *schemaBuilder) extractValidateConstraints(fd protoreflect.FieldDescriptor, rules *validate.FieldRules) (map[string]any, []string) {
+	constraints := make(map[string]any)
+	if rules == nil {
+		return constraints, nil
+	}
+	var notes []string
+
+	if sr := rules.GetString(); sr != nil {
+		notes = stringConstraints(sr, constraints)
+	}
+	if br := rules.GetBytes(); br != nil {
+		notes = bytesConstraints(br, constraints)
+	}
+	if er := rules.GetEnum(); er != nil && fd.Enum() != nil {
+		enumConstraints(fd.Enum(), er, constraints)
+	}
+	if tr := rules.GetTimestamp(); tr != nil {
+		notes = timestampNotes(tr)
+	}
+	if dr := rules.GetDuration(); dr != nil {
+		notes = durationNotes(dr)
+	}
+	if nr := numericRules(rules); nr != nil {
+		notes = append(notes, numericConstraints(nr, b.opts.kindToType(fd.Kind()) == "string", constraints)...)
+	}
+	return constraints, append(notes, celNotes(rules.GetCel(), rules.GetCelExpression())...)
+}
+
+// numericRules returns the rules message of the numeric type case
+// (float, double, int32, ..., sfixed64) of rules, or nil.
+func numericRules(rules *validate.FieldRules) protoreflect.Message {
+	m := rules.ProtoReflect()
+	fd := m.WhichOneof(m.Descriptor().Oneofs().ByName("type"))
+	if fd == nil || fd.Message() == nil {
+		return nil
+	}
+	switch fd.Name() {
+	case "float", "double", "int32", "int64", "uint32", "uint64",
+		"sint32", "sint64", "fixed32", "fixed64", "sfixed32", "sfixed64":
+		return m.Get(fd).Message()
+	}
+	return nil
+}
+
+// numericConstraints maps const, gt/gte/lt/lte and in/not_in of a numeric
+// rules message. Exclusive integer bounds become inclusive ones. Values of
+// 64-bit integers encoded as JSON strings are rendered as strings, and
+// their bounds, which JSON Schema only defines for numbers, are returned as
+// description notes.
+func numericConstraints(r protoreflect.Message, asString bool, c map[string]any) []string {
+	get := func(name protoreflect.Name) (protoreflect.Value, bool) {
+		fd := r.Descriptor().Fields().ByName(name)
+		if fd == nil || !r.Has(fd) {
+			return protoreflect.Value{}, false
+		}
+		return r.Get(fd), true
+	}
+	value := func(v protoreflect.Value) any {
+		if asString {
+			return fmt.Sprint(v.Interface())
+		}
+		return v.Interface()
+	}
+	list := func(v protoreflect.Value) []any {
+		out := make([]any, v.List().Len())
+		for i := range out {
+			out[i] = value(v.List().Get(i))
+		}
+		return out
+	}
+	if v, ok := get("const"); ok {
+		c["const"] = value(v)
+	}
+	if v, ok := get("in"); ok && v.List().Len() > 0 {
+		c["enum"] = list(v)
+	}
+	if v, ok := get("not_in"); ok && v.List().Len() > 0 {
+		c["not"] = map[string]any{"enum": list(v)}
+	}
+	if asString {
+		var notes []string
+		if v, ok := get("gt"); ok {
+			notes = append(notes, fmt.Sprintf("Must be greater than %v.", v.Interface()))
+		} else if v, ok := get("gte"); ok {
+			notes = append(notes, fmt.Sprintf("Must be at least %v.", v.Interface()))
+		}
+		if v, ok := get("lt"); ok {
+			notes = append(notes, fmt.Sprintf("Must be less than %v.", v.Interface()))
+		} else if v, ok := get("lte"); ok {
+			notes = append(notes, fmt.Sprintf("Must be at most %v.", v.Interface()))
+		}
+		return notes
+	}
+	if v, ok := get("gt"); ok {
+		setExclusiveBound(c, "minimum", "exclusiveMinimum", v.Interface(), 1)
+	} else if v, ok := get("gte"); ok {
+		c["minimum"] = v.Interface()
+	}
+	if v, ok := get("lt"); ok {
+		setExclusiveBound(c, "maximum", "exclusiveMaximum", v.Interface(), -1)
+	} else if v, ok := get("lte"); ok {
+		c["maximum"] = v.Interface()
+	}
+	return nil
+}
+
+// setExclusiveBound sets an exclusive bound: integers are shifted by delta
+// into an inclusive bound, floats use the exclusive keyword.
+func setExclusiveBound(c map[string]any, inclusive, exclusive string, v any, delta int64) {
+	switch n := v.(type) {
+	case int32:
+		c[inclusive] = int64(n) + delta
+	case int64:
+		c[inclusive] = n + delta
+	case uint32:
+		c[inclusive] = int64(n) + delta
+	case uint64:
+		if delta > 0 {
+			c[inclusive] = n + 1
+		} else {
+			c[inclusive] = n - 1
+		}
+	default:
+		c[exclusive] = v
+	}
+}
+
+// stringFormats maps buf.validate string well-known rules to JSON Schema formats.
+var stringFormats = []struct {
+	set    func(*validate.StringRules) bool
+	format string
+}{
+	{(*validate.StringRules).GetEmail, "email"},
+	{(*validate.StringRules).GetHostname, "hostname"},
+	{(*validate.StringRules).GetIpv4, "ipv4"},
+	{(*validate.StringRules).GetIpv6, "ipv6"},
+	{(*validate.StringRules).GetUri, "uri"},
+	{(*validate.StringRules).GetUriRef, "uri-reference"},
+	{(*validate.StringRules).GetUuid, "uuid"},
+}
+
+// stringNotes maps string well-known rules without a JSON Schema format to notes.
+var stringNotes = []struct {
+	set  func(*validate.StringRules) bool
+	note string
+}{
+	{(*validate.StringRules).GetIp, "Must be an IPv4 or IPv6 address."},
+	{(*validate.StringRules).GetAddress, "Must be a hostname or IP address."},
+	{(*validate.StringRules).GetHostAndPort, "Must be a host and port, e.g. example.com:8080."},
+	{(*validate.StringRules).GetIpWithPrefixlen, "Must be an IP address with prefix length, e.g. 192.168.0.1/24."},
+	{(*validate.StringRules).GetIpv4WithPrefixlen, "Must be an IPv4 address with prefix length."},
+	{(*validate.StringRules).GetIpv6WithPrefixlen, "Must be an IPv6 address with prefix length."},
+	{(*validate.StringRules).GetIpPrefix, "Must be an IP prefix, e.g. 192.168.0.0/16."},
+	{(*validate.StringRules).GetIpv4Prefix, "Must be an IPv4 prefix."},
+	{(*validate.StringRules).GetIpv6Prefix, "Must be an IPv6 prefix."},
+	{(*validate.StringRules).GetProtobufFqn, "Must be a fully-qualified protobuf name."},
+	{(*validate.StringRules).GetProtobufDotFqn, "Must be a fully-qualified protobuf name with a leading dot."},
+}
+
+func stringConstraints(sr *validate.StringRules, c map[string]any) []string {
+	var notes []string
+	if sr.HasConst() {
+		c["const"] = sr.GetConst()
+	}
+	for _, f := range stringFormats {
+		if f.set(sr) {
+			c["format"] = f.format
+		}
+	}
+	for _, n := range stringNotes {
+		if n.set(sr) {
+			notes = append(notes, n.note)
+		}
+	}
+	var patterns []string
+	if p := sr.GetPattern(); p != "" {
+		patterns = append(patterns, p)
+	}
+	if sr.HasPrefix() {
+		patterns = append(patterns, "^"+regexp.QuoteMeta(sr.GetPrefix()))
+	}
+	if sr.HasSuffix() {
+		patterns = append(patterns, regexp.QuoteMeta(sr.GetSuffix())+"$")
+	}
+	if sr.HasContains() {
+		patterns = append(patterns, regexp.QuoteMeta(sr.GetContains()))
+	}
+	if sr.GetTuuid() {
+		patterns = append(patterns, "^[0-9a-fA-F]{32}$")
+	}
+	if sr.GetUlid() {
+		patterns = append(patterns, "^[0-7][0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{25}$")
+	}
+	setPatterns(c, patterns)
+	if sr.HasNotContains() {
+		notes = append(notes, fmt.Sprintf("Must not contain %q.", sr.GetNotContains()))
+	}
+	if sr.HasLen() {
+		c["minLength"] = int(sr.GetLen())
+		c["maxLength"] = int(sr.GetLen())
+	}
+	if sr.HasMinLen() {
+		c["minLength"] = int(sr.GetMinLen())
+	}
+	if sr.HasMaxLen() {
+		c["maxLength"] = int(sr.GetMaxLen())
+	}
+	if sr.HasLenBytes() {
+		notes = append(notes, fmt.Sprintf("Must be exactly %d bytes long.", sr.GetLenBytes()))
+	}
+	if sr.HasMinBytes() {
+		notes = append(notes, fmt.Sprintf("Must be at least %d bytes long.", sr.GetMinBytes()))
+	}
+	if sr.HasMaxBytes() {
+		notes = append(notes, fmt.Sprintf("Must be at most %d bytes long.", sr.GetMaxBytes()))
+	}
+	if in := sr.GetIn(); len(in) > 0 {
+		c["enum"] = in
+	}
+	if notIn := sr.GetNotIn(); len(notIn) > 0 {
+		c["not"] = map[string]any{"enum": notIn}
+	}
+	return notes
+}
+
+// setPatterns sets the first pattern as "pattern" and requires the rest
+// through allOf, since a schema holds a single pattern.
+func setPatterns(c map[string]any, patterns []string) {
+	if len(patterns) == 0 {
+		return
+	}
+	c["pattern"] = patterns[0]
+	if len(patterns) > 1 {
+		var all []map[string]any
+		for _, p := range patterns[1:] {
+			all = append(all, map[string]any{"pattern": p})
+		}
+		c["allOf"] = all
+	}
+}
+
+// bytesConstraints describes bytes rules. Values are base64 in JSON, so
+// lengths and contents are given as notes on the decoded bytes.
+func bytesConstraints(br *validate.BytesRules, c map[string]any) []string {
+	var notes []string
+	if br.HasConst() {
+		c["const"] = base64.StdEncoding.EncodeToString(br.GetConst())
+	}
+	if br.HasLen() {
+		notes = append(notes, fmt.Sprintf("Must decode to exactly %d bytes.", br.GetLen()))
+	}
+	if br.HasMinLen() {
+		notes = append(notes, fmt.Sprintf("Must decode to at least %d bytes.", br.GetMinLen()))
+	}
+	if br.HasMaxLen() {
+		notes = append(notes, fmt.Sprintf("Must decode to at most %d bytes.", br.GetMaxLen()))
+	}
+	if br.HasPattern() {
+		notes = append(notes, fmt.Sprintf("Decoded bytes must match %q.", br.GetPattern()))
+	}
+	if br.HasPrefix() {
+		notes = append(notes, fmt.Sprintf("Decoded bytes must start with 0x%x.", br.GetPrefix()))
+	}
+	if br.HasSuffix() {
+		notes = append(notes, fmt.Sprintf("Decoded bytes must end with 0x%x.", br.GetSuffix()))
+	}
+	if br.HasContains() {
+		notes = append(notes, fmt.Sprintf("Decoded bytes must contain 0x%x.", br.GetContains()))
+	}
+	switch {
+	case br.GetIp():
+		notes = append(notes, "Must decode to an IPv4 or IPv6 address (4 or 16 bytes).")
+	case br.GetIpv4():
+		notes = append(notes, "Must decode to an IPv4 address (4 bytes).")
+	case br.GetIpv6():
+		notes = append(notes, "Must decode to an IPv6 address (16 bytes).")
+	case br.GetUuid():
+		notes = append(notes, "Must decode to a UUID (16 bytes).")
+	}
+	encode := func(values [][]byte) []string {
+		out := make([]string, len(values))
+		for i, v := range values {
+			out[i] = base64.StdEncoding.EncodeToString(v)
+		}
+		return out
+	}
+	if in := br.GetIn(); len(in) > 0 {
+		c["enum"] = encode(in)
+	}
+	if notIn := br.GetNotIn(); len(notIn) > 0 {
+		c["not"] = map[string]any{"enum": encode(notIn)}
+	}
+	return notes
+}
+
+// enumConstraints narrows the enum value names by const, in and not_in.
+// defined_only needs nothing: the schema only lists defined values.
+func enumConstraints(ed protoreflect.EnumDescriptor, er *validate.EnumRules, c map[string]any) {
+	name := func(n int32) string {
+		if v := ed.Values().ByNumber(protoreflect.EnumNumber(n)); v != nil {
+			return string(v.Name())
+		}
+		return strconv.Itoa(int(n))
+	}
+	if er.HasConst() {
+		c["const"] = name(er.GetConst())
+	}
+	var names []string
+	if in := er.GetIn(); len(in) > 0 {
+		for _, n := range in {
+			names = append(names, name(n))
+		}
+	} else if notIn := er.GetNotIn(); len(notIn) > 0 {
+		excluded := map[protoreflect.EnumNumber]bool{}
+		for _, n := range notIn {
+			excluded[protoreflect.EnumNumber(n)] = true
+		}
+		for i := 0; i < ed.Values().Len(); i++ {
+			if v := ed.Values().Get(i); !excluded[v.Number()] {
+				names = append(names, string(v.Name()))
+			}
+		}
+	}
+	if names != nil {
+		c["enum"] = names
+	}
+}
+
+func timestampNotes(tr *validate.TimestampRules) []string {
+	format := func(ts *timestamppb.Timestamp) string {
+		return ts.AsTime().UTC().Format("2006-01-02T15:04:05Z07:00")
+	}
+	var notes []string
+	if tr.HasConst() {
+		notes = append(notes, "Must be "+format(tr.GetConst())+".")
+	}
+	switch {
+	case tr.HasGt():
+		notes = append(notes, "Must be after "+format(tr.GetGt())+".")
+	case tr.HasGte():
+		notes = append(notes, "Must be at or after "+format(tr.GetGte())+".")
+	case tr.GetGtNow():
+		notes = append(notes, "Must be in the future.")
+	}
+	switch {
+	case tr.HasLt():
+		notes = append(notes, "Must be before "+format(tr.GetLt())+".")
+	case tr.HasLte():
+		notes = append(notes, "Must be at or before "+format(tr.GetLte())+".")
+	case tr.GetLtNow():
+		notes = append(notes, "Must be in the past.")
+	}
+	if tr.HasWithin() {
+		notes = append(notes, "Must be within "+formatDuration(tr.GetWithin())+" of the current time.")
+	}
+	return notes
+}
+
+func durationNotes(dr *validate.DurationRules) []string {
+	var notes []string
+	if dr.HasConst() {
+		notes = append(notes, "Must be "+formatDuration(dr.GetConst())+".")
+	}
+	switch {
+	case dr.HasGt():
+		notes = append(notes, "Must be longer than "+formatDuration(dr.GetGt())+".")
+	case dr.HasGte():
+		notes = append(notes, "Must be at least "+formatDuration(dr.GetGte())+".")
+	}
+	switch {
+	case dr.HasLt():
+		notes = append(notes, "Must be shorter than "+formatDuration(dr.GetLt())+".")
+	case dr.HasLte():
+		notes = append(notes, "Must be at most "+formatDuration(dr.GetLte())+".")
+	}
+	list := func(values []*durationpb.Duration) string {
+		out := make([]string, len(values))
+		for i, v := range values {
+			out[i] = formatDuration(v)
+		}
+		return strings.Join(out, ", ")
+	}
+	if in := dr.GetIn(); len(in) > 0 {
+		notes = append(notes, "Must be one of "+list(in)+".")
+	}
+	if notIn := dr.GetNotIn(); len(notIn) > 0 {
+		notes = append(notes, "Must not be any of "+list(notIn)+".")
+	}
+	return notes
+}
+
+// formatDuration renders a Duration in its JSON form, e.g. "1.5s".
+func formatDuration(d *durationpb.Duration) string {
+	return strconv.FormatFloat(d.AsDuration().Seconds(), 'f', -1, 64) + "s"
+}
+
+// repeatedConstraints applies repeated rules to an array schema.
+func repeatedConstraints(rr *validate.RepeatedRules, schema map[string]any) {
+	if rr == nil {
+		return
+	}
+	if rr.HasMinItems() {
+		schema["minItems"] = int(rr.GetMinItems())
+	}
+	if rr.HasMaxItems() {
+		schema["maxItems"] = int(rr.GetMaxItems())
+	}
+	if rr.GetUnique() {
+		schema["uniqueItems"] = true
+	}
+}
+
+// mapConstraints applies map rules to a map schema, which is an object, or
+// an array of key/value pairs in OpenAI mode.
+func mapConstraints(mr *validate.MapRules, schema map[string]any) {
+	if mr == nil {
+		return
+	}
+	minKey, maxKey := "minProperties", "maxProperties"
+	if schema["type"] == "array" {
+		minKey, maxKey = "minItems", "maxItems"
+	}
+	if mr.HasMinPairs() {
+		schema[minKey] = int(mr.GetMinPairs())
+	}
+	if mr.HasMaxPairs() {
+		schema[maxKey] = int(mr.GetMaxPairs())
+	}
+}
+
+// celNotes renders CEL rules as description notes, using the rule message
+// when there is one.
+func celNotes(rules []*validate.Rule, expressions []string) []string {
+	var notes []string
+	for _, r := range rules {
+		msg := strings.TrimSuffix(strings.TrimSpace(r.GetMessage()), ".")
+		switch {
+		case msg != "" && r.GetExpression() != "":
+			notes = append(notes, fmt.Sprintf("%s (CEL: `%s`).", msg, r.GetExpression()))
+		case r.GetExpression() != "":
+			notes = append(notes, fmt.Sprintf("Must satisfy CEL: `%s`.", r.GetExpression()))
+		case msg != "":
+			notes = append(notes, msg+".")
+		}
+	}
+	for _, expr := range expressions {
+		notes = append(notes, fmt.Sprintf("Must satisfy CEL: `%s`.", expr))
+	}
+	return notes
+}
+
+// messageValidateNotes renders buf.validate.message CEL rules and oneof
+// rules as description notes.
+func messageValidateNotes(md protoreflect.MessageDescriptor) []string {
+	if !proto.HasExtension(md.Options(), validate.E_Message) {
+		return nil
+	}
+	rules, _ := proto.GetExtension(md.Options(), validate.E_Message).(*validate.MessageRules)
+	if rules == nil {
+		return nil
+	}
+	var notes []string
+	for _, o := range rules.GetOneof() {
+		verb := "At most one"
+		if o.GetRequired() {
+			verb = "Exactly one"
+		}
+		notes = append(notes, fmt.Sprintf("%s of %s must be set.", verb, strings.Join(o.GetFields(), ", ")))
+	}
+	return append(notes, celNotes(rules.GetCel(), rules.GetCelExpression())...)
+}
@@ -6,7 +6,7 @@ package generator
 import "google.golang.org/protobuf/reflect/protoreflect"
 
 // mapSchema handles protobuf map<K,V> fields.
-func mapSchema(fd protoreflect.FieldDescriptor, openAI bool) map[string]any {
+func (b *schemaBuilder) mapSchema(fd protoreflect.FieldDescriptor) map[string]any {
 	keyConstraints := map[string]any{"type": "string"}
 	switch fd.MapKey().Kind() {
 	case protoreflect.BoolKind:
@@ -19,14 +19,14 @@ func mapSchema(fd protoreflect.FieldDescriptor, openAI bool) map[string]any {
 		keyConstraints["pattern"] = `^-?(0|[1-9]\d*)$`
 	}
 
-	if openAI {
+	if b.openAI {
 		return map[string]any{
 			"type": "array", "description": "List of key-value pairs",
 			"items": map[string]any{
 				"type": "object",
 				"properties": map[string]any{
-					"key":   map[string]any{"type": "string"},
-					"value": map[string]any{"type": fieldSchema(fd.MapValue(), openAI)["type"]},
+					"key":   keyConstraints,
+					"value": b.fieldSchema(fd.MapValue()),
 				},
 				"required": []string{"key", "value"}, "additionalProperties": false,
 			},
@@ -34,12 +34,44 @@ func mapSchema(fd protoreflect.FieldDescriptor, openAI bool) map[string]any {
 	}
 	return map[string]any{
 		"type": "object", "propertyNames": keyConstraints,
-		"additionalProperties": fieldSchema(fd.MapValue(), openAI),
+		"additionalProperties": b.fieldSchema(fd.MapValue()),
 	}
 }
 
+// wellKnownSchemaTypes lists the message types messageFieldSchema renders
+// with a fixed schema instead of expanding their fields.
+var wellKnownSchemaTypes = map[string]bool{
+	"google.protobuf.Timestamp":   true,
+	"google.protobuf.Duration":    true,
+	"google.protobuf.Struct":      true,
+	"google.protobuf.Value":       true,
+	"google.protobuf.ListValue":   true,
+	"google.protobuf.FieldMask":   true,
+	"google.protobuf.Any":         true,
+	"google.protobuf.DoubleValue": true,
+	"google.protobuf.FloatValue":  true,
+	"google.protobuf.Int32Value":  true,
+	"google.protobuf.UInt32Value": true,
+	"google.protobuf.Int64Value":  true,
+	"google.protobuf.UInt64Value": true,
+	"google.protobuf.StringValue": true,
+	"google.protobuf.BoolValue":   true,
+	"google.protobuf.BytesValue":  true,
+	// google.type common types, see schema_gtype.go.
+	"google.type.Date":          true,
+	"google.type.TimeOfDay":     true,
+	"google.type.Money":         true,
+	"google.type.LatLng":        true,
+	"google.type.Color":         true,
+	"google.type.PostalAddress": true,
+	"google.type.Interval":      true,
+	"google.type.DateTime":      true,
+	"google.type.Decimal":       true,
+}
+
 // messageFieldSchema handles message-typed fields including well-known types.
-func messageFieldSchema(fd protoreflect.FieldDescriptor, openAI bool) map[string]any {
+func (b *schemaBuilder) messageFieldSchema(fd protoreflect.FieldDescriptor) map[string]any {
+	openAI := b.openAI
 	switch fullName := string(fd.Message().FullName()); fullName {
 	case "google.protobuf.Timestamp":
 		return map[string]any{"type": []string{"string", "null"}, "format": "date-time"}
@@ -77,20 +109,33 @@ func messageFieldSchema(fd protoreflect.FieldDescriptor, openAI bool) map[string
 		return s
 	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue",
 		"google.protobuf.Int32Value", "google.protobuf.UInt32Value":
-		return map[string]any{"type": "number", "nullable": true}
+		return nullableSchema("number", openAI)
 	case "google.protobuf.Int64Value", "google.protobuf.UInt64Value":
-		return map[string]any{"type": "string", "nullable": true}
+		return nullableSchema("string", openAI)
 	case "google.protobuf.StringValue":
-		return map[string]any{"type": "string", "nullable": true}
+		return nullableSchema("string", openAI)
 	case "google.protobuf.BoolValue":
-		return map[string]any{"type": "boolean", "nullable": true}
+		return nullableSchema("boolean", openAI)
 	case "google.protobuf.BytesValue":
-		s := map[string]any{"type": "string", "nullable": true}
+		s := nullableSchema("string", openAI)
 		if !openAI {
 			s["format"] = "byte"
 		}
 		return s
 	default:
-		return messageSchema(fd.Message(), openAI, "")
+		if s := googleTypeFieldSchema(fullName, openAI); s != nil {
+			return s
+		}
+		return b.messageRef(fd.Message())
+	}
+}
+
+// nullableSchema returns a schema for a wrapper type: "nullable" for
+// standard clients, a ["type", "null"] union in strict mode, which has no
+// "nullable" keyword.
+func nullableSchema(typ string, openAI bool) map[string]any {
+	if openAI {
+		return map[string]any{"type": []string{typ, "null"}}
 	}
+	return map[string]any{"type": typ, "nullable": true}
 }
@@ -0,0 +1,37 @@
+package generator
+
+import "google.golang.org/protobuf/compiler/protogen"
+
+// StreamCollectInfo describes a server-streaming RPC without the MCPProgress
+// convention that is exposed in collect mode (mcp.protobuf.MCPStreamCollect).
+type StreamCollectInfo struct {
+	StreamChunkType  string // Go type for the streamed message
+	StreamClientType string // Go type for gRPC client stream (e.g. "LogService_TailClient")
+	StreamServerType string // Go type for gRPC server stream (e.g. "LogService_TailServer")
+	MaxItems         uint32
+	MaxBytes         uint64
+	MaxDurationMs    uint32
+	Progress         bool
+}
+
+// DetectStreamCollect returns StreamCollectInfo if the method is
+// server-streaming and its tool options set stream_collect. Callers check
+// DetectProgressStream first; the progress convention takes precedence.
+func DetectStreamCollect(meth *protogen.Method, opts *MCPMethodOpts, resolveType func(protogen.GoIdent) string) *StreamCollectInfo {
+	if !meth.Desc.IsStreamingServer() || meth.Desc.IsStreamingClient() {
+		return nil
+	}
+	if opts == nil || opts.StreamCollect == nil {
+		return nil
+	}
+	svcName := string(meth.Parent.Desc.Name())
+	return &StreamCollectInfo{
+		StreamChunkType:  resolveType(meth.Output.GoIdent),
+		StreamClientType: svcName + "_" + meth.GoName + "Client",
+		StreamServerType: svcName + "_" + meth.GoName + "Server",
+		MaxItems:         opts.StreamCollect.MaxItems,
+		MaxBytes:         opts.StreamCollect.MaxBytes,
+		MaxDurationMs:    opts.StreamCollect.MaxDurationMs,
+		Progress:         opts.StreamCollect.Progress,
+	}
+}
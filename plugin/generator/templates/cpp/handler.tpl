@@ -9,10 +9,11 @@ use rmcp::{ErrorData as McpError, RoleServer, ServerHandler, ServiceExt, model::
 use serde_json::{self, json, Value};
 
 #[allow(dead_code)]
-fn make_tool(name: &str, description: &str, schema_json: &str) -> Tool {
+fn make_tool(name: &str, title: &str, description: &str, schema_json: &str, icons: Value) -> Tool {
     serde_json::from_value(json!({
-        "name": name, "description": description,
+        "name": name, "title": title, "description": description,
         "inputSchema": serde_json::from_str::<Value>(schema_json).unwrap(),
+        "icons": icons,
     })).expect("generated tool schema must be valid")
 }
 {{ range $svcName, $methods := .Services }}
@@ -36,7 +37,8 @@ impl {{ $svcName }}McpHandler {
     fn tools() -> Vec<Tool> {
         vec![
         {{- range $methName, $info := $methods }}
-            make_tool("{{ $info.ToolName }}", "{{ $info.Description | rsEscape }}", {{ $info.ConstName }}_SCHEMA_JSON),
+        {{- $meta := index $.ToolMeta (printf "%s_%s" $svcName $methName) }}
+            make_tool("{{ $info.ToolName }}", "{{ $meta.Title | rsEscape }}", "{{ $info.Description | rsEscape }}", {{ $info.ConstName }}_SCHEMA_JSON, {{ with $meta.Icons }}json!({{ jsonIcons . }}){{ else }}Value::Null{{ end }}),
         {{- end }}
         ]
     }
@@ -48,8 +50,29 @@ impl Clone for {{ $svcName }}McpHandler {
 
 impl ServerHandler for {{ $svcName }}McpHandler {
     fn get_info(&self) -> ServerInfo {
+{{- $svcOpts := index $.ServiceOpts $svcName }}
         ServerInfo::new(ServerCapabilities::builder().enable_tools().build())
+{{- if and $svcOpts $svcOpts.Server }}
+{{- $server := $svcOpts.Server }}
+            .with_server_info(serde_json::from_value(json!({
+                "name": "{{ $server.Name | rsEscape }}",
+                "version": "{{ or $server.Version "0.1.0" | rsEscape }}",
+{{- with $server.Title }}
+                "title": "{{ . | rsEscape }}",
+{{- end }}
+{{- with $server.WebsiteURL }}
+                "websiteUrl": "{{ . | rsEscape }}",
+{{- end }}
+{{- with $server.Icons }}
+                "icons": {{ jsonIcons . }},
+{{- end }}
+            })).expect("generated server info must be valid"))
+{{- with $server.Instructions }}
+            .with_instructions("{{ . | rsEscape }}")
+{{- end }}
+{{- else }}
             .with_server_info(Implementation::new("{{ $svcName }}", "0.1.0"))
+{{- end }}
     }
 
     async fn list_tools(&self, _: Option<PaginatedRequestParams>, _: RequestContext<RoleServer>) -> std::result::Result<ListToolsResult, McpError> {
@@ -10,7 +10,9 @@ import (
 	"context"
 {{- if .HasStreamProgress }}
 	"errors"
-	"fmt"
+{{- end }}
+{{- if .HasStreamCollectTimeout }}
+	"time"
 {{- end }}
 
 {{- range .ExtraImports }}
@@ -19,7 +21,6 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 {{- if .HasAnyMethods }}
 	"google.golang.org/grpc"
-	"google.golang.org/protobuf/encoding/protojson"
 {{- end }}
 
 	"github.com/machanirobotics/grpc-mcp-gateway/runtime"
@@ -34,7 +35,8 @@ var {{ $key }}SchemaJSON = {{ safeRawString $val }}
 // so that LLM clients can discover and invoke the underlying RPCs.
 var (
 {{- range $key, $val := .SchemaJSON }}
-	{{ $key }}Tool = runtime.MustCreateTool("{{ (index $.ToolMeta $key).Name }}", {{ safeRawString (index $.ToolMeta $key).Description }}, {{ $key }}SchemaJSON)
+{{- $meta := index $.ToolMeta $key }}
+	{{ $key }}Tool = runtime.ToolDisplay(runtime.MustCreateTool("{{ $meta.Name }}", {{ safeRawString $meta.Description }}, {{ $key }}SchemaJSON), {{ printf "%q" $meta.Title }}{{ with $meta.Icons }}, {{ goIcons . }}...{{ end }})
 {{- end }}
 )
 
@@ -42,13 +44,17 @@ var (
 
 // {{ $svcName }}MCPServer is the interface that users implement to handle MCP
 // tool calls backed by {{ $svcName }} RPCs. Unary RPCs take (ctx, req) and
-// return (resp, error). Server-streaming RPCs (with MCPProgress) take (req,
-// stream) matching the gRPC server interface — any type implementing
+// return (resp, error). Streaming RPCs take (req, stream) or (stream)
+// matching the gRPC server interface — any type implementing
 // {{ $svcName }}Server automatically satisfies this interface.
 type {{ $svcName }}MCPServer interface {
 {{- range $methName, $tool := $methods }}
 {{- if $tool.StreamProgress }}
 	{{ $methName }}(req *{{ $tool.RequestType }}, stream {{ $tool.StreamProgress.StreamServerType }}) error
+{{- else if $tool.StreamCollect }}
+	{{ $methName }}(req *{{ $tool.RequestType }}, stream {{ $tool.StreamCollect.StreamServerType }}) error
+{{- else if $tool.ClientStream }}
+	{{ $methName }}(stream {{ $tool.ClientStream.StreamServerType }}) error
 {{- else }}
 	{{ $methName }}(ctx context.Context, req *{{ $tool.RequestType }}) (*{{ $tool.ResponseType }}, error)
 {{- end }}
@@ -61,6 +67,10 @@ type {{ $svcName }}MCPClient interface {
 {{- range $methName, $tool := $methods }}
 {{- if $tool.StreamProgress }}
 	{{ $methName }}(ctx context.Context, req *{{ $tool.RequestType }}, opts ...grpc.CallOption) ({{ $tool.StreamProgress.StreamClientType }}, error)
+{{- else if $tool.StreamCollect }}
+	{{ $methName }}(ctx context.Context, req *{{ $tool.RequestType }}, opts ...grpc.CallOption) ({{ $tool.StreamCollect.StreamClientType }}, error)
+{{- else if $tool.ClientStream }}
+	{{ $methName }}(ctx context.Context, opts ...grpc.CallOption) ({{ $tool.ClientStream.StreamClientType }}, error)
 {{- else }}
 	{{ $methName }}(ctx context.Context, req *{{ $tool.RequestType }}, opts ...grpc.CallOption) (*{{ $tool.ResponseType }}, error)
 {{- end }}
@@ -80,18 +90,28 @@ type {{ $svcName }}MCPClient interface {
 func Register{{ $svcName }}MCPHandler(s *mcp.Server, srv {{ $svcName }}MCPServer, opts ...runtime.Option) {
 	cfg := runtime.ApplyOptions(opts...)
 	_ = cfg
-{{- if and $svcOpts $svcOpts.App }}
-	appResourceURI := runtime.AppResourceURI("{{ $svcName }}")
+{{- $hasOperations := false }}
+{{- range $methName, $tool := $methods }}
+{{- if $tool.Operation }}{{ $hasOperations = true }}{{ end }}
+{{- end }}
+{{- if $hasOperations }}
+	runtime.RequireOperationsClient(cfg, "{{ $svcName }}", srv)
 {{- end }}
 
 {{- range $methName, $tool := $methods }}
 {{- if $tool.StreamProgress }}
 	{
 		tool := runtime.PrepareToolWithExtras({{ $svcName }}_{{ $methName }}Tool, cfg.ExtraProperties)
-{{- if and $svcOpts $svcOpts.App }}
-		tool = runtime.SetToolAppMeta(tool, appResourceURI)
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*{{ $tool.RequestType }})(nil).ProtoReflect().Descriptor())
+{{- $app := (index $.ToolMeta (printf "%s_%s" $svcName $methName)).App }}
+{{- with $app }}
+		tool = runtime.SetToolAppMeta(tool, "{{ .ResourceURI }}"{{ range .Visibility }}, "{{ . }}"{{ end }})
 {{- end }}
-		s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		formatter := cfg.ResultFormatterFor(runtime.ResultFormat{{ $tool.ResultFormat }})
+{{- if $app }}
+		formatter = runtime.StructuredFormatter(formatter)
+{{- end }}
+		runtime.AddTool(s, cfg, tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 {{- if and $tool.MethodOpts $tool.MethodOpts.Elicitation }}
 			elicitFields := []runtime.ElicitField{
 			{{- range $tool.MethodOpts.Elicitation.Fields }}
@@ -114,60 +134,63 @@ func Register{{ $svcName }}MCPHandler(s *mcp.Server, srv {{ $svcName }}MCPServer
 			}
 {{- end }}
 			var pbReq {{ $tool.RequestType }}
-			args, ctx := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			args, ctx, err := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			if err != nil {
+				return nil, err
+			}
 {{- if and $tool.MethodOpts $tool.MethodOpts.Elicitation }}
 			args = runtime.MergeElicitResult(args, elicitResult.Content, elicitFields)
 {{- end }}
-			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(args, &pbReq); err != nil {
+			if err := runtime.UnmarshalArgs(ctx, args, &pbReq); err != nil {
+				return nil, err
+			}
+			if err := runtime.BindFields(ctx, req, cfg, &pbReq); err != nil {
 				return nil, err
 			}
 			token := req.Params.GetProgressToken()
-			session := req.Session
-			// notifCtx is unbound so progress notifications are not tied to the
-			// tool-call request lifetime.
-			// grpcCtx detaches from the tool-call cancellation so the gRPC
-			// server method can complete its stream after the HTTP response is sent.
-			notifCtx := context.Background()
-			grpcCtx := runtime.WithIncomingProgressToken(context.WithoutCancel(ctx), token)
-			stream := runtime.NewInProcessServerStream[*{{ $tool.StreamProgress.StreamChunkType }}](grpcCtx)
-			errCh := make(chan error, 1)
-			go func() {
-				defer stream.Close()
-				errCh <- srv.{{ $methName }}(&pbReq, stream)
-			}()
-			go func() {
+			// The RPC runs as a task: the call returns the task immediately and the
+			// final {{ $tool.StreamProgress.ResultField }} is stored for retrieval via the tasks-result tool.
+			return runtime.StartTask(ctx, req, cfg, func(taskCtx context.Context, task *runtime.TaskHandle) (*mcp.CallToolResult, error) {
+				grpcCtx := runtime.WithIncomingProgressToken(taskCtx, token)
+				stream := runtime.NewInProcessServerStream[*{{ $tool.StreamProgress.StreamChunkType }}](grpcCtx)
+				errCh := make(chan error, 1)
+				go func() {
+					defer stream.Close()
+					errCh <- srv.{{ $methName }}(&pbReq, stream)
+				}()
 				for {
 					chunk, ok := stream.Recv()
 					if !ok {
 						if err := <-errCh; err != nil {
-							_ = runtime.SendDoneProgress(notifCtx, session, token, fmt.Sprintf(`{"error":%q}`, err.Error()))
+							return runtime.HandleError(err)
 						}
-						return
+						return nil, errors.New("stream ended without result")
 					}
 					switch {
 					case chunk.Get{{ $tool.StreamProgress.ProgressField }}() != nil:
-						_ = runtime.SendProgressFromProto(notifCtx, session, token, chunk.Get{{ $tool.StreamProgress.ProgressField }}())
+						_ = task.Progress(chunk.Get{{ $tool.StreamProgress.ProgressField }}())
 					case chunk.Get{{ $tool.StreamProgress.ResultField }}() != nil:
-						out, err := (protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}).Marshal(chunk.Get{{ $tool.StreamProgress.ResultField }}())
-						if err != nil {
-							_ = runtime.SendDoneProgress(notifCtx, session, token, fmt.Sprintf(`{"error":%q}`, err.Error()))
-						} else {
-							_ = runtime.SendDoneProgress(notifCtx, session, token, string(out))
-						}
-						return
+						return runtime.FormatResult(formatter, chunk.Get{{ $tool.StreamProgress.ResultField }}())
 					}
 				}
-			}()
-			return runtime.TextResult(`{"status":"started"}`), nil
+			})
 		})
 	}
 {{- else }}
 	{
 		tool := runtime.PrepareToolWithExtras({{ $svcName }}_{{ $methName }}Tool, cfg.ExtraProperties)
-{{- if and $svcOpts $svcOpts.App }}
-		tool = runtime.SetToolAppMeta(tool, appResourceURI)
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*{{ $tool.RequestType }})(nil).ProtoReflect().Descriptor())
+{{- $app := (index $.ToolMeta (printf "%s_%s" $svcName $methName)).App }}
+{{- with $app }}
+		tool = runtime.SetToolAppMeta(tool, "{{ .ResourceURI }}"{{ range .Visibility }}, "{{ . }}"{{ end }})
+{{- end }}
+{{- if not (or (and $tool.ClientStream $tool.ClientStream.Bidi) (and $tool.Operation (eq $tool.Operation.Mode "tools"))) }}
+		formatter := cfg.ResultFormatterFor(runtime.ResultFormat{{ $tool.ResultFormat }})
+{{- if $app }}
+		formatter = runtime.StructuredFormatter(formatter)
 {{- end }}
-		s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+{{- end }}
+		runtime.AddTool(s, cfg, tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 {{- if and $tool.MethodOpts $tool.MethodOpts.Elicitation }}
 			elicitFields := []runtime.ElicitField{
 			{{- range $tool.MethodOpts.Elicitation.Fields }}
@@ -189,27 +212,116 @@ func Register{{ $svcName }}MCPHandler(s *mcp.Server, srv {{ $svcName }}MCPServer
 				return runtime.TextResult("Action cancelled by user."), nil
 			}
 {{- end }}
+{{- if not $tool.ClientStream }}
 			var pbReq {{ $tool.RequestType }}
-			args, ctx := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+{{- end }}
+			args, ctx, err := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			if err != nil {
+				return nil, err
+			}
 {{- if and $tool.MethodOpts $tool.MethodOpts.Elicitation }}
 			args = runtime.MergeElicitResult(args, elicitResult.Content, elicitFields)
 {{- end }}
-			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(args, &pbReq); err != nil {
+{{- if and $tool.Pagination $tool.Pagination.Opts $tool.Pagination.Opts.Cursor }}
+			args = runtime.CursorToPageToken(args)
+{{- end }}
+{{- if $tool.ClientStream }}
+{{- if not $tool.ClientStream.Bidi }}
+			reqs, err := runtime.DecodeStreamRequests(ctx, args, func() *{{ $tool.RequestType }} { return new({{ $tool.RequestType }}) })
+			if err != nil {
 				return nil, err
 			}
-			resp, err := srv.{{ $methName }}(ctx, &pbReq)
-			if err != nil {
+			for _, r := range reqs {
+				if err := runtime.BindFields(ctx, req, cfg, r); err != nil {
+					return nil, err
+				}
+			}
+{{- end }}
+{{- else }}
+			if err := runtime.UnmarshalArgs(ctx, args, &pbReq); err != nil {
+				return nil, err
+			}
+			if err := runtime.BindFields(ctx, req, cfg, &pbReq); err != nil {
+				return nil, err
+			}
+{{- end }}
+{{- if $tool.StreamCollect }}
+			collector := runtime.NewStreamCollector(ctx, req, {{ template "streamCollectOptions" $tool.StreamCollect }})
+			defer collector.Close()
+			stream := runtime.NewInProcessServerStream[*{{ $tool.StreamCollect.StreamChunkType }}](collector.Context())
+			go func() {
+				stream.CloseWithError(srv.{{ $methName }}(&pbReq, stream))
+			}()
+			return runtime.CollectStream(collector, func() (*{{ $tool.StreamCollect.StreamChunkType }}, error) {
+				return stream.RecvContext(collector.Context())
+			})
+{{- else if and $tool.ClientStream $tool.ClientStream.Bidi }}
+			return runtime.OpenBidiStream(ctx, s, req, cfg, args, func() *{{ $tool.RequestType }} { return new({{ $tool.RequestType }}) }, func(streamCtx context.Context) (runtime.BidiClient[*{{ $tool.RequestType }}, *{{ $tool.ResponseType }}], error) {
+				stream := runtime.NewInProcessBidiStream[*{{ $tool.RequestType }}, *{{ $tool.ResponseType }}](streamCtx)
+				go func() {
+					stream.Finish(srv.{{ $methName }}(stream))
+				}()
+				return stream.Client(), nil
+			})
+{{- else if $tool.ClientStream }}
+			stream := runtime.NewInProcessClientStream[*{{ $tool.RequestType }}, *{{ $tool.ResponseType }}](ctx, reqs)
+			if err := srv.{{ $methName }}(stream); err != nil {
 				return runtime.HandleError(err)
 			}
-			out, err := (protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}).Marshal(resp)
+			return runtime.FormatResult(formatter, stream.Response())
+{{- else }}
+{{- if $tool.Pagination }}
+			if paging := cfg.PaginationFor({{ template "paginationOptions" $tool.Pagination.Opts }}); paging != nil {
+				return runtime.Paginate(ctx, paging, formatter, &pbReq, "{{ $tool.Pagination.ItemsField }}", srv.{{ $methName }})
+			}
+{{- end }}
+			resp, err := srv.{{ $methName }}(ctx, &pbReq)
 			if err != nil {
-				return nil, err
+				return runtime.HandleError(err)
 			}
-			return runtime.TextResult(string(out)), nil
+{{- if $tool.Operation }}
+{{- if eq $tool.Operation.Mode "tools" }}
+			return runtime.OperationResult(resp, &{{ $tool.Operation.ResponseType }}{})
+{{- else }}
+			return runtime.WaitOperation(ctx, req, cfg, resp, &{{ $tool.Operation.ResponseType }}{}, formatter)
+{{- end }}
+{{- else }}
+			return runtime.FormatResult(formatter, resp)
+{{- end }}
+{{- end }}
 		})
 	}
 {{- end }}
+{{- if and $tool.ClientStream $tool.ClientStream.Bidi }}
+	{
+		tool := runtime.PrepareToolWithExtras({{ $tool.ClientStream.SendKey }}Tool, cfg.ExtraProperties)
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*{{ $tool.RequestType }})(nil).ProtoReflect().Descriptor())
+		runtime.AddTool(s, cfg, tool, runtime.SendBidiStream)
+	}
+{{- end }}
+{{- end }}
+{{- $hasTasks := false }}
+{{- range $methName, $tool := $methods }}
+{{- if $tool.StreamProgress }}{{ $hasTasks = true }}{{ end }}
+{{- end }}
+{{- if $hasTasks }}
+	runtime.RegisterTaskTools(s, cfg.TaskStoreOrDefault())
 {{- end }}
+{{- $hasOperationTools := false }}
+{{- range $methName, $tool := $methods }}
+{{- if and $tool.Operation (eq $tool.Operation.Mode "tools") }}{{ $hasOperationTools = true }}{{ end }}
+{{- end }}
+{{- if $hasOperationTools }}
+	runtime.RegisterOperationTools(s, cfg.OperationsClient)
+{{- end }}
+{{- $hasBidi := false }}
+{{- range $methName, $tool := $methods }}
+{{- if and $tool.ClientStream $tool.ClientStream.Bidi }}{{ $hasBidi = true }}{{ end }}
+{{- end }}
+{{- if $hasBidi }}
+	runtime.RegisterBidiStreamResource(s)
+{{- end }}
+	runtime.RegisterResultResource(s, cfg)
 {{- if and $svcOpts $svcOpts.Resources }}
 
 {{- range $svcOpts.Resources }}
@@ -217,17 +329,29 @@ func Register{{ $svcName }}MCPHandler(s *mcp.Server, srv {{ $svcName }}MCPServer
 	s.AddResource(&mcp.Resource{
 		URI:         "{{ .URI }}",
 		Name:        "{{ .Name }}",
+{{- with .Title }}
+		Title:       {{ printf "%q" . }},
+{{- end }}
 		Description: "{{ .Description }}",
 		MIMEType:    "{{ .MimeType }}",
-	}, runtime.DefaultResourceHandler())
+{{- with .Icons }}
+		Icons:       {{ goIcons . }},
+{{- end }}
+	}, {{ if .RPC }}runtime.RPCResourceHandler("{{ .URI }}", "{{ .MimeType }}", srv.{{ .RPC }}){{ else }}runtime.DefaultResourceHandler(){{ end }})
 {{- end }}
 {{- if .URITemplate }}
 	s.AddResourceTemplate(&mcp.ResourceTemplate{
 		URITemplate: "{{ .URITemplate }}",
 		Name:        "{{ .Name }}",
+{{- with .Title }}
+		Title:       {{ printf "%q" . }},
+{{- end }}
 		Description: "{{ .Description }}",
 		MIMEType:    "{{ .MimeType }}",
-	}, runtime.DefaultResourceHandler())
+{{- with .Icons }}
+		Icons:       {{ goIcons . }},
+{{- end }}
+	}, {{ if .RPC }}runtime.RPCResourceHandler("{{ .URITemplate }}", "{{ .MimeType }}", srv.{{ .RPC }}){{ else }}runtime.DefaultResourceHandler(){{ end }})
 {{- end }}
 {{- end }}
 {{- end }}
@@ -241,7 +365,11 @@ func Register{{ $svcName }}MCPHandler(s *mcp.Server, srv {{ $svcName }}MCPServer
 {{- if and $tool.MethodOpts $tool.MethodOpts.Prompt }}
 	s.AddPrompt(&mcp.Prompt{
 		Name:        "{{ $tool.MethodOpts.Prompt.Name }}",
+		Title:       {{ printf "%q" $tool.MethodOpts.Prompt.Title }},
 		Description: "{{ $tool.MethodOpts.Prompt.Description }}",
+{{- with $tool.MethodOpts.Prompt.Icons }}
+		Icons:       {{ goIcons . }},
+{{- end }}
 		Arguments: []*mcp.PromptArgument{
 		{{- range $tool.MethodOpts.Prompt.Arguments }}
 			{Name: "{{ .Name }}", Description: "{{ escapeQuotes .Description }}", Required: {{ .Required }}},
@@ -251,13 +379,22 @@ func Register{{ $svcName }}MCPHandler(s *mcp.Server, srv {{ $svcName }}MCPServer
 {{- end }}
 {{- end }}
 {{- end }}
-{{- if and $svcOpts $svcOpts.App }}
+{{- if $svcOpts }}
+{{- range $svcOpts.AppViews }}
 
 	s.AddResource(&mcp.Resource{
-		URI:      appResourceURI,
-		Name:     "{{ escapeQuotes $svcOpts.App.Name }}",
-		MIMEType: "text/html",
-	}, runtime.DefaultAppResourceHandler("{{ escapeQuotes $svcOpts.App.Name }}", "{{ $svcOpts.App.Version }}", "{{ escapeQuotes $svcOpts.App.Description }}"))
+		URI:         "{{ .URI }}",
+		Name:        {{ printf "%q" .Name }},
+{{- with .Description }}
+		Description: {{ printf "%q" . }},
+{{- end }}
+		MIMEType:    runtime.AppMIMEType,
+{{- if .Entry }}
+	}, runtime.AppViewHandler(cfg, {{ template "appView" . }}))
+{{- else }}
+	}, runtime.DefaultAppResourceHandler({{ printf "%q" .Name }}, {{ printf "%q" .Version }}, {{ printf "%q" .Description }}))
+{{- end }}
+{{- end }}
 {{- end }}
 }
 {{- end }}
@@ -267,6 +404,30 @@ func Register{{ $svcName }}MCPHandler(s *mcp.Server, srv {{ $svcName }}MCPServer
 // {{ $svcName }}MCPDefaultBasePath is the default HTTP path prefix for
 // {{ $svcName }} MCP endpoints, derived from the protobuf package and service name.
 const {{ $svcName }}MCPDefaultBasePath = "{{ index $.ServiceBasePaths $svcName }}"
+{{- $svcOpts := index $.ServiceOpts $svcName }}
+{{- if and $svcOpts $svcOpts.Server }}
+
+// {{ $svcName }}MCPServerIdentity is the server identity declared in the
+// {{ $svcName }} (mcp.protobuf.service) options.
+var {{ $svcName }}MCPServerIdentity = runtime.ServerIdentity{
+	Name: {{ printf "%q" $svcOpts.Server.Name }},
+{{- with $svcOpts.Server.Title }}
+	Title: {{ printf "%q" . }},
+{{- end }}
+{{- with $svcOpts.Server.Version }}
+	Version: {{ printf "%q" . }},
+{{- end }}
+{{- with $svcOpts.Server.Instructions }}
+	Instructions: {{ printf "%q" . }},
+{{- end }}
+{{- with $svcOpts.Server.WebsiteURL }}
+	WebsiteURL: {{ printf "%q" . }},
+{{- end }}
+{{- with $svcOpts.Server.Icons }}
+	Icons: {{ goIcons . }},
+{{- end }}
+}
+{{- end }}
 {{- end }}
 
 {{- range $svcName, $methods := .Services }}
@@ -295,6 +456,9 @@ func {{ $svcName }}CompletionMap() map[string][]string {
 func Serve{{ $svcName }}MCP(ctx context.Context, srv {{ $svcName }}MCPServer, cfg *runtime.MCPServerConfig, opts ...runtime.Option) error {
 	// Set the proto-derived path as the generated default
 	cfg.GeneratedBasePath = {{ $svcName }}MCPDefaultBasePath
+{{- if and $svcOpts $svcOpts.Server }}
+	runtime.ApplyServerIdentity(cfg, {{ $svcName }}MCPServerIdentity)
+{{- end }}
 
 	// Wire completion handler for prompt arguments with enum_values.
 	completionMap := {{ $svcName }}CompletionMap()
@@ -320,17 +484,29 @@ func Serve{{ $svcName }}MCP(ctx context.Context, srv {{ $svcName }}MCPServer, cf
 func ForwardTo{{ $svcName }}MCPClient(s *mcp.Server, client {{ $svcName }}MCPClient, opts ...runtime.Option) {
 	cfg := runtime.ApplyOptions(opts...)
 	_ = cfg
-{{- if and $svcOpts $svcOpts.App }}
-	appResourceURI := runtime.AppResourceURI("{{ $svcName }}")
+{{- $hasOperations := false }}
+{{- range $methName, $tool := $methods }}
+{{- if $tool.Operation }}{{ $hasOperations = true }}{{ end }}
+{{- end }}
+{{- if $hasOperations }}
+	runtime.RequireOperationsClient(cfg, "{{ $svcName }}", client)
 {{- end }}
 
 {{- range $methName, $tool := $methods }}
 	{
 		tool := runtime.PrepareToolWithExtras({{ $svcName }}_{{ $methName }}Tool, cfg.ExtraProperties)
-{{- if and $svcOpts $svcOpts.App }}
-		tool = runtime.SetToolAppMeta(tool, appResourceURI)
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*{{ $tool.RequestType }})(nil).ProtoReflect().Descriptor())
+{{- $app := (index $.ToolMeta (printf "%s_%s" $svcName $methName)).App }}
+{{- with $app }}
+		tool = runtime.SetToolAppMeta(tool, "{{ .ResourceURI }}"{{ range .Visibility }}, "{{ . }}"{{ end }})
+{{- end }}
+{{- if not (or (and $tool.ClientStream $tool.ClientStream.Bidi) (and $tool.Operation (eq $tool.Operation.Mode "tools"))) }}
+		formatter := cfg.ResultFormatterFor(runtime.ResultFormat{{ $tool.ResultFormat }})
+{{- if $app }}
+		formatter = runtime.StructuredFormatter(formatter)
 {{- end }}
-		s.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+{{- end }}
+		runtime.AddTool(s, cfg, tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 {{- if and $tool.MethodOpts $tool.MethodOpts.Elicitation }}
 			elicitFields := []runtime.ElicitField{
 			{{- range $tool.MethodOpts.Elicitation.Fields }}
@@ -352,24 +528,69 @@ func ForwardTo{{ $svcName }}MCPClient(s *mcp.Server, client {{ $svcName }}MCPCli
 				return runtime.TextResult("Action cancelled by user."), nil
 			}
 {{- end }}
+{{- if not $tool.ClientStream }}
 			var pbReq {{ $tool.RequestType }}
-			args, ctx := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+{{- end }}
+			args, ctx, err := runtime.ExtractExtras(ctx, req.Params.Arguments, cfg)
+			if err != nil {
+				return nil, err
+			}
 {{- if and $tool.MethodOpts $tool.MethodOpts.Elicitation }}
 			args = runtime.MergeElicitResult(args, elicitResult.Content, elicitFields)
 {{- end }}
-			if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(args, &pbReq); err != nil {
+{{- if and $tool.Pagination $tool.Pagination.Opts $tool.Pagination.Opts.Cursor }}
+			args = runtime.CursorToPageToken(args)
+{{- end }}
+{{- if $tool.ClientStream }}
+{{- if not $tool.ClientStream.Bidi }}
+			reqs, err := runtime.DecodeStreamRequests(ctx, args, func() *{{ $tool.RequestType }} { return new({{ $tool.RequestType }}) })
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range reqs {
+				if err := runtime.BindFields(ctx, req, cfg, r); err != nil {
+					return nil, err
+				}
+			}
+{{- end }}
+{{- else }}
+			if err := runtime.UnmarshalArgs(ctx, args, &pbReq); err != nil {
 				return nil, err
 			}
+			if err := runtime.BindFields(ctx, req, cfg, &pbReq); err != nil {
+				return nil, err
+			}
+{{- end }}
 			ctx = runtime.ForwardMetadata(ctx)
 {{- if $tool.StreamProgress }}
-			if token := req.Params.GetProgressToken(); token != nil {
+			token := req.Params.GetProgressToken()
+			if token != nil {
 				ctx = runtime.WithProgressToken(ctx, token)
 			}
+			if cfg.TaskStore != nil {
+				return runtime.StartTask(ctx, req, cfg, func(taskCtx context.Context, task *runtime.TaskHandle) (*mcp.CallToolResult, error) {
+					stream, err := client.{{ $methName }}(taskCtx, &pbReq)
+					if err != nil {
+						return runtime.HandleError(err)
+					}
+					for {
+						chunk, err := stream.Recv()
+						if err != nil {
+							return runtime.HandleError(err)
+						}
+						switch {
+						case chunk.Get{{ $tool.StreamProgress.ProgressField }}() != nil:
+							_ = task.Progress(chunk.Get{{ $tool.StreamProgress.ProgressField }}())
+						case chunk.Get{{ $tool.StreamProgress.ResultField }}() != nil:
+							return runtime.FormatResult(formatter, chunk.Get{{ $tool.StreamProgress.ResultField }}())
+						}
+					}
+				})
+			}
 			stream, err := client.{{ $methName }}(ctx, &pbReq)
 			if err != nil {
 				return runtime.HandleError(err)
 			}
-			token := req.Params.GetProgressToken()
 			for {
 				chunk, err := stream.Recv()
 				if err != nil {
@@ -382,27 +603,87 @@ func ForwardTo{{ $svcName }}MCPClient(s *mcp.Server, client {{ $svcName }}MCPCli
 				case chunk.Get{{ $tool.StreamProgress.ProgressField }}() != nil:
 					_ = runtime.SendProgressFromProto(ctx, req.Session, token, chunk.Get{{ $tool.StreamProgress.ProgressField }}())
 				case chunk.Get{{ $tool.StreamProgress.ResultField }}() != nil:
-					out, err := (protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}).Marshal(chunk.Get{{ $tool.StreamProgress.ResultField }}())
-					if err != nil {
-						return nil, err
-					}
-					return runtime.TextResult(string(out)), nil
+					return runtime.FormatResult(formatter, chunk.Get{{ $tool.StreamProgress.ResultField }}())
 				}
 			}
-{{- else }}
-			resp, err := client.{{ $methName }}(ctx, &pbReq)
+{{- else if $tool.StreamCollect }}
+			collector := runtime.NewStreamCollector(ctx, req, {{ template "streamCollectOptions" $tool.StreamCollect }})
+			defer collector.Close()
+			stream, err := client.{{ $methName }}(collector.Context(), &pbReq)
 			if err != nil {
 				return runtime.HandleError(err)
 			}
-			out, err := (protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}).Marshal(resp)
+			return runtime.CollectStream(collector, stream.Recv)
+{{- else if and $tool.ClientStream $tool.ClientStream.Bidi }}
+			return runtime.OpenBidiStream(ctx, s, req, cfg, args, func() *{{ $tool.RequestType }} { return new({{ $tool.RequestType }}) }, func(streamCtx context.Context) (runtime.BidiClient[*{{ $tool.RequestType }}, *{{ $tool.ResponseType }}], error) {
+				return client.{{ $methName }}(streamCtx)
+			})
+{{- else if $tool.ClientStream }}
+			stream, err := client.{{ $methName }}(ctx)
 			if err != nil {
-				return nil, err
+				return runtime.HandleError(err)
 			}
-			return runtime.TextResult(string(out)), nil
+			resp, err := runtime.ForwardClientStream[*{{ $tool.RequestType }}, *{{ $tool.ResponseType }}](stream, reqs)
+			if err != nil {
+				return runtime.HandleError(err)
+			}
+			return runtime.FormatResult(formatter, resp)
+{{- else }}
+{{- if $tool.Pagination }}
+			if paging := cfg.PaginationFor({{ template "paginationOptions" $tool.Pagination.Opts }}); paging != nil {
+				return runtime.Paginate(ctx, paging, formatter, &pbReq, "{{ $tool.Pagination.ItemsField }}", func(ctx context.Context, r *{{ $tool.RequestType }}) (*{{ $tool.ResponseType }}, error) {
+					return client.{{ $methName }}(ctx, r)
+				})
+			}
+{{- end }}
+			resp, err := client.{{ $methName }}(ctx, &pbReq)
+			if err != nil {
+				return runtime.HandleError(err)
+			}
+{{- if $tool.Operation }}
+{{- if eq $tool.Operation.Mode "tools" }}
+			return runtime.OperationResult(resp, &{{ $tool.Operation.ResponseType }}{})
+{{- else }}
+			return runtime.WaitOperation(ctx, req, cfg, resp, &{{ $tool.Operation.ResponseType }}{}, formatter)
+{{- end }}
+{{- else }}
+			return runtime.FormatResult(formatter, resp)
+{{- end }}
 {{- end }}
 		})
 	}
+{{- if and $tool.ClientStream $tool.ClientStream.Bidi }}
+	{
+		tool := runtime.PrepareToolWithExtras({{ $tool.ClientStream.SendKey }}Tool, cfg.ExtraProperties)
+		tool = runtime.PrepareToolWithBindings(tool, cfg.FieldBindings, (*{{ $tool.RequestType }})(nil).ProtoReflect().Descriptor())
+		runtime.AddTool(s, cfg, tool, runtime.SendBidiStream)
+	}
+{{- end }}
+{{- end }}
+{{- $hasTasks := false }}
+{{- range $methName, $tool := $methods }}
+{{- if $tool.StreamProgress }}{{ $hasTasks = true }}{{ end }}
+{{- end }}
+{{- if $hasTasks }}
+	if cfg.TaskStore != nil {
+		runtime.RegisterTaskTools(s, cfg.TaskStore)
+	}
+{{- end }}
+{{- $hasOperationTools := false }}
+{{- range $methName, $tool := $methods }}
+{{- if and $tool.Operation (eq $tool.Operation.Mode "tools") }}{{ $hasOperationTools = true }}{{ end }}
 {{- end }}
+{{- if $hasOperationTools }}
+	runtime.RegisterOperationTools(s, cfg.OperationsClient)
+{{- end }}
+{{- $hasBidi := false }}
+{{- range $methName, $tool := $methods }}
+{{- if and $tool.ClientStream $tool.ClientStream.Bidi }}{{ $hasBidi = true }}{{ end }}
+{{- end }}
+{{- if $hasBidi }}
+	runtime.RegisterBidiStreamResource(s)
+{{- end }}
+	runtime.RegisterResultResource(s, cfg)
 {{- if and $svcOpts $svcOpts.Resources }}
 
 {{- range $svcOpts.Resources }}
@@ -410,17 +691,33 @@ func ForwardTo{{ $svcName }}MCPClient(s *mcp.Server, client {{ $svcName }}MCPCli
 	s.AddResource(&mcp.Resource{
 		URI:         "{{ .URI }}",
 		Name:        "{{ .Name }}",
+{{- with .Title }}
+		Title:       {{ printf "%q" . }},
+{{- end }}
 		Description: "{{ .Description }}",
 		MIMEType:    "{{ .MimeType }}",
-	}, runtime.DefaultResourceHandler())
+{{- with .Icons }}
+		Icons:       {{ goIcons . }},
+{{- end }}
+	}, {{ if .RPC }}{{ $tool := index $methods .RPC }}runtime.RPCResourceHandler("{{ .URI }}", "{{ .MimeType }}", func(ctx context.Context, req *{{ $tool.RequestType }}) (*{{ $tool.ResponseType }}, error) {
+		return client.{{ .RPC }}(runtime.ForwardMetadata(ctx), req)
+	}){{ else }}runtime.DefaultResourceHandler(){{ end }})
 {{- end }}
 {{- if .URITemplate }}
 	s.AddResourceTemplate(&mcp.ResourceTemplate{
 		URITemplate: "{{ .URITemplate }}",
 		Name:        "{{ .Name }}",
+{{- with .Title }}
+		Title:       {{ printf "%q" . }},
+{{- end }}
 		Description: "{{ .Description }}",
 		MIMEType:    "{{ .MimeType }}",
-	}, runtime.DefaultResourceHandler())
+{{- with .Icons }}
+		Icons:       {{ goIcons . }},
+{{- end }}
+	}, {{ if .RPC }}{{ $tool := index $methods .RPC }}runtime.RPCResourceHandler("{{ .URITemplate }}", "{{ .MimeType }}", func(ctx context.Context, req *{{ $tool.RequestType }}) (*{{ $tool.ResponseType }}, error) {
+		return client.{{ .RPC }}(runtime.ForwardMetadata(ctx), req)
+	}){{ else }}runtime.DefaultResourceHandler(){{ end }})
 {{- end }}
 {{- end }}
 {{- end }}
@@ -434,7 +731,11 @@ func ForwardTo{{ $svcName }}MCPClient(s *mcp.Server, client {{ $svcName }}MCPCli
 {{- if and $tool.MethodOpts $tool.MethodOpts.Prompt }}
 	s.AddPrompt(&mcp.Prompt{
 		Name:        "{{ $tool.MethodOpts.Prompt.Name }}",
+		Title:       {{ printf "%q" $tool.MethodOpts.Prompt.Title }},
 		Description: "{{ $tool.MethodOpts.Prompt.Description }}",
+{{- with $tool.MethodOpts.Prompt.Icons }}
+		Icons:       {{ goIcons . }},
+{{- end }}
 		Arguments: []*mcp.PromptArgument{
 		{{- range $tool.MethodOpts.Prompt.Arguments }}
 			{Name: "{{ .Name }}", Description: "{{ escapeQuotes .Description }}", Required: {{ .Required }}},
@@ -444,13 +745,76 @@ func ForwardTo{{ $svcName }}MCPClient(s *mcp.Server, client {{ $svcName }}MCPCli
 {{- end }}
 {{- end }}
 {{- end }}
-{{- if and $svcOpts $svcOpts.App }}
+{{- if $svcOpts }}
+{{- range $svcOpts.AppViews }}
 
 	s.AddResource(&mcp.Resource{
-		URI:      appResourceURI,
-		Name:     "{{ escapeQuotes $svcOpts.App.Name }}",
-		MIMEType: "text/html",
-	}, runtime.DefaultAppResourceHandler("{{ escapeQuotes $svcOpts.App.Name }}", "{{ $svcOpts.App.Version }}", "{{ escapeQuotes $svcOpts.App.Description }}"))
+		URI:         "{{ .URI }}",
+		Name:        {{ printf "%q" .Name }},
+{{- with .Description }}
+		Description: {{ printf "%q" . }},
 {{- end }}
+		MIMEType:    runtime.AppMIMEType,
+{{- if .Entry }}
+	}, runtime.AppViewHandler(cfg, {{ template "appView" . }}))
+{{- else }}
+	}, runtime.DefaultAppResourceHandler({{ printf "%q" .Name }}, {{ printf "%q" .Version }}, {{ printf "%q" .Description }}))
+{{- end }}
+{{- end }}
+{{- end }}
+}
+{{- end }}
+
+{{- define "appView" -}}
+&runtime.AppView{
+		Entry:  "{{ .Entry }}",
+{{- with .Assets }}
+		Assets: []string{ {{- range $i, $a := . }}{{ if $i }}, {{ end }}"{{ $a }}"{{ end -}} },
+{{- end }}
+		HTML:   {{ printf "%q" .HTML }},
+{{- with .CSP }}
+		CSP: &runtime.AppCSP{
+{{- with .ConnectDomains }}
+			ConnectDomains: {{ printf "%#v" . }},
+{{- end }}
+{{- with .ResourceDomains }}
+			ResourceDomains: {{ printf "%#v" . }},
+{{- end }}
+{{- with .FrameDomains }}
+			FrameDomains: {{ printf "%#v" . }},
+{{- end }}
+{{- with .BaseURIDomains }}
+			BaseURIDomains: {{ printf "%#v" . }},
+{{- end }}
+		},
+{{- end }}
+{{- with .Domain }}
+		Domain: {{ printf "%q" . }},
+{{- end }}
+{{- if .PrefersBorder }}
+		PrefersBorder: true,
+{{- end }}
+	}
+{{- end }}
+
+{{- define "streamCollectOptions" -}}
+runtime.StreamCollectOptions{
+{{- if .MaxItems }}MaxItems: {{ .MaxItems }}, {{ end -}}
+{{- if .MaxBytes }}MaxBytes: {{ .MaxBytes }}, {{ end -}}
+{{- if .MaxDurationMs }}MaxDuration: {{ .MaxDurationMs }} * time.Millisecond, {{ end -}}
+{{- if .Progress }}Progress: true, {{ end -}}
+Formatter: formatter}
+{{- end }}
+
+{{- define "paginationOptions" -}}
+{{- if . -}}
+&runtime.PaginationOptions{
+{{- if .Enabled }}Enabled: true, {{ end -}}
+{{- if .MaxItems }}MaxItems: {{ .MaxItems }}, {{ end -}}
+{{- if .MaxBytes }}MaxBytes: {{ .MaxBytes }}, {{ end -}}
+{{- if .Cursor }}Cursor: true{{ end -}}
 }
+{{- else -}}
+nil
+{{- end -}}
 {{- end }}
@@ -7,11 +7,14 @@
 from __future__ import annotations
 
 import json
+import re
 from typing import Any, Iterator, Protocol
+from urllib.parse import unquote
 
 import mcp.types as types
 from mcp.server.fastmcp import FastMCP
 from mcp.server.lowlevel import Server
+from mcp.server.lowlevel.helper_types import ReadResourceContents
 from google.protobuf.json_format import MessageToDict, ParseDict
 
 {{ .PBImports }}
@@ -26,13 +29,62 @@ from google.protobuf.json_format import MessageToDict, ParseDict
 {{ range $key, $val := .SchemaJSON }}
 {{ $key }}_TOOL = types.Tool(
     name="{{ (index $.ToolMeta $key).Name }}",
+    title={{ (index $.ToolMeta $key).Title | pyString }},
     description={{ (index $.ToolMeta $key).Description | pyString }},
     inputSchema={{ $key }}_SCHEMA,
+{{- with (index $.ToolMeta $key).Icons }}
+    icons={{ pyIcons . }},
+{{- end }}
 )
 {{ end }}
 
-def _app_resource_uri(service_name: str) -> str:
-    return f"ui://{service_name.lower()}/app.html"
+_APP_MIME_TYPE = "text/html;profile=mcp-app"
+
+
+def _resource_args(uri_template: str, uri: str, bindings: dict[str, str], name_binding: bool) -> dict[str, Any] | None:
+    """Map the variables of uri_template in uri to request fields, or return None if uri does not match."""
+    parts = re.split(r"\{(\+?)([^}]+)\}", uri_template)
+    pattern, names = re.escape(parts[0]), []
+    for i in range(1, len(parts), 3):
+        pattern += ("(.*)" if parts[i] else "([^/]*)") + re.escape(parts[i + 2])
+        names.append(parts[i + 1])
+    match = re.fullmatch(pattern, uri)
+    if match is None:
+        return None
+    args: dict[str, Any] = {}
+    if name_binding:
+        args["name"] = unquote(uri.split("://", 1)[-1])
+    for name, value in zip(names, match.groups()):
+        if name in bindings:
+            *parents, leaf = bindings[name].split(".")
+            target = args
+            for parent in parents:
+                target = target.setdefault(parent, {})
+            target[leaf] = unquote(value)
+    return args
+
+
+def _resource_data(data: bytes, mime_type: str) -> str | bytes:
+    base = mime_type.split(";", 1)[0]
+    if base.startswith("text/") or base in ("application/json", "application/xml") or base.endswith(("+json", "+xml")):
+        return data.decode()
+    return data
+
+
+def _resource_contents(resp: Any, mime_type: str) -> list[ReadResourceContents]:
+    """Render an RPC response as resource contents: HttpBody data, the value of a single string or bytes field, or JSON."""
+    if resp.DESCRIPTOR.full_name == "google.api.HttpBody":
+        content_type = resp.content_type or mime_type
+        return [ReadResourceContents(content=_resource_data(resp.data, content_type), mime_type=content_type)]
+    fields = resp.DESCRIPTOR.fields
+    if len(fields) == 1 and fields[0].label != fields[0].LABEL_REPEATED:
+        value = getattr(resp, fields[0].name)
+        if isinstance(value, bytes):
+            return [ReadResourceContents(content=_resource_data(value, mime_type), mime_type=mime_type)]
+        if isinstance(value, str):
+            return [ReadResourceContents(content=value, mime_type=mime_type)]
+    text = json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True))
+    return [ReadResourceContents(content=text, mime_type="application/json")]
 
 def _default_app_html(app_name: str, version: str, description: str) -> str:
     return (
@@ -46,12 +98,28 @@ def _default_app_html(app_name: str, version: str, description: str) -> str:
         "</body></html>"
     )
 
-def _tool_with_app_meta(tool: types.Tool, app_resource_uri: str) -> types.Tool:
-    meta = {"ui": {"resourceUri": app_resource_uri}}
+def _tool_with_app_meta(tool: types.Tool, resource_uri: str, visibility: list[str] | None = None) -> types.Tool:
+    ui: dict[str, Any] = {"resourceUri": resource_uri}
+    if visibility:
+        ui["visibility"] = visibility
+    meta = {**(tool.meta or {}), "ui": ui}
     if hasattr(tool, "model_copy"):
         return tool.model_copy(update={"meta": meta})
     return tool.copy(deep=True, update={"meta": meta})
 
+
+def _app_view_contents(html: str, ui: dict[str, Any]) -> list[ReadResourceContents]:
+    return [ReadResourceContents(content=html, mime_type=_APP_MIME_TYPE, meta={"ui": ui} if ui else None)]
+
+
+def _tool_result(resp: Any, structured: bool) -> list[types.TextContent] | tuple[list[types.TextContent], dict[str, Any]]:
+    """Render an RPC response as tool result content, also returned as structured content for MCP App views."""
+    data = MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)
+    content = [types.TextContent(type="text", text=json.dumps(data))]
+    if not structured:
+        return content
+    return content, data if isinstance(data, dict) else {"value": data}
+
 {{- range $svcName, $methods := .Services }}
 
 class {{ $svcName }}MCPServer(Protocol):
@@ -81,8 +149,12 @@ class {{ $svcName }}MCPClient(Protocol):
 
 {{ $svcName }}_TOOLS: list[types.Tool] = [
 {{- range $methName, $tool := $methods }}
+{{- with (index $.ToolMeta (printf "%s_%s" $svcName $methName)).App }}
+    _tool_with_app_meta({{ $svcName }}_{{ $methName }}_TOOL, "{{ .ResourceURI }}"{{ with .Visibility }}, [{{ range $i, $v := . }}{{ if $i }}, {{ end }}"{{ $v }}"{{ end }}]{{ end }}),
+{{- else }}
     {{ $svcName }}_{{ $methName }}_TOOL,
 {{- end }}
+{{- end }}
 ]
 {{- end }}
 
@@ -96,7 +168,11 @@ def _{{ $svcName | snakeCase }}_prompts() -> list[types.Prompt]:
 {{- if and $tool.MethodOpts $tool.MethodOpts.Prompt }}
         types.Prompt(
             name="{{ $tool.MethodOpts.Prompt.Name }}",
+            title={{ $tool.MethodOpts.Prompt.Title | pyString }},
             description="{{ escapeQuotes $tool.MethodOpts.Prompt.Description }}",
+{{- with $tool.MethodOpts.Prompt.Icons }}
+            icons={{ pyIcons . }},
+{{- end }}
             arguments=[
             {{- range $tool.MethodOpts.Prompt.Arguments }}
                 types.PromptArgument(name="{{ .Name }}", description="{{ escapeQuotes .Description }}", required={{ if .Required }}True{{ else }}False{{ end }}),
@@ -113,15 +189,17 @@ def _{{ $svcName | snakeCase }}_resources() -> list[types.Resource | types.Resou
 {{- if and $svcOpts $svcOpts.Resources }}
 {{- range $svcOpts.Resources }}
 {{- if .URI }}
-        types.Resource(uri="{{ .URI }}", name="{{ .Name }}", description="{{ escapeQuotes .Description }}", mimeType="{{ .MimeType }}"),
+        types.Resource(uri="{{ .URI }}", name="{{ .Name }}"{{ with .Title }}, title={{ . | pyString }}{{ end }}, description="{{ escapeQuotes .Description }}", mimeType="{{ .MimeType }}"{{ with .Icons }}, icons={{ pyIcons . }}{{ end }}),
 {{- end }}
 {{- if .URITemplate }}
-        types.ResourceTemplate(uriTemplate="{{ .URITemplate }}", name="{{ .Name }}", description="{{ escapeQuotes .Description }}", mimeType="{{ .MimeType }}"),
+        types.ResourceTemplate(uriTemplate="{{ .URITemplate }}", name="{{ .Name }}"{{ with .Title }}, title={{ . | pyString }}{{ end }}, description="{{ escapeQuotes .Description }}", mimeType="{{ .MimeType }}"{{ with .Icons }}, icons={{ pyIcons . }}{{ end }}),
 {{- end }}
 {{- end }}
 {{- end }}
-{{- if and $svcOpts $svcOpts.App }}
-        types.Resource(uri=_app_resource_uri("{{ $svcName }}"), name="{{ escapeQuotes $svcOpts.App.Name }}", mimeType="text/html"),
+{{- if $svcOpts }}
+{{- range $svcOpts.AppViews }}
+        types.Resource(uri="{{ .URI }}", name={{ .Name | pyString }}{{ with .Description }}, description={{ . | pyString }}{{ end }}, mimeType=_APP_MIME_TYPE),
+{{- end }}
 {{- end }}
     ]
 
@@ -142,20 +220,13 @@ def _{{ $svcName | snakeCase }}_completion_map() -> dict[str, list[str]]:
 
 def register_{{ $svcName | snakeCase }}_mcp_handler(server: Server, impl: {{ $svcName }}MCPServer) -> None:
     """Register all {{ $svcName }} tools, prompts, and resources on the given MCP server."""
-{{- if and $svcOpts $svcOpts.App }}
-    _app_resource_uri_value = _app_resource_uri("{{ $svcName }}")
-{{- end }}
 
     @server.list_tools()
     async def handle_list_tools() -> list[types.Tool]:
-{{- if and $svcOpts $svcOpts.App }}
-        return [_tool_with_app_meta(t, _app_resource_uri_value) for t in {{ $svcName }}_TOOLS]
-{{- else }}
         return {{ $svcName }}_TOOLS
-{{- end }}
 
     @server.call_tool()
-    async def handle_call_tool(name: str, arguments: dict[str, Any]) -> list[types.TextContent]:
+    async def handle_call_tool(name: str, arguments: dict[str, Any]) -> list[types.TextContent] | tuple[list[types.TextContent], dict[str, Any]]:
 {{- range $methName, $tool := $methods }}
         if name == "{{ $tool.ToolName }}":
 {{- if and $tool.MethodOpts $tool.MethodOpts.Elicitation }}
@@ -200,11 +271,11 @@ def register_{{ $svcName | snakeCase }}_mcp_handler(server: Server, impl: {{ $sv
                         )
                 elif chunk.HasField("{{ $tool.StreamProgress.ResultField | snakeCase }}"):
                     resp = chunk.{{ $tool.StreamProgress.ResultField | snakeCase }}
-                    return [types.TextContent(type="text", text=json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)))]
+                    return _tool_result(resp, {{ if (index $.ToolMeta (printf "%s_%s" $svcName $methName)).App }}True{{ else }}False{{ end }})
             raise ValueError("Stream ended without result")
 {{- else }}
             resp = await impl.{{ $tool.PyMethodName }}(req)
-            return [types.TextContent(type="text", text=json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)))]
+            return _tool_result(resp, {{ if (index $.ToolMeta (printf "%s_%s" $svcName $methName)).App }}True{{ else }}False{{ end }})
 {{- end }}
 {{- end }}
         raise ValueError(f"Unknown tool: {name}")
@@ -237,10 +308,21 @@ def register_{{ $svcName | snakeCase }}_mcp_handler(server: Server, impl: {{ $sv
             return [r for r in _resources if isinstance(r, types.ResourceTemplate)]
 
         @server.read_resource()
-        async def handle_read_resource(uri: str) -> str:
-{{- if and $svcOpts $svcOpts.App }}
-            if uri == _app_resource_uri_value:
-                return _default_app_html("{{ escapeQuotes $svcOpts.App.Name }}", "{{ $svcOpts.App.Version }}", "{{ escapeQuotes $svcOpts.App.Description }}")
+        async def handle_read_resource(uri: str) -> str | list[ReadResourceContents]:
+{{- if $svcOpts }}
+{{- range $svcOpts.AppViews }}
+            if uri == "{{ .URI }}":
+                return _app_view_contents({{ if .Entry }}{{ .HTML | pyString }}{{ else }}_default_app_html({{ .Name | pyString }}, {{ .Version | pyString }}, {{ .Description | pyString }}){{ end }}, {{ template "pyAppUI" . }})
+{{- end }}
+{{- range $svcOpts.Resources }}
+{{- if .RPC }}
+{{- $tool := index $methods .RPC }}
+            args = _resource_args({{ or .URI .URITemplate | pyString }}, str(uri), { {{- range $i, $b := .Bindings }}{{ if $i }}, {{ end }}"{{ $b.Var }}": "{{ $b.Field }}"{{ end }}}, {{ if .NameBinding }}True{{ else }}False{{ end }})
+            if args is not None:
+                resp = await impl.{{ $tool.PyMethodName }}(ParseDict(args, {{ $tool.PyRequestType }}()))
+                return _resource_contents(resp, "{{ .MimeType }}")
+{{- end }}
+{{- end }}
 {{- end }}
             return "{}"
 
@@ -262,6 +344,7 @@ def register_{{ $svcName | snakeCase }}_mcp_handler(server: Server, impl: {{ $sv
 {{- end }}
 
 {{- range $svcName, $methods := .Services }}
+{{- $svcOpts := index $.ServiceOpts $svcName }}
 
 def serve_{{ $svcName | snakeCase }}_mcp(
     impl: {{ $svcName }}MCPServer,
@@ -280,7 +363,27 @@ def serve_{{ $svcName | snakeCase }}_mcp(
     has_stdio = "stdio" in transports
     http_transports = [t for t in transports if t in ("sse", "streamable-http")]
 
-    mcp_server = FastMCP("{{ $svcName }}", json_response=True, streamable_http_path="/")
+    mcp_server = FastMCP(
+{{- if and $svcOpts $svcOpts.Server }}
+{{- $server := $svcOpts.Server }}
+        {{ $server.Name | pyString }},
+{{- with $server.Instructions }}
+        instructions={{ . | pyString }},
+{{- end }}
+{{- with $server.WebsiteURL }}
+        website_url={{ . | pyString }},
+{{- end }}
+{{- with $server.Icons }}
+        icons={{ pyIcons . }},
+{{- end }}
+        json_response=True,
+        streamable_http_path="/",
+    )
+{{- with $server.Version }}
+    mcp_server._mcp_server.version = {{ . | pyString }}
+{{- end }}
+{{- else }}"{{ $svcName }}", json_response=True, streamable_http_path="/")
+{{- end }}
     register_{{ $svcName | snakeCase }}_mcp_handler(mcp_server._mcp_server, impl)
 
     @contextlib.asynccontextmanager
@@ -322,20 +425,13 @@ def serve_{{ $svcName | snakeCase }}_mcp(
 def forward_to_{{ $svcName | snakeCase }}_mcp_client(server: Server, client: {{ $svcName }}MCPClient) -> None:
     """Register all {{ $svcName }} tools, prompts, and resources on the MCP server, forwarding each call to a remote gRPC server."""
 {{- $svcOpts := index $.ServiceOpts $svcName }}
-{{- if and $svcOpts $svcOpts.App }}
-    _app_resource_uri_value = _app_resource_uri("{{ $svcName }}")
-{{- end }}
 
     @server.list_tools()
     async def handle_list_tools() -> list[types.Tool]:
-{{- if and $svcOpts $svcOpts.App }}
-        return [_tool_with_app_meta(t, _app_resource_uri_value) for t in {{ $svcName }}_TOOLS]
-{{- else }}
         return {{ $svcName }}_TOOLS
-{{- end }}
 
     @server.call_tool()
-    async def handle_call_tool(name: str, arguments: dict[str, Any]) -> list[types.TextContent]:
+    async def handle_call_tool(name: str, arguments: dict[str, Any]) -> list[types.TextContent] | tuple[list[types.TextContent], dict[str, Any]]:
 {{- range $methName, $tool := $methods }}
         if name == "{{ $tool.ToolName }}":
 {{- if and $tool.MethodOpts $tool.MethodOpts.Elicitation }}
@@ -380,11 +476,11 @@ def forward_to_{{ $svcName | snakeCase }}_mcp_client(server: Server, client: {{
                         )
                 elif chunk.HasField("{{ $tool.StreamProgress.ResultField | snakeCase }}"):
                     resp = chunk.{{ $tool.StreamProgress.ResultField | snakeCase }}
-                    return [types.TextContent(type="text", text=json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)))]
+                    return _tool_result(resp, {{ if (index $.ToolMeta (printf "%s_%s" $svcName $methName)).App }}True{{ else }}False{{ end }})
             raise ValueError("Stream ended without result")
 {{- else }}
             resp = await client.{{ $tool.PyMethodName }}(req)
-            return [types.TextContent(type="text", text=json.dumps(MessageToDict(resp, preserving_proto_field_name=True, always_print_fields_with_no_presence=True)))]
+            return _tool_result(resp, {{ if (index $.ToolMeta (printf "%s_%s" $svcName $methName)).App }}True{{ else }}False{{ end }})
 {{- end }}
 {{- end }}
         raise ValueError(f"Unknown tool: {name}")
@@ -417,10 +513,21 @@ def forward_to_{{ $svcName | snakeCase }}_mcp_client(server: Server, client: {{
             return [r for r in _resources if isinstance(r, types.ResourceTemplate)]
 
         @server.read_resource()
-        async def handle_read_resource(uri: str) -> str:
-{{- if and $svcOpts $svcOpts.App }}
-            if uri == _app_resource_uri_value:
-                return _default_app_html("{{ escapeQuotes $svcOpts.App.Name }}", "{{ $svcOpts.App.Version }}", "{{ escapeQuotes $svcOpts.App.Description }}")
+        async def handle_read_resource(uri: str) -> str | list[ReadResourceContents]:
+{{- if $svcOpts }}
+{{- range $svcOpts.AppViews }}
+            if uri == "{{ .URI }}":
+                return _app_view_contents({{ if .Entry }}{{ .HTML | pyString }}{{ else }}_default_app_html({{ .Name | pyString }}, {{ .Version | pyString }}, {{ .Description | pyString }}){{ end }}, {{ template "pyAppUI" . }})
+{{- end }}
+{{- range $svcOpts.Resources }}
+{{- if .RPC }}
+{{- $tool := index $methods .RPC }}
+            args = _resource_args({{ or .URI .URITemplate | pyString }}, str(uri), { {{- range $i, $b := .Bindings }}{{ if $i }}, {{ end }}"{{ $b.Var }}": "{{ $b.Field }}"{{ end }}}, {{ if .NameBinding }}True{{ else }}False{{ end }})
+            if args is not None:
+                resp = await client.{{ $tool.PyMethodName }}(ParseDict(args, {{ $tool.PyRequestType }}()))
+                return _resource_contents(resp, "{{ .MimeType }}")
+{{- end }}
+{{- end }}
 {{- end }}
             return "{}"
 
@@ -440,3 +547,9 @@ def forward_to_{{ $svcName | snakeCase }}_mcp_client(server: Server, client: {{
             filtered = [v for v in values if v.lower().startswith(prefix)]
             return types.Completion(values=filtered, total=len(filtered), hasMore=False)
 {{- end }}
+
+{{- define "pyAppUI" -}}
+{ {{- with .CSP }}"csp": { {{- with .ConnectDomains }}"connectDomains": {{ pyList . }}, {{ end }}{{ with .ResourceDomains }}"resourceDomains": {{ pyList . }}, {{ end }}{{ with .FrameDomains }}"frameDomains": {{ pyList . }}, {{ end }}{{ with .BaseURIDomains }}"baseUriDomains": {{ pyList . }}{{ end }}}, {{ end }}
+{{- with .Domain }}"domain": {{ . | pyString }}, {{ end }}
+{{- if .PrefersBorder }}"prefersBorder": True{{ end }}}
+{{- end }}
@@ -9,23 +9,120 @@ use async_trait::async_trait;
 use rmcp::{ErrorData as McpError, RoleServer, ServerHandler, ServiceExt, model::*, service::RequestContext};
 use serde_json::{self, json, Value};
 
-fn make_tool(name: &str, description: &str, schema_json: &str) -> Tool {
+fn make_tool(name: &str, title: &str, description: &str, schema_json: &str, icons: Value) -> Tool {
     serde_json::from_value(json!({
-        "name": name, "description": description,
+        "name": name, "title": title, "description": description,
         "inputSchema": serde_json::from_str::<Value>(schema_json).unwrap(),
+        "icons": icons,
     })).expect("generated tool schema must be valid")
 }
 
-fn make_tool_with_app_meta(name: &str, description: &str, schema_json: &str, app_resource_uri: &str) -> Tool {
+fn make_tool_with_app_meta(name: &str, title: &str, description: &str, schema_json: &str, icons: Value, resource_uri: &str, visibility: &[&str]) -> Tool {
+    let mut ui = json!({ "resourceUri": resource_uri });
+    if !visibility.is_empty() {
+        ui["visibility"] = json!(visibility);
+    }
     serde_json::from_value(json!({
-        "name": name, "description": description,
+        "name": name, "title": title, "description": description,
         "inputSchema": serde_json::from_str::<Value>(schema_json).unwrap(),
-        "_meta": { "ui": { "resourceUri": app_resource_uri } }
+        "icons": icons,
+        "_meta": { "ui": ui }
     })).expect("generated tool schema must be valid")
 }
 
-fn app_resource_uri(service_name: &str) -> String {
-    format!("ui://{}/app.html", service_name.to_lowercase())
+const APP_MIME_TYPE: &str = "text/html;profile=mcp-app";
+
+fn app_view_contents(html: String, uri: String, ui: Value) -> ResourceContents {
+    let mut contents = json!({ "uri": uri, "mimeType": APP_MIME_TYPE, "text": html });
+    if ui.as_object().is_some_and(|ui| !ui.is_empty()) {
+        contents["_meta"] = json!({ "ui": ui });
+    }
+    serde_json::from_value(contents).expect("generated app view contents must be valid")
+}
+
+/// Renders an RPC response as a tool result, also carried as structured
+/// content for MCP App views.
+fn tool_result(result: Value, structured: bool) -> std::result::Result<CallToolResult, McpError> {
+    let text = serde_json::to_string(&result)
+        .map_err(|e| McpError::internal_error(format!("serialize response: {e}"), None))?;
+    let mut res = CallToolResult::success(vec![Content::text(text)]);
+    if structured {
+        res.structured_content = Some(if result.is_object() { result } else { json!({ "value": result }) });
+    }
+    Ok(res)
+}
+
+fn percent_decode(s: &str) -> String {
+    let mut out = Vec::with_capacity(s.len());
+    let mut i = 0;
+    while i < s.len() {
+        let byte = s.as_bytes()[i];
+        if byte == b'%' {
+            if let Some(b) = s.get(i + 1..i + 3).and_then(|h| u8::from_str_radix(h, 16).ok()) {
+                out.push(b);
+                i += 3;
+                continue;
+            }
+        }
+        out.push(byte);
+        i += 1;
+    }
+    String::from_utf8_lossy(&out).into_owned()
+}
+
+/// Maps the variables of `uri_template` in `uri` to request fields, or returns
+/// None if `uri` does not match the template.
+fn resource_args(uri_template: &str, uri: &str, bindings: &[(&str, &str)], name_binding: bool) -> Option<Value> {
+    let mut args = serde_json::Map::new();
+    if name_binding {
+        let name = uri.split_once("://").map_or(uri, |(_, path)| path);
+        args.insert("name".to_string(), Value::String(percent_decode(name)));
+    }
+    let (mut template, mut rest) = (uri_template, uri);
+    while let Some(start) = template.find('{') {
+        rest = rest.strip_prefix(&template[..start])?;
+        let end = start + template[start..].find('}')?;
+        let var = &template[start + 1..end];
+        template = &template[end + 1..];
+        let (var, reserved) = var.strip_prefix('+').map_or((var, false), |v| (v, true));
+        let literal = &template[..template.find('{').unwrap_or(template.len())];
+        let len = if literal.is_empty() { rest.len() } else { rest.find(literal)? };
+        let value = &rest[..len];
+        if !reserved && value.contains('/') {
+            return None;
+        }
+        rest = &rest[len..];
+        if let Some((_, field)) = bindings.iter().find(|(v, _)| *v == var) {
+            let mut target = &mut args;
+            let mut parts = field.split('.').peekable();
+            while let Some(part) = parts.next() {
+                if parts.peek().is_none() {
+                    target.insert(part.to_string(), Value::String(percent_decode(value)));
+                } else {
+                    target = target.entry(part.to_string()).or_insert_with(|| json!({})).as_object_mut()?;
+                }
+            }
+        }
+    }
+    (rest == template).then_some(Value::Object(args))
+}
+
+/// Renders an RPC result as resource contents: HttpBody data, the value of the
+/// response's only string or bytes field, or JSON.
+fn resource_contents(result: Value, uri: String, mime_type: &str, content: &str, field: &str) -> ResourceContents {
+    let blob = |data: &Value, mime_type: &str, uri: String| -> ResourceContents {
+        serde_json::from_value(json!({ "uri": uri, "mimeType": mime_type, "blob": data.as_str().unwrap_or_default() }))
+            .expect("generated blob resource must be valid")
+    };
+    match content {
+        "http_body" => {
+            let content_type = result["content_type"].as_str().filter(|s| !s.is_empty()).unwrap_or(mime_type);
+            blob(&result["data"], content_type, uri)
+        }
+        "bytes" => blob(&result[field], mime_type, uri),
+        "string" => ResourceContents::text(result[field].as_str().unwrap_or_default(), uri).with_mime_type(mime_type),
+        _ => ResourceContents::text(result.to_string(), uri).with_mime_type("application/json"),
+    }
 }
 
 fn default_app_html(app_name: &str, version: &str, description: &str) -> String {
@@ -35,7 +132,7 @@ fn default_app_html(app_name: &str, version: &str, description: &str) -> String
 {{- $svcOpts := index $.ServiceOpts $svcName }}
 {{- $hasResources := false }}
 {{- if and $svcOpts $svcOpts.Resources }}{{ $hasResources = true }}{{ end }}
-{{- if and $svcOpts $svcOpts.App }}{{ $hasResources = true }}{{ end }}
+{{- if and $svcOpts $svcOpts.AppViews }}{{ $hasResources = true }}{{ end }}
 {{- range $methName, $info := $methods }}
 const {{ $info.ConstName }}_SCHEMA_JSON: &str = r##"{{ index $.SchemaJSON (printf "%s_%s" $svcName $methName) }}"##;
 {{- end }}
@@ -59,16 +156,14 @@ impl<T: {{ $svcName }}McpServer> {{ $svcName }}McpHandler<T> {
     pub fn new(svc: T) -> Self { Self { inner: Arc::new(svc) } }
 
     fn tools() -> Vec<Tool> {
-{{- if and $svcOpts $svcOpts.App }}
-        let app_uri = app_resource_uri("{{ $svcName }}");
-{{- end }}
         vec![
         {{- range $methName, $info := $methods }}
         {{- if not $info.StreamProgress }}
-{{- if and $svcOpts $svcOpts.App }}
-            make_tool_with_app_meta("{{ $info.ToolName }}", "{{ $info.Description | rsEscape }}", {{ $info.ConstName }}_SCHEMA_JSON, &app_uri),
+{{- $meta := index $.ToolMeta (printf "%s_%s" $svcName $methName) }}
+{{- if $meta.App }}
+            make_tool_with_app_meta("{{ $info.ToolName }}", "{{ $meta.Title | rsEscape }}", "{{ $info.Description | rsEscape }}", {{ $info.ConstName }}_SCHEMA_JSON, {{ with $meta.Icons }}json!({{ jsonIcons . }}){{ else }}Value::Null{{ end }}, "{{ $meta.App.ResourceURI }}", &[{{ range $i, $v := $meta.App.Visibility }}{{ if $i }}, {{ end }}"{{ $v }}"{{ end }}]),
 {{- else }}
-            make_tool("{{ $info.ToolName }}", "{{ $info.Description | rsEscape }}", {{ $info.ConstName }}_SCHEMA_JSON),
+            make_tool("{{ $info.ToolName }}", "{{ $meta.Title | rsEscape }}", "{{ $info.Description | rsEscape }}", {{ $info.ConstName }}_SCHEMA_JSON, {{ with $meta.Icons }}json!({{ jsonIcons . }}){{ else }}Value::Null{{ end }}),
 {{- end }}
         {{- end }}
         {{- end }}
@@ -76,15 +171,13 @@ impl<T: {{ $svcName }}McpServer> {{ $svcName }}McpHandler<T> {
     }
 
     fn all_tools() -> Vec<Tool> {
-{{- if and $svcOpts $svcOpts.App }}
-        let app_uri = app_resource_uri("{{ $svcName }}");
-{{- end }}
         vec![
         {{- range $methName, $info := $methods }}
-{{- if and $svcOpts $svcOpts.App }}
-            make_tool_with_app_meta("{{ $info.ToolName }}", "{{ $info.Description | rsEscape }}", {{ $info.ConstName }}_SCHEMA_JSON, &app_uri),
+{{- $meta := index $.ToolMeta (printf "%s_%s" $svcName $methName) }}
+{{- if $meta.App }}
+            make_tool_with_app_meta("{{ $info.ToolName }}", "{{ $meta.Title | rsEscape }}", "{{ $info.Description | rsEscape }}", {{ $info.ConstName }}_SCHEMA_JSON, {{ with $meta.Icons }}json!({{ jsonIcons . }}){{ else }}Value::Null{{ end }}, "{{ $meta.App.ResourceURI }}", &[{{ range $i, $v := $meta.App.Visibility }}{{ if $i }}, {{ end }}"{{ $v }}"{{ end }}]),
 {{- else }}
-            make_tool("{{ $info.ToolName }}", "{{ $info.Description | rsEscape }}", {{ $info.ConstName }}_SCHEMA_JSON),
+            make_tool("{{ $info.ToolName }}", "{{ $meta.Title | rsEscape }}", "{{ $info.Description | rsEscape }}", {{ $info.ConstName }}_SCHEMA_JSON, {{ with $meta.Icons }}json!({{ jsonIcons . }}){{ else }}Value::Null{{ end }}),
 {{- end }}
         {{- end }}
         ]
@@ -107,7 +200,11 @@ impl<T: {{ $svcName }}McpServer> {{ $svcName }}McpHandler<T> {
         {{- if and $info.MethodOpts $info.MethodOpts.Prompt }}
             serde_json::from_value(json!({
                 "name": "{{ $info.MethodOpts.Prompt.Name }}",
+                "title": "{{ $info.MethodOpts.Prompt.Title | rsEscape }}",
                 "description": "{{ $info.MethodOpts.Prompt.Description | rsEscape }}",
+{{- with $info.MethodOpts.Prompt.Icons }}
+                "icons": {{ jsonIcons . }},
+{{- end }}
                 "arguments": [
                 {{- range $info.MethodOpts.Prompt.Arguments }}
                     {"name": "{{ .Name }}", "description": "{{ .Description | rsEscape }}", "required": {{ if .Required }}true{{ else }}false{{ end }}},
@@ -146,16 +243,16 @@ impl<T: {{ $svcName }}McpServer> {{ $svcName }}McpHandler<T> {
         {{- range $svcOpts.Resources }}
         {{- if .URI }}
             serde_json::from_value(json!({
-                "uri": "{{ .URI }}", "name": "{{ .Name }}",
-                "description": "{{ .Description | rsEscape }}", "mimeType": "{{ .MimeType }}"
+                "uri": "{{ .URI }}", "name": "{{ .Name }}",{{ with .Title }} "title": "{{ . | rsEscape }}",{{ end }}
+                "description": "{{ .Description | rsEscape }}", "mimeType": "{{ .MimeType }}"{{ with .Icons }},
+                "icons": {{ jsonIcons . }}{{ end }}
             })).expect("generated resource must be valid"),
         {{- end }}
         {{- end }}
-        {{- if and $svcOpts $svcOpts.App }}
+        {{- range $svcOpts.AppViews }}
             serde_json::from_value(json!({
-                "uri": app_resource_uri("{{ $svcName }}"),
-                "name": "{{ $svcOpts.App.Name }}",
-                "mimeType": "text/html"
+                "uri": "{{ .URI }}", "name": "{{ .Name | rsEscape }}",{{ with .Description }} "description": "{{ . | rsEscape }}",{{ end }}
+                "mimeType": APP_MIME_TYPE
             })).expect("generated app resource must be valid"),
         {{- end }}
         ]
@@ -166,8 +263,9 @@ impl<T: {{ $svcName }}McpServer> {{ $svcName }}McpHandler<T> {
         {{- range $svcOpts.Resources }}
         {{- if .URITemplate }}
             serde_json::from_value(json!({
-                "uriTemplate": "{{ .URITemplate }}", "name": "{{ .Name }}",
-                "description": "{{ .Description | rsEscape }}", "mimeType": "{{ .MimeType }}"
+                "uriTemplate": "{{ .URITemplate }}", "name": "{{ .Name }}",{{ with .Title }} "title": "{{ . | rsEscape }}",{{ end }}
+                "description": "{{ .Description | rsEscape }}", "mimeType": "{{ .MimeType }}"{{ with .Icons }},
+                "icons": {{ jsonIcons . }}{{ end }}
             })).expect("generated resource template must be valid"),
         {{- end }}
         {{- end }}
@@ -192,7 +290,27 @@ impl<T: {{ $svcName }}McpServer> ServerHandler for {{ $svcName }}McpHandler<T> {
 {{- end }}
                 .build(),
         )
+{{- if and $svcOpts $svcOpts.Server }}
+{{- $server := $svcOpts.Server }}
+        .with_server_info(serde_json::from_value(json!({
+            "name": "{{ $server.Name | rsEscape }}",
+            "version": "{{ or $server.Version "0.1.0" | rsEscape }}",
+{{- with $server.Title }}
+            "title": "{{ . | rsEscape }}",
+{{- end }}
+{{- with $server.WebsiteURL }}
+            "websiteUrl": "{{ . | rsEscape }}",
+{{- end }}
+{{- with $server.Icons }}
+            "icons": {{ jsonIcons . }},
+{{- end }}
+        })).expect("generated server info must be valid"))
+{{- with $server.Instructions }}
+        .with_instructions("{{ . | rsEscape }}")
+{{- end }}
+{{- else }}
         .with_server_info(Implementation::new("{{ $svcName }}", "0.1.0"))
+{{- end }}
     }
 
     async fn list_tools(&self, _: Option<PaginatedRequestParams>, _: RequestContext<RoleServer>) -> std::result::Result<ListToolsResult, McpError> {
@@ -231,9 +349,7 @@ impl<T: {{ $svcName }}McpServer> ServerHandler for {{ $svcName }}McpHandler<T> {
                 }
 {{- end }}
                 let result = self.inner.{{ $info.RsMethodName }}(args).await?;
-                let text = serde_json::to_string(&result)
-                    .map_err(|e| McpError::internal_error(format!("serialize response: {e}"), None))?;
-                Ok(CallToolResult::success(vec![Content::text(text)]))
+                tool_result(result, {{ if (index $.ToolMeta (printf "%s_%s" $svcName $methName)).App }}true{{ else }}false{{ end }})
             }
         {{- end }}
         {{- end }}
@@ -297,14 +413,34 @@ impl<T: {{ $svcName }}McpServer> ServerHandler for {{ $svcName }}McpHandler<T> {
     }
 
     async fn read_resource(&self, request: ReadResourceRequestParams, _: RequestContext<RoleServer>) -> std::result::Result<ReadResourceResult, McpError> {
-{{- if and $svcOpts $svcOpts.App }}
-        let app_uri = app_resource_uri("{{ $svcName }}");
-        if request.uri == app_uri {
-            let html = default_app_html("{{ $svcOpts.App.Name | rsEscape }}", "{{ $svcOpts.App.Version }}", "{{ $svcOpts.App.Description | rsEscape }}");
+{{- if $svcOpts }}
+{{- range $svcOpts.AppViews }}
+        if request.uri == "{{ .URI }}" {
+{{- if .Entry }}
+            let html = "{{ .HTML | rsEscape }}".to_string();
+{{- else }}
+            let html = default_app_html("{{ .Name | rsEscape }}", "{{ .Version | rsEscape }}", "{{ .Description | rsEscape }}");
+{{- end }}
+            return Ok(ReadResourceResult::new(vec![app_view_contents(html, request.uri, json!({
+                {{- with .CSP }}"csp": { {{- with .ConnectDomains }}"connectDomains": {{ jsonList . }}, {{ end }}{{ with .ResourceDomains }}"resourceDomains": {{ jsonList . }}, {{ end }}{{ with .FrameDomains }}"frameDomains": {{ jsonList . }}, {{ end }}{{ with .BaseURIDomains }}"baseUriDomains": {{ jsonList . }}{{ end }}}, {{ end }}
+                {{- with .Domain }}"domain": "{{ . | rsEscape }}", {{ end }}
+                {{- if .PrefersBorder }}"prefersBorder": true{{ end -}}
+            }))]));
+        }
+{{- end }}
+{{- end }}
+{{- if $svcOpts }}
+{{- range $svcOpts.Resources }}
+{{- if .RPC }}
+{{- $info := index $methods .RPC }}
+        if let Some(args) = resource_args("{{ or .URI .URITemplate | rsEscape }}", &request.uri, &[{{ range $i, $b := .Bindings }}{{ if $i }}, {{ end }}("{{ $b.Var }}", "{{ $b.Field }}"){{ end }}], {{ .NameBinding }}) {
+            let result = self.inner.{{ $info.RsMethodName }}(args).await?;
             return Ok(ReadResourceResult::new(vec![
-                ResourceContents::text(html, request.uri).with_mime_type("text/html")
+                resource_contents(result, request.uri, "{{ .MimeType }}", "{{ .Content }}", "{{ .ContentField }}")
             ]));
         }
+{{- end }}
+{{- end }}
 {{- end }}
         Ok(ReadResourceResult::new(vec![ResourceContents::text("{}", request.uri)]))
     }
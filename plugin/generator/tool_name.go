@@ -5,6 +5,7 @@ import (
 	"math/big"
 	"regexp"
 	"strings"
+	"unicode"
 )
 
 const maxToolNameLen = 64
@@ -49,6 +50,40 @@ func BuildToolName(fullName string) string {
 	return MangleHeadIfTooLong(name, maxToolNameLen)
 }
 
+// HumanizeName turns a method or prompt name into a display title by splitting
+// CamelCase and snake_case words and capitalizing each one:
+// "CreateTodo" → "Create Todo", "GetHTTPConfig" → "Get HTTP Config",
+// "summarize_todos" → "Summarize Todos".
+func HumanizeName(name string) string {
+	runes := []rune(name)
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			word[0] = unicode.ToUpper(word[0])
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+	for i, r := range runes {
+		if r == '_' || r == '-' || r == '.' || unicode.IsSpace(r) {
+			flush()
+			continue
+		}
+		if i > 0 && len(word) > 0 {
+			prev := runes[i-1]
+			lowerToUpper := unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev))
+			acronymEnd := unicode.IsUpper(r) && unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if lowerToUpper || acronymEnd {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return strings.Join(words, " ")
+}
+
 // MangleHeadIfTooLong truncates the head of name and prepends a short hash
 // when name exceeds maxLen.  The tail (most-specific part) is preserved.
 func MangleHeadIfTooLong(name string, maxLen int) string {
@@ -0,0 +1,17 @@
+package generator
+
+import "testing"
+
+func TestHumanizeName(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"CreateTodo", "Create Todo"},
+		{"GetHTTPConfig", "Get HTTP Config"},
+		{"list_v2_items", "List V2 Items"},
+		{"already split", "Already Split"},
+	}
+	for _, tt := range tests {
+		if got := HumanizeName(tt.in); got != tt.want {
+			t.Errorf("HumanizeName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
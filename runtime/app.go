@@ -0,0 +1,131 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+
+	"github.com/machanirobotics/grpc-mcp-gateway/internal/appview"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/proto"
+)
+
+// AppMIMEType is the media type of MCP App view resources.
+const AppMIMEType = "text/html;profile=mcp-app"
+
+// AppCSP lists the origins an MCP App view needs. Hosts add them to the
+// Content Security Policy of the view's sandbox.
+type AppCSP struct {
+	ConnectDomains  []string `json:"connectDomains,omitempty"`
+	ResourceDomains []string `json:"resourceDomains,omitempty"`
+	FrameDomains    []string `json:"frameDomains,omitempty"`
+	BaseURIDomains  []string `json:"baseUriDomains,omitempty"`
+}
+
+// AppView is an MCP App view declared in proto options.
+type AppView struct {
+	// Entry and Assets are the view's files, read from Config.AppFS when set.
+	Entry  string
+	Assets []string
+	// HTML is the entry with its assets inlined, embedded at generate time.
+	HTML          string
+	CSP           *AppCSP
+	Domain        string
+	PrefersBorder bool
+}
+
+// WithAppFS returns an Option that serves MCP App views from fsys, such as
+// an embed.FS or os.DirFS, instead of the HTML embedded at generate time.
+// Entry and asset paths from the proto options are resolved against fsys
+// on every read, so views can be edited without regenerating code.
+func WithAppFS(fsys fs.FS) Option {
+	return func(c *Config) {
+		c.AppFS = fsys
+	}
+}
+
+// AppViewHandler returns a resource handler serving view with the MCP App
+// media type and its CSP, domain and border preferences under _meta.ui.
+func AppViewHandler(cfg *Config, view *AppView) mcp.ResourceHandler {
+	ui := map[string]any{}
+	if view.CSP != nil {
+		ui["csp"] = view.CSP
+	}
+	if view.Domain != "" {
+		ui["domain"] = view.Domain
+	}
+	if view.PrefersBorder {
+		ui["prefersBorder"] = true
+	}
+	var meta mcp.Meta
+	if len(ui) > 0 {
+		meta = mcp.Meta{"ui": ui}
+	}
+	return func(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		html := view.HTML
+		if cfg != nil && cfg.AppFS != nil && view.Entry != "" {
+			var err error
+			if html, err = appview.Bundle(cfg.AppFS, view.Entry, view.Assets); err != nil {
+				return nil, err
+			}
+		}
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{
+				{URI: req.Params.URI, MIMEType: AppMIMEType, Text: html, Meta: meta},
+			},
+		}, nil
+	}
+}
+
+// StructuredFormatter wraps f so that results rendered with FormatResult
+// also carry the response as structuredContent (protojson with proto field
+// names), which MCP App views receive from the host to render the result.
+// Collected streams carry {"items": [...]}.
+func StructuredFormatter(f ResultFormatter) ResultFormatter {
+	if f == nil {
+		f = JSONFormatter
+	}
+	if _, ok := f.(structuredFormatter); ok {
+		return f
+	}
+	return structuredFormatter{f}
+}
+
+type structuredFormatter struct {
+	ResultFormatter
+}
+
+// unwrapStructured returns the formatter wrapped by StructuredFormatter, or
+// f and false.
+func unwrapStructured(f ResultFormatter) (ResultFormatter, bool) {
+	if sf, ok := f.(structuredFormatter); ok {
+		return sf.ResultFormatter, true
+	}
+	return f, false
+}
+
+// structuredMessage renders msg as a JSON object. Well-known types whose
+// JSON form is not an object, such as google.protobuf.StringValue, are
+// wrapped as {"value": ...}.
+func structuredMessage(msg proto.Message) (json.RawMessage, error) {
+	b, err := protoJSON.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > 0 && b[0] != '{' {
+		return json.Marshal(map[string]json.RawMessage{"value": b})
+	}
+	return b, nil
+}
+
+func structuredList(msgs []proto.Message) (json.RawMessage, error) {
+	items := make([]json.RawMessage, len(msgs))
+	for i, msg := range msgs {
+		b, err := protoJSON.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = b
+	}
+	return json.Marshal(map[string]any{"items": items})
+}
@@ -0,0 +1,61 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestAppViewHandler(t *testing.T) {
+	fsys := fstest.MapFS{
+		"views/index.html": {Data: []byte(`<html><script src="./app.js"></script></html>`)},
+		"views/app.js":     {Data: []byte(`run()`)},
+	}
+	view := &AppView{
+		Entry:         "views/index.html",
+		Assets:        []string{"views/app.js"},
+		HTML:          "<html>embedded</html>",
+		CSP:           &AppCSP{ConnectDomains: []string{"https://api.example.com"}},
+		PrefersBorder: true,
+	}
+	tests := []struct {
+		name     string
+		cfg      *Config
+		view     *AppView
+		wantHTML string
+		wantMeta string
+	}{
+		{"embedded HTML", nil, view, "<html>embedded</html>", `{"ui":{"csp":{"connectDomains":["https://api.example.com"]},"prefersBorder":true}}`},
+		{"served from AppFS", &Config{AppFS: fsys}, view, "<html><script>run()</script></html>", `{"ui":{"csp":{"connectDomains":["https://api.example.com"]},"prefersBorder":true}}`},
+		{"no meta", &Config{}, &AppView{HTML: "<p>hi</p>"}, "<p>hi</p>", `null`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := AppViewHandler(tt.cfg, tt.view)(context.Background(), &mcp.ReadResourceRequest{Params: &mcp.ReadResourceParams{URI: "ui://todo/list"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := res.Contents[0]
+			if c.URI != "ui://todo/list" || c.MIMEType != AppMIMEType {
+				t.Errorf("contents = %s %s, want ui://todo/list %s", c.URI, c.MIMEType, AppMIMEType)
+			}
+			if c.Text != tt.wantHTML {
+				t.Errorf("text = %q, want %q", c.Text, tt.wantHTML)
+			}
+			if got, _ := json.Marshal(c.Meta); string(got) != tt.wantMeta {
+				t.Errorf("_meta = %s, want %s", got, tt.wantMeta)
+			}
+		})
+	}
+}
+
+func TestAppViewHandler_MissingAsset(t *testing.T) {
+	cfg := &Config{AppFS: fstest.MapFS{"index.html": {Data: []byte(`<html></html>`)}}}
+	h := AppViewHandler(cfg, &AppView{Entry: "index.html", Assets: []string{"app.js"}})
+	if _, err := h(context.Background(), &mcp.ReadResourceRequest{Params: &mcp.ReadResourceParams{URI: "ui://x"}}); err == nil {
+		t.Error("expected an error for a missing asset")
+	}
+}
@@ -0,0 +1,417 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	grpcmd "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// BidiStreamURIPrefix is the scheme of the resources that expose the messages
+// received on a bidirectional stream opened by a tool.
+const BidiStreamURIPrefix = "streams://"
+
+// maxBidiBuffered bounds how many received messages are kept per stream.
+// Older messages are dropped; their sequence numbers are not reused.
+const maxBidiBuffered = 1000
+
+// BidiClient is the client side of a bidirectional RPC, as implemented by
+// generated gRPC client streams and by InProcessBidiStream.Client.
+type BidiClient[Req, Resp any] interface {
+	Send(Req) error
+	Recv() (Resp, error)
+	CloseSend() error
+}
+
+// bidiMessage is a received message with its 1-based sequence number.
+type bidiMessage struct {
+	Seq     int64           `json:"seq"`
+	Message json.RawMessage `json:"message"`
+}
+
+// bidiStream is an open bidirectional stream owned by one MCP session.
+type bidiStream struct {
+	id        string
+	session   *mcp.ServerSession
+	send      func(context.Context, json.RawMessage) error
+	coerce    *coercionLog // coercion settings of the opening tool call, or nil
+	closeSend func() error
+	cancel    context.CancelFunc
+	sendMu    sync.Mutex // serializes send and closeSend
+
+	mu       sync.Mutex
+	seq      int64
+	messages []bidiMessage
+	done     bool
+	err      error
+}
+
+// bidiStreams holds the open streams of this process, keyed by stream ID.
+var bidiStreams sync.Map // id -> *bidiStream
+
+// BidiStreamURI returns the resource URI for the stream with the given ID.
+func BidiStreamURI(id string) string { return BidiStreamURIPrefix + id }
+
+// OpenBidiStream opens a bidirectional stream for the tool call req and
+// returns its stream ID and resource URI. Request messages in the
+// StreamRequestsProperty argument are sent right away; more can be sent
+// with SendBidiStream.
+//
+// Bound fields of every request message, including those sent later with
+// SendBidiStream, are set by BindFields from the context and request of
+// this call. The stream outlives the tool call and is closed when the MCP
+// session ends, so calls without a session are rejected.
+// Every received message is buffered for the streams:// resource, announced
+// with a resource-updated notification, and sent to the session as a log
+// message whose logger is the resource URI.
+func OpenBidiStream[Req, Resp proto.Message](ctx context.Context, s *mcp.Server, req *mcp.CallToolRequest, cfg *Config, args json.RawMessage, newReq func() Req, open func(ctx context.Context) (BidiClient[Req, Resp], error)) (*mcp.CallToolResult, error) {
+	if req.Session == nil {
+		return ErrorResult("bidirectional streams need an MCP session"), nil
+	}
+	initial, err := DecodeStreamRequests(ctx, args, newReq)
+	if err != nil {
+		return ErrorResult(err.Error()), nil
+	}
+	for _, m := range initial {
+		if err := BindFields(ctx, req, cfg, m); err != nil {
+			return ErrorResult(err.Error()), nil
+		}
+	}
+	streamCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	client, err := open(streamCtx)
+	if err != nil {
+		cancel()
+		return HandleError(err)
+	}
+
+	st := &bidiStream{
+		id:      newTaskID(),
+		session: req.Session,
+		send: func(ctx context.Context, raw json.RawMessage) error {
+			msg := newReq()
+			if err := UnmarshalArgs(ctx, raw, msg); err != nil {
+				return err
+			}
+			if err := BindFields(streamCtx, req, cfg, msg); err != nil {
+				return err
+			}
+			return client.Send(msg)
+		},
+		closeSend: client.CloseSend,
+		cancel:    cancel,
+		coerce:    coercionLogFrom(ctx),
+	}
+	bidiStreams.Store(st.id, st)
+	uri := BidiStreamURI(st.id)
+
+	go func() {
+		for {
+			msg, err := client.Recv()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				st.finish(err)
+				break
+			}
+			b, err := protoJSON.Marshal(msg)
+			if err != nil {
+				continue
+			}
+			st.append(b)
+			_ = s.ResourceUpdated(streamCtx, &mcp.ResourceUpdatedNotificationParams{URI: uri})
+			_ = st.session.Log(streamCtx, &mcp.LoggingMessageParams{Level: "info", Logger: uri, Data: json.RawMessage(b)})
+		}
+		_ = s.ResourceUpdated(streamCtx, &mcp.ResourceUpdatedNotificationParams{URI: uri})
+	}()
+	go func() {
+		_ = st.session.Wait()
+		st.close()
+	}()
+
+	st.sendMu.Lock()
+	defer st.sendMu.Unlock()
+	for _, m := range initial {
+		if err := client.Send(m); err != nil {
+			st.close()
+			return HandleError(err)
+		}
+	}
+	b, err := json.Marshal(map[string]any{"stream_id": st.id, "resource": uri})
+	if err != nil {
+		return nil, err
+	}
+	return TextResult(string(b)), nil
+}
+
+// close cancels the stream and forgets it.
+func (st *bidiStream) close() {
+	st.cancel()
+	bidiStreams.Delete(st.id)
+}
+
+func (st *bidiStream) append(msg json.RawMessage) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.seq++
+	st.messages = append(st.messages, bidiMessage{Seq: st.seq, Message: msg})
+	if len(st.messages) > maxBidiBuffered {
+		st.messages = st.messages[len(st.messages)-maxBidiBuffered:]
+	}
+}
+
+func (st *bidiStream) finish(err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.done = true
+	st.err = err
+}
+
+// snapshot returns the JSON state of the stream: its buffered messages, the
+// last sequence number, and whether the server has finished.
+func (st *bidiStream) snapshot() ([]byte, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	v := struct {
+		StreamID string        `json:"stream_id"`
+		Done     bool          `json:"done"`
+		Error    *grpcError    `json:"error,omitempty"`
+		LastSeq  int64         `json:"last_seq"`
+		Messages []bidiMessage `json:"messages"`
+	}{StreamID: st.id, Done: st.done, LastSeq: st.seq, Messages: st.messages}
+	if v.Messages == nil {
+		v.Messages = []bidiMessage{}
+	}
+	if st.err != nil {
+		e := grpcError{Message: st.err.Error()}
+		if s, ok := status.FromError(st.err); ok {
+			e = grpcErrorFromStatus(s)
+		}
+		v.Error = &e
+	}
+	return json.Marshal(v)
+}
+
+// lookupBidiStream returns the stream with id if it belongs to session.
+func lookupBidiStream(id string, session *mcp.ServerSession) (*bidiStream, error) {
+	v, ok := bidiStreams.Load(id)
+	if !ok {
+		return nil, fmt.Errorf("stream %q not found", id)
+	}
+	st := v.(*bidiStream)
+	if st.session != session {
+		return nil, fmt.Errorf("stream %q belongs to another session", id)
+	}
+	return st, nil
+}
+
+// SendBidiStream handles the send tool of a bidirectional RPC. Arguments are
+// stream_id, the StreamRequestsProperty array, and close_send, which
+// half-closes the stream after the messages are sent.
+func SendBidiStream(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var args struct {
+		StreamID  string            `json:"stream_id"`
+		Requests  []json.RawMessage `json:"requests"`
+		CloseSend bool              `json:"close_send"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+		return ErrorResult(fmt.Sprintf("invalid arguments: %v", err)), nil
+	}
+	st, err := lookupBidiStream(args.StreamID, req.Session)
+	if err != nil {
+		return ErrorResult(err.Error()), nil
+	}
+	var log *coercionLog
+	if st.coerce != nil {
+		ctx, log = withCoercionLog(ctx, st.coerce.typeShorthand)
+	}
+	st.sendMu.Lock()
+	defer st.sendMu.Unlock()
+	for i, raw := range args.Requests {
+		if err := st.send(ctx, raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return ErrorResult(fmt.Sprintf("stream %s is closed", st.id)), nil
+			}
+			return ErrorResult(fmt.Sprintf("%s[%d]: %v", StreamRequestsProperty, i, err)), nil
+		}
+	}
+	if args.CloseSend {
+		if err := st.closeSend(); err != nil {
+			return HandleError(err)
+		}
+	}
+	b, err := json.Marshal(map[string]any{"stream_id": st.id, "sent": len(args.Requests), "resource": BidiStreamURI(st.id)})
+	if err != nil {
+		return nil, err
+	}
+	res := TextResult(string(b))
+	log.annotate(res)
+	return res, nil
+}
+
+// RegisterBidiStreamResource adds the streams://{stream_id} resource template
+// that returns the messages received on a stream. Generated handlers call
+// this for services with bidirectional RPCs; registering twice is harmless.
+func RegisterBidiStreamResource(s *mcp.Server) {
+	s.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: BidiStreamURIPrefix + "{stream_id}",
+		Name:        "streams",
+		Description: "Messages received on a bidirectional stream opened by a tool, with sequence numbers.",
+		MIMEType:    "application/json",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		uri := req.Params.URI
+		st, err := lookupBidiStream(strings.TrimPrefix(uri, BidiStreamURIPrefix), req.Session)
+		if err != nil {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		b, err := st.snapshot()
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: "application/json", Text: string(b)}}}, nil
+	})
+}
+
+// InProcessBidiStream connects a bidirectional gRPC server method to a
+// client in the same process. The stream itself is the server side
+// (Recv/Send); Client returns the client side.
+//
+// Usage:
+//
+//	stream := runtime.NewInProcessBidiStream[*MyRequest, *MyResponse](ctx)
+//	go func() { stream.Finish(srv.MyBidiRPC(stream)) }()
+//	client := stream.Client()
+type InProcessBidiStream[Req, Resp any] struct {
+	ctx    context.Context
+	reqCh  chan Req
+	respCh chan Resp
+	done   chan struct{}
+	err    error // set by Finish before respCh is closed
+
+	sendMu     sync.Mutex // guards reqCh against sends after CloseSend
+	sendClosed bool
+}
+
+// NewInProcessBidiStream creates a stream whose buffers hold 16 messages in
+// each direction.
+func NewInProcessBidiStream[Req, Resp any](ctx context.Context) *InProcessBidiStream[Req, Resp] {
+	return &InProcessBidiStream[Req, Resp]{
+		ctx:    ctx,
+		reqCh:  make(chan Req, 16),
+		respCh: make(chan Resp, 16),
+		done:   make(chan struct{}),
+	}
+}
+
+// Recv returns the next client message, io.EOF after CloseSend, or the
+// context error.
+func (s *InProcessBidiStream[Req, Resp]) Recv() (Req, error) {
+	var zero Req
+	select {
+	case r, ok := <-s.reqCh:
+		if !ok {
+			return zero, io.EOF
+		}
+		return r, nil
+	case <-s.ctx.Done():
+		return zero, s.ctx.Err()
+	}
+}
+
+// Send delivers a server message to the client.
+func (s *InProcessBidiStream[Req, Resp]) Send(resp Resp) error {
+	select {
+	case s.respCh <- resp:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+// Finish records the server method's result and ends the stream. Must be
+// called exactly once, after the server method returns.
+func (s *InProcessBidiStream[Req, Resp]) Finish(err error) {
+	s.err = err
+	close(s.done)
+	close(s.respCh)
+}
+
+// Context returns the stream context.
+func (s *InProcessBidiStream[Req, Resp]) Context() context.Context { return s.ctx }
+
+// The following methods satisfy grpc.ServerStream but are no-ops for in-process
+// use since there is no network transport.
+func (s *InProcessBidiStream[Req, Resp]) SetHeader(grpcmd.MD) error  { return nil }
+func (s *InProcessBidiStream[Req, Resp]) SendHeader(grpcmd.MD) error { return nil }
+func (s *InProcessBidiStream[Req, Resp]) SetTrailer(grpcmd.MD)       {}
+
+// SendMsg type-asserts m to Resp and forwards it to Send.
+func (s *InProcessBidiStream[Req, Resp]) SendMsg(m any) error {
+	if msg, ok := m.(Resp); ok {
+		return s.Send(msg)
+	}
+	return fmt.Errorf("InProcessBidiStream.SendMsg: unexpected type %T", m)
+}
+
+// RecvMsg is not supported; use Recv.
+func (s *InProcessBidiStream[Req, Resp]) RecvMsg(any) error {
+	return fmt.Errorf("InProcessBidiStream.RecvMsg: not supported")
+}
+
+// Client returns the client side of the stream.
+func (s *InProcessBidiStream[Req, Resp]) Client() BidiClient[Req, Resp] {
+	return inProcessBidiClient[Req, Resp]{s}
+}
+
+type inProcessBidiClient[Req, Resp any] struct {
+	s *InProcessBidiStream[Req, Resp]
+}
+
+// Send delivers a client message, or returns io.EOF once the server has finished.
+func (c inProcessBidiClient[Req, Resp]) Send(req Req) error {
+	c.s.sendMu.Lock()
+	defer c.s.sendMu.Unlock()
+	if c.s.sendClosed {
+		return errors.New("InProcessBidiStream: send after CloseSend")
+	}
+	select {
+	case c.s.reqCh <- req:
+		return nil
+	case <-c.s.done:
+		return io.EOF
+	case <-c.s.ctx.Done():
+		return c.s.ctx.Err()
+	}
+}
+
+// Recv returns the next server message, then the server's error or io.EOF.
+func (c inProcessBidiClient[Req, Resp]) Recv() (Resp, error) {
+	r, ok := <-c.s.respCh
+	if !ok {
+		var zero Resp
+		if c.s.err != nil {
+			return zero, c.s.err
+		}
+		return zero, io.EOF
+	}
+	return r, nil
+}
+
+// CloseSend signals that the client will send no more messages.
+func (c inProcessBidiClient[Req, Resp]) CloseSend() error {
+	c.s.sendMu.Lock()
+	defer c.s.sendMu.Unlock()
+	if !c.s.sendClosed {
+		c.s.sendClosed = true
+		close(c.s.reqCh)
+	}
+	return nil
+}
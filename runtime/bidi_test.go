@@ -0,0 +1,141 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// failingBidiClient fails every Send and blocks Recv until its context ends.
+type failingBidiClient struct {
+	ctx context.Context
+}
+
+func (c failingBidiClient) Send(*wrapperspb.StringValue) error { return errors.New("send failed") }
+func (c failingBidiClient) CloseSend() error                   { return nil }
+func (c failingBidiClient) Recv() (*wrapperspb.StringValue, error) {
+	<-c.ctx.Done()
+	return nil, io.EOF
+}
+
+func TestOpenBidiStream(t *testing.T) {
+	tests := []struct {
+		name    string
+		session bool
+		args    string
+		wantErr bool
+	}{
+		{"no session", false, `{}`, true},
+		{"initial send fails", true, `{"requests":[{"value":"hi"}]}`, true},
+		{"opened", true, `{}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var streamCtx context.Context
+			open := func(ctx context.Context) (BidiClient[*wrapperspb.StringValue, *wrapperspb.StringValue], error) {
+				streamCtx = ctx
+				return failingBidiClient{ctx}, nil
+			}
+			s := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+			call := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				if !tt.session {
+					req.Session = nil
+				}
+				return OpenBidiStream(ctx, s, req, &Config{}, req.Params.Arguments, func() *wrapperspb.StringValue { return new(wrapperspb.StringValue) }, open)
+			}
+			s.AddTool(&mcp.Tool{Name: "open", InputSchema: &jsonschema.Schema{Type: "object"}}, call)
+			res, err := connectTestClient(t, s).CallTool(context.Background(), &mcp.CallToolParams{Name: "open", Arguments: json.RawMessage(tt.args)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.IsError != tt.wantErr {
+				t.Fatalf("IsError = %v, want %v", res.IsError, tt.wantErr)
+			}
+			if tt.wantErr {
+				// A failed open cancels and forgets the stream.
+				if streamCtx != nil && streamCtx.Err() == nil {
+					t.Error("stream context not cancelled after failure")
+				}
+				return
+			}
+			var opened struct {
+				StreamID string `json:"stream_id"`
+			}
+			if err := json.Unmarshal([]byte(res.Content[0].(*mcp.TextContent).Text), &opened); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := bidiStreams.Load(opened.StreamID); !ok {
+				t.Errorf("stream %q not registered", opened.StreamID)
+			}
+		})
+	}
+}
+
+func TestLookupBidiStream_OtherSession(t *testing.T) {
+	st := &bidiStream{id: "owned", session: &mcp.ServerSession{}}
+	bidiStreams.Store(st.id, st)
+	defer bidiStreams.Delete(st.id)
+	for _, session := range []*mcp.ServerSession{nil, {}} {
+		if _, err := lookupBidiStream(st.id, session); err == nil {
+			t.Errorf("lookup from session %p succeeded", session)
+		}
+	}
+}
+
+// recordingBidiClient records sent messages and blocks Recv until its
+// context ends.
+type recordingBidiClient struct {
+	ctx  context.Context
+	sent chan string
+}
+
+func (c recordingBidiClient) Send(m *wrapperspb.StringValue) error {
+	c.sent <- m.GetValue()
+	return nil
+}
+func (c recordingBidiClient) CloseSend() error { return nil }
+func (c recordingBidiClient) Recv() (*wrapperspb.StringValue, error) {
+	<-c.ctx.Done()
+	return nil, io.EOF
+}
+
+func TestOpenBidiStream_BindsFields(t *testing.T) {
+	cfg := &Config{FieldBindings: []FieldBinding{{Field: "value", Value: "bound"}}}
+	sent := make(chan string, 2)
+	open := func(ctx context.Context) (BidiClient[*wrapperspb.StringValue, *wrapperspb.StringValue], error) {
+		return recordingBidiClient{ctx, sent}, nil
+	}
+	s := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	s.AddTool(&mcp.Tool{Name: "open", InputSchema: &jsonschema.Schema{Type: "object"}}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return OpenBidiStream(ctx, s, req, cfg, req.Params.Arguments, func() *wrapperspb.StringValue { return new(wrapperspb.StringValue) }, open)
+	})
+	s.AddTool(&mcp.Tool{Name: "send", InputSchema: &jsonschema.Schema{Type: "object"}}, SendBidiStream)
+	cs := connectTestClient(t, s)
+	ctx := context.Background()
+
+	res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: "open", Arguments: json.RawMessage(`{"requests":["model"]}`)})
+	if err != nil || res.IsError {
+		t.Fatalf("open = %+v, %v", res, err)
+	}
+	var opened struct {
+		StreamID string `json:"stream_id"`
+	}
+	if err := json.Unmarshal([]byte(res.Content[0].(*mcp.TextContent).Text), &opened); err != nil {
+		t.Fatal(err)
+	}
+	args, _ := json.Marshal(map[string]any{"stream_id": opened.StreamID, "requests": []string{"later"}})
+	if res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: "send", Arguments: json.RawMessage(args)}); err != nil || res.IsError {
+		t.Fatalf("send = %+v, %v", res, err)
+	}
+	for i := 0; i < 2; i++ {
+		if got := <-sent; got != "bound" {
+			t.Errorf("message %d value = %q, want bound", i, got)
+		}
+	}
+}
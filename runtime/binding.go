@@ -0,0 +1,342 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppb"
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FieldBinding binds a request field to a value from the request context,
+// like the (mcp.protobuf.field).bind option. Bound fields are removed from
+// the tool schema and overwritten after the arguments are decoded, so the
+// model cannot choose them. Set exactly one of Claim, Header, Extra and
+// Value.
+//
+// Example: take the parent of CreateTodo from the caller's token:
+//
+//	runtime.WithFieldBindings(runtime.FieldBinding{
+//	    Message: "todo.v1.CreateTodoRequest", Field: "parent",
+//	    Claim: "sub", Template: "users/{value}",
+//	})
+type FieldBinding struct {
+	// Message is the full name of the request message; empty binds the
+	// field in every request message that has it.
+	Message string
+	// Field is a dotted path of proto or JSON field names to a singular
+	// scalar field, e.g. "parent" or "filter.owner".
+	Field string
+	// Claim is a claim of the caller's verified token. "sub" falls back to
+	// the token's user ID.
+	Claim string
+	// Header is the gRPC metadata key of an HTTP header mapping, e.g.
+	// "x-tenant-id".
+	Header string
+	// Extra is the name of an extra property.
+	Extra string
+	// Value is a constant.
+	Value string
+	// Template, when set, is the value with "{value}" replaced by the
+	// source value, e.g. "users/{value}".
+	Template string
+}
+
+// WithFieldBindings returns an Option that binds request fields to values
+// from the request context. See FieldBinding.
+func WithFieldBindings(bindings ...FieldBinding) Option {
+	return func(c *Config) {
+		c.FieldBindings = append(c.FieldBindings, bindings...)
+	}
+}
+
+// extraValuesKey is the context key for the extra property values taken
+// from the arguments by ExtractExtras.
+type extraValuesKeyType struct{}
+
+var extraValuesKey = extraValuesKeyType{}
+
+// PrepareToolWithBindings returns a shallow clone of tool with the fields
+// bound by bindings removed from its InputSchema. md is the tool's request
+// message; for client-streaming and bidirectional tools the fields are
+// removed from the items of the StreamRequestsProperty array. If no binding
+// applies the original tool is returned as-is.
+func PrepareToolWithBindings(tool *mcp.Tool, bindings []FieldBinding, md protoreflect.MessageDescriptor) *mcp.Tool {
+	original, ok := tool.InputSchema.(*jsonschema.Schema)
+	if !ok {
+		return tool
+	}
+	var schema *jsonschema.Schema
+	for _, b := range bindings {
+		path, ok := b.protoPath(md)
+		if !ok {
+			continue
+		}
+		if schema == nil {
+			schema = original.CloneSchemas()
+		}
+		removeSchemaField(schema, requestSchema(schema, md), path)
+	}
+	if schema == nil {
+		return tool
+	}
+	cloned := *tool
+	cloned.InputSchema = schema
+	return &cloned
+}
+
+// requestSchema returns the schema of the request message md within the
+// input schema of its tool: the items of the StreamRequestsProperty array
+// of streaming tools, otherwise schema itself.
+func requestSchema(schema *jsonschema.Schema, md protoreflect.MessageDescriptor) *jsonschema.Schema {
+	if md.Fields().ByName(StreamRequestsProperty) != nil {
+		return schema
+	}
+	if p := schema.Properties[StreamRequestsProperty]; p != nil && p.Type == "array" && p.Items != nil {
+		return p.Items
+	}
+	return schema
+}
+
+// removeSchemaField removes the property at path, given in proto field
+// names, from schema and its required list. root holds the $defs that
+// references resolve against. A referenced message along the path is
+// inlined first, so other fields of its type keep the property.
+func removeSchemaField(root, schema *jsonschema.Schema, path []string) {
+	for _, name := range path[:len(path)-1] {
+		next := schema.Properties[name]
+		if next == nil {
+			return
+		}
+		if def, ok := root.Defs[strings.TrimPrefix(next.Ref, "#/$defs/")]; ok && next.Ref != "" {
+			inlined := def.CloneSchemas()
+			if next.Description != "" {
+				inlined.Description = next.Description
+			}
+			schema.Properties[name] = inlined
+			next = inlined
+		}
+		schema = next
+	}
+	name := path[len(path)-1]
+	delete(schema.Properties, name)
+	for i, r := range schema.Required {
+		if r == name {
+			schema.Required = append(schema.Required[:i:i], schema.Required[i+1:]...)
+			break
+		}
+	}
+}
+
+// protoPath returns the proto field names along b.Field when b applies to
+// messages of type md.
+func (b FieldBinding) protoPath(md protoreflect.MessageDescriptor) ([]string, bool) {
+	if b.Message != "" && protoreflect.FullName(b.Message) != md.FullName() {
+		return nil, false
+	}
+	parts := strings.Split(b.Field, ".")
+	path := make([]string, len(parts))
+	for i, part := range parts {
+		if md == nil {
+			return nil, false
+		}
+		fd := md.Fields().ByName(protoreflect.Name(part))
+		if fd == nil {
+			fd = md.Fields().ByJSONName(part)
+		}
+		if fd == nil {
+			return nil, false
+		}
+		path[i] = string(fd.Name())
+		md = fd.Message()
+	}
+	return path, true
+}
+
+// BindFields sets the bound fields of msg: those declared with the
+// (mcp.protobuf.field).bind option, in msg and in the messages it holds,
+// and those of cfg.FieldBindings. Generated handlers call it after the
+// arguments are decoded. It fails when a source has no value.
+func BindFields(ctx context.Context, req *mcp.CallToolRequest, cfg *Config, msg proto.Message) error {
+	m := msg.ProtoReflect()
+	if hasBoundFields(m.Descriptor()) {
+		if err := bindDeclared(ctx, req, cfg, m, ""); err != nil {
+			return err
+		}
+	}
+	for _, b := range cfg.FieldBindings {
+		if _, ok := b.protoPath(m.Descriptor()); !ok {
+			continue
+		}
+		fd, holder := resolveResourceField(m, b.Field)
+		if fd == nil {
+			return fmt.Errorf("bound field %s: not a singular scalar field of %s", b.Field, m.Descriptor().FullName())
+		}
+		if err := b.set(ctx, req, cfg, holder, fd, b.Field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindDeclared sets the fields of m bound by field options and recurses
+// into the messages m holds. prefix is the path of m within the request.
+func bindDeclared(ctx context.Context, req *mcp.CallToolRequest, cfg *Config, m protoreflect.Message, prefix string) error {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		p := joinPath(prefix, string(fd.Name()))
+		if b := declaredBinding(fd); b != nil {
+			if fd.IsList() || fd.IsMap() || fd.Message() != nil {
+				return fmt.Errorf("bound field %s: not a singular scalar field", p)
+			}
+			if err := b.set(ctx, req, cfg, m, fd, p); err != nil {
+				return err
+			}
+			continue
+		}
+		if !m.Has(fd) {
+			continue
+		}
+		v := m.Get(fd)
+		switch {
+		case fd.IsMap():
+			if vd := fd.MapValue(); vd.Message() == nil || !hasBoundFields(vd.Message()) {
+				continue
+			}
+			var err error
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				err = bindDeclared(ctx, req, cfg, mv.Message(), p+"."+k.String())
+				return err == nil
+			})
+			if err != nil {
+				return err
+			}
+		case fd.Message() == nil || !hasBoundFields(fd.Message()):
+		case fd.IsList():
+			for j := 0; j < v.List().Len(); j++ {
+				if err := bindDeclared(ctx, req, cfg, v.List().Get(j).Message(), fmt.Sprintf("%s[%d]", p, j)); err != nil {
+					return err
+				}
+			}
+		default:
+			if err := bindDeclared(ctx, req, cfg, v.Message(), p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// declaredBinding returns the binding declared on fd by the
+// (mcp.protobuf.field).bind option, or nil.
+func declaredBinding(fd protoreflect.FieldDescriptor) *FieldBinding {
+	opts, ok := proto.GetExtension(fd.Options(), mcppb.E_Field).(*mcppb.MCPFieldOptions)
+	if !ok || opts.GetBind() == nil {
+		return nil
+	}
+	b := opts.GetBind()
+	return &FieldBinding{
+		Claim:    b.GetClaim(),
+		Header:   b.GetHeader(),
+		Extra:    b.GetExtra(),
+		Value:    b.GetValue(),
+		Template: b.GetTemplate(),
+	}
+}
+
+// hasBoundFields reports whether a message, or any message reachable from
+// it, declares a bound field.
+var hasBoundFields = reachesField(func(fd protoreflect.FieldDescriptor) bool {
+	return declaredBinding(fd) != nil
+})
+
+// set resolves the source of b and sets field fd of m to it. path names
+// the field in errors.
+func (b FieldBinding) set(ctx context.Context, req *mcp.CallToolRequest, cfg *Config, m protoreflect.Message, fd protoreflect.FieldDescriptor, path string) error {
+	s, err := b.resolve(ctx, req, cfg)
+	if err != nil {
+		return fmt.Errorf("bound field %s: %w", path, err)
+	}
+	if b.Template != "" {
+		s = strings.ReplaceAll(b.Template, "{value}", s)
+	}
+	v, err := scalarValue(fd, s)
+	if err != nil {
+		return fmt.Errorf("bound field %s: %w", path, err)
+	}
+	m.Set(fd, v)
+	return nil
+}
+
+// resolve returns the value of the source of b.
+func (b FieldBinding) resolve(ctx context.Context, req *mcp.CallToolRequest, cfg *Config) (string, error) {
+	switch {
+	case b.Claim != "":
+		if v, ok := tokenClaim(ctx, req, b.Claim); ok {
+			return metadataValue(v), nil
+		}
+		return "", fmt.Errorf("no claim %q in the caller's token", b.Claim)
+	case b.Header != "":
+		if v, ok := capturedHeader(ctx, req, cfg, b.Header); ok {
+			return v, nil
+		}
+		return "", fmt.Errorf("no header for %q in the request", b.Header)
+	case b.Extra != "":
+		extras, _ := ctx.Value(extraValuesKey).(map[string]any)
+		if v, ok := extras[b.Extra]; ok {
+			return metadataValue(v), nil
+		}
+		return "", fmt.Errorf("no extra property %q in the arguments", b.Extra)
+	case b.Value != "":
+		return b.Value, nil
+	}
+	return "", fmt.Errorf("binding has no source")
+}
+
+// tokenClaim returns a claim of the verified token of the request, set by
+// auth.RequireBearerToken.
+func tokenClaim(ctx context.Context, req *mcp.CallToolRequest, name string) (any, bool) {
+	info := auth.TokenInfoFromContext(ctx)
+	if req != nil && req.Extra != nil && req.Extra.TokenInfo != nil {
+		info = req.Extra.TokenInfo
+	}
+	if info == nil {
+		return nil, false
+	}
+	if v, ok := info.Extra[name]; ok && v != nil {
+		return v, true
+	}
+	if name == "sub" && info.UserID != "" {
+		return info.UserID, true
+	}
+	return nil, false
+}
+
+// capturedHeader returns the header mapped to the gRPC metadata key: the one
+// stored by HeadersMiddleware, or else the HTTP header of the request named
+// by a mapping of cfg.
+func capturedHeader(ctx context.Context, req *mcp.CallToolRequest, cfg *Config, key string) (string, bool) {
+	pairs, _ := ctx.Value(httpHeadersKey).(map[string]string)
+	for k, v := range pairs {
+		if strings.EqualFold(k, key) && v != "" {
+			return v, true
+		}
+	}
+	if req == nil || req.Extra == nil || req.Extra.Header == nil {
+		return "", false
+	}
+	for _, m := range cfg.HeaderMappings {
+		if strings.EqualFold(m.GRPCKey, key) {
+			if v := req.Extra.Header.Get(m.HTTPHeader); v != "" {
+				return v, true
+			}
+		}
+	}
+	return "", false
+}
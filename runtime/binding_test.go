@@ -0,0 +1,200 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const bindingTestFile = `
+name: "binding_test.proto"
+package: "bindingtest"
+syntax: "proto3"
+message_type {
+  name: "Owner"
+  field { name: "tenant" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "tenant"
+    options { [mcp.protobuf.field] { bind { header: "x-tenant-id" } } } }
+  field { name: "label" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "label" }
+}
+message_type {
+  name: "Request"
+  field { name: "parent" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "parent"
+    options { [mcp.protobuf.field] { bind { claim: "sub" template: "users/{value}" } } } }
+  field { name: "owners" number: 2 type: TYPE_MESSAGE label: LABEL_REPEATED json_name: "owners" type_name: ".bindingtest.Owner" }
+  field { name: "page_size" number: 3 type: TYPE_INT32 label: LABEL_OPTIONAL json_name: "pageSize" }
+  field { name: "title" number: 4 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "title" }
+}
+message_type {
+  name: "Plain"
+  field { name: "title" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "title" }
+  field { name: "owner" number: 2 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "owner" type_name: ".bindingtest.Plain.Ref" }
+  field { name: "backup" number: 3 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "backup" type_name: ".bindingtest.Plain.Ref" }
+  nested_type { name: "Ref"
+    field { name: "id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "id" }
+    field { name: "note" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "note" } }
+}
+`
+
+func TestBindFields(t *testing.T) {
+	md := testMessage(t, bindingTestFile, "Request")
+	plain := testMessage(t, bindingTestFile, "Plain")
+	token := &auth.TokenInfo{UserID: "u1", Extra: map[string]any{"tenant": "acme"}}
+	ctx := context.WithValue(context.Background(), httpHeadersKey, map[string]string{"x-tenant-id": "t1"})
+	ctx = context.WithValue(ctx, extraValuesKey, map[string]any{"size": float64(25)})
+	tests := []struct {
+		name     string
+		bindings []FieldBinding
+		token    *auth.TokenInfo
+		header   http.Header
+		ctx      context.Context
+		msg      string
+		args     string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:  "declared claim and nested header",
+			token: token,
+			ctx:   ctx,
+			args:  `{"parent":"users/evil","owners":[{"label":"a"},{"tenant":"evil"}]}`,
+			want:  `{"parent":"users/u1","owners":[{"tenant":"t1","label":"a"},{"tenant":"t1"}]}`,
+		},
+		{
+			name:     "runtime bindings",
+			bindings: []FieldBinding{{Message: "bindingtest.Request", Field: "pageSize", Extra: "size"}, {Field: "title", Claim: "tenant"}},
+			token:    token,
+			ctx:      ctx,
+			args:     `{"title":"x"}`,
+			want:     `{"parent":"users/u1","pageSize":25,"title":"acme"}`,
+		},
+		{
+			name:     "header from a mapping",
+			bindings: []FieldBinding{{Field: "title", Header: "x-tenant-id"}},
+			header:   http.Header{"X-Tenant": []string{"t2"}},
+			msg:      "Plain",
+			args:     `{}`,
+			want:     `{"title":"t2"}`,
+		},
+		{
+			name:     "constant in another message type is ignored",
+			bindings: []FieldBinding{{Message: "bindingtest.Request", Field: "title", Value: "fixed"}},
+			msg:      "Plain",
+			args:     `{"title":"x"}`,
+			want:     `{"title":"x"}`,
+		},
+		{
+			name:    "missing claim",
+			ctx:     ctx,
+			args:    `{}`,
+			wantErr: `bound field parent: no claim "sub"`,
+		},
+		{
+			name:     "not a scalar",
+			bindings: []FieldBinding{{Field: "owner", Value: "x"}},
+			msg:      "Plain",
+			args:     `{}`,
+			wantErr:  "bound field owner: not a singular scalar field",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := md
+			if tt.msg == "Plain" {
+				m = plain
+			}
+			msg := newTestMessage(t, m, tt.args)
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			cfg := &Config{FieldBindings: tt.bindings, HeaderMappings: []HeaderMapping{{HTTPHeader: "X-Tenant", GRPCKey: "x-tenant-id"}}}
+			req := &mcp.CallToolRequest{Extra: &mcp.RequestExtra{TokenInfo: tt.token, Header: tt.header}}
+			err := BindFields(ctx, req, cfg, msg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _ := protojson.Marshal(msg)
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestPrepareToolWithBindings(t *testing.T) {
+	md := testMessage(t, bindingTestFile, "Plain")
+	ref := &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{
+		"id": {Type: "string"}, "note": {Type: "string"},
+	}, Required: []string{"id"}}
+	message := func() *jsonschema.Schema {
+		return &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"title":  {Type: "string"},
+				"owner":  {Ref: "#/$defs/bindingtest.Plain.Ref", Description: "Owner."},
+				"backup": {Ref: "#/$defs/bindingtest.Plain.Ref"},
+			},
+			Required: []string{"title"},
+		}
+	}
+	unary := message()
+	unary.Defs = map[string]*jsonschema.Schema{"bindingtest.Plain.Ref": ref}
+	stream := &jsonschema.Schema{
+		Type:       "object",
+		Properties: map[string]*jsonschema.Schema{StreamRequestsProperty: {Type: "array", Items: message()}},
+		Defs:       map[string]*jsonschema.Schema{"bindingtest.Plain.Ref": ref},
+	}
+	tests := []struct {
+		name     string
+		schema   *jsonschema.Schema
+		bindings []FieldBinding
+		want     string
+	}{
+		{"top-level field", unary, []FieldBinding{{Field: "title", Value: "x"}},
+			`{"properties":{"backup":{"$ref":"#/$defs/bindingtest.Plain.Ref"},"owner":{"$ref":"#/$defs/bindingtest.Plain.Ref","description":"Owner."}}}`},
+		{"field behind a $ref", unary, []FieldBinding{{Field: "owner.id", Value: "x"}},
+			`{"properties":{"backup":{"$ref":"#/$defs/bindingtest.Plain.Ref"},"owner":{"description":"Owner.","properties":{"note":{"type":"string"}},"type":"object"},"title":{"type":"string"}},"required":["title"]}`},
+		{"streaming tool", stream, []FieldBinding{{Field: "title", Value: "x"}},
+			`{"properties":{"requests":{"items":{"properties":{"backup":{"$ref":"#/$defs/bindingtest.Plain.Ref"},"owner":{"$ref":"#/$defs/bindingtest.Plain.Ref","description":"Owner."}},"type":"object"},"type":"array"}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := &mcp.Tool{Name: "t", InputSchema: tt.schema}
+			got := PrepareToolWithBindings(tool, tt.bindings, md)
+			if got == tool {
+				t.Fatal("tool not cloned")
+			}
+			schema := got.InputSchema.(*jsonschema.Schema)
+			if mustSchemaJSON(t, schema.Defs["bindingtest.Plain.Ref"]) != mustSchemaJSON(t, ref) {
+				t.Errorf("shared definition changed: %s", mustSchemaJSON(t, schema.Defs["bindingtest.Plain.Ref"]))
+			}
+			schema.Defs = nil
+			schema.Type = ""
+			assertJSONEqual(t, []byte(mustSchemaJSON(t, schema)), tt.want)
+		})
+	}
+
+	tool := &mcp.Tool{Name: "t", InputSchema: unary}
+	if got := PrepareToolWithBindings(tool, []FieldBinding{{Message: "other.Message", Field: "title"}}, md); got != tool {
+		t.Error("tool cloned although no binding applies")
+	}
+}
+
+func mustSchemaJSON(t *testing.T, s *jsonschema.Schema) string {
+	t.Helper()
+	b, err := s.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
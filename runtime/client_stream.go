@@ -0,0 +1,134 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	grpcmd "google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// StreamRequestsProperty is the tool argument that carries the request
+// messages of a client-streaming or bidirectional RPC, in send order.
+const StreamRequestsProperty = "requests"
+
+// DecodeStreamRequests reads the StreamRequestsProperty array from tool
+// arguments and unmarshals each element into a new message from newMsg.
+// A missing property yields no messages.
+func DecodeStreamRequests[T proto.Message](ctx context.Context, args json.RawMessage, newMsg func() T) ([]T, error) {
+	var in map[string]json.RawMessage
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &in); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+	raw, ok := in[StreamRequestsProperty]
+	if !ok || string(raw) == "null" {
+		return nil, nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, fmt.Errorf("%s must be an array: %w", StreamRequestsProperty, err)
+	}
+	out := make([]T, 0, len(items))
+	for i, item := range items {
+		msg := newMsg()
+		if err := unmarshalArgsAt(ctx, fmt.Sprintf("%s[%d]", StreamRequestsProperty, i), item, msg); err != nil {
+			return nil, fmt.Errorf("%s[%d]: %w", StreamRequestsProperty, i, err)
+		}
+		out = append(out, msg)
+	}
+	return out, nil
+}
+
+// ClientStreamSender is the client side of a client-streaming RPC, as
+// implemented by generated gRPC client streams.
+type ClientStreamSender[Req, Resp any] interface {
+	Send(Req) error
+	CloseAndRecv() (Resp, error)
+}
+
+// ForwardClientStream sends reqs in order on stream and returns the response.
+// If the server closes the stream early, the server's status is returned.
+func ForwardClientStream[Req, Resp any](stream ClientStreamSender[Req, Resp], reqs []Req) (Resp, error) {
+	for _, r := range reqs {
+		if err := stream.Send(r); err != nil {
+			if errors.Is(err, io.EOF) {
+				break // the real status is reported by CloseAndRecv
+			}
+			var zero Resp
+			return zero, err
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+// InProcessClientStream implements the server side of a client-streaming RPC
+// over a fixed list of requests, so a gRPC server method can be called
+// in-process. Recv returns the requests in order and then io.EOF; the value
+// passed to SendAndClose is available from Response.
+//
+// Usage:
+//
+//	stream := runtime.NewInProcessClientStream[*MyRequest, *MyResponse](ctx, reqs)
+//	if err := srv.MyClientStreamingRPC(stream); err != nil { ... }
+//	resp := stream.Response()
+type InProcessClientStream[Req, Resp any] struct {
+	ctx  context.Context
+	reqs []Req
+	resp Resp
+}
+
+// NewInProcessClientStream creates a stream that yields reqs.
+func NewInProcessClientStream[Req, Resp any](ctx context.Context, reqs []Req) *InProcessClientStream[Req, Resp] {
+	return &InProcessClientStream[Req, Resp]{ctx: ctx, reqs: reqs}
+}
+
+// Recv returns the next request, io.EOF after the last one, or the context
+// error if ctx is done.
+func (s *InProcessClientStream[Req, Resp]) Recv() (Req, error) {
+	var zero Req
+	if err := s.ctx.Err(); err != nil {
+		return zero, err
+	}
+	if len(s.reqs) == 0 {
+		return zero, io.EOF
+	}
+	r := s.reqs[0]
+	s.reqs = s.reqs[1:]
+	return r, nil
+}
+
+// SendAndClose records the response.
+func (s *InProcessClientStream[Req, Resp]) SendAndClose(resp Resp) error {
+	s.resp = resp
+	return nil
+}
+
+// Response returns the value passed to SendAndClose.
+func (s *InProcessClientStream[Req, Resp]) Response() Resp { return s.resp }
+
+// Context returns the stream context.
+func (s *InProcessClientStream[Req, Resp]) Context() context.Context { return s.ctx }
+
+// The following methods satisfy grpc.ServerStream but are no-ops for in-process
+// use since there is no network transport.
+func (s *InProcessClientStream[Req, Resp]) SetHeader(grpcmd.MD) error  { return nil }
+func (s *InProcessClientStream[Req, Resp]) SendHeader(grpcmd.MD) error { return nil }
+func (s *InProcessClientStream[Req, Resp]) SetTrailer(grpcmd.MD)       {}
+
+// SendMsg type-asserts m to Resp and forwards it to SendAndClose.
+func (s *InProcessClientStream[Req, Resp]) SendMsg(m any) error {
+	if msg, ok := m.(Resp); ok {
+		return s.SendAndClose(msg)
+	}
+	return fmt.Errorf("InProcessClientStream.SendMsg: unexpected type %T", m)
+}
+
+// RecvMsg is not supported; use Recv.
+func (s *InProcessClientStream[Req, Resp]) RecvMsg(any) error {
+	return fmt.Errorf("InProcessClientStream.RecvMsg: not supported")
+}
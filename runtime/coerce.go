@@ -0,0 +1,468 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/machanirobotics/grpc-mcp-gateway/internal/enumname"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// CoercionsMetaKey is the _meta key listing the argument coercions applied
+// to a tool call.
+const CoercionsMetaKey = "coercions"
+
+// Coercion records one argument value rewritten into its protojson form.
+type Coercion struct {
+	Path string `json:"path"` // JSON path of the value, e.g. todo.priority or items[2].count
+	From any    `json:"from"`
+	To   any    `json:"to"`
+}
+
+// WithStrictArgs returns an Option that turns off lenient argument
+// coercion: tool arguments must already be valid protojson.
+func WithStrictArgs() Option {
+	return func(c *Config) {
+		c.StrictArgs = true
+	}
+}
+
+// WithTypeShorthand returns an Option that also accepts string shorthand for
+// google.type messages during argument coercion, e.g. "2026-10-17" for a
+// Date or "12.50 USD" for Money. It has no effect with WithStrictArgs.
+func WithTypeShorthand() Option {
+	return func(c *Config) {
+		c.TypeShorthand = true
+	}
+}
+
+type coercionLogKey struct{}
+
+// coercionLog collects the coercions of one tool call.
+type coercionLog struct {
+	typeShorthand bool // expand google.type shorthand, see WithTypeShorthand
+
+	mu      sync.Mutex
+	entries []Coercion
+}
+
+func withCoercionLog(ctx context.Context, typeShorthand bool) (context.Context, *coercionLog) {
+	log := &coercionLog{typeShorthand: typeShorthand}
+	return context.WithValue(ctx, coercionLogKey{}, log), log
+}
+
+func coercionLogFrom(ctx context.Context) *coercionLog {
+	log, _ := ctx.Value(coercionLogKey{}).(*coercionLog)
+	return log
+}
+
+func (l *coercionLog) add(entries []Coercion) {
+	l.mu.Lock()
+	l.entries = append(l.entries, entries...)
+	l.mu.Unlock()
+}
+
+// annotate lists the collected coercions under _meta.coercions of res.
+func (l *coercionLog) annotate(res *mcp.CallToolResult) {
+	if l == nil || res == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.entries) == 0 {
+		return
+	}
+	if res.Meta == nil {
+		res.Meta = mcp.Meta{}
+	}
+	res.Meta[CoercionsMetaKey] = l.entries
+}
+
+// coercingHandler wraps h so that the arguments it decodes are coerced
+// leniently (see CoerceArgs) and the applied coercions are listed under
+// _meta.coercions of the result.
+func coercingHandler(cfg *Config, h mcp.ToolHandler) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, log := withCoercionLog(ctx, cfg.TypeShorthand)
+		res, err := h(ctx, req)
+		log.annotate(res)
+		return res, err
+	}
+}
+
+// CoerceArgs rewrites values in args that protojson would reject, or
+// misread, for md into their protojson form:
+//
+//   - enum names matched case-insensitively, with or without the enum type
+//     prefix ("high" for PRIORITY_HIGH), and numeric strings;
+//   - numeric strings with spaces, "_" or "," separators, integral floats for
+//     integer fields, and numbers or booleans for string fields;
+//   - "true"/"false"/"yes"/"no"/"1"/"0" for booleans;
+//   - dates and date-times in common layouts, or Unix seconds, for Timestamps;
+//   - Go durations ("1h30m"), phrases ("30 minutes", "1.5 hours"), clock
+//     times ("01:30:00") and bare seconds for Durations.
+//
+// Values that are already valid are left alone. The returned coercions list
+// each change.
+func CoerceArgs(args json.RawMessage, md protoreflect.MessageDescriptor) (json.RawMessage, []Coercion, error) {
+	obj, ok, err := decodeArgs(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return args, nil, nil
+	}
+	c := &coercer{}
+	c.message(obj, md, "")
+	if len(c.coerced) == 0 {
+		return args, nil, nil
+	}
+	out, err := json.Marshal(obj)
+	return out, c.coerced, err
+}
+
+// coercer walks decoded arguments and collects the coercions it applies.
+type coercer struct {
+	coerced       []Coercion
+	typeShorthand bool
+}
+
+func (c *coercer) message(obj map[string]any, md protoreflect.MessageDescriptor, path string) {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		val := obj[key]
+		fd := md.Fields().ByJSONName(key)
+		if fd == nil {
+			fd = md.Fields().ByName(protoreflect.Name(key))
+		}
+		if fd == nil || val == nil {
+			continue
+		}
+		p := key
+		if path != "" {
+			p = path + "." + key
+		}
+		switch {
+		case fd.IsMap():
+			if m, ok := val.(map[string]any); ok {
+				mapKeys := make([]string, 0, len(m))
+				for k := range m {
+					mapKeys = append(mapKeys, k)
+				}
+				sort.Strings(mapKeys)
+				for _, k := range mapKeys {
+					if nv, ok := c.value(m[k], fd.MapValue(), p+"."+k); ok {
+						m[k] = nv
+					}
+				}
+			}
+		case fd.IsList():
+			if items, ok := val.([]any); ok {
+				for i, item := range items {
+					if nv, ok := c.value(item, fd, fmt.Sprintf("%s[%d]", p, i)); ok {
+						items[i] = nv
+					}
+				}
+			}
+		default:
+			if nv, ok := c.value(val, fd, p); ok {
+				obj[key] = nv
+			}
+		}
+	}
+}
+
+// value coerces a single (non-list) value of fd and reports whether it
+// changed. Nested messages are coerced in place.
+func (c *coercer) value(val any, fd protoreflect.FieldDescriptor, path string) (any, bool) {
+	if val == nil {
+		return val, false
+	}
+	if md := fd.Message(); md != nil {
+		if coerce := wellKnownCoercion(md); coerce != nil {
+			return c.record(val, coerce, path)
+		}
+		if c.typeShorthand {
+			if expand := googleTypeShorthand(md); expand != nil {
+				if _, isObject := val.(map[string]any); !isObject {
+					return c.record(val, expand, path)
+				}
+			}
+		}
+		if obj, ok := val.(map[string]any); ok {
+			c.message(obj, md, path)
+		}
+		return val, false
+	}
+	return c.record(val, func(v any) (any, bool) { return coerceScalar(v, fd) }, path)
+}
+
+func (c *coercer) record(val any, coerce func(any) (any, bool), path string) (any, bool) {
+	nv, ok := coerce(val)
+	if !ok {
+		return val, false
+	}
+	c.coerced = append(c.coerced, Coercion{Path: path, From: val, To: nv})
+	return nv, true
+}
+
+// wellKnownCoercion returns the coercion for Timestamp, Duration and
+// wrapper types, or nil.
+func wellKnownCoercion(md protoreflect.MessageDescriptor) func(any) (any, bool) {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return coerceTimestamp
+	case "google.protobuf.Duration":
+		return coerceDuration
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue",
+		"google.protobuf.Int64Value", "google.protobuf.UInt64Value",
+		"google.protobuf.Int32Value", "google.protobuf.UInt32Value",
+		"google.protobuf.BoolValue", "google.protobuf.StringValue":
+		inner := md.Fields().ByName("value")
+		return func(v any) (any, bool) { return coerceScalar(v, inner) }
+	}
+	return nil
+}
+
+func coerceScalar(v any, fd protoreflect.FieldDescriptor) (any, bool) {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		return coerceEnum(v, fd.Enum())
+	case protoreflect.BoolKind:
+		return coerceBool(v)
+	case protoreflect.StringKind:
+		switch t := v.(type) {
+		case json.Number:
+			return t.String(), true
+		case bool:
+			return strconv.FormatBool(t), true
+		}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return coerceNumber(v, false)
+	case protoreflect.BytesKind, protoreflect.MessageKind, protoreflect.GroupKind:
+	default:
+		return coerceNumber(v, true)
+	}
+	return v, false
+}
+
+func coerceEnum(v any, ed protoreflect.EnumDescriptor) (any, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return v, false
+	}
+	if ed.Values().ByName(protoreflect.Name(s)) != nil {
+		return v, false
+	}
+	want := strings.ToLower(strings.TrimSpace(s))
+	want = strings.NewReplacer(" ", "_", "-", "_").Replace(want)
+	if n, err := strconv.Atoi(want); err == nil {
+		if vd := ed.Values().ByNumber(protoreflect.EnumNumber(n)); vd != nil {
+			return string(vd.Name()), true
+		}
+	}
+	for i := 0; i < ed.Values().Len(); i++ {
+		name := string(ed.Values().Get(i).Name())
+		if strings.ToLower(name) == want || enumname.Friendly(name, string(ed.Name())) == want {
+			return name, true
+		}
+	}
+	return v, false
+}
+
+func coerceBool(v any) (any, bool) {
+	switch t := v.(type) {
+	case string:
+		switch strings.ToLower(strings.TrimSpace(t)) {
+		case "true", "yes", "y", "1", "on":
+			return true, true
+		case "false", "no", "n", "0", "off":
+			return false, true
+		}
+	case json.Number:
+		switch t.String() {
+		case "1":
+			return true, true
+		case "0":
+			return false, true
+		}
+	}
+	return v, false
+}
+
+// coerceNumber normalizes numeric strings and, for integers, integral
+// floats such as 5.0 or 1e3.
+func coerceNumber(v any, integer bool) (any, bool) {
+	var text string
+	switch t := v.(type) {
+	case string:
+		text = strings.NewReplacer(" ", "", "_", "", ",", "").Replace(strings.TrimSpace(t))
+		if text == t || text == "" {
+			if !integer || !strings.ContainsAny(t, ".eE") {
+				return v, false
+			}
+		}
+	case json.Number:
+		if !integer || !strings.ContainsAny(t.String(), ".eE") {
+			return v, false
+		}
+		text = t.String()
+	default:
+		return v, false
+	}
+	f, err := strconv.ParseFloat(strings.TrimPrefix(text, "+"), 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return v, false
+	}
+	if integer {
+		if f != math.Trunc(f) || math.Abs(f) > 1<<53 {
+			return v, false
+		}
+		return json.Number(strconv.FormatInt(int64(f), 10)), true
+	}
+	return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), true
+}
+
+// timestampLayouts are tried in order for Timestamp strings that are not
+// RFC 3339. Layouts without a zone are read as UTC.
+var timestampLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"2 Jan 2006",
+	"2 January 2006",
+}
+
+func coerceTimestamp(v any) (any, bool) {
+	var t time.Time
+	switch x := v.(type) {
+	case json.Number:
+		secs, err := x.Float64()
+		if err != nil {
+			return v, false
+		}
+		t = time.Unix(0, int64(secs*float64(time.Second)))
+	case string:
+		s := strings.TrimSpace(x)
+		if _, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			return v, false
+		}
+		if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+			t = time.Unix(secs, 0)
+			break
+		}
+		parsed := false
+		for _, layout := range timestampLayouts {
+			if pt, err := time.Parse(layout, s); err == nil {
+				t, parsed = pt, true
+				break
+			}
+		}
+		if !parsed {
+			return v, false
+		}
+	default:
+		return v, false
+	}
+	return t.UTC().Format(time.RFC3339Nano), true
+}
+
+var (
+	protoDuration  = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?s$`)
+	durationPhrase = regexp.MustCompile(`(?i)([0-9]*\.?[0-9]+)\s*(milliseconds?|millis|msecs?|ms|seconds?|secs?|s|minutes?|mins?|m|hours?|hrs?|h|days?|d|weeks?|w)\b`)
+	clockDuration  = regexp.MustCompile(`^(\d+):(\d{1,2})(?::(\d{1,2}(?:\.\d+)?))?$`)
+)
+
+var durationUnits = map[byte]time.Duration{
+	's': time.Second, 'm': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour,
+}
+
+func coerceDuration(v any) (any, bool) {
+	var d time.Duration
+	switch x := v.(type) {
+	case json.Number:
+		secs, err := x.Float64()
+		if err != nil {
+			return v, false
+		}
+		d = time.Duration(secs * float64(time.Second))
+	case string:
+		s := strings.TrimSpace(x)
+		if protoDuration.MatchString(s) {
+			return v, false
+		}
+		var ok bool
+		if d, ok = parseDuration(s); !ok {
+			return v, false
+		}
+	default:
+		return v, false
+	}
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s", true
+}
+
+// parseDuration reads bare seconds, Go durations, clock times and phrases
+// such as "1 hour 30 minutes".
+func parseDuration(s string) (time.Duration, bool) {
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, true
+	}
+	if m := clockDuration.FindStringSubmatch(s); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		min, _ := strconv.Atoi(m[2])
+		sec, _ := strconv.ParseFloat("0"+m[3], 64)
+		return time.Duration(h)*time.Hour + time.Duration(min)*time.Minute + time.Duration(sec*float64(time.Second)), true
+	}
+	matches := durationPhrase.FindAllStringSubmatch(s, -1)
+	if len(matches) == 0 {
+		return 0, false
+	}
+	var total time.Duration
+	for _, m := range matches {
+		n, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return 0, false
+		}
+		unit := strings.ToLower(m[2])
+		scale := durationUnits[unit[0]]
+		if strings.HasPrefix(unit, "ms") || strings.HasPrefix(unit, "milli") {
+			scale = time.Millisecond
+		} else if unit != "m" && strings.HasPrefix(unit, "m") {
+			scale = time.Minute
+		}
+		total += time.Duration(n * float64(scale))
+	}
+	return total, true
+}
+
+func joinPath(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	return prefix + "." + path
+}
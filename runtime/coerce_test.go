@@ -0,0 +1,133 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const coerceTestFile = `
+name: "coerce_test.proto"
+package: "coercetest"
+syntax: "proto3"
+dependency: "google/protobuf/duration.proto"
+dependency: "google/protobuf/timestamp.proto"
+enum_type {
+  name: "Priority"
+  value { name: "PRIORITY_UNSPECIFIED" number: 0 }
+  value { name: "PRIORITY_HIGH" number: 1 }
+}
+message_type {
+  name: "Item"
+  field { name: "count" number: 1 type: TYPE_INT32 label: LABEL_OPTIONAL json_name: "count" }
+}
+message_type {
+  name: "Request"
+  field { name: "priority" number: 1 type: TYPE_ENUM label: LABEL_OPTIONAL json_name: "priority" type_name: ".coercetest.Priority" }
+  field { name: "done" number: 2 type: TYPE_BOOL label: LABEL_OPTIONAL json_name: "done" }
+  field { name: "size" number: 3 type: TYPE_INT64 label: LABEL_OPTIONAL json_name: "size" }
+  field { name: "title" number: 4 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "title" }
+  field { name: "due" number: 5 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "due" type_name: ".google.protobuf.Timestamp" }
+  field { name: "ttl" number: 6 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "ttl" type_name: ".google.protobuf.Duration" }
+  field { name: "items" number: 7 type: TYPE_MESSAGE label: LABEL_REPEATED json_name: "items" type_name: ".coercetest.Item" }
+}
+`
+
+func TestCoerceArgs(t *testing.T) {
+	md := testMessage(t, coerceTestFile, "Request")
+	tests := []struct {
+		name     string
+		args     string
+		want     string
+		wantPath string
+	}{
+		{"friendly enum", `{"priority":"high"}`, `{"priority":"PRIORITY_HIGH"}`, "priority"},
+		{"enum number string", `{"priority":"1"}`, `{"priority":"PRIORITY_HIGH"}`, "priority"},
+		{"yes for bool", `{"done":"yes"}`, `{"done":true}`, "done"},
+		{"separated number", `{"size":"1,000"}`, `{"size":1000}`, "size"},
+		{"number for string", `{"title":42}`, `{"title":"42"}`, "title"},
+		{"date for timestamp", `{"due":"2026-10-17"}`, `{"due":"2026-10-17T00:00:00Z"}`, "due"},
+		{"phrase for duration", `{"ttl":"30 minutes"}`, `{"ttl":"1800s"}`, "ttl"},
+		{"nested list", `{"items":[{"count":1},{"count":"2.0"}]}`, `{"items":[{"count":1},{"count":2}]}`, "items[1].count"},
+		{"already valid", `{"priority":"PRIORITY_HIGH","done":true}`, `{"priority":"PRIORITY_HIGH","done":true}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, coerced, err := CoerceArgs(json.RawMessage(tt.args), md)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertJSONEqual(t, out, tt.want)
+			if tt.wantPath == "" {
+				if len(coerced) != 0 {
+					t.Errorf("coercions = %+v, want none", coerced)
+				}
+				return
+			}
+			if len(coerced) != 1 || coerced[0].Path != tt.wantPath {
+				t.Errorf("coercions = %+v, want one at %s", coerced, tt.wantPath)
+			}
+			if err := protojson.Unmarshal(out, dynamicpb.NewMessage(md)); err != nil {
+				t.Errorf("coerced arguments are not valid protojson: %v", err)
+			}
+		})
+	}
+}
+
+func TestUnmarshalArgs_CoercionLog(t *testing.T) {
+	md := testMessage(t, coerceTestFile, "Request")
+	tests := []struct {
+		name      string
+		lenient   bool
+		args      string
+		wantErr   bool
+		wantCount int
+	}{
+		{"lenient", true, `{"done":"yes","items":[{"count":"3.0"}]}`, false, 2},
+		{"strict", false, `{"done":"yes"}`, true, 0},
+		{"valid", false, `{"done":true}`, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			var log *coercionLog
+			if tt.lenient {
+				ctx, log = withCoercionLog(ctx, false)
+			}
+			err := UnmarshalArgs(ctx, json.RawMessage(tt.args), dynamicpb.NewMessage(md))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalArgs error = %v, want error %v", err, tt.wantErr)
+			}
+			res := &mcp.CallToolResult{}
+			log.annotate(res)
+			got, _ := res.Meta[CoercionsMetaKey].([]Coercion)
+			if len(got) != tt.wantCount {
+				t.Errorf("coercions = %+v, want %d", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+// assertJSONEqual fails t unless got and want decode to equal values.
+func assertJSONEqual(t *testing.T, got json.RawMessage, want string) {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("invalid JSON %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatal(err)
+	}
+	gb, _ := json.Marshal(g)
+	wb, _ := json.Marshal(w)
+	if string(gb) != string(wb) {
+		t.Errorf("got %s, want %s", gb, wb)
+	}
+}
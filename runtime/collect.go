@@ -0,0 +1,161 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/proto"
+)
+
+// Default budgets for StreamCollectOptions fields left at zero.
+const (
+	DefaultStreamCollectMaxItems = 100
+	DefaultStreamCollectMaxBytes = 1 << 20
+)
+
+// TruncatedMetaKey is the _meta key set on a collected stream result that
+// was cut short by a budget. Its value is the budget that was hit:
+// "max_items", "max_bytes" or "max_duration".
+const TruncatedMetaKey = "truncated"
+
+// StreamCollectOptions bounds how much of a server stream is collected into
+// a single tool result. It mirrors mcp.protobuf.MCPStreamCollect.
+type StreamCollectOptions struct {
+	MaxItems    int             // 0 uses DefaultStreamCollectMaxItems
+	MaxBytes    int             // 0 uses DefaultStreamCollectMaxBytes
+	MaxDuration time.Duration   // 0 means no time limit
+	Progress    bool            // send each message as a progress notification
+	Formatter   ResultFormatter // renders the collected messages; nil uses JSONFormatter
+}
+
+// StreamCollector reads a server stream within the budgets of its options.
+// Open the stream with Context so that the time budget also stops the RPC,
+// and call Close when done.
+type StreamCollector struct {
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+	req    *mcp.CallToolRequest
+	opts   StreamCollectOptions
+}
+
+// NewStreamCollector creates a collector for the tool call req.
+func NewStreamCollector(ctx context.Context, req *mcp.CallToolRequest, opts StreamCollectOptions) *StreamCollector {
+	if opts.MaxItems <= 0 {
+		opts.MaxItems = DefaultStreamCollectMaxItems
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultStreamCollectMaxBytes
+	}
+	c := &StreamCollector{parent: ctx, req: req, opts: opts}
+	if opts.MaxDuration > 0 {
+		c.ctx, c.cancel = context.WithTimeout(ctx, opts.MaxDuration)
+	} else {
+		c.ctx, c.cancel = context.WithCancel(ctx)
+	}
+	return c
+}
+
+// Context returns the context to open the stream with. It is cancelled when
+// the time budget expires or once collection stops.
+func (c *StreamCollector) Context() context.Context { return c.ctx }
+
+// Close releases the collector's context, cancelling the stream.
+func (c *StreamCollector) Close() { c.cancel() }
+
+// timedOut reports whether the time budget, rather than the caller, ended the stream.
+func (c *StreamCollector) timedOut() bool {
+	return errors.Is(c.ctx.Err(), context.DeadlineExceeded) && c.parent.Err() == nil
+}
+
+// CollectStream reads messages with recv until it returns io.EOF or a budget
+// is exhausted, and returns them rendered by the options' Formatter (a JSON
+// array by default) followed by any media content (see FormatResult). If a budget stopped
+// the stream, the result gets a TruncatedMetaKey _meta entry and a final text
+// block describing the truncation. Stream errors are converted via HandleError.
+func CollectStream[T proto.Message](c *StreamCollector, recv func() (T, error)) (*mcp.CallToolResult, error) {
+	defer c.cancel()
+	var session *mcp.ServerSession
+	var token any
+	if c.req != nil {
+		session = c.req.Session
+		token = c.req.Params.GetProgressToken()
+	}
+
+	var msgs []proto.Message
+	var media []mcp.Content
+	size := 2 // the enclosing brackets
+	truncated := ""
+	for {
+		msg, err := recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if c.timedOut() {
+				truncated = "max_duration"
+				break
+			}
+			if c.parent.Err() != nil {
+				return nil, c.parent.Err()
+			}
+			return HandleError(err)
+		}
+		stripped, msgMedia := ExtractMedia(msg)
+		b, err := protoJSON.Marshal(stripped)
+		if err != nil {
+			return nil, err
+		}
+		if size+len(b)+1 > c.opts.MaxBytes {
+			truncated = "max_bytes"
+			break
+		}
+		msgs = append(msgs, stripped)
+		media = append(media, msgMedia...)
+		size += len(b) + 1
+		if c.opts.Progress {
+			_ = SendProgressFromProto(c.parent, session, token, &mcppb.MCPProgress{
+				Progress: float64(len(msgs)),
+				Message:  string(b),
+			})
+		}
+		if len(msgs) >= c.opts.MaxItems {
+			// Stop the RPC without waiting for another message, then tell a
+			// stream that ended here from one that had more to send.
+			c.cancel()
+			if _, err := recv(); !errors.Is(err, io.EOF) {
+				truncated = "max_items"
+			}
+			break
+		}
+	}
+
+	f := c.opts.Formatter
+	if f == nil {
+		f = JSONFormatter
+	}
+	f, structured := unwrapStructured(f)
+	out, err := f.FormatList(msgs)
+	if err != nil {
+		return nil, err
+	}
+	res := TextResult(out)
+	res.Content = append(res.Content, media...)
+	if structured {
+		if res.StructuredContent, err = structuredList(msgs); err != nil {
+			return nil, err
+		}
+	}
+	if truncated != "" {
+		res.Content = append(res.Content, &mcp.TextContent{
+			Text: fmt.Sprintf("Stream truncated after %d items (%s).", len(msgs), truncated),
+		})
+		res.Meta = mcp.Meta{TruncatedMetaKey: truncated}
+	}
+	return res, nil
+}
@@ -0,0 +1,124 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestCollectStream(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          StreamCollectOptions
+		items         []string
+		quiet         bool  // keep the stream open after the items
+		end           error // passed to CloseWithError
+		want          string
+		wantTruncated string
+		wantError     bool
+	}{
+		{"whole stream", StreamCollectOptions{}, []string{"a", "b"}, false, nil, `["a","b"]`, "", false},
+		{"exactly max_items", StreamCollectOptions{MaxItems: 2}, []string{"a", "b"}, false, nil, `["a","b"]`, "", false},
+		{"max_items", StreamCollectOptions{MaxItems: 2}, []string{"a", "b", "c"}, false, nil, `["a","b"]`, "max_items", false},
+		{"max_items on a quiet stream", StreamCollectOptions{MaxItems: 2}, []string{"a", "b"}, true, nil, `["a","b"]`, "max_items", false},
+		{"max_bytes", StreamCollectOptions{MaxBytes: 10}, []string{"a", "b", "c"}, false, nil, `["a","b"]`, "max_bytes", false},
+		{"max_duration", StreamCollectOptions{MaxDuration: 20 * time.Millisecond}, []string{"a"}, true, nil, `["a"]`, "max_duration", false},
+		{"stream error", StreamCollectOptions{}, []string{"a"}, false, status.Error(codes.Internal, "boom"), "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewStreamCollector(context.Background(), nil, tt.opts)
+			defer c.Close()
+			stream := NewInProcessServerStream[*wrapperspb.StringValue](c.Context())
+			go func() {
+				for _, item := range tt.items {
+					if err := stream.Send(wrapperspb.String(item)); err != nil {
+						stream.CloseWithError(err)
+						return
+					}
+				}
+				if tt.quiet {
+					<-stream.Context().Done()
+					stream.CloseWithError(stream.Context().Err())
+					return
+				}
+				stream.CloseWithError(tt.end)
+			}()
+			done := make(chan struct{})
+			var res *mcp.CallToolResult
+			var err error
+			go func() {
+				defer close(done)
+				res, err = CollectStream(c, func() (*wrapperspb.StringValue, error) {
+					return stream.RecvContext(c.Context())
+				})
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("CollectStream did not return")
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.IsError != tt.wantError {
+				t.Fatalf("IsError = %v, want %v", res.IsError, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			assertJSONEqual(t, []byte(res.Content[0].(*mcp.TextContent).Text), tt.want)
+			got, _ := res.Meta[TruncatedMetaKey].(string)
+			if got != tt.wantTruncated {
+				t.Errorf("truncated = %q, want %q", got, tt.wantTruncated)
+			}
+			wantBlocks := 1
+			if tt.wantTruncated != "" {
+				wantBlocks++ // the truncation note
+			}
+			if len(res.Content) != wantBlocks {
+				t.Errorf("content = %d blocks, want %d", len(res.Content), wantBlocks)
+			}
+			if tt.wantTruncated == "max_items" && c.Context().Err() == nil {
+				t.Error("stream context not cancelled after max_items")
+			}
+		})
+	}
+}
+
+func TestInProcessServerStream_RecvContext(t *testing.T) {
+	stream := NewInProcessServerStream[string](context.Background())
+	boom := errors.New("boom")
+	if err := stream.Send("a"); err != nil {
+		t.Fatal(err)
+	}
+	stream.CloseWithError(boom)
+
+	// Buffered items and the end of the stream win over a done context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got, err := stream.RecvContext(ctx); got != "a" || err != nil {
+		t.Errorf("first RecvContext = %q, %v, want a", got, err)
+	}
+	if _, err := stream.RecvContext(ctx); !errors.Is(err, boom) {
+		t.Errorf("second RecvContext error = %v, want %v", err, boom)
+	}
+
+	closed := NewInProcessServerStream[string](context.Background())
+	closed.CloseWithError(nil)
+	if _, err := closed.RecvContext(context.Background()); !errors.Is(err, io.EOF) {
+		t.Errorf("RecvContext after CloseWithError(nil) = %v, want io.EOF", err)
+	}
+
+	open := NewInProcessServerStream[string](context.Background())
+	if _, err := open.RecvContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("RecvContext on an open stream = %v, want context.Canceled", err)
+	}
+}
@@ -1,6 +1,12 @@
 package runtime
 
-import "context"
+import (
+	"context"
+	"io/fs"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"github.com/google/jsonschema-go/jsonschema"
+)
 
 // Transport represents the transport protocol for the MCP server.
 type Transport string
@@ -21,8 +27,12 @@ type Option func(*Config)
 // It is typically built via ApplyOptions and passed to Register*MCPHandler.
 type Config struct {
 	// ExtraProperties are injected into tool schemas and extracted from
-	// request arguments into context. Use WithExtraProperties to add them.
+	// request arguments into context and, optionally, gRPC metadata. Use
+	// WithExtraProperties to add them.
 	ExtraProperties []ExtraProperty
+	// FieldBindings bind request fields to values from the request context
+	// instead of the tool arguments. Use WithFieldBindings to add them.
+	FieldBindings []FieldBinding
 	// HeaderMappings configure HTTP header to gRPC metadata forwarding.
 	// Use WithHeaderMappings or DefaultHeaderMappings().
 	HeaderMappings []HeaderMapping
@@ -34,22 +44,66 @@ type Config struct {
 	// modify elicitation fields at runtime (e.g. inject dynamic enum values).
 	// toolName is the MCP tool name. Returning an error aborts the tool call.
 	ElicitHook func(ctx context.Context, toolName string, fields []ElicitField) ([]ElicitField, error)
+	// TaskStore holds task state and results for long-running streaming tools.
+	// In-process handlers always run streaming tools as tasks, falling back to
+	// DefaultTaskStore when nil. ForwardTo handlers only use tasks when a store
+	// is set. Use WithTaskStore to configure it.
+	TaskStore TaskStore
+	// OperationsClient polls and cancels google.longrunning.Operation values
+	// returned by RPCs with operation_info. Use WithOperationsClient to set it.
+	OperationsClient longrunningpb.OperationsClient
+	// Pagination makes AIP-158 List tools without their own pagination
+	// option fetch pages automatically. Use WithAutoPagination to set it.
+	Pagination *PaginationOptions
+	// ResultBudget bounds the size of every tool result; ToolResultBudgets
+	// overrides it per MCP tool name. Use WithResultBudget and
+	// WithToolResultBudget to set them.
+	ResultBudget      *ResultBudget
+	ToolResultBudgets map[string]ResultBudget
+	// ResultStore keeps results offloaded by ResultBudgetOffload. Use
+	// WithResultStore to set it; DefaultResultStore is used when nil.
+	ResultStore ResultStore
+	// ResultFormatter renders response messages for tools without a
+	// result_format proto option. Use WithResultFormatter; defaults to
+	// JSONFormatter.
+	ResultFormatter ResultFormatter
+	// StrictArgs turns off lenient argument coercion, so tool arguments must
+	// be valid protojson. Use WithStrictArgs to set it.
+	StrictArgs bool
+	// TypeShorthand accepts string shorthand for google.type messages, such
+	// as "2026-10-17" for a Date. Use WithTypeShorthand to set it.
+	TypeShorthand bool
+	// AppFS serves MCP App views from their entry and asset files instead of
+	// the HTML embedded at generate time. Use WithAppFS to set it.
+	AppFS fs.FS
 }
 
 // ExtraProperty defines an additional property to inject into tool schemas
-// and extract from request arguments into context.
+// and extract from request arguments into context. Extras are removed from
+// the arguments before they are unmarshalled into the request message.
 //
-// Example: add an "api_key" property that gets extracted into context:
+// Example: add an "api_key" property that gets extracted into context and
+// forwarded to the gRPC backend as x-api-key metadata:
 //
 //	runtime.WithExtraProperties(runtime.ExtraProperty{
 //	    Name: "api_key", Description: "API key for auth", Required: true,
-//	    ContextKey: contextKeyForAPIKey,
+//	    ContextKey: contextKeyForAPIKey, MetadataKey: "x-api-key",
 //	})
 type ExtraProperty struct {
 	Name        string // JSON property name in tool arguments
 	Description string // Shown in tool schema
 	Required    bool   // If true, adds to schema.required
-	ContextKey  any    // Key for context.WithValue(ctx, ContextKey, value)
+	ContextKey  any    // Key for context.WithValue(ctx, ContextKey, value); nil skips it
+	// Type is the JSON Schema type of the property ("string" when empty),
+	// Enum its allowed values and Default the value used when the argument
+	// is absent. Schema, when set, replaces all three.
+	Type    string
+	Enum    []any
+	Default any
+	Schema  *jsonschema.Schema
+	// MetadataKey, when set, forwards the value as outgoing gRPC metadata
+	// through ForwardMetadata. Strings are sent as is, other values as JSON.
+	MetadataKey string
 }
 
 // WithExtraProperties returns an Option that adds extra properties to tool
@@ -95,6 +149,59 @@ func WithElicitHook(hook func(ctx context.Context, toolName string, fields []Eli
 	}
 }
 
+// WithTaskStore returns an Option that sets the TaskStore used for
+// server-streaming tools with MCPProgress. Setting a store also makes
+// ForwardTo handlers run those tools as tasks instead of blocking.
+func WithTaskStore(store TaskStore) Option {
+	return func(c *Config) {
+		c.TaskStore = store
+	}
+}
+
+// TaskStoreOrDefault returns the configured TaskStore, or DefaultTaskStore.
+func (c *Config) TaskStoreOrDefault() TaskStore {
+	if c.TaskStore != nil {
+		return c.TaskStore
+	}
+	return DefaultTaskStore()
+}
+
+// WithOperationsClient returns an Option that sets the client used to poll
+// and cancel long-running operations. For a remote server pass
+// longrunningpb.NewOperationsClient(conn); for an in-process
+// OperationsServer use OperationsClientFromServer.
+func WithOperationsClient(client longrunningpb.OperationsClient) Option {
+	return func(c *Config) {
+		c.OperationsClient = client
+	}
+}
+
+// WithAutoPagination returns an Option that makes AIP-158 List tools fetch
+// pages until opts' budgets are reached. Methods that set the
+// mcp.protobuf.MCPPagination tool option keep their own settings. Cursor is
+// ignored: cursor mode changes the tool schema, so it is only available
+// through the proto option.
+func WithAutoPagination(opts PaginationOptions) Option {
+	return func(c *Config) {
+		opts.Enabled = true
+		opts.Cursor = false
+		c.Pagination = &opts
+	}
+}
+
+// PaginationFor returns the pagination settings for a List tool: the tool's
+// own options when the method sets MCPPagination, otherwise the configured
+// Pagination. It returns nil when pagination is off.
+func (c *Config) PaginationFor(tool *PaginationOptions) *PaginationOptions {
+	if tool == nil {
+		tool = c.Pagination
+	}
+	if tool == nil || !tool.Enabled {
+		return nil
+	}
+	return tool
+}
+
 // ApplyOptions creates a Config and applies all provided options.
 func ApplyOptions(opts ...Option) *Config {
 	cfg := &Config{}
@@ -42,4 +42,15 @@
 //	result, err := runtime.RunElicitation(ctx, session, "Are you sure?", []runtime.ElicitField{
 //	    {Name: "confirm", Required: true, Type: "string", EnumValues: []string{"yes", "no"}},
 //	})
+//
+// # Tasks
+//
+// Progress-streaming tools return a task immediately and run in the background.
+// Use the tasks-get, tasks-result, tasks-list and tasks-cancel tools to follow
+// them, and WithTaskStore to plug in a shared TaskStore.
+//
+// # Bidirectional streams
+//
+// Bidi RPCs are opened with one tool and fed with a companion send tool;
+// received messages are buffered on the streams://{stream_id} resource.
 package runtime
@@ -0,0 +1,109 @@
+package runtime
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/machanirobotics/grpc-mcp-gateway/internal/enumname"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// NormalizeEnumNames rewrites friendly enum names, as listed by schemas
+// generated with schema_friendly_enums ("high" for PRIORITY_HIGH), to their
+// proto names for md. Proto names and numbers are left alone, so arguments
+// from either schema variant are accepted. Arguments without enum fields are
+// returned unchanged.
+func NormalizeEnumNames(args json.RawMessage, md protoreflect.MessageDescriptor) (json.RawMessage, error) {
+	if !hasEnums(md) {
+		return args, nil
+	}
+	obj, ok, err := decodeArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || !normalizeEnumMessage(obj, md) {
+		return args, nil
+	}
+	return json.Marshal(obj)
+}
+
+// normalizeEnumMessage rewrites obj in place and reports whether it changed.
+func normalizeEnumMessage(obj map[string]any, md protoreflect.MessageDescriptor) bool {
+	changed := false
+	for key, val := range obj {
+		fd := md.Fields().ByJSONName(key)
+		if fd == nil {
+			fd = md.Fields().ByName(protoreflect.Name(key))
+		}
+		if fd == nil || val == nil {
+			continue
+		}
+		switch {
+		case fd.IsMap():
+			if m, ok := val.(map[string]any); ok {
+				for k, item := range m {
+					if nv, ok := normalizeEnumValue(item, fd.MapValue()); ok {
+						m[k], changed = nv, true
+					}
+				}
+			}
+		case fd.IsList():
+			if items, ok := val.([]any); ok {
+				for i, item := range items {
+					if nv, ok := normalizeEnumValue(item, fd); ok {
+						items[i], changed = nv, true
+					}
+				}
+			}
+		default:
+			if nv, ok := normalizeEnumValue(val, fd); ok {
+				obj[key], changed = nv, true
+			}
+		}
+	}
+	return changed
+}
+
+// normalizeEnumValue returns the proto name for a friendly enum name of fd,
+// recursing into message values, and whether the value changed.
+func normalizeEnumValue(val any, fd protoreflect.FieldDescriptor) (any, bool) {
+	if md := fd.Message(); md != nil {
+		if obj, ok := val.(map[string]any); ok {
+			return obj, normalizeEnumMessage(obj, md)
+		}
+		return val, false
+	}
+	s, ok := val.(string)
+	if !ok || fd.Enum() == nil || fd.Enum().Values().ByName(protoreflect.Name(s)) != nil {
+		return val, false
+	}
+	if name, ok := friendlyEnumNames(fd.Enum())[s]; ok {
+		return name, true
+	}
+	return val, false
+}
+
+// enumNameMaps caches, per enum full name, friendly name -> proto name.
+var enumNameMaps sync.Map
+
+func friendlyEnumNames(ed protoreflect.EnumDescriptor) map[string]string {
+	if v, ok := enumNameMaps.Load(ed.FullName()); ok {
+		return v.(map[string]string)
+	}
+	names := make(map[string]string, ed.Values().Len())
+	for i := 0; i < ed.Values().Len(); i++ {
+		name := string(ed.Values().Get(i).Name())
+		names[enumname.Friendly(name, string(ed.Name()))] = name
+	}
+	enumNameMaps.Store(ed.FullName(), names)
+	return names
+}
+
+// hasEnums reports whether a message, or any message reachable from it, has
+// an enum field.
+var hasEnums = reachesField(func(fd protoreflect.FieldDescriptor) bool {
+	if fd.IsMap() {
+		fd = fd.MapValue()
+	}
+	return fd.Enum() != nil
+})
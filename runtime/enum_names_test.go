@@ -0,0 +1,53 @@
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const enumNamesTestFile = `
+name: "enum_names_test.proto"
+package: "enumnamestest"
+syntax: "proto3"
+enum_type {
+  name: "Priority"
+  value { name: "PRIORITY_UNSPECIFIED" number: 0 }
+  value { name: "PRIORITY_HIGH" number: 1 }
+  value { name: "PRIORITY_LOW" number: 2 }
+}
+message_type {
+  name: "Task"
+  field { name: "priority" number: 1 type: TYPE_ENUM label: LABEL_OPTIONAL json_name: "priority" type_name: ".enumnamestest.Priority" }
+}
+message_type {
+  name: "Request"
+  field { name: "priority" number: 1 type: TYPE_ENUM label: LABEL_OPTIONAL json_name: "priority" type_name: ".enumnamestest.Priority" }
+  field { name: "tags" number: 2 type: TYPE_ENUM label: LABEL_REPEATED json_name: "tags" type_name: ".enumnamestest.Priority" }
+  field { name: "tasks" number: 3 type: TYPE_MESSAGE label: LABEL_REPEATED json_name: "tasks" type_name: ".enumnamestest.Task" }
+}
+`
+
+func TestNormalizeEnumNames(t *testing.T) {
+	md := testMessage(t, enumNamesTestFile, "Request")
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{"friendly name", `{"priority":"high"}`, `{"priority":"PRIORITY_HIGH"}`},
+		{"proto name kept", `{"priority":"PRIORITY_LOW"}`, `{"priority":"PRIORITY_LOW"}`},
+		{"number kept", `{"priority":2}`, `{"priority":2}`},
+		{"repeated", `{"tags":["low","PRIORITY_HIGH"]}`, `{"tags":["PRIORITY_LOW","PRIORITY_HIGH"]}`},
+		{"nested message", `{"tasks":[{"priority":"low"}]}`, `{"tasks":[{"priority":"PRIORITY_LOW"}]}`},
+		{"unknown name kept", `{"priority":"urgent"}`, `{"priority":"urgent"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := NormalizeEnumNames(json.RawMessage(tt.args), md)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertJSONEqual(t, out, tt.want)
+		})
+	}
+}
@@ -39,12 +39,16 @@ func HandleError(err error) (*mcp.CallToolResult, error) {
 }
 
 func errorFromGRPC(st *status.Status) *mcp.CallToolResult {
+	return marshalErrorResult(grpcErrorFromStatus(st))
+}
+
+func grpcErrorFromStatus(st *status.Status) grpcError {
 	e := grpcError{
 		Code:    st.Code().String(),
 		Message: st.Message(),
 	}
 	e.Details = append(e.Details, st.Details()...)
-	return marshalErrorResult(e)
+	return e
 }
 
 func marshalErrorResult(e grpcError) *mcp.CallToolResult {
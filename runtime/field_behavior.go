@@ -0,0 +1,165 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// UnmarshalArgs unmarshals tool arguments into msg, ignoring unknown fields,
+// and clears any OUTPUT_ONLY fields the client set anyway. Arguments shaped
+// by strict schemas and friendly enum names are normalized first (see
+// NormalizeStrictArgs and NormalizeEnumNames). Inside
+// tools registered with AddTool, lenient values are coerced (see CoerceArgs)
+// unless Config.StrictArgs is set, and the coercions are reported in the
+// result's _meta. Arguments setting several members of a oneof are rejected
+// (see CheckOneofs).
+func UnmarshalArgs(ctx context.Context, args json.RawMessage, msg proto.Message) error {
+	return unmarshalArgsAt(ctx, "", args, msg)
+}
+
+// unmarshalArgsAt is UnmarshalArgs for arguments found at path within the
+// tool arguments; path prefixes reported coercions and oneof errors. The
+// arguments are decoded once and every normalizer rewrites the decoded
+// object in place; they are only re-encoded when one of them changed it.
+func unmarshalArgsAt(ctx context.Context, path string, args json.RawMessage, msg proto.Message) error {
+	md := msg.ProtoReflect().Descriptor()
+	obj, ok, err := decodeArgs(args)
+	if err != nil {
+		return err
+	}
+	if ok {
+		changed := false
+		if hasStrictShapes(md) && normalizeMessage(obj, md) {
+			changed = true
+		}
+		if hasEnums(md) && normalizeEnumMessage(obj, md) {
+			changed = true
+		}
+		if log := coercionLogFrom(ctx); log != nil {
+			c := &coercer{typeShorthand: log.typeShorthand}
+			c.message(obj, md, path)
+			log.add(c.coerced)
+			changed = changed || len(c.coerced) > 0
+		}
+		if hasOneofs(md) {
+			if err := checkOneofMessage(obj, md, path); err != nil {
+				return err
+			}
+		}
+		if changed {
+			if args, err = json.Marshal(obj); err != nil {
+				return err
+			}
+		}
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(args, msg); err != nil {
+		return err
+	}
+	StripOutputOnly(msg)
+	return nil
+}
+
+// decodeArgs decodes tool arguments for the normalizers, keeping numbers
+// as json.Number. ok is false when args are empty or not a JSON object.
+func decodeArgs(args json.RawMessage) (obj map[string]any, ok bool, err error) {
+	if len(args) == 0 {
+		return nil, false, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, false, fmt.Errorf("invalid arguments: %w", err)
+	}
+	obj, ok = v.(map[string]any)
+	return obj, ok, nil
+}
+
+// reachesField returns a function that reports whether a message, or any
+// message reachable from it through message, list and map value fields,
+// declares a field matching pred. Results are cached per message full name.
+func reachesField(pred func(protoreflect.FieldDescriptor) bool) func(protoreflect.MessageDescriptor) bool {
+	var cache sync.Map
+	var reaches func(md protoreflect.MessageDescriptor, seen map[protoreflect.FullName]bool) bool
+	reaches = func(md protoreflect.MessageDescriptor, seen map[protoreflect.FullName]bool) bool {
+		if seen[md.FullName()] {
+			return false
+		}
+		seen[md.FullName()] = true
+		for i := 0; i < md.Fields().Len(); i++ {
+			fd := md.Fields().Get(i)
+			if pred(fd) {
+				return true
+			}
+			if fd.IsMap() {
+				fd = fd.MapValue()
+			}
+			if fd.Message() != nil && reaches(fd.Message(), seen) {
+				return true
+			}
+		}
+		return false
+	}
+	return func(md protoreflect.MessageDescriptor) bool {
+		if v, ok := cache.Load(md.FullName()); ok {
+			return v.(bool)
+		}
+		found := reaches(md, map[protoreflect.FullName]bool{})
+		cache.Store(md.FullName(), found)
+		return found
+	}
+}
+
+// StripOutputOnly clears every field of msg, and of messages nested in it,
+// annotated with google.api.field_behavior OUTPUT_ONLY.
+func StripOutputOnly(msg proto.Message) {
+	if msg == nil || !hasOutputOnly(msg.ProtoReflect().Descriptor()) {
+		return
+	}
+	stripOutputOnly(msg.ProtoReflect())
+}
+
+func stripOutputOnly(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case isOutputOnly(fd):
+			m.Clear(fd)
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					stripOutputOnly(mv.Message())
+					return true
+				})
+			}
+		case fd.Message() != nil && fd.IsList():
+			for i := 0; i < v.List().Len(); i++ {
+				stripOutputOnly(v.List().Get(i).Message())
+			}
+		case fd.Message() != nil:
+			stripOutputOnly(v.Message())
+		}
+		return true
+	})
+}
+
+func isOutputOnly(fd protoreflect.FieldDescriptor) bool {
+	behaviors, _ := proto.GetExtension(fd.Options(), annotations.E_FieldBehavior).([]annotations.FieldBehavior)
+	for _, b := range behaviors {
+		if b == annotations.FieldBehavior_OUTPUT_ONLY {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOutputOnly reports whether a message, or any message reachable from
+// it, has an OUTPUT_ONLY field.
+var hasOutputOnly = reachesField(isOutputOnly)
@@ -0,0 +1,49 @@
+package runtime
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const reachTestFile = `
+name: "reach_test.proto"
+package: "reachtest"
+syntax: "proto3"
+message_type {
+  name: "Node"
+  field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "name" }
+  field { name: "children" number: 2 type: TYPE_MESSAGE label: LABEL_REPEATED json_name: "children" type_name: ".reachtest.Node" }
+  field { name: "leaves" number: 3 type: TYPE_MESSAGE label: LABEL_REPEATED json_name: "leaves" type_name: ".reachtest.Node.LeavesEntry" }
+  nested_type { name: "LeavesEntry" options { map_entry: true }
+    field { name: "key" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "key" }
+    field { name: "value" number: 2 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "value" type_name: ".reachtest.Leaf" } }
+}
+message_type {
+  name: "Leaf"
+  field { name: "flag" number: 1 type: TYPE_BOOL label: LABEL_OPTIONAL json_name: "flag" }
+}
+`
+
+func TestReachesField(t *testing.T) {
+	md := testMessage(t, reachTestFile, "Node")
+	tests := []struct {
+		name string
+		pred func(protoreflect.FieldDescriptor) bool
+		want bool
+	}{
+		{"own field", func(fd protoreflect.FieldDescriptor) bool { return fd.Name() == "name" }, true},
+		{"through map value", func(fd protoreflect.FieldDescriptor) bool { return fd.Kind() == protoreflect.BoolKind }, true},
+		{"recursive without match", func(fd protoreflect.FieldDescriptor) bool { return fd.Kind() == protoreflect.BytesKind }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			has := reachesField(tt.pred)
+			for i := 0; i < 2; i++ { // the second call is served from the cache
+				if got := has(md); got != tt.want {
+					t.Errorf("call %d = %v, want %v", i, got, tt.want)
+				}
+			}
+		})
+	}
+}
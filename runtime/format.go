@@ -0,0 +1,350 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// ResultFormatter renders response messages as the text of a tool result.
+// Implement it to plug in a custom format with WithResultFormatter.
+type ResultFormatter interface {
+	// FormatMessage renders a single response message.
+	FormatMessage(msg proto.Message) (string, error)
+	// FormatList renders several messages, such as a collected stream.
+	FormatList(msgs []proto.Message) (string, error)
+}
+
+// ResultFormat selects a built-in ResultFormatter. It mirrors
+// mcp.protobuf.MCPResultFormat.
+type ResultFormat int
+
+const (
+	// ResultFormatDefault uses the formatter set with WithResultFormatter,
+	// or JSONFormatter.
+	ResultFormatDefault ResultFormat = iota
+	ResultFormatJSON
+	ResultFormatCompactJSON
+	ResultFormatYAML
+	ResultFormatMarkdown
+)
+
+// Built-in formatters.
+var (
+	// JSONFormatter renders protojson with proto field names and default values.
+	JSONFormatter ResultFormatter = jsonFormatter{opts: protoJSON}
+	// CompactJSONFormatter renders protojson with proto field names, without
+	// default values or whitespace.
+	CompactJSONFormatter ResultFormatter = jsonFormatter{opts: compactProtoJSON, compact: true}
+	// YAMLFormatter renders YAML without default values.
+	YAMLFormatter ResultFormatter = yamlFormatter{}
+	// MarkdownFormatter renders fields as a bullet list and repeated
+	// messages as tables, without default values.
+	MarkdownFormatter ResultFormatter = markdownFormatter{}
+)
+
+var compactProtoJSON = protojson.MarshalOptions{UseProtoNames: true}
+
+// WithResultFormatter returns an Option that sets the formatter for tools
+// without a result_format in their proto options.
+func WithResultFormatter(f ResultFormatter) Option {
+	return func(c *Config) {
+		c.ResultFormatter = f
+	}
+}
+
+// ResultFormatterFor returns the formatter for a tool: the built-in one for
+// format, or for ResultFormatDefault the configured ResultFormatter or
+// JSONFormatter.
+func (c *Config) ResultFormatterFor(format ResultFormat) ResultFormatter {
+	switch format {
+	case ResultFormatJSON:
+		return JSONFormatter
+	case ResultFormatCompactJSON:
+		return CompactJSONFormatter
+	case ResultFormatYAML:
+		return YAMLFormatter
+	case ResultFormatMarkdown:
+		return MarkdownFormatter
+	}
+	if c.ResultFormatter != nil {
+		return c.ResultFormatter
+	}
+	return JSONFormatter
+}
+
+// FormatResult renders msg with f as a tool result. Bytes fields annotated
+// with a mime_type in (mcp.protobuf.field) are removed from the text and
+// appended as image, audio or embedded resource content. A nil f uses
+// JSONFormatter; an f wrapped with StructuredFormatter also sets
+// structuredContent.
+func FormatResult(f ResultFormatter, msg proto.Message) (*mcp.CallToolResult, error) {
+	if f == nil {
+		f = JSONFormatter
+	}
+	f, structured := unwrapStructured(f)
+	msg, media := ExtractMedia(msg)
+	text, err := f.FormatMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	res := TextResult(text)
+	res.Content = append(res.Content, media...)
+	if structured {
+		if res.StructuredContent, err = structuredMessage(msg); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+type jsonFormatter struct {
+	opts    protojson.MarshalOptions
+	compact bool
+}
+
+func (f jsonFormatter) FormatMessage(msg proto.Message) (string, error) {
+	b, err := f.opts.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+	if f.compact {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, b); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+	return string(b), nil
+}
+
+func (f jsonFormatter) FormatList(msgs []proto.Message) (string, error) {
+	items := make([]json.RawMessage, 0, len(msgs))
+	for _, m := range msgs {
+		b, err := f.opts.Marshal(m)
+		if err != nil {
+			return "", err
+		}
+		items = append(items, b)
+	}
+	out, err := json.Marshal(items)
+	return string(out), err
+}
+
+// orderedTree marshals msg with compactProtoJSON and decodes it keeping
+// field order.
+func orderedTree(msg proto.Message) (any, error) {
+	b, err := compactProtoJSON.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return decodeOrdered(dec)
+}
+
+func orderedList(msgs []proto.Message) (*jsonArray, error) {
+	arr := &jsonArray{}
+	for _, m := range msgs {
+		v, err := orderedTree(m)
+		if err != nil {
+			return nil, err
+		}
+		arr.items = append(arr.items, v)
+	}
+	return arr, nil
+}
+
+type yamlFormatter struct{}
+
+func (yamlFormatter) FormatMessage(msg proto.Message) (string, error) {
+	v, err := orderedTree(msg)
+	if err != nil {
+		return "", err
+	}
+	return marshalYAML(v)
+}
+
+func (yamlFormatter) FormatList(msgs []proto.Message) (string, error) {
+	arr, err := orderedList(msgs)
+	if err != nil {
+		return "", err
+	}
+	return marshalYAML(arr)
+}
+
+func marshalYAML(v any) (string, error) {
+	var b strings.Builder
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlNode(v)); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// yamlNode converts a decodeOrdered tree to a yaml.Node, keeping key order.
+func yamlNode(v any) *yaml.Node {
+	switch t := v.(type) {
+	case *jsonObject:
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		for _, k := range t.keys {
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k}, yamlNode(t.values[k]))
+		}
+		return n
+	case *jsonArray:
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range t.items {
+			n.Content = append(n.Content, yamlNode(item))
+		}
+		return n
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(t.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: t.String()}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(t)}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+}
+
+type markdownFormatter struct{}
+
+func (markdownFormatter) FormatMessage(msg proto.Message) (string, error) {
+	v, err := orderedTree(msg)
+	if err != nil {
+		return "", err
+	}
+	obj, ok := v.(*jsonObject)
+	if !ok {
+		return markdownCell(v), nil
+	}
+	var list, tables strings.Builder
+	writeMarkdownFields(&list, &tables, obj, "")
+	out := strings.TrimSpace(strings.TrimSpace(list.String()) + "\n\n" + strings.TrimSpace(tables.String()))
+	if out == "" {
+		return "_Empty result._", nil
+	}
+	return out, nil
+}
+
+func (markdownFormatter) FormatList(msgs []proto.Message) (string, error) {
+	arr, err := orderedList(msgs)
+	if err != nil {
+		return "", err
+	}
+	if len(arr.items) == 0 {
+		return "_No results._", nil
+	}
+	var b strings.Builder
+	writeMarkdownTable(&b, arr)
+	return strings.TrimSpace(b.String()), nil
+}
+
+// writeMarkdownFields writes scalar and nested fields of obj as bullets
+// (nested keys joined with dots) and repeated messages as tables.
+func writeMarkdownFields(list, tables *strings.Builder, obj *jsonObject, prefix string) {
+	for _, k := range obj.keys {
+		key := prefix + k
+		switch t := obj.values[k].(type) {
+		case *jsonObject:
+			writeMarkdownFields(list, tables, t, key+".")
+		case *jsonArray:
+			if isObjectArray(t) {
+				fmt.Fprintf(tables, "\n**%s** (%d)\n\n", key, len(t.items))
+				writeMarkdownTable(tables, t)
+				continue
+			}
+			cells := make([]string, len(t.items))
+			for i, item := range t.items {
+				cells[i] = markdownCell(item)
+			}
+			fmt.Fprintf(list, "- **%s**: %s\n", key, strings.Join(cells, ", "))
+		default:
+			fmt.Fprintf(list, "- **%s**: %s\n", key, markdownCell(t))
+		}
+	}
+}
+
+func isObjectArray(a *jsonArray) bool {
+	for _, item := range a.items {
+		if _, ok := item.(*jsonObject); !ok {
+			return false
+		}
+	}
+	return len(a.items) > 0
+}
+
+// writeMarkdownTable writes a table whose columns are the keys of all rows
+// in order of first appearance.
+func writeMarkdownTable(b *strings.Builder, rows *jsonArray) {
+	var cols []string
+	seen := map[string]bool{}
+	for _, item := range rows.items {
+		if obj, ok := item.(*jsonObject); ok {
+			for _, k := range obj.keys {
+				if !seen[k] {
+					seen[k] = true
+					cols = append(cols, k)
+				}
+			}
+		}
+	}
+	if len(cols) == 0 {
+		cols = []string{"value"}
+	}
+	b.WriteString("| " + strings.Join(cols, " | ") + " |\n|")
+	b.WriteString(strings.Repeat(" --- |", len(cols)) + "\n")
+	for _, item := range rows.items {
+		b.WriteString("|")
+		obj, ok := item.(*jsonObject)
+		for _, c := range cols {
+			cell := ""
+			if !ok {
+				cell = markdownCell(item)
+			} else if v, has := obj.values[c]; has {
+				cell = markdownCell(v)
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+	}
+}
+
+// markdownCell renders a value for a table cell or bullet: scalars as text,
+// nested values as compact JSON.
+func markdownCell(v any) string {
+	var s string
+	switch t := v.(type) {
+	case string:
+		s = t
+	case json.Number:
+		s = t.String()
+	case bool:
+		s = fmt.Sprint(t)
+	case nil:
+		s = ""
+	default:
+		b, err := encodeJSON(t)
+		if err != nil {
+			return ""
+		}
+		s = "`" + string(b) + "`"
+	}
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
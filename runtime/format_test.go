@@ -0,0 +1,144 @@
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/proto"
+)
+
+const formatTestFile = `
+name: "format_test.proto"
+package: "formattest"
+syntax: "proto3"
+message_type {
+  name: "Item"
+  field { name: "id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "id" }
+  field { name: "count" number: 2 type: TYPE_INT32 label: LABEL_OPTIONAL json_name: "count" }
+}
+message_type {
+  name: "Response"
+  field { name: "title" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "title" }
+  field { name: "total_size" number: 2 type: TYPE_INT32 label: LABEL_OPTIONAL json_name: "totalSize" }
+  field { name: "tags" number: 3 type: TYPE_STRING label: LABEL_REPEATED json_name: "tags" }
+  field { name: "owner" number: 4 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "owner" type_name: ".formattest.Item" }
+  field { name: "items" number: 5 type: TYPE_MESSAGE label: LABEL_REPEATED json_name: "items" type_name: ".formattest.Item" }
+}
+`
+
+func TestFormatters(t *testing.T) {
+	md := testMessage(t, formatTestFile, "Response")
+	msg := newTestMessage(t, md, `{"title":"a|b","tags":["x","y"],"owner":{"id":"o1"},"items":[{"id":"1","count":2},{"id":"2"}]}`)
+	empty := newTestMessage(t, md, `{}`)
+	tests := []struct {
+		name string
+		f    ResultFormatter
+		msg  proto.Message
+		want string
+	}{
+		{"compact JSON", CompactJSONFormatter, msg,
+			`{"title":"a|b","tags":["x","y"],"owner":{"id":"o1"},"items":[{"id":"1","count":2},{"id":"2"}]}`},
+		{"YAML", YAMLFormatter, msg,
+			"title: a|b\ntags:\n  - x\n  - y\nowner:\n  id: o1\nitems:\n  - id: \"1\"\n    count: 2\n  - id: \"2\"\n"},
+		{"Markdown", MarkdownFormatter, msg,
+			"- **title**: a\\|b\n- **tags**: x, y\n- **owner.id**: o1\n\n" +
+				"**items** (2)\n\n| id | count |\n| --- | --- |\n| 1 | 2 |\n| 2 |  |"},
+		{"Markdown empty", MarkdownFormatter, empty, "_Empty result._"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.f.FormatMessage(tt.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatters_List(t *testing.T) {
+	md := testMessage(t, formatTestFile, "Item")
+	msgs := []proto.Message{newTestMessage(t, md, `{"id":"1","count":2}`), newTestMessage(t, md, `{"id":"2"}`)}
+	tests := []struct {
+		name string
+		f    ResultFormatter
+		msgs []proto.Message
+		want string
+	}{
+		{"compact JSON", CompactJSONFormatter, msgs, `[{"id":"1","count":2},{"id":"2"}]`},
+		{"YAML", YAMLFormatter, msgs, "- id: \"1\"\n  count: 2\n- id: \"2\"\n"},
+		{"Markdown", MarkdownFormatter, msgs, "| id | count |\n| --- | --- |\n| 1 | 2 |\n| 2 |  |"},
+		{"Markdown empty", MarkdownFormatter, nil, "_No results._"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.f.FormatList(tt.msgs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONFormatter_EmitsDefaults(t *testing.T) {
+	md := testMessage(t, formatTestFile, "Item")
+	got, err := JSONFormatter.FormatMessage(newTestMessage(t, md, `{"id":"1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, json.RawMessage(got), `{"id":"1","count":0}`)
+}
+
+func TestConfig_ResultFormatterFor(t *testing.T) {
+	custom := StructuredFormatter(YAMLFormatter)
+	tests := []struct {
+		name   string
+		cfg    *Config
+		format ResultFormat
+		want   ResultFormatter
+	}{
+		{"default", &Config{}, ResultFormatDefault, JSONFormatter},
+		{"configured", &Config{ResultFormatter: custom}, ResultFormatDefault, custom},
+		{"tool format wins", &Config{ResultFormatter: custom}, ResultFormatMarkdown, MarkdownFormatter},
+	}
+	for _, tt := range tests {
+		if got := tt.cfg.ResultFormatterFor(tt.format); got != tt.want {
+			t.Errorf("%s: got %T, want %T", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatResult(t *testing.T) {
+	md := testMessage(t, formatTestFile, "Item")
+	msg := newTestMessage(t, md, `{"id":"1","count":2}`)
+	tests := []struct {
+		name           string
+		f              ResultFormatter
+		want           string
+		wantStructured bool
+	}{
+		{"nil uses JSON", nil, `{"id":"1","count":2}`, false},
+		{"structured", StructuredFormatter(CompactJSONFormatter), `{"id":"1","count":2}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := FormatResult(tt.f, msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Content) != 1 {
+				t.Fatalf("content = %d blocks, want 1", len(res.Content))
+			}
+			assertJSONEqual(t, json.RawMessage(res.Content[0].(*mcp.TextContent).Text), tt.want)
+			if got := res.StructuredContent != nil; got != tt.wantStructured {
+				t.Errorf("structuredContent set = %v, want %v", got, tt.wantStructured)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package runtime
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/machanirobotics/grpc-mcp-gateway/internal/mimetype"
+	"github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// MediaURIPrefix is the URI scheme of embedded blob resources produced from
+// bytes fields whose media type is neither image/* nor audio/*. The rest of
+// the URI is the field path, e.g. attachment://parts/1/doc.
+const MediaURIPrefix = "attachment://"
+
+// MIMEType returns the media type string for an MCPMimeType value, e.g.
+// "image/png" for MCP_MIME_TYPE_IMAGE_PNG, or "" for UNSPECIFIED.
+func MIMEType(t mcppb.MCPMimeType) string {
+	return mimetype.String(t)
+}
+
+// ExtractMedia returns msg without its media bytes fields, and those fields
+// as MCP content in field order. msg is returned unchanged when it has no
+// media fields set; otherwise a modified copy is returned.
+func ExtractMedia(msg proto.Message) (proto.Message, []mcp.Content) {
+	if msg == nil || !hasMediaFields(msg.ProtoReflect().Descriptor()) {
+		return msg, nil
+	}
+	msg = proto.Clone(msg)
+	var media []mcp.Content
+	extractMedia(msg.ProtoReflect(), "", &media)
+	return msg, media
+}
+
+func extractMedia(m protoreflect.Message, path string, media *[]mcp.Content) {
+	// Range order is unspecified; walk the fields in declaration order.
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !m.Has(fd) {
+			continue
+		}
+		v := m.Get(fd)
+		p := string(fd.Name())
+		if path != "" {
+			p = path + "/" + p
+		}
+		switch {
+		case fd.Kind() == protoreflect.BytesKind && fieldMIMEType(fd) != "":
+			mimeType := fieldMIMEType(fd)
+			if fd.IsList() {
+				for j := 0; j < v.List().Len(); j++ {
+					*media = append(*media, mediaContent(v.List().Get(j).Bytes(), mimeType, p+"/"+strconv.Itoa(j)))
+				}
+			} else {
+				*media = append(*media, mediaContent(v.Bytes(), mimeType, p))
+			}
+			m.Clear(fd)
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+					extractMedia(mv.Message(), p+"/"+k.String(), media)
+					return true
+				})
+			}
+		case fd.Message() != nil && fd.IsList():
+			for j := 0; j < v.List().Len(); j++ {
+				extractMedia(v.List().Get(j).Message(), p+"/"+strconv.Itoa(j), media)
+			}
+		case fd.Message() != nil:
+			extractMedia(v.Message(), p, media)
+		}
+	}
+}
+
+func mediaContent(data []byte, mimeType, path string) mcp.Content {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return &mcp.ImageContent{Data: data, MIMEType: mimeType}
+	case strings.HasPrefix(mimeType, "audio/"):
+		return &mcp.AudioContent{Data: data, MIMEType: mimeType}
+	default:
+		return &mcp.EmbeddedResource{Resource: &mcp.ResourceContents{URI: MediaURIPrefix + path, MIMEType: mimeType, Blob: data}}
+	}
+}
+
+func fieldMIMEType(fd protoreflect.FieldDescriptor) string {
+	opts, ok := proto.GetExtension(fd.Options(), mcppb.E_Field).(*mcppb.MCPFieldOptions)
+	if !ok || opts == nil {
+		return ""
+	}
+	return MIMEType(opts.GetMimeType())
+}
+
+// hasMediaFields reports whether a message, or any message reachable from
+// it, declares a media bytes field.
+var hasMediaFields = reachesField(func(fd protoreflect.FieldDescriptor) bool {
+	return fd.Kind() == protoreflect.BytesKind && fieldMIMEType(fd) != ""
+})
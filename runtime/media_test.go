@@ -0,0 +1,152 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppb"
+)
+
+// testMessage builds the message named name from a FileDescriptorProto in
+// text format, which may use the [mcp.protobuf.field] option. Imports
+// resolve against the global registry. Runtime caches are keyed by full
+// name, so every test file needs its own package.
+func testMessage(t *testing.T, file, name string) protoreflect.MessageDescriptor {
+	t.Helper()
+	var fdp descriptorpb.FileDescriptorProto
+	if err := prototext.Unmarshal([]byte(file), &fdp); err != nil {
+		t.Fatal(err)
+	}
+	fd, err := protodesc.NewFile(&fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := fd.Messages().ByName(protoreflect.Name(name))
+	if md == nil {
+		t.Fatalf("no message %s in %s", name, fdp.GetName())
+	}
+	return md
+}
+
+// newTestMessage returns a dynamic message of type md set from protojson.
+func newTestMessage(t *testing.T, md protoreflect.MessageDescriptor, js string) proto.Message {
+	t.Helper()
+	msg := dynamicpb.NewMessage(md)
+	if err := protojson.Unmarshal([]byte(js), msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+const mediaTestFile = `
+name: "media_test.proto"
+package: "mediatest"
+syntax: "proto3"
+message_type {
+  name: "Part"
+  field { name: "data" number: 1 type: TYPE_BYTES label: LABEL_OPTIONAL
+    options { [mcp.protobuf.field] { mime_type: MCP_MIME_TYPE_APPLICATION_PDF } } }
+}
+message_type {
+  name: "Reply"
+  field { name: "text" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL }
+  field { name: "image" number: 2 type: TYPE_BYTES label: LABEL_OPTIONAL
+    options { [mcp.protobuf.field] { mime_type: MCP_MIME_TYPE_IMAGE_PNG } } }
+  field { name: "clips" number: 3 type: TYPE_BYTES label: LABEL_REPEATED
+    options { [mcp.protobuf.field] { mime_type: MCP_MIME_TYPE_AUDIO_WAV } } }
+  field { name: "parts" number: 4 type: TYPE_MESSAGE label: LABEL_REPEATED type_name: ".mediatest.Part" }
+}
+message_type {
+  name: "Plain"
+  field { name: "raw" number: 1 type: TYPE_BYTES label: LABEL_OPTIONAL }
+}
+`
+
+func TestExtractMedia(t *testing.T) {
+	tests := []struct {
+		name      string
+		msg       string
+		js        string
+		wantMedia []string // MIME type or resource URI of each content block
+		wantJSON  string
+	}{
+		{
+			name:      "top-level and repeated",
+			msg:       "Reply",
+			js:        `{"text":"hi","image":"AQI=","clips":["AQ==","Ag=="]}`,
+			wantMedia: []string{"image/png", "audio/wav", "audio/wav"},
+			wantJSON:  `{"text":"hi"}`,
+		},
+		{
+			name:      "nested message",
+			msg:       "Reply",
+			js:        `{"parts":[{},{"data":"AQI="}]}`,
+			wantMedia: []string{MediaURIPrefix + "parts/1/data"},
+			wantJSON:  `{"parts":[{},{}]}`,
+		},
+		{
+			name:     "no media fields",
+			msg:      "Plain",
+			js:       `{"raw":"AQI="}`,
+			wantJSON: `{"raw":"AQI="}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := newTestMessage(t, testMessage(t, mediaTestFile, tt.msg), tt.js)
+			orig := proto.Clone(in)
+			out, media := ExtractMedia(in)
+			if !proto.Equal(in, orig) {
+				t.Error("ExtractMedia modified its input")
+			}
+			var got []string
+			for _, c := range media {
+				switch c := c.(type) {
+				case *mcp.ImageContent:
+					got = append(got, c.MIMEType)
+				case *mcp.AudioContent:
+					got = append(got, c.MIMEType)
+				case *mcp.EmbeddedResource:
+					got = append(got, c.Resource.URI)
+				}
+			}
+			if len(got) != len(tt.wantMedia) {
+				t.Fatalf("media = %v, want %v", got, tt.wantMedia)
+			}
+			for i := range got {
+				if got[i] != tt.wantMedia[i] {
+					t.Errorf("media[%d] = %s, want %s", i, got[i], tt.wantMedia[i])
+				}
+			}
+			want := newTestMessage(t, out.ProtoReflect().Descriptor(), tt.wantJSON)
+			if !proto.Equal(out, want) {
+				t.Errorf("message = %v, want %s", out, tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestMIMEType(t *testing.T) {
+	tests := []struct {
+		in   mcppb.MCPMimeType
+		want string
+	}{
+		{mcppb.MCPMimeType_MCP_MIME_TYPE_UNSPECIFIED, ""},
+		{mcppb.MCPMimeType_MCP_MIME_TYPE_IMAGE_PNG, "image/png"},
+		{mcppb.MCPMimeType_MCP_MIME_TYPE_APPLICATION_OCTET_STREAM, "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		if got := MIMEType(tt.in); got != tt.want {
+			t.Errorf("MIMEType(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -28,16 +28,24 @@ type httpHeadersKeyType struct{}
 
 var httpHeadersKey = httpHeadersKeyType{}
 
+// extraMetadataKey is the context key for metadata taken from extra
+// properties by ExtractExtras.
+type extraMetadataKeyType struct{}
+
+var extraMetadataKey = extraMetadataKeyType{}
+
 // ForwardMetadata prepares gRPC outgoing metadata on the context by combining:
 //
 //  1. Incoming gRPC metadata (for gRPC→gRPC proxy scenarios) — all keys
 //     except reserved "grpc-" prefixed ones are forwarded automatically.
 //  2. HTTP headers stored by HeadersMiddleware (for HTTP→gRPC scenarios) —
 //     custom header mappings configured at runtime.
+//  3. Extra property values with a MetadataKey, stored by ExtractExtras.
 //
-// HTTP-extracted headers take precedence over incoming gRPC metadata for the
-// same key. This function is called by generated ForwardTo code before every
-// gRPC client call.
+// HTTP headers take precedence over incoming metadata for the same key.
+// Extras only fill keys set by neither, so a tool argument cannot replace a
+// credential forwarded by the client. This function is called by generated
+// ForwardTo code before every gRPC client call.
 func ForwardMetadata(ctx context.Context) context.Context {
 	md := metadata.MD{}
 
@@ -59,6 +67,15 @@ func ForwardMetadata(ctx context.Context) context.Context {
 		}
 	}
 
+	// 3. Merge extra properties mapped to metadata keys not set above.
+	if pairs, ok := ctx.Value(extraMetadataKey).(map[string]string); ok {
+		for k, v := range pairs {
+			if _, set := md[k]; !set {
+				md.Set(k, v)
+			}
+		}
+	}
+
 	if len(md) == 0 {
 		return ctx
 	}
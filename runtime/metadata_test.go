@@ -105,3 +105,27 @@ func TestForwardMetadata_NoHeaders(t *testing.T) {
 		t.Error("expected no outgoing metadata on empty context")
 	}
 }
+
+func TestForwardMetadata_ExtrasDoNotOverride(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "from-grpc"))
+	ctx = context.WithValue(ctx, httpHeadersKey, map[string]string{"authorization": "Bearer http-token"})
+	ctx = context.WithValue(ctx, extraMetadataKey, map[string]string{
+		"authorization": "Bearer extra",
+		"x-request-id":  "from-extra",
+		"x-tenant-id":   "tenant-1",
+	})
+
+	outMD, ok := metadata.FromOutgoingContext(ForwardMetadata(ctx))
+	if !ok {
+		t.Fatal("expected outgoing metadata")
+	}
+	for key, want := range map[string]string{
+		"authorization": "Bearer http-token",
+		"x-request-id":  "from-grpc",
+		"x-tenant-id":   "tenant-1",
+	} {
+		if got := outMD.Get(key); len(got) != 1 || got[0] != want {
+			t.Errorf("%s: got %v, want [%s]", key, got, want)
+		}
+	}
+}
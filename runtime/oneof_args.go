@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// CheckOneofs reports an error naming the group and its members when args
+// set more than one member of a oneof of md, or of a message nested in it.
+// Members sent as null count as unset, as in strict schemas.
+func CheckOneofs(args json.RawMessage, md protoreflect.MessageDescriptor) error {
+	if !hasOneofs(md) {
+		return nil
+	}
+	obj, ok, err := decodeArgs(args)
+	if err != nil || !ok {
+		return err
+	}
+	return checkOneofMessage(obj, md, "")
+}
+
+func checkOneofMessage(obj map[string]any, md protoreflect.MessageDescriptor, path string) error {
+	set := map[protoreflect.Name][]string{}
+	for key, val := range obj {
+		fd := md.Fields().ByJSONName(key)
+		if fd == nil {
+			fd = md.Fields().ByName(protoreflect.Name(key))
+		}
+		if fd == nil || val == nil {
+			continue
+		}
+		if oo := fd.ContainingOneof(); oo != nil && !oo.IsSynthetic() {
+			set[oo.Name()] = append(set[oo.Name()], string(fd.Name()))
+		}
+		p := joinPath(path, key)
+		if fd.Message() == nil {
+			continue
+		}
+		switch {
+		case fd.IsMap():
+			if m, ok := val.(map[string]any); ok && fd.MapValue().Message() != nil {
+				for k, item := range m {
+					if err := checkOneofValue(item, fd.MapValue().Message(), p+"."+k); err != nil {
+						return err
+					}
+				}
+			}
+		case fd.IsList():
+			if items, ok := val.([]any); ok {
+				for i, item := range items {
+					if err := checkOneofValue(item, fd.Message(), fmt.Sprintf("%s[%d]", p, i)); err != nil {
+						return err
+					}
+				}
+			}
+		default:
+			if err := checkOneofValue(val, fd.Message(), p); err != nil {
+				return err
+			}
+		}
+	}
+	for i := 0; i < md.Oneofs().Len(); i++ {
+		oo := md.Oneofs().Get(i)
+		if names := set[oo.Name()]; len(names) > 1 {
+			sort.Strings(names)
+			var members []string
+			for j := 0; j < oo.Fields().Len(); j++ {
+				members = append(members, string(oo.Fields().Get(j).Name()))
+			}
+			group := string(oo.Name())
+			if path != "" {
+				group = path + "." + group
+			}
+			return fmt.Errorf("invalid arguments: oneof %q accepts only one of %s, but %s are set; keep one and remove the others",
+				group, strings.Join(members, ", "), strings.Join(names, " and "))
+		}
+	}
+	return nil
+}
+
+func checkOneofValue(val any, md protoreflect.MessageDescriptor, path string) error {
+	if obj, ok := val.(map[string]any); ok {
+		return checkOneofMessage(obj, md, path)
+	}
+	return nil
+}
+
+// hasOneofs reports whether a message, or any message reachable from it,
+// has a (non-synthetic) oneof.
+var hasOneofs = reachesField(func(fd protoreflect.FieldDescriptor) bool {
+	oo := fd.ContainingOneof()
+	return oo != nil && !oo.IsSynthetic()
+})
@@ -0,0 +1,57 @@
+package runtime
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const oneofTestFile = `
+name: "oneof_args_test.proto"
+package: "oneofargstest"
+syntax: "proto3"
+message_type {
+  name: "Target"
+  field { name: "email" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "email" oneof_index: 0 }
+  field { name: "phone" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "phone" oneof_index: 0 }
+  oneof_decl { name: "channel" }
+}
+message_type {
+  name: "Request"
+  field { name: "id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "id" oneof_index: 0 }
+  field { name: "name" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "name" oneof_index: 0 }
+  field { name: "note" number: 3 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "note" proto3_optional: true oneof_index: 1 }
+  field { name: "targets" number: 4 type: TYPE_MESSAGE label: LABEL_REPEATED json_name: "targets" type_name: ".oneofargstest.Target" }
+  oneof_decl { name: "key" }
+  oneof_decl { name: "_note" }
+}
+`
+
+func TestCheckOneofs(t *testing.T) {
+	md := testMessage(t, oneofTestFile, "Request")
+	tests := []struct {
+		name    string
+		args    string
+		wantErr string
+	}{
+		{"one member", `{"id":"1","note":"x"}`, ""},
+		{"null counts as unset", `{"id":"1","name":null}`, ""},
+		{"two members", `{"id":"1","name":"a"}`, `oneof "key" accepts only one of id, name, but id and name are set`},
+		{"nested", `{"targets":[{"email":"a"},{"email":"a","phone":"b"}]}`, `oneof "targets[1].channel"`},
+		{"not an object", `[]`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckOneofs(json.RawMessage(tt.args), md)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
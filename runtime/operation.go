@@ -0,0 +1,297 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Tool names for the operation companion tools registered by RegisterOperationTools.
+const (
+	GetOperationToolName    = "get_operation"
+	CancelOperationToolName = "cancel_operation"
+)
+
+// Poll intervals used by WaitOperation. The interval doubles after every
+// poll, starting at minOperationPollInterval.
+const (
+	minOperationPollInterval = 500 * time.Millisecond
+	maxOperationPollInterval = 5 * time.Second
+)
+
+// errNoOperationsClient is reported when an operation must be polled but no
+// OperationsClient was configured.
+var errNoOperationsClient = errors.New("runtime: no OperationsClient configured; use WithOperationsClient")
+
+// protoJSON is the marshaller used for operation responses and metadata,
+// matching the options used by generated tool handlers.
+var protoJSON = protojson.MarshalOptions{UseProtoNames: true, EmitDefaultValues: true}
+
+// WaitOperation polls op with cfg.OperationsClient until it is done, sending
+// an MCP progress notification after every poll, and returns the operation's
+// response unpacked into response and rendered with formatter. An operation
+// that finished with an error is converted via HandleError.
+//
+// If the metadata message has a numeric progress_percent field it is reported
+// as progress out of 100; otherwise the poll count is reported.
+func WaitOperation(ctx context.Context, req *mcp.CallToolRequest, cfg *Config, op *longrunningpb.Operation, response proto.Message, formatter ResultFormatter) (*mcp.CallToolResult, error) {
+	var session *mcp.ServerSession
+	var token any
+	if req != nil {
+		session = req.Session
+		token = req.Params.GetProgressToken()
+	}
+	interval := minOperationPollInterval
+	for polls := 1; !op.GetDone(); polls++ {
+		if cfg.OperationsClient == nil {
+			return ErrorResult(fmt.Sprintf("operation %s is still running: %v", op.GetName(), errNoOperationsClient)), nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, maxOperationPollInterval)
+
+		var err error
+		op, err = cfg.OperationsClient.GetOperation(ctx, &longrunningpb.GetOperationRequest{Name: op.GetName()})
+		if err != nil {
+			return HandleError(err)
+		}
+		if !op.GetDone() {
+			_ = SendProgressFromProto(ctx, session, token, operationProgress(op, polls))
+		}
+	}
+
+	if e := op.GetError(); e != nil {
+		return HandleError(status.ErrorProto(e))
+	}
+	if response == nil || op.GetResponse() == nil {
+		return OperationResult(op, response)
+	}
+	if err := op.GetResponse().UnmarshalTo(response); err != nil {
+		return nil, fmt.Errorf("runtime: unpack operation response: %w", err)
+	}
+	return FormatResult(formatter, response)
+}
+
+// operationView is the JSON form of an operation returned to MCP clients.
+// Metadata and response are unpacked from their Any wrappers.
+type operationView struct {
+	Name     string          `json:"name"`
+	Done     bool            `json:"done"`
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    *grpcError      `json:"error,omitempty"`
+}
+
+// OperationResult returns op as a tool result: its name, done flag, unpacked
+// metadata and, once done, the response unpacked into response (or resolved
+// from the global type registry when response is nil) or the error. A
+// finished operation with an error is flagged as an error result.
+func OperationResult(op *longrunningpb.Operation, response proto.Message) (*mcp.CallToolResult, error) {
+	v := operationView{
+		Name:     op.GetName(),
+		Done:     op.GetDone(),
+		Metadata: anyJSON(op.GetMetadata(), nil),
+	}
+	if r := op.GetResponse(); r != nil {
+		v.Response = anyJSON(r, response)
+	}
+	if e := op.GetError(); e != nil {
+		ge := grpcErrorFromStatus(status.FromProto(e))
+		v.Error = &ge
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if v.Error != nil {
+		return ErrorResult(string(b)), nil
+	}
+	return TextResult(string(b)), nil
+}
+
+// anyJSON unpacks a into dst (or a new message from the global registry when
+// dst is nil) and marshals it. Unknown types are rendered as {"@type": url}.
+func anyJSON(a *anypb.Any, dst proto.Message) json.RawMessage {
+	if a == nil {
+		return nil
+	}
+	var err error
+	if dst != nil {
+		err = a.UnmarshalTo(dst)
+	} else {
+		dst, err = a.UnmarshalNew()
+	}
+	if err == nil {
+		if out, err := protoJSON.Marshal(dst); err == nil {
+			return out
+		}
+	}
+	out, _ := json.Marshal(map[string]string{"@type": a.GetTypeUrl()})
+	return out
+}
+
+// operationProgress builds a progress update for a running operation.
+func operationProgress(op *longrunningpb.Operation, polls int) *mcppb.MCPProgress {
+	p := &mcppb.MCPProgress{
+		Progress: float64(polls),
+		Message:  fmt.Sprintf("Waiting for operation %s", op.GetName()),
+	}
+	meta, err := op.GetMetadata().UnmarshalNew()
+	if err != nil {
+		return p
+	}
+	m := meta.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName("progress_percent")
+	if fd == nil || fd.IsList() || fd.IsMap() {
+		return p
+	}
+	var pct float64
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		pct = float64(m.Get(fd).Int())
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		pct = float64(m.Get(fd).Uint())
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		pct = m.Get(fd).Float()
+	default:
+		return p
+	}
+	total := 100.0
+	p.Progress = pct
+	p.Total = &total
+	return p
+}
+
+// RequireOperationsClient makes sure cfg has an OperationsClient for the
+// service svcName, whose RPCs return operations. Without WithOperationsClient
+// it uses backend, the server or client the service is registered with, when
+// that implements longrunningpb.OperationsClient or OperationsServer.
+// Generated handlers call it at registration; it panics when no client is
+// available, since every call of those tools would fail.
+func RequireOperationsClient(cfg *Config, svcName string, backend any) {
+	if cfg.OperationsClient != nil {
+		return
+	}
+	switch b := backend.(type) {
+	case longrunningpb.OperationsClient:
+		cfg.OperationsClient = b
+	case longrunningpb.OperationsServer:
+		cfg.OperationsClient = OperationsClientFromServer(b)
+	default:
+		panic(fmt.Sprintf("runtime: %s has RPCs returning operations but no OperationsClient; use WithOperationsClient or implement longrunningpb.OperationsServer", svcName))
+	}
+}
+
+// RegisterOperationTools adds the get_operation and cancel_operation tools
+// backed by client. Generated handlers call this for services with RPCs in
+// MCP_OPERATION_MODE_TOOLS; registering twice on the same server is harmless.
+// Nothing is registered when client is nil.
+func RegisterOperationTools(s *mcp.Server, client longrunningpb.OperationsClient) {
+	if client == nil {
+		return
+	}
+	nameSchema := &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"name": {Type: "string", Description: "Operation name returned by the tool that started it."},
+		},
+		Required: []string{"name"},
+	}
+	s.AddTool(&mcp.Tool{
+		Name:        GetOperationToolName,
+		Title:       "Get Operation",
+		Description: "Returns the latest state of a long-running operation, including its response once done.",
+		InputSchema: nameSchema,
+	}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := operationNameArg(req)
+		if err != nil {
+			return ErrorResult(err.Error()), nil
+		}
+		op, err := client.GetOperation(ForwardMetadata(ctx), &longrunningpb.GetOperationRequest{Name: name})
+		if err != nil {
+			return HandleError(err)
+		}
+		return OperationResult(op, nil)
+	})
+	s.AddTool(&mcp.Tool{
+		Name:        CancelOperationToolName,
+		Title:       "Cancel Operation",
+		Description: "Requests cancellation of a long-running operation and returns its latest state.",
+		InputSchema: nameSchema,
+	}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := operationNameArg(req)
+		if err != nil {
+			return ErrorResult(err.Error()), nil
+		}
+		ctx = ForwardMetadata(ctx)
+		if _, err := client.CancelOperation(ctx, &longrunningpb.CancelOperationRequest{Name: name}); err != nil {
+			return HandleError(err)
+		}
+		op, err := client.GetOperation(ctx, &longrunningpb.GetOperationRequest{Name: name})
+		if err != nil {
+			return HandleError(err)
+		}
+		return OperationResult(op, nil)
+	})
+}
+
+func operationNameArg(req *mcp.CallToolRequest) (string, error) {
+	var args struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	if args.Name == "" {
+		return "", errors.New("name is required")
+	}
+	return args.Name, nil
+}
+
+// OperationsClientFromServer adapts an in-process OperationsServer to the
+// OperationsClient interface expected by WithOperationsClient.
+func OperationsClientFromServer(srv longrunningpb.OperationsServer) longrunningpb.OperationsClient {
+	return operationsServerClient{srv: srv}
+}
+
+type operationsServerClient struct {
+	srv longrunningpb.OperationsServer
+}
+
+func (c operationsServerClient) ListOperations(ctx context.Context, in *longrunningpb.ListOperationsRequest, _ ...grpc.CallOption) (*longrunningpb.ListOperationsResponse, error) {
+	return c.srv.ListOperations(ctx, in)
+}
+
+func (c operationsServerClient) GetOperation(ctx context.Context, in *longrunningpb.GetOperationRequest, _ ...grpc.CallOption) (*longrunningpb.Operation, error) {
+	return c.srv.GetOperation(ctx, in)
+}
+
+func (c operationsServerClient) DeleteOperation(ctx context.Context, in *longrunningpb.DeleteOperationRequest, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+	return c.srv.DeleteOperation(ctx, in)
+}
+
+func (c operationsServerClient) CancelOperation(ctx context.Context, in *longrunningpb.CancelOperationRequest, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+	return c.srv.CancelOperation(ctx, in)
+}
+
+func (c operationsServerClient) WaitOperation(ctx context.Context, in *longrunningpb.WaitOperationRequest, _ ...grpc.CallOption) (*longrunningpb.Operation, error) {
+	return c.srv.WaitOperation(ctx, in)
+}
@@ -0,0 +1,69 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type testOperationsServer struct {
+	longrunningpb.UnimplementedOperationsServer
+}
+
+// connectTestClient connects an in-memory client to s.
+func connectTestClient(t *testing.T, s *mcp.Server) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+	st, ct := mcp.NewInMemoryTransports()
+	if _, err := s.Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = cs.Close() })
+	return cs
+}
+
+func TestRequireOperationsClient(t *testing.T) {
+	configured := OperationsClientFromServer(&testOperationsServer{})
+	tests := []struct {
+		name      string
+		cfg       *Config
+		backend   any
+		wantPanic bool
+	}{
+		{"configured client", &Config{OperationsClient: configured}, struct{}{}, false},
+		{"server backend", &Config{}, &testOperationsServer{}, false},
+		{"client backend", &Config{}, configured, false},
+		{"no client", &Config{}, struct{}{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Fatalf("panic = %v, want panic %v", r, tt.wantPanic)
+				}
+			}()
+			RequireOperationsClient(tt.cfg, "TestService", tt.backend)
+			if tt.cfg.OperationsClient == nil {
+				t.Error("OperationsClient not set")
+			}
+		})
+	}
+}
+
+func TestRegisterOperationTools_NilClient(t *testing.T) {
+	s := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	RegisterOperationTools(s, nil)
+	res, err := connectTestClient(t, s).ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Tools) != 0 {
+		t.Errorf("registered %d tools without a client", len(res.Tools))
+	}
+}
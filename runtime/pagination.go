@@ -0,0 +1,175 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Default budgets for PaginationOptions fields left at zero.
+const (
+	DefaultPaginationMaxItems = 1000
+	DefaultPaginationMaxBytes = 1 << 20
+)
+
+// PaginationCursorProperty is the tool argument that replaces page_token
+// when a List tool uses cursor mode; NextCursorProperty replaces
+// next_page_token in its result.
+const (
+	PaginationCursorProperty = "cursor"
+	NextCursorProperty       = "nextCursor"
+)
+
+// ExceededMetaKey is the _meta key set on a paginated result whose first
+// page alone exceeds a budget, from a backend that ignores page_size. The
+// page is returned whole; the value names the budget: "max_items" or
+// "max_bytes".
+const ExceededMetaKey = "exceeded"
+
+// PaginationOptions controls automatic pagination of AIP-158 List tools.
+// It mirrors mcp.protobuf.MCPPagination.
+type PaginationOptions struct {
+	Enabled  bool
+	MaxItems int  // 0 uses DefaultPaginationMaxItems
+	MaxBytes int  // 0 uses DefaultPaginationMaxBytes
+	Cursor   bool // cursor / nextCursor instead of page_token / next_page_token; proto option only
+}
+
+// CursorToPageToken rewrites the PaginationCursorProperty argument of a
+// cursor-mode List tool to page_token, so args unmarshal into the request.
+func CursorToPageToken(args json.RawMessage) json.RawMessage {
+	var in map[string]json.RawMessage
+	if len(args) == 0 || json.Unmarshal(args, &in) != nil {
+		return args
+	}
+	cursor, ok := in[PaginationCursorProperty]
+	if !ok {
+		return args
+	}
+	delete(in, PaginationCursorProperty)
+	in["page_token"] = cursor
+	out, err := json.Marshal(in)
+	if err != nil {
+		return args
+	}
+	return out
+}
+
+// Paginate calls a List method repeatedly, following next_page_token, until
+// there are no more pages or a budget in opts is reached. page_size is
+// lowered to the items left in the budget, and a later page that would
+// exceed either budget is left for the next call. The first page is always
+// kept whole, so next_page_token never skips items; if it alone exceeds a
+// budget, from a backend that ignores page_size, paging stops there and the
+// result gets an ExceededMetaKey _meta entry. The result is a copy of the
+// first response with the items of all pages in itemsField and
+// next_page_token set to where the next call should continue, rendered with
+// formatter; req and the responses are not modified. In cursor mode
+// next_page_token is omitted and the token is returned as NextCursorProperty
+// in a final text block and in _meta.
+func Paginate[Req, Resp proto.Message](ctx context.Context, opts *PaginationOptions, formatter ResultFormatter, req Req, itemsField string, call func(context.Context, Req) (Resp, error)) (*mcp.CallToolResult, error) {
+	maxItems, maxBytes := opts.MaxItems, opts.MaxBytes
+	if maxItems <= 0 {
+		maxItems = DefaultPaginationMaxItems
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultPaginationMaxBytes
+	}
+
+	req = proto.Clone(req).(Req)
+	rm := req.ProtoReflect()
+	pageSizeFd := rm.Descriptor().Fields().ByName("page_size")
+	pageTokenFd := rm.Descriptor().Fields().ByName("page_token")
+	if pageSizeFd == nil || pageTokenFd == nil {
+		return nil, fmt.Errorf("paginate: %s is not an AIP-158 list request", rm.Descriptor().FullName())
+	}
+	pageSize := int(rm.Get(pageSizeFd).Int())
+	token := rm.Get(pageTokenFd).String()
+
+	var combined protoreflect.Message
+	var items protoreflect.List
+	var itemsFd, nextFd protoreflect.FieldDescriptor
+	count, size := 0, 0
+	next, exceeded := "", ""
+	for {
+		if remaining := maxItems - count; pageSize <= 0 || pageSize > remaining {
+			rm.Set(pageSizeFd, protoreflect.ValueOfInt32(int32(remaining)))
+		}
+		rm.Set(pageTokenFd, protoreflect.ValueOfString(token))
+		resp, err := call(ctx, req)
+		if err != nil {
+			return HandleError(err)
+		}
+		m := resp.ProtoReflect()
+		if combined == nil {
+			itemsFd = m.Descriptor().Fields().ByName(protoreflect.Name(itemsField))
+			nextFd = m.Descriptor().Fields().ByName("next_page_token")
+			if itemsFd == nil || !itemsFd.IsList() || nextFd == nil {
+				return nil, fmt.Errorf("paginate: %s is not an AIP-158 list response", m.Descriptor().FullName())
+			}
+		}
+		page := m.Get(itemsFd).List()
+		keep := page.Len()
+		pageBytes := 0
+		for i := 0; i < keep; i++ {
+			if itemsFd.Kind() == protoreflect.MessageKind {
+				b, err := protoJSON.Marshal(page.Get(i).Message().Interface())
+				if err != nil {
+					return nil, err
+				}
+				pageBytes += len(b) + 1
+			} else {
+				pageBytes += len(page.Get(i).String()) + 1
+			}
+		}
+		if combined != nil && (size+pageBytes > maxBytes || count+keep > maxItems) {
+			next = token // resume with the page that did not fit
+			break
+		}
+		if combined == nil {
+			combined = proto.Clone(resp).ProtoReflect()
+			items = combined.Mutable(itemsFd).List()
+			switch {
+			case keep > maxItems:
+				exceeded = "max_items"
+			case pageBytes > maxBytes:
+				exceeded = "max_bytes"
+			}
+		} else {
+			for i := 0; i < keep; i++ {
+				items.Append(page.Get(i))
+			}
+		}
+		count += keep
+		size += pageBytes
+		next = m.Get(nextFd).String()
+		if next == "" || count >= maxItems || size >= maxBytes || keep == 0 {
+			break
+		}
+		token = next
+	}
+	if opts.Cursor {
+		combined.Clear(nextFd)
+	} else {
+		combined.Set(nextFd, protoreflect.ValueOfString(next))
+	}
+	res, err := FormatResult(formatter, combined.Interface())
+	if err != nil {
+		return nil, err
+	}
+	if opts.Cursor && next != "" {
+		res.Content = append(res.Content, &mcp.TextContent{Text: NextCursorProperty + ": " + next})
+		res.Meta = mcp.Meta{NextCursorProperty: next}
+	}
+	if exceeded != "" {
+		if res.Meta == nil {
+			res.Meta = mcp.Meta{}
+		}
+		res.Meta[ExceededMetaKey] = exceeded
+	}
+	return res, nil
+}
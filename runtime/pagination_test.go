@@ -0,0 +1,119 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/proto"
+)
+
+// listBackend serves total operations in pages of pageSize, or of the
+// requested page_size when honorPageSize is set. Page tokens are offsets.
+type listBackend struct {
+	total, pageSize int
+	honorPageSize   bool
+	first           *longrunningpb.ListOperationsResponse
+}
+
+func (b *listBackend) list(_ context.Context, req *longrunningpb.ListOperationsRequest) (*longrunningpb.ListOperationsResponse, error) {
+	start, _ := strconv.Atoi(req.GetPageToken())
+	n := b.pageSize
+	if b.honorPageSize && int(req.GetPageSize()) < n {
+		n = int(req.GetPageSize())
+	}
+	resp := &longrunningpb.ListOperationsResponse{}
+	for i := start; i < start+n && i < b.total; i++ {
+		resp.Operations = append(resp.Operations, &longrunningpb.Operation{Name: fmt.Sprint(i)})
+	}
+	if end := start + len(resp.Operations); end < b.total {
+		resp.NextPageToken = strconv.Itoa(end)
+	}
+	if b.first == nil {
+		b.first = resp
+	}
+	return resp, nil
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name         string
+		backend      *listBackend
+		opts         PaginationOptions
+		wantItems    int
+		wantNext     string
+		wantExceeded string
+	}{
+		{"all pages", &listBackend{total: 5, pageSize: 2, honorPageSize: true}, PaginationOptions{}, 5, "", ""},
+		{"item budget", &listBackend{total: 10, pageSize: 2, honorPageSize: true}, PaginationOptions{MaxItems: 5}, 5, "5", ""},
+		{"page over budget is left for the next call", &listBackend{total: 10, pageSize: 3}, PaginationOptions{MaxItems: 5}, 3, "3", ""},
+		{"first page over item budget is kept whole", &listBackend{total: 10, pageSize: 6}, PaginationOptions{MaxItems: 4}, 6, "6", "max_items"},
+		{"first page over byte budget is kept whole", &listBackend{total: 10, pageSize: 2, honorPageSize: true}, PaginationOptions{MaxBytes: 1}, 2, "2", "max_bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &longrunningpb.ListOperationsRequest{Name: "ops"}
+			res, err := Paginate(context.Background(), &tt.opts, JSONFormatter, req, "operations", tt.backend.list)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got struct {
+				Operations    []json.RawMessage `json:"operations"`
+				NextPageToken string            `json:"next_page_token"`
+			}
+			if err := json.Unmarshal([]byte(res.Content[0].(*mcp.TextContent).Text), &got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Operations) != tt.wantItems {
+				t.Errorf("items = %d, want %d", len(got.Operations), tt.wantItems)
+			}
+			if got.NextPageToken != tt.wantNext {
+				t.Errorf("next_page_token = %q, want %q", got.NextPageToken, tt.wantNext)
+			}
+			if got, _ := res.Meta[ExceededMetaKey].(string); got != tt.wantExceeded {
+				t.Errorf("exceeded meta = %q, want %q", got, tt.wantExceeded)
+			}
+			if !proto.Equal(req, &longrunningpb.ListOperationsRequest{Name: "ops"}) {
+				t.Errorf("request modified: %v", req)
+			}
+			if n := len(tt.backend.first.GetOperations()); n > tt.backend.pageSize {
+				t.Errorf("first response modified: %d operations", n)
+			}
+		})
+	}
+}
+
+func TestPaginate_Cursor(t *testing.T) {
+	backend := &listBackend{total: 4, pageSize: 2, honorPageSize: true}
+	res, err := Paginate(context.Background(), &PaginationOptions{MaxItems: 2, Cursor: true}, JSONFormatter, &longrunningpb.ListOperationsRequest{}, "operations", backend.list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.Meta[NextCursorProperty]; got != "2" {
+		t.Errorf("nextCursor = %v, want 2", got)
+	}
+}
+
+func TestWithAutoPagination_IgnoresCursor(t *testing.T) {
+	cfg := ApplyOptions(WithAutoPagination(PaginationOptions{Cursor: true}))
+	if p := cfg.PaginationFor(nil); p == nil || p.Cursor {
+		t.Errorf("PaginationFor(nil) = %+v, want enabled without cursor", p)
+	}
+}
+
+func TestCursorToPageToken(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{`{"cursor":"abc","filter":"x"}`, `{"filter":"x","page_token":"abc"}`},
+		{`{"filter":"x"}`, `{"filter":"x"}`},
+		{``, ``},
+	}
+	for _, tt := range tests {
+		if got := string(CursorToPageToken(json.RawMessage(tt.in))); got != tt.want {
+			t.Errorf("CursorToPageToken(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package runtime
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -83,13 +84,21 @@ func AppResourceURI(serviceName string) string {
 }
 
 // SetToolAppMeta returns a shallow clone of tool with _meta.ui.resourceUri set,
-// which makes the tool show up as an MCP App in supporting hosts.
-func SetToolAppMeta(tool *mcp.Tool, resourceURI string) *mcp.Tool {
+// which makes the tool show up as an MCP App in supporting hosts. visibility
+// lists who may call the tool ("model", "app"); empty means both.
+func SetToolAppMeta(tool *mcp.Tool, resourceURI string, visibility ...string) *mcp.Tool {
 	cloned := *tool
-	cloned.Meta = mcp.Meta{
-		"ui": map[string]any{
-			"resourceUri": resourceURI,
-		},
+	ui := map[string]any{
+		"resourceUri": resourceURI,
+	}
+	if len(visibility) > 0 {
+		ui["visibility"] = visibility
+	}
+	cloned.Meta = mcp.Meta{"ui": ui}
+	for k, v := range tool.Meta {
+		if k != "ui" {
+			cloned.Meta[k] = v
+		}
 	}
 	return &cloned
 }
@@ -121,7 +130,7 @@ func DefaultAppResourceHandler(appName, version, description string) func(contex
 	return func(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
 		return &mcp.ReadResourceResult{
 			Contents: []*mcp.ResourceContents{
-				{URI: req.Params.URI, MIMEType: "text/html", Text: html},
+				{URI: req.Params.URI, MIMEType: AppMIMEType, Text: html},
 			},
 		}, nil
 	}
@@ -187,9 +196,12 @@ func MergeElicitResult(args json.RawMessage, content map[string]any, fields []El
 			protoMap[f.Name] = m
 		}
 	}
-	// Unmarshal existing args.
+	// Unmarshal existing args, keeping numbers exact so 64-bit integers
+	// survive the round trip.
 	var merged map[string]any
-	if err := json.Unmarshal(args, &merged); err != nil || merged == nil {
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.UseNumber()
+	if err := dec.Decode(&merged); err != nil || merged == nil {
 		merged = make(map[string]any)
 	}
 	// Overlay elicitation content, reverse-mapping enum values where needed.
@@ -0,0 +1,253 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// RPCResourceHandler returns a resource handler whose content is supplied by
+// a unary RPC. The request is built from the read URI with BindResourceURI
+// and the response is rendered with ResourceContents. A NotFound status from
+// call is reported as a resource-not-found error.
+func RPCResourceHandler[Req, Resp proto.Message](uriTemplate, mimeType string, call func(context.Context, Req) (Resp, error)) mcp.ResourceHandler {
+	return func(ctx context.Context, rr *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		uri := rr.Params.URI
+		var zero Req
+		req := zero.ProtoReflect().New().Interface().(Req)
+		if err := BindResourceURI(uriTemplate, uri, req); err != nil {
+			return nil, err
+		}
+		resp, err := call(ctx, req)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil, mcp.ResourceNotFoundError(uri)
+			}
+			return nil, err
+		}
+		contents, err := ResourceContents(uri, mimeType, resp)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{contents}}, nil
+	}
+}
+
+// BindResourceURI sets the fields of req named by the variables of
+// uriTemplate (proto or JSON names, dotted for nested fields) to their
+// values in uri. When no variable names a field, the string name field of
+// req receives uri without its scheme, so "todo://users/1/todos/2" becomes
+// the AIP resource name "users/1/todos/2". Variables use simple ({var}) or
+// reserved ({+var}) expansion.
+func BindResourceURI(uriTemplate, uri string, req proto.Message) error {
+	re, names, err := compileURITemplate(uriTemplate)
+	if err != nil {
+		return err
+	}
+	m := re.FindStringSubmatch(uri)
+	if m == nil {
+		return mcp.ResourceNotFoundError(uri)
+	}
+	msg := req.ProtoReflect()
+	bound := false
+	for i, name := range names {
+		fd, target := resolveResourceField(msg, name)
+		if fd == nil {
+			continue
+		}
+		value, err := url.PathUnescape(m[i+1])
+		if err != nil {
+			value = m[i+1]
+		}
+		v, err := scalarValue(fd, value)
+		if err != nil {
+			return fmt.Errorf("resource %s: variable %s: %w", uri, name, err)
+		}
+		target.Set(fd, v)
+		bound = true
+	}
+	if !bound && len(names) > 0 {
+		if fd := msg.Descriptor().Fields().ByName("name"); fd != nil && fd.Kind() == protoreflect.StringKind && !fd.IsList() {
+			_, path, ok := strings.Cut(uri, "://")
+			if !ok {
+				path = uri
+			}
+			if p, err := url.PathUnescape(path); err == nil {
+				path = p
+			}
+			msg.Set(fd, protoreflect.ValueOfString(path))
+		}
+	}
+	return nil
+}
+
+// ResourceContents renders msg as the contents of the resource at uri:
+//   - a google.api.HttpBody supplies its data and content type;
+//   - a message whose only field is a string or bytes supplies that value;
+//   - otherwise the message is rendered as Markdown for text/markdown
+//     resources and as JSON for all others.
+//
+// Data of textual media types is returned as text, other data as a blob.
+func ResourceContents(uri, mimeType string, msg proto.Message) (*mcp.ResourceContents, error) {
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	if m.Descriptor().FullName() == "google.api.HttpBody" {
+		if ct := m.Get(fields.ByName("content_type")).String(); ct != "" {
+			mimeType = ct
+		}
+		return resourceData(uri, mimeType, m.Get(fields.ByName("data")).Bytes()), nil
+	}
+	if fields.Len() == 1 && !fields.Get(0).IsList() && !fields.Get(0).IsMap() {
+		fd := fields.Get(0)
+		switch fd.Kind() {
+		case protoreflect.BytesKind:
+			return resourceData(uri, mimeType, m.Get(fd).Bytes()), nil
+		case protoreflect.StringKind:
+			return &mcp.ResourceContents{URI: uri, MIMEType: mimeType, Text: m.Get(fd).String()}, nil
+		}
+	}
+	f, outType := JSONFormatter, "application/json"
+	if mimeType == "text/markdown" {
+		f, outType = MarkdownFormatter, mimeType
+	}
+	text, err := f.FormatMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ResourceContents{URI: uri, MIMEType: outType, Text: text}, nil
+}
+
+func resourceData(uri, mimeType string, data []byte) *mcp.ResourceContents {
+	if isTextMIMEType(mimeType) {
+		return &mcp.ResourceContents{URI: uri, MIMEType: mimeType, Text: string(data)}
+	}
+	return &mcp.ResourceContents{URI: uri, MIMEType: mimeType, Blob: data}
+}
+
+func isTextMIMEType(mimeType string) bool {
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	return strings.HasPrefix(mimeType, "text/") ||
+		mimeType == "application/json" || mimeType == "application/xml" ||
+		strings.HasSuffix(mimeType, "+json") || strings.HasSuffix(mimeType, "+xml")
+}
+
+type uriTemplateRegexp struct {
+	re    *regexp.Regexp
+	names []string
+}
+
+// uriTemplates caches compiled URI templates by template string.
+var uriTemplates sync.Map
+
+var uriTemplateVar = regexp.MustCompile(`\{(\+?)([^}]+)\}`)
+
+// compileURITemplate returns a regexp matching URIs of uriTemplate with one
+// group per variable, and the variable names.
+func compileURITemplate(uriTemplate string) (*regexp.Regexp, []string, error) {
+	if v, ok := uriTemplates.Load(uriTemplate); ok {
+		t := v.(uriTemplateRegexp)
+		return t.re, t.names, nil
+	}
+	var b strings.Builder
+	var names []string
+	b.WriteString("^")
+	last := 0
+	for _, loc := range uriTemplateVar.FindAllStringSubmatchIndex(uriTemplate, -1) {
+		b.WriteString(regexp.QuoteMeta(uriTemplate[last:loc[0]]))
+		if loc[3] > loc[2] {
+			b.WriteString("(.*)")
+		} else {
+			b.WriteString("([^/]*)")
+		}
+		names = append(names, uriTemplate[loc[4]:loc[5]])
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(uriTemplate[last:]))
+	b.WriteString("$")
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, nil, fmt.Errorf("uri template %q: %w", uriTemplate, err)
+	}
+	uriTemplates.Store(uriTemplate, uriTemplateRegexp{re: re, names: names})
+	return re, names, nil
+}
+
+// resolveResourceField resolves a dotted path of proto or JSON field names
+// to a singular scalar field and the message holding it, creating
+// intermediate messages. It returns a nil descriptor when the path does not
+// name such a field.
+func resolveResourceField(msg protoreflect.Message, path string) (protoreflect.FieldDescriptor, protoreflect.Message) {
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		fields := msg.Descriptor().Fields()
+		fd := fields.ByName(protoreflect.Name(part))
+		if fd == nil {
+			fd = fields.ByJSONName(part)
+		}
+		if fd == nil || fd.IsList() || fd.IsMap() {
+			return nil, nil
+		}
+		if i == len(parts)-1 {
+			if fd.Message() != nil {
+				return nil, nil
+			}
+			return fd, msg
+		}
+		if fd.Message() == nil {
+			return nil, nil
+		}
+		msg = msg.Mutable(fd).Message()
+	}
+	return nil, nil
+}
+
+// scalarValue parses s as a value of the scalar field fd.
+func scalarValue(fd protoreflect.FieldDescriptor, s string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(s)), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		return protoreflect.ValueOfBool(b), err
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(s)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("unknown %s value %q", fd.Enum().Name(), s)
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(s, 10, 32)
+		return protoreflect.ValueOfInt32(int32(n)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(s, 10, 64)
+		return protoreflect.ValueOfInt64(n), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(s, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(n)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(s, 10, 64)
+		return protoreflect.ValueOfUint64(n), err
+	case protoreflect.FloatKind:
+		f, err := strconv.ParseFloat(s, 32)
+		return protoreflect.ValueOfFloat32(float32(f)), err
+	case protoreflect.DoubleKind:
+		f, err := strconv.ParseFloat(s, 64)
+		return protoreflect.ValueOfFloat64(f), err
+	}
+	return protoreflect.Value{}, fmt.Errorf("unsupported field kind %s", fd.Kind())
+}
@@ -0,0 +1,125 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const resourceTestFile = `
+name: "resource_test.proto"
+package: "resourcetest"
+syntax: "proto3"
+enum_type {
+  name: "State"
+  value { name: "STATE_UNSPECIFIED" number: 0 }
+  value { name: "STATE_OPEN" number: 1 }
+}
+message_type {
+  name: "Parent"
+  field { name: "user_id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "userId" }
+}
+message_type {
+  name: "Request"
+  field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "name" }
+  field { name: "page" number: 2 type: TYPE_INT32 label: LABEL_OPTIONAL json_name: "page" }
+  field { name: "state" number: 3 type: TYPE_ENUM label: LABEL_OPTIONAL json_name: "state" type_name: ".resourcetest.State" }
+  field { name: "parent" number: 4 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "parent" type_name: ".resourcetest.Parent" }
+}
+`
+
+func TestBindResourceURI(t *testing.T) {
+	md := testMessage(t, resourceTestFile, "Request")
+	tests := []struct {
+		name     string
+		template string
+		uri      string
+		want     string
+		wantErr  bool
+	}{
+		{"name from path", "todo://users/{user}/todos/{todo}", "todo://users/1/todos/a%20b", `{"name":"users/1/todos/a b"}`, false},
+		{"scalar variables", "todo://{page}/{state}", "todo://3/STATE_OPEN", `{"page":3,"state":"STATE_OPEN"}`, false},
+		{"nested JSON name", "todo://users/{parent.userId}", "todo://users/u1", `{"parent":{"userId":"u1"}}`, false},
+		{"reserved expansion", "files://{+name}", "files://a/b/c", `{"name":"a/b/c"}`, false},
+		{"bad number", "todo://{page}", "todo://x", "", true},
+		{"no match", "todo://users/{user}", "other://users/1", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := dynamicpb.NewMessage(md)
+			err := BindResourceURI(tt.template, tt.uri, req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil {
+				got, _ := protojson.Marshal(req)
+				assertJSONEqual(t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRPCResourceHandler(t *testing.T) {
+	ops := RPCResourceHandler("ops://operations/{op}", "application/json",
+		func(_ context.Context, req *longrunningpb.GetOperationRequest) (*longrunningpb.Operation, error) {
+			if req.GetName() != "operations/1" {
+				return nil, status.Error(codes.NotFound, "no such operation")
+			}
+			return &longrunningpb.Operation{Name: req.GetName(), Done: true}, nil
+		})
+	notes := RPCResourceHandler("notes://{value}", "text/plain",
+		func(_ context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+			if req.GetValue() == "fail" {
+				return nil, status.Error(codes.Internal, "boom")
+			}
+			return wrapperspb.String("note " + req.GetValue()), nil
+		})
+	tests := []struct {
+		name         string
+		handler      mcp.ResourceHandler
+		uri          string
+		wantMIMEType string
+		wantText     string
+		wantNotFound bool
+		wantErr      bool
+	}{
+		{"JSON message", ops, "ops://operations/1", "application/json", `{"name":"operations/1","done":true}`, false, false},
+		{"single string field", notes, "notes://a", "text/plain", "note a", false, false},
+		{"NotFound status", ops, "ops://operations/2", "", "", true, true},
+		{"unmatched URI", ops, "ops://other/1", "", "", true, true},
+		{"other error", notes, "notes://fail", "", "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.handler(context.Background(), &mcp.ReadResourceRequest{Params: &mcp.ReadResourceParams{URI: tt.uri}})
+			var rpcErr *jsonrpc.Error
+			if notFound := errors.As(err, &rpcErr) && rpcErr.Code == mcp.CodeResourceNotFound; notFound != tt.wantNotFound {
+				t.Errorf("error = %v, want resource not found %v", err, tt.wantNotFound)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			c := res.Contents[0]
+			if c.URI != tt.uri || c.MIMEType != tt.wantMIMEType {
+				t.Errorf("contents = %s %s, want %s %s", c.URI, c.MIMEType, tt.uri, tt.wantMIMEType)
+			}
+			if tt.wantMIMEType == "application/json" {
+				assertJSONEqual(t, []byte(c.Text), tt.wantText)
+			} else if c.Text != tt.wantText {
+				t.Errorf("text = %q, want %q", c.Text, tt.wantText)
+			}
+		})
+	}
+}
@@ -0,0 +1,583 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ResultURIPrefix is the URI scheme of offloaded tool results.
+const ResultURIPrefix = "results://"
+
+// ResultURIMetaKey is the _meta key set on a tool result whose payload was
+// offloaded to a results:// resource. Its value is the URI of the first
+// offloaded payload.
+const ResultURIMetaKey = "resultUri"
+
+// DefaultResultTTL is how long InMemoryResultStore keeps offloaded results.
+const DefaultResultTTL = time.Hour
+
+// bytesPerToken is the rough protojson bytes-per-token ratio used to turn a
+// token budget into a byte budget.
+const bytesPerToken = 4
+
+// ErrResultNotFound is returned by ResultStore.Get for unknown or expired IDs.
+var ErrResultNotFound = errors.New("result not found")
+
+// ResultBudgetMode selects what happens to a tool result over its budget.
+type ResultBudgetMode int
+
+const (
+	// ResultBudgetTruncate shortens the largest JSON array in the result (or
+	// the text itself) to fit and appends a summary. Media that does not fit
+	// is omitted with a note.
+	ResultBudgetTruncate ResultBudgetMode = iota
+	// ResultBudgetOffload stores the full payload, and media that does not
+	// fit, in the ResultStore and returns resource_links to results://
+	// resources instead.
+	ResultBudgetOffload
+)
+
+// ResultBudget bounds the size of a tool result returned to the model.
+// When both limits are set the smaller one applies.
+type ResultBudget struct {
+	MaxBytes  int // 0 means no byte limit
+	MaxTokens int // estimated at 4 bytes per token; 0 means no token limit
+	Mode      ResultBudgetMode
+}
+
+// limit returns the budget in bytes, or 0 if the budget is unlimited.
+func (b ResultBudget) limit() int {
+	n := b.MaxBytes
+	if t := b.MaxTokens * bytesPerToken; t > 0 && (n == 0 || t < n) {
+		n = t
+	}
+	return n
+}
+
+// WithResultBudget returns an Option that applies budget to every tool result.
+func WithResultBudget(budget ResultBudget) Option {
+	return func(c *Config) {
+		c.ResultBudget = &budget
+	}
+}
+
+// WithToolResultBudget returns an Option that sets the budget for the tool
+// with the given MCP name, overriding WithResultBudget.
+func WithToolResultBudget(toolName string, budget ResultBudget) Option {
+	return func(c *Config) {
+		if c.ToolResultBudgets == nil {
+			c.ToolResultBudgets = make(map[string]ResultBudget)
+		}
+		c.ToolResultBudgets[toolName] = budget
+	}
+}
+
+// WithResultStore returns an Option that sets where offloaded results are
+// kept. Defaults to DefaultResultStore.
+func WithResultStore(store ResultStore) Option {
+	return func(c *Config) {
+		c.ResultStore = store
+	}
+}
+
+// resultBudgetFor returns the budget for a tool, or nil when unlimited.
+func (c *Config) resultBudgetFor(toolName string) *ResultBudget {
+	if b, ok := c.ToolResultBudgets[toolName]; ok {
+		return &b
+	}
+	return c.ResultBudget
+}
+
+// ResultStoreOrDefault returns the configured ResultStore, or DefaultResultStore.
+func (c *Config) ResultStoreOrDefault() ResultStore {
+	if c.ResultStore != nil {
+		return c.ResultStore
+	}
+	return DefaultResultStore()
+}
+
+// ResultStore keeps offloaded tool results for the results:// resource.
+// Results belong to the MCP session that produced them; Get must not return
+// a result stored for another session. Implementations must be safe for
+// concurrent use.
+type ResultStore interface {
+	// Put stores a payload for the session and returns its ID.
+	Put(ctx context.Context, sessionID string, data []byte, mimeType string) (string, error)
+	// Get returns a payload stored for the session, or ErrResultNotFound.
+	Get(ctx context.Context, sessionID, id string) (data []byte, mimeType string, err error)
+}
+
+// InMemoryResultStore is the default ResultStore. Entries are dropped once
+// their TTL has elapsed.
+type InMemoryResultStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]storedResult
+}
+
+type storedResult struct {
+	sessionID string
+	data      []byte
+	mimeType  string
+	expires   time.Time
+}
+
+// NewInMemoryResultStore creates an in-memory store that keeps results for
+// ttl. A ttl of zero uses DefaultResultTTL.
+func NewInMemoryResultStore(ttl time.Duration) *InMemoryResultStore {
+	if ttl <= 0 {
+		ttl = DefaultResultTTL
+	}
+	return &InMemoryResultStore{ttl: ttl, entries: make(map[string]storedResult)}
+}
+
+var (
+	defaultResultStoreOnce sync.Once
+	defaultResultStore     ResultStore
+)
+
+// DefaultResultStore returns the process-wide in-memory ResultStore used
+// when no store is configured via WithResultStore.
+func DefaultResultStore() ResultStore {
+	defaultResultStoreOnce.Do(func() {
+		defaultResultStore = NewInMemoryResultStore(DefaultResultTTL)
+	})
+	return defaultResultStore
+}
+
+// Put implements ResultStore.
+func (s *InMemoryResultStore) Put(_ context.Context, sessionID string, data []byte, mimeType string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for id, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, id)
+		}
+	}
+	id := newTaskID()
+	s.entries[id] = storedResult{sessionID: sessionID, data: data, mimeType: mimeType, expires: now.Add(s.ttl)}
+	return id, nil
+}
+
+// Get implements ResultStore.
+func (s *InMemoryResultStore) Get(_ context.Context, sessionID, id string) ([]byte, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[id]
+	if !ok || e.sessionID != sessionID || time.Now().After(e.expires) {
+		return nil, "", ErrResultNotFound
+	}
+	return e.data, e.mimeType, nil
+}
+
+// ResultURI returns the results:// URI of an offloaded result.
+func ResultURI(id string) string { return ResultURIPrefix + id }
+
+// RegisterResultResource adds the results://{id} resource template that
+// serves offloaded tool results to the session that produced them.
+// Generated handlers call this for every service; it does nothing unless a
+// budget is configured.
+func RegisterResultResource(s *mcp.Server, cfg *Config) {
+	if cfg.ResultBudget == nil && len(cfg.ToolResultBudgets) == 0 {
+		return
+	}
+	store := cfg.ResultStoreOrDefault()
+	s.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: ResultURIPrefix + "{id}",
+		Name:        "results",
+		Description: "Full payload of a tool result that exceeded its size budget.",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		uri := req.Params.URI
+		data, mimeType, err := store.Get(ctx, sessionID(req.Session), strings.TrimPrefix(uri, ResultURIPrefix))
+		if err != nil {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{resourceData(uri, mimeType, data)}}, nil
+	})
+}
+
+// AddTool registers a generated tool handler on s, applying the result
+// budget configured for the tool. Unless cfg.StrictArgs is set, arguments
+// are coerced leniently (see coercingHandler).
+func AddTool(s *mcp.Server, cfg *Config, tool *mcp.Tool, h mcp.ToolHandler) {
+	if cfg.resultBudgetFor(tool.Name) != nil {
+		inner := h
+		h = func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			res, err := inner(ctx, req)
+			if err != nil || res == nil {
+				return res, err
+			}
+			return cfg.shapeResult(ctx, sessionID(req.Session), tool.Name, res), nil
+		}
+	}
+	if !cfg.StrictArgs {
+		h = coercingHandler(cfg, h)
+	}
+	s.AddTool(tool, h)
+}
+
+// shapeResult applies the tool's budget to res. Error results and results
+// within budget are returned unchanged. Every content block counts against
+// the budget, media at its base64-encoded size. The largest text block is
+// the payload: it is shortened (or offloaded) to fit next to the other text
+// blocks, which are kept as they are. Media is kept while it fits in what
+// is left and is otherwise offloaded or omitted with a note.
+func (c *Config) shapeResult(ctx context.Context, sessionID, toolName string, res *mcp.CallToolResult) *mcp.CallToolResult {
+	budget := c.resultBudgetFor(toolName)
+	if budget == nil || res == nil || res.IsError {
+		return res
+	}
+	limit := budget.limit()
+	if limit <= 0 || contentSize(res.Content...) <= limit {
+		return res
+	}
+	sh := &resultShaper{
+		ctx:       ctx,
+		sessionID: sessionID,
+		limit:     limit,
+		out:       &mcp.CallToolResult{Meta: copyMeta(res.Meta)},
+	}
+	if budget.Mode == ResultBudgetOffload {
+		sh.store = c.ResultStoreOrDefault()
+	}
+
+	body, room := -1, limit
+	for i, ct := range res.Content {
+		if tc, ok := ct.(*mcp.TextContent); ok && (body < 0 || len(tc.Text) > len(res.Content[body].(*mcp.TextContent).Text)) {
+			body = i
+		}
+	}
+	for i, ct := range res.Content {
+		if i != body && !isMediaContent(ct) {
+			room -= contentSize(ct)
+		}
+	}
+	var shapedBody []mcp.Content
+	if body >= 0 {
+		shapedBody = sh.text(res.Content[body].(*mcp.TextContent).Text, room)
+		room -= contentSize(shapedBody...)
+	}
+	for i, ct := range res.Content {
+		switch {
+		case i == body:
+			sh.out.Content = append(sh.out.Content, shapedBody...)
+		case isMediaContent(ct):
+			if n := contentSize(ct); n <= room {
+				room -= n
+				sh.out.Content = append(sh.out.Content, ct)
+			} else {
+				sh.out.Content = append(sh.out.Content, sh.media(ct))
+			}
+		default:
+			sh.out.Content = append(sh.out.Content, ct)
+		}
+	}
+	return sh.out
+}
+
+// resultShaper builds the result that shapeResult returns. store is nil in
+// truncate mode.
+type resultShaper struct {
+	ctx       context.Context
+	store     ResultStore
+	sessionID string
+	limit     int
+	out       *mcp.CallToolResult
+}
+
+// text fits a text block into room bytes: it offloads it when a store is
+// set, and otherwise, or if the store fails, truncates it. For JSON,
+// trailing items of the largest array are dropped; other text is cut at a
+// character boundary. A second block summarizes what was removed.
+func (sh *resultShaper) text(text string, room int) []mcp.Content {
+	if len(text) <= room {
+		return []mcp.Content{&mcp.TextContent{Text: text}}
+	}
+	if room < 0 {
+		room = 0
+	}
+	mimeType := "text/plain"
+	if json.Valid([]byte(text)) {
+		mimeType = "application/json"
+	}
+	if link, ok := sh.offload([]byte(text), mimeType); ok {
+		return []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("The result is %d bytes, over this tool's %d-byte budget. Read the full result from %s with resources/read.", len(text), sh.limit, link.URI)},
+			link,
+		}
+	}
+	body, summary, ok := truncateJSON(text, room)
+	if !ok {
+		cut := room
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		body = text[:cut]
+		summary = fmt.Sprintf("Result truncated to %d of %d bytes to fit this tool's budget.", cut, len(text))
+	}
+	sh.out.Meta[TruncatedMetaKey] = "result_budget"
+	return []mcp.Content{&mcp.TextContent{Text: body}, &mcp.TextContent{Text: summary}}
+}
+
+// media replaces a media block that does not fit with a resource_link to
+// its offloaded data, or else with a note that it was omitted.
+func (sh *resultShaper) media(c mcp.Content) mcp.Content {
+	data, mimeType := mediaData(c)
+	if link, ok := sh.offload(data, mimeType); ok {
+		return link
+	}
+	sh.out.Meta[TruncatedMetaKey] = "result_budget"
+	return &mcp.TextContent{Text: fmt.Sprintf("Omitted %s content of %d bytes to fit this tool's budget.", mimeType, len(data))}
+}
+
+// offload stores data for the session and returns a resource_link to it.
+// ok is false in truncate mode or if the store fails. The first offloaded
+// URI is recorded under _meta.resultUri.
+func (sh *resultShaper) offload(data []byte, mimeType string) (link *mcp.ResourceLink, ok bool) {
+	if sh.store == nil {
+		return nil, false
+	}
+	id, err := sh.store.Put(sh.ctx, sh.sessionID, data, mimeType)
+	if err != nil {
+		return nil, false
+	}
+	uri := ResultURI(id)
+	size := int64(len(data))
+	if _, set := sh.out.Meta[ResultURIMetaKey]; !set {
+		sh.out.Meta[ResultURIMetaKey] = uri
+	}
+	return &mcp.ResourceLink{URI: uri, Name: "result-" + id, MIMEType: mimeType, Size: &size}, true
+}
+
+// contentSize returns the bytes the content adds to a result: text length,
+// and the base64-encoded length of media data.
+func contentSize(content ...mcp.Content) int {
+	n := 0
+	for _, c := range content {
+		switch c := c.(type) {
+		case *mcp.TextContent:
+			n += len(c.Text)
+		case *mcp.ImageContent:
+			n += base64.StdEncoding.EncodedLen(len(c.Data))
+		case *mcp.AudioContent:
+			n += base64.StdEncoding.EncodedLen(len(c.Data))
+		case *mcp.EmbeddedResource:
+			if c.Resource != nil {
+				n += len(c.Resource.Text) + base64.StdEncoding.EncodedLen(len(c.Resource.Blob))
+			}
+		}
+	}
+	return n
+}
+
+// isMediaContent reports whether c carries image, audio or resource data.
+func isMediaContent(c mcp.Content) bool {
+	switch c.(type) {
+	case *mcp.ImageContent, *mcp.AudioContent, *mcp.EmbeddedResource:
+		return true
+	}
+	return false
+}
+
+// mediaData returns the data and MIME type of a media content block.
+func mediaData(c mcp.Content) ([]byte, string) {
+	switch c := c.(type) {
+	case *mcp.ImageContent:
+		return c.Data, c.MIMEType
+	case *mcp.AudioContent:
+		return c.Data, c.MIMEType
+	case *mcp.EmbeddedResource:
+		if c.Resource != nil {
+			if c.Resource.Blob != nil {
+				return c.Resource.Blob, c.Resource.MIMEType
+			}
+			return []byte(c.Resource.Text), c.Resource.MIMEType
+		}
+	}
+	return nil, ""
+}
+
+func copyMeta(m mcp.Meta) mcp.Meta {
+	out := make(mcp.Meta, len(m)+1)
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// truncateJSON keeps the largest prefix of the largest array in text (a JSON
+// document) such that the re-encoded document fits limit. Object key order
+// is preserved. ok is false if text is not JSON or has no array to shorten.
+func truncateJSON(text string, limit int) (body, summary string, ok bool) {
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	root, err := decodeOrdered(dec)
+	if err != nil {
+		return "", "", false
+	}
+	var best *jsonArray
+	walkArrays(root, "", func(a *jsonArray) {
+		if best == nil || len(a.items) > len(best.items) {
+			best = a
+		}
+	})
+	if best == nil || len(best.items) == 0 {
+		return "", "", false
+	}
+	all := best.items
+	encode := func(n int) ([]byte, bool) {
+		best.items = all[:n]
+		b, err := encodeJSON(root)
+		return b, err == nil && len(b) <= limit
+	}
+	lo, hi := 0, len(all) // largest n with encode(n) fitting, by binary search
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if _, fits := encode(mid); fits {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	b, fits := encode(lo)
+	if !fits {
+		return "", "", false
+	}
+	where := "the result"
+	if best.path != "" {
+		where = best.path
+	}
+	return string(b), fmt.Sprintf("Result truncated to fit this tool's budget: kept %d of %d items in %s.", lo, len(all), where), true
+}
+
+// jsonObject is a decoded JSON object that remembers its key order.
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+// jsonArray is a decoded JSON array; path is its location for summaries.
+type jsonArray struct {
+	path  string
+	items []any
+}
+
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := &jsonObject{values: map[string]any{}}
+			for dec.More() {
+				kt, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ := kt.(string)
+				v, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				obj.keys = append(obj.keys, key)
+				obj.values[key] = v
+			}
+			_, err := dec.Token()
+			return obj, err
+		case '[':
+			arr := &jsonArray{}
+			for dec.More() {
+				v, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr.items = append(arr.items, v)
+			}
+			_, err := dec.Token()
+			return arr, err
+		}
+		return nil, fmt.Errorf("unexpected %v", t)
+	default:
+		return t, nil
+	}
+}
+
+func walkArrays(v any, path string, fn func(*jsonArray)) {
+	switch t := v.(type) {
+	case *jsonObject:
+		for _, k := range t.keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			walkArrays(t.values[k], p, fn)
+		}
+	case *jsonArray:
+		t.path = path
+		fn(t)
+		for _, item := range t.items {
+			walkArrays(item, path+"[]", fn)
+		}
+	}
+}
+
+func encodeJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeJSON(buf *bytes.Buffer, v any) error {
+	switch t := v.(type) {
+	case *jsonObject:
+		buf.WriteByte('{')
+		for i, k := range t.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, k); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeJSON(buf, t.values[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case *jsonArray:
+		buf.WriteByte('[')
+		for i, item := range t.items {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(t); err != nil {
+			return err
+		}
+		buf.Truncate(buf.Len() - 1) // Encode appends a newline
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestTruncateJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		limit    int
+		wantBody string
+		wantOK   bool
+	}{
+		{"top-level array", `[1,2,3,4,5]`, 7, `[1,2,3]`, true},
+		{"largest nested array", `{"b":[1],"a":[1,2,3,4]}`, 19, `{"b":[1],"a":[1,2]}`, true},
+		{"nothing fits", `{"name":"a long name","items":[1]}`, 5, "", false},
+		{"not JSON", `items: 1, 2, 3`, 5, "", false},
+		{"no array", `{"a":"b"}`, 3, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _, ok := truncateJSON(tt.text, tt.limit)
+			if ok != tt.wantOK || body != tt.wantBody {
+				t.Errorf("truncateJSON(%s, %d) = %q, %v; want %q, %v", tt.text, tt.limit, body, ok, tt.wantBody, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestShapeResult(t *testing.T) {
+	items := `{"items":[` + strings.TrimSuffix(strings.Repeat(`"xxxxxxxx",`, 20), ",") + `]}`
+	note := "Stream truncated after 20 messages."
+	image := &mcp.ImageContent{Data: make([]byte, 300), MIMEType: "image/png"}
+
+	tests := []struct {
+		name      string
+		mode      ResultBudgetMode
+		content   []mcp.Content
+		wantSame  bool
+		wantTypes []string
+		wantMeta  string
+	}{
+		{
+			name:     "within budget",
+			content:  []mcp.Content{&mcp.TextContent{Text: "ok"}},
+			wantSame: true,
+		},
+		{
+			name:      "collected stream keeps its note",
+			content:   []mcp.Content{&mcp.TextContent{Text: items}, &mcp.TextContent{Text: note}},
+			wantTypes: []string{"json", "text", "text"},
+			wantMeta:  TruncatedMetaKey,
+		},
+		{
+			name:      "media over budget is omitted",
+			content:   []mcp.Content{&mcp.TextContent{Text: "caption"}, image},
+			wantTypes: []string{"text", "text"},
+			wantMeta:  TruncatedMetaKey,
+		},
+		{
+			name:      "media over budget is offloaded",
+			mode:      ResultBudgetOffload,
+			content:   []mcp.Content{&mcp.TextContent{Text: "caption"}, image},
+			wantTypes: []string{"text", "link"},
+			wantMeta:  ResultURIMetaKey,
+		},
+		{
+			name:      "text over budget is offloaded",
+			mode:      ResultBudgetOffload,
+			content:   []mcp.Content{&mcp.TextContent{Text: items}},
+			wantTypes: []string{"text", "link"},
+			wantMeta:  ResultURIMetaKey,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := ApplyOptions(
+				WithResultBudget(ResultBudget{MaxBytes: 200, Mode: tt.mode}),
+				WithResultStore(NewInMemoryResultStore(0)),
+			)
+			res := &mcp.CallToolResult{Content: tt.content}
+			got := cfg.shapeResult(context.Background(), "session", "tool", res)
+			if tt.wantSame {
+				if got != res {
+					t.Error("result within budget was changed")
+				}
+				return
+			}
+			var types []string
+			for _, c := range got.Content {
+				switch c := c.(type) {
+				case *mcp.TextContent:
+					if json.Valid([]byte(c.Text)) {
+						types = append(types, "json")
+					} else {
+						types = append(types, "text")
+					}
+				case *mcp.ResourceLink:
+					types = append(types, "link")
+				default:
+					types = append(types, "other")
+				}
+			}
+			if strings.Join(types, ",") != strings.Join(tt.wantTypes, ",") {
+				t.Errorf("content = %v, want %v", types, tt.wantTypes)
+			}
+			if _, ok := got.Meta[tt.wantMeta]; !ok {
+				t.Errorf("_meta.%s not set: %v", tt.wantMeta, got.Meta)
+			}
+			if n := contentSize(got.Content...); n > 200+100 {
+				t.Errorf("shaped result is %d bytes", n)
+			}
+		})
+	}
+}
+
+func TestInMemoryResultStore_SessionScope(t *testing.T) {
+	store := NewInMemoryResultStore(0)
+	id, err := store.Put(context.Background(), "mine", []byte("data"), "text/plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		session string
+		wantErr error
+	}{
+		{"mine", nil},
+		{"other", ErrResultNotFound},
+		{"", ErrResultNotFound},
+	}
+	for _, tt := range tests {
+		if _, _, err := store.Get(context.Background(), tt.session, id); !errors.Is(err, tt.wantErr) {
+			t.Errorf("Get(%q) error = %v, want %v", tt.session, err, tt.wantErr)
+		}
+	}
+}
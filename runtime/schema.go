@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -30,6 +31,13 @@ func MustCreateTool(name, description, schemaJSON string) *mcp.Tool {
 	}
 }
 
+// ToolDisplay sets the title and icons clients show for tool and returns it.
+func ToolDisplay(tool *mcp.Tool, title string, icons ...mcp.Icon) *mcp.Tool {
+	tool.Title = title
+	tool.Icons = icons
+	return tool
+}
+
 // PrepareToolWithExtras returns a shallow clone of tool with extra properties
 // injected into its InputSchema.  If there are no extras the original tool is
 // returned as-is.
@@ -49,10 +57,7 @@ func PrepareToolWithExtras(tool *mcp.Tool, extras []ExtraProperty) *mcp.Tool {
 	}
 
 	for _, prop := range extras {
-		schema.Properties[prop.Name] = &jsonschema.Schema{
-			Type:        "string",
-			Description: prop.Description,
-		}
+		schema.Properties[prop.Name] = prop.jsonSchema()
 		if prop.Required {
 			schema.Required = append(schema.Required, prop.Name)
 		}
@@ -63,6 +68,27 @@ func PrepareToolWithExtras(tool *mcp.Tool, extras []ExtraProperty) *mcp.Tool {
 	return &cloned
 }
 
+// jsonSchema returns the tool schema of the property.
+func (p ExtraProperty) jsonSchema() *jsonschema.Schema {
+	if p.Schema != nil {
+		s := p.Schema.CloneSchemas()
+		if s.Description == "" {
+			s.Description = p.Description
+		}
+		return s
+	}
+	s := &jsonschema.Schema{Type: p.Type, Description: p.Description, Enum: p.Enum}
+	if s.Type == "" {
+		s.Type = "string"
+	}
+	if p.Default != nil {
+		if b, err := json.Marshal(p.Default); err == nil {
+			s.Default = b
+		}
+	}
+	return s
+}
+
 // TextResult creates a CallToolResult containing a single text content block.
 // Use for successful tool responses:
 //
@@ -82,31 +108,78 @@ func ErrorResult(text string) *mcp.CallToolResult {
 	}
 }
 
-// ExtractExtras unmarshals raw JSON arguments, pulls out any configured extra
-// properties into the context, and returns the (potentially modified) arguments
-// as a byte slice ready for protojson.Unmarshal.
+// ExtractExtras unmarshals raw JSON arguments, moves the configured extra
+// properties into the context, and returns the remaining arguments ready for
+// protojson.Unmarshal. Absent extras take their Default. Extras with a
+// MetadataKey are also recorded for ForwardMetadata, and all values for
+// fields bound to extras (see FieldBinding). It fails when the arguments are
+// not a JSON object or a Required extra without a Default is absent.
 //
 // When no extra properties are configured the raw bytes are returned unchanged.
-func ExtractExtras(ctx context.Context, raw json.RawMessage, cfg *Config) (json.RawMessage, context.Context) {
+func ExtractExtras(ctx context.Context, raw json.RawMessage, cfg *Config) (json.RawMessage, context.Context, error) {
 	if len(cfg.ExtraProperties) == 0 {
-		return raw, ctx
+		return raw, ctx, nil
 	}
 
-	var m map[string]any
-	if err := json.Unmarshal(raw, &m); err != nil {
-		return raw, ctx
+	// Other arguments stay raw so that large integers keep their precision.
+	var m map[string]json.RawMessage
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return nil, ctx, fmt.Errorf("arguments: %w", err)
+		}
 	}
 
+	var md map[string]string
+	values := make(map[string]any)
 	for _, prop := range cfg.ExtraProperties {
-		if v, ok := m[prop.Name]; ok {
+		var v any
+		if b, ok := m[prop.Name]; ok {
+			delete(m, prop.Name)
+			if err := json.Unmarshal(b, &v); err != nil {
+				return nil, ctx, fmt.Errorf("argument %s: %w", prop.Name, err)
+			}
+		} else if prop.Default != nil {
+			v = prop.Default
+		} else if prop.Required {
+			return nil, ctx, fmt.Errorf("missing required argument %s", prop.Name)
+		} else {
+			continue
+		}
+		values[prop.Name] = v
+		if prop.ContextKey != nil {
 			ctx = context.WithValue(ctx, prop.ContextKey, v)
 		}
+		if prop.MetadataKey != "" {
+			if md == nil {
+				md = make(map[string]string)
+			}
+			md[strings.ToLower(prop.MetadataKey)] = metadataValue(v)
+		}
+	}
+	ctx = context.WithValue(ctx, extraValuesKey, values)
+	if md != nil {
+		ctx = context.WithValue(ctx, extraMetadataKey, md)
+	}
+	if m == nil {
+		m = map[string]json.RawMessage{}
 	}
 
-	// Re-marshal in case the caller wants the extras stripped later.
 	out, err := json.Marshal(m)
 	if err != nil {
-		return raw, ctx
+		return nil, ctx, err
+	}
+	return out, ctx, nil
+}
+
+// metadataValue renders an extra property or claim value as a string, for
+// gRPC metadata and bound fields.
+func metadataValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
 	}
-	return out, ctx
+	return string(b)
 }
@@ -0,0 +1,64 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+type tenantKey struct{}
+
+func TestExtractExtras(t *testing.T) {
+	cfg := &Config{ExtraProperties: []ExtraProperty{
+		{Name: "tenant", Required: true, ContextKey: tenantKey{}, MetadataKey: "X-Tenant-Id"},
+		{Name: "limit", Type: "integer", Default: float64(10), MetadataKey: "x-limit"},
+		{Name: "trace"},
+	}}
+	tests := []struct {
+		name       string
+		args       string
+		wantArgs   string
+		wantTenant any
+		wantMD     map[string]string
+		wantErr    bool
+	}{
+		{"extras moved out", `{"tenant":"t1","limit":5,"id":12345678901234567890}`, `{"id":12345678901234567890}`, "t1",
+			map[string]string{"x-tenant-id": "t1", "x-limit": "5"}, false},
+		{"default", `{"tenant":"t1"}`, `{}`, "t1", map[string]string{"x-tenant-id": "t1", "x-limit": "10"}, false},
+		{"missing required", `{"limit":5}`, "", nil, nil, true},
+		{"not an object", `["t1"]`, "", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, ctx, err := ExtractExtras(context.Background(), json.RawMessage(tt.args), cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if string(args) != tt.wantArgs {
+				t.Errorf("args = %s, want %s", args, tt.wantArgs)
+			}
+			if got := ctx.Value(tenantKey{}); got != tt.wantTenant {
+				t.Errorf("context value = %v, want %v", got, tt.wantTenant)
+			}
+			md, _ := metadata.FromOutgoingContext(ForwardMetadata(ctx))
+			for k, want := range tt.wantMD {
+				if got := md.Get(k); len(got) != 1 || got[0] != want {
+					t.Errorf("metadata %s = %v, want [%s]", k, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractExtras_NoneConfigured(t *testing.T) {
+	raw := json.RawMessage(`{"tenant":"t1"}`)
+	args, _, err := ExtractExtras(context.Background(), raw, &Config{})
+	if err != nil || string(args) != string(raw) {
+		t.Errorf("ExtractExtras = %s, %v, want the arguments unchanged", args, err)
+	}
+}
@@ -22,6 +22,15 @@ type MCPServerConfig struct {
 	Name string
 	// Version is the MCP server version reported during initialization.
 	Version string
+	// Title is the human-readable server name reported during initialization.
+	Title string
+	// WebsiteURL is the server website reported during initialization.
+	WebsiteURL string
+	// Icons are the server icons reported during initialization.
+	Icons []mcp.Icon
+	// Instructions describe how to use the server; they are reported during
+	// initialization unless ServerOptions.Instructions is set.
+	Instructions string
 	// Transport selects a single wire protocol (for backward compatibility).
 	// Ignored when Transports is non-empty.
 	Transport Transport
@@ -68,7 +77,52 @@ func NewMCPServer(cfg *MCPServerConfig) *mcp.Server {
 	if opts == nil {
 		opts = &mcp.ServerOptions{}
 	}
-	return mcp.NewServer(&mcp.Implementation{Name: cfg.Name, Version: cfg.Version}, opts)
+	if cfg.Instructions != "" && opts.Instructions == "" {
+		withInstructions := *opts
+		withInstructions.Instructions = cfg.Instructions
+		opts = &withInstructions
+	}
+	return mcp.NewServer(&mcp.Implementation{
+		Name:       cfg.Name,
+		Title:      cfg.Title,
+		Version:    cfg.Version,
+		WebsiteURL: cfg.WebsiteURL,
+		Icons:      cfg.Icons,
+	}, opts)
+}
+
+// ServerIdentity is the server identity declared in a service's
+// (mcp.protobuf.service) options. Generated code exposes it as
+// <Service>MCPServerIdentity.
+type ServerIdentity struct {
+	Name         string
+	Title        string
+	Version      string
+	Instructions string
+	WebsiteURL   string
+	Icons        []mcp.Icon
+}
+
+// ApplyServerIdentity fills the identity fields of cfg that are unset from id.
+func ApplyServerIdentity(cfg *MCPServerConfig, id ServerIdentity) {
+	if cfg.Name == "" {
+		cfg.Name = id.Name
+	}
+	if cfg.Title == "" {
+		cfg.Title = id.Title
+	}
+	if cfg.Version == "" {
+		cfg.Version = id.Version
+	}
+	if cfg.Instructions == "" {
+		cfg.Instructions = id.Instructions
+	}
+	if cfg.WebsiteURL == "" {
+		cfg.WebsiteURL = id.WebsiteURL
+	}
+	if len(cfg.Icons) == 0 {
+		cfg.Icons = id.Icons
+	}
 }
 
 // ParseTransports splits a comma-separated transport string into a []Transport slice.
@@ -0,0 +1,63 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestApplyServerIdentity(t *testing.T) {
+	cfg := &MCPServerConfig{
+		Name:         "explicit",
+		Instructions: "Explicit instructions.",
+		Icons:        []mcp.Icon{{Source: "https://example.com/explicit.png"}},
+	}
+	ApplyServerIdentity(cfg, ServerIdentity{
+		Name:         "from-proto",
+		Title:        "From Proto",
+		Version:      "1.2.3",
+		Instructions: "Proto instructions.",
+		WebsiteURL:   "https://example.com",
+		Icons:        []mcp.Icon{{Source: "https://example.com/proto.png"}},
+	})
+	fields := []struct{ name, got, want string }{
+		{"Name", cfg.Name, "explicit"},
+		{"Title", cfg.Title, "From Proto"},
+		{"Version", cfg.Version, "1.2.3"},
+		{"Instructions", cfg.Instructions, "Explicit instructions."},
+		{"WebsiteURL", cfg.WebsiteURL, "https://example.com"},
+	}
+	for _, f := range fields {
+		if f.got != f.want {
+			t.Errorf("%s = %q, want %q", f.name, f.got, f.want)
+		}
+	}
+	if len(cfg.Icons) != 1 || cfg.Icons[0].Source != "https://example.com/explicit.png" {
+		t.Errorf("icons = %+v, want the explicit icon", cfg.Icons)
+	}
+}
+
+func TestNewMCPServer_Instructions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *mcp.ServerOptions
+		cfg  string
+		want string
+	}{
+		{"config only", nil, "From config.", "From config."},
+		{"server options win", &mcp.ServerOptions{Instructions: "From options."}, "From config.", "From options."},
+		{"server options without instructions", &mcp.ServerOptions{}, "From config.", "From config."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &MCPServerConfig{Name: "test", Version: "1.0.0", Instructions: tt.cfg, ServerOptions: tt.opts}
+			cs := connectTestClient(t, NewMCPServer(cfg))
+			if got := cs.InitializeResult().Instructions; got != tt.want {
+				t.Errorf("instructions = %q, want %q", got, tt.want)
+			}
+			if tt.opts != nil && tt.opts.Instructions == "" && cfg.ServerOptions.Instructions != "" {
+				t.Error("NewMCPServer modified cfg.ServerOptions")
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package runtime
 import (
 	"context"
 	"fmt"
+	"io"
 
 	grpcmd "google.golang.org/grpc/metadata"
 )
@@ -26,6 +27,7 @@ import (
 type InProcessServerStream[T any] struct {
 	ctx context.Context
 	ch  chan T
+	err error // set by CloseWithError before ch is closed
 }
 
 // NewInProcessServerStream creates a new InProcessServerStream with a buffered
@@ -52,11 +54,50 @@ func (s *InProcessServerStream[T]) Recv() (T, bool) {
 	return chunk, ok
 }
 
+// RecvContext is like Recv but gives up when ctx is done. It returns io.EOF
+// once the stream is closed and drained, or the error passed to
+// CloseWithError, matching the behaviour of a gRPC client stream.
+// Items already buffered, and the end of a closed stream, are returned even
+// when ctx is done.
+func (s *InProcessServerStream[T]) RecvContext(ctx context.Context) (T, error) {
+	select {
+	case chunk, ok := <-s.ch:
+		return s.received(chunk, ok)
+	default:
+	}
+	select {
+	case chunk, ok := <-s.ch:
+		return s.received(chunk, ok)
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// received converts a channel receive to the result of RecvContext.
+func (s *InProcessServerStream[T]) received(chunk T, ok bool) (T, error) {
+	if ok {
+		return chunk, nil
+	}
+	if s.err != nil {
+		return chunk, s.err
+	}
+	return chunk, io.EOF
+}
+
 // Close signals that no more items will be sent. The consumer goroutine will
 // exit its Recv loop once all buffered items are drained. Must be called
 // exactly once, after the producer (gRPC method) returns.
 func (s *InProcessServerStream[T]) Close() { close(s.ch) }
 
+// CloseWithError is like Close but records the producer's error, which
+// RecvContext returns after the buffered items are drained. Use it instead
+// of Close, not in addition to it.
+func (s *InProcessServerStream[T]) CloseWithError(err error) {
+	s.err = err
+	close(s.ch)
+}
+
 // Context returns the stream context. The gRPC server method calls
 // stream.Context() to get its context.
 func (s *InProcessServerStream[T]) Context() context.Context { return s.ctx }
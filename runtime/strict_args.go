@@ -0,0 +1,118 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// NormalizeStrictArgs rewrites argument shapes produced by strict
+// (schema_strict) input schemas into their protojson form for md: maps sent
+// as [{"key": ..., "value": ...}] arrays become objects, and Struct,
+// ListValue and Value fields sent as JSON-encoded strings are decoded. A
+// Value string is only decoded when it holds a JSON object or array, so
+// plain string values are kept. Arguments without such shapes are returned
+// unchanged.
+func NormalizeStrictArgs(args json.RawMessage, md protoreflect.MessageDescriptor) (json.RawMessage, error) {
+	if !hasStrictShapes(md) {
+		return args, nil
+	}
+	obj, ok, err := decodeArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || !normalizeMessage(obj, md) {
+		return args, nil
+	}
+	return json.Marshal(obj)
+}
+
+// normalizeMessage rewrites obj in place and reports whether it changed.
+func normalizeMessage(obj map[string]any, md protoreflect.MessageDescriptor) bool {
+	changed := false
+	for key, val := range obj {
+		fd := md.Fields().ByJSONName(key)
+		if fd == nil {
+			fd = md.Fields().ByName(protoreflect.Name(key))
+		}
+		if fd == nil || val == nil {
+			continue
+		}
+		switch {
+		case fd.IsMap():
+			if pairs, ok := val.([]any); ok {
+				m := make(map[string]any, len(pairs))
+				for _, p := range pairs {
+					if kv, ok := p.(map[string]any); ok {
+						m[fmt.Sprint(kv["key"])] = kv["value"]
+					}
+				}
+				obj[key], val, changed = m, m, true
+			}
+			if m, ok := val.(map[string]any); ok && fd.MapValue().Message() != nil {
+				for k, item := range m {
+					if nv, ok := normalizeValue(item, fd.MapValue().Message()); ok {
+						m[k], changed = nv, true
+					}
+				}
+			}
+		case fd.Message() != nil && fd.IsList():
+			if items, ok := val.([]any); ok {
+				for i, item := range items {
+					if nv, ok := normalizeValue(item, fd.Message()); ok {
+						items[i], changed = nv, true
+					}
+				}
+			}
+		case fd.Message() != nil:
+			if nv, ok := normalizeValue(val, fd.Message()); ok {
+				obj[key], changed = nv, true
+			}
+		}
+	}
+	return changed
+}
+
+// normalizeValue returns the normalized form of a message-typed value and
+// whether it changed.
+func normalizeValue(val any, md protoreflect.MessageDescriptor) (any, bool) {
+	if isStructLike(md) {
+		s, ok := val.(string)
+		if !ok {
+			return val, false
+		}
+		trimmed := strings.TrimSpace(s)
+		if md.FullName() == "google.protobuf.Value" && !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+			return val, false
+		}
+		dec := json.NewDecoder(strings.NewReader(trimmed))
+		dec.UseNumber()
+		var decoded any
+		if err := dec.Decode(&decoded); err != nil {
+			return val, false
+		}
+		return decoded, true
+	}
+	if obj, ok := val.(map[string]any); ok {
+		return obj, normalizeMessage(obj, md)
+	}
+	return val, false
+}
+
+// hasStrictShapes reports whether a message, or any message reachable from
+// it, has a map or Struct-like field.
+var hasStrictShapes = reachesField(func(fd protoreflect.FieldDescriptor) bool {
+	return fd.IsMap() || (fd.Message() != nil && isStructLike(fd.Message()))
+})
+
+// isStructLike reports whether md is Struct, ListValue or Value, which
+// strict schemas type as JSON-encoded strings.
+func isStructLike(md protoreflect.MessageDescriptor) bool {
+	switch md.FullName() {
+	case "google.protobuf.Struct", "google.protobuf.ListValue", "google.protobuf.Value":
+		return true
+	}
+	return false
+}
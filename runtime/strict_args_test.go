@@ -0,0 +1,58 @@
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+
+	_ "google.golang.org/protobuf/types/known/structpb"
+)
+
+const strictTestFile = `
+name: "strict_args_test.proto"
+package: "strictargstest"
+syntax: "proto3"
+dependency: "google/protobuf/struct.proto"
+message_type {
+  name: "Request"
+  field { name: "labels" number: 1 type: TYPE_MESSAGE label: LABEL_REPEATED json_name: "labels" type_name: ".strictargstest.Request.LabelsEntry" }
+  field { name: "data" number: 2 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "data" type_name: ".google.protobuf.Struct" }
+  field { name: "value" number: 3 type: TYPE_MESSAGE label: LABEL_OPTIONAL json_name: "value" type_name: ".google.protobuf.Value" }
+  field { name: "title" number: 4 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "title" }
+  nested_type { name: "LabelsEntry" options { map_entry: true }
+    field { name: "key" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "key" }
+    field { name: "value" number: 2 type: TYPE_INT32 label: LABEL_OPTIONAL json_name: "value" } }
+}
+message_type {
+  name: "Plain"
+  field { name: "title" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "title" }
+}
+`
+
+func TestNormalizeStrictArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     string
+		args    string
+		want    string
+		wantErr bool
+	}{
+		{"map pairs", "Request", `{"labels":[{"key":"a","value":1}]}`, `{"labels":{"a":1}}`, false},
+		{"map object kept", "Request", `{"labels":{"a":1}}`, `{"labels":{"a":1}}`, false},
+		{"encoded struct", "Request", `{"data":"{\"k\":[1,2]}"}`, `{"data":{"k":[1,2]}}`, false},
+		{"plain Value string kept", "Request", `{"value":"hello"}`, `{"value":"hello"}`, false},
+		{"encoded Value object", "Request", `{"value":"{\"a\":true}"}`, `{"value":{"a":true}}`, false},
+		{"no strict shapes", "Plain", `{"title":"x"}`, `{"title":"x"}`, false},
+		{"invalid JSON", "Request", `{"labels":`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := NormalizeStrictArgs(json.RawMessage(tt.args), testMessage(t, strictTestFile, tt.msg))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil {
+				assertJSONEqual(t, out, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,580 @@
+package runtime
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/machanirobotics/grpc-mcp-gateway/mcp/protobuf/mcppb"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// TaskStatus is the lifecycle state of a task, mirroring the MCP tasks utility.
+type TaskStatus string
+
+const (
+	// TaskStatusWorking means the underlying RPC is still running.
+	TaskStatusWorking TaskStatus = "working"
+	// TaskStatusCompleted means the RPC finished and a result is available.
+	TaskStatusCompleted TaskStatus = "completed"
+	// TaskStatusFailed means the RPC returned an error; the error result is available.
+	TaskStatusFailed TaskStatus = "failed"
+	// TaskStatusCancelled means the task was cancelled before it finished.
+	TaskStatusCancelled TaskStatus = "cancelled"
+)
+
+// RelatedTaskMetaKey is the _meta key used to associate a tool result with a task.
+const RelatedTaskMetaKey = "io.modelcontextprotocol/related-task"
+
+// Tool names for the task companion tools registered by RegisterTaskTools.
+// The MCP SDK does not route tasks/* methods to user code, so the gateway
+// exposes the same operations as tools.
+const (
+	TaskGetToolName    = "tasks-get"
+	TaskResultToolName = "tasks-result"
+	TaskListToolName   = "tasks-list"
+	TaskCancelToolName = "tasks-cancel"
+)
+
+// DefaultTaskTTL is how long finished tasks are kept by the default store.
+const DefaultTaskTTL = time.Hour
+
+// defaultTaskPollInterval is the suggested client poll interval and the
+// interval used by tasks-result while waiting for a task to finish.
+const defaultTaskPollInterval = 250 * time.Millisecond
+
+// ErrTaskNotFound is returned by a TaskStore when the task ID is unknown or expired.
+var ErrTaskNotFound = errors.New("runtime: task not found")
+
+// Task describes a long-running tool call. JSON field names follow the MCP
+// tasks utility so the value can be returned to clients unchanged.
+type Task struct {
+	TaskID        string     `json:"taskId"`
+	ToolName      string     `json:"toolName,omitempty"`
+	Status        TaskStatus `json:"status"`
+	StatusMessage string     `json:"statusMessage,omitempty"`
+	Progress      float64    `json:"progress,omitempty"`
+	Total         float64    `json:"total,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	LastUpdatedAt time.Time  `json:"lastUpdatedAt"`
+	TTL           int64      `json:"ttl,omitempty"`          // milliseconds
+	PollInterval  int64      `json:"pollInterval,omitempty"` // milliseconds
+	// SessionID is the MCP session that started the task. The companion
+	// tools only show a task to the same session. It is never sent to
+	// clients, so a TaskStore that serializes tasks must save it separately.
+	SessionID string `json:"-"`
+}
+
+// Terminal reports whether the task has reached a final state.
+func (t *Task) Terminal() bool {
+	switch t.Status {
+	case TaskStatusCompleted, TaskStatusFailed, TaskStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// TaskStore persists task state and results. Implementations must be safe
+// for concurrent use. Use NewInMemoryTaskStore for a single-process gateway,
+// or implement TaskStore over a shared database when running several replicas.
+type TaskStore interface {
+	// Create stores a new task. The task ID is already set.
+	Create(ctx context.Context, task *Task) error
+	// Get returns a copy of the task, or ErrTaskNotFound.
+	Get(ctx context.Context, taskID string) (*Task, error)
+	// List returns all known tasks, oldest first.
+	List(ctx context.Context) ([]*Task, error)
+	// Update calls update on the stored task and saves the changes unless it
+	// returns false, as one atomic step, and returns a copy of the task.
+	Update(ctx context.Context, taskID string, update func(task *Task) bool) (*Task, error)
+	// SetResult stores the final tool result for a task.
+	SetResult(ctx context.Context, taskID string, result *mcp.CallToolResult) error
+	// Result returns the stored result, or nil if the task has not finished.
+	Result(ctx context.Context, taskID string) (*mcp.CallToolResult, error)
+}
+
+// InMemoryTaskStore is the default TaskStore. Finished tasks are dropped
+// once their TTL has elapsed.
+type InMemoryTaskStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	tasks   map[string]*Task
+	results map[string]*mcp.CallToolResult
+}
+
+// NewInMemoryTaskStore creates an in-memory store that keeps finished tasks
+// for ttl. A ttl of zero uses DefaultTaskTTL.
+func NewInMemoryTaskStore(ttl time.Duration) *InMemoryTaskStore {
+	if ttl <= 0 {
+		ttl = DefaultTaskTTL
+	}
+	return &InMemoryTaskStore{
+		ttl:     ttl,
+		tasks:   make(map[string]*Task),
+		results: make(map[string]*mcp.CallToolResult),
+	}
+}
+
+var (
+	defaultTaskStoreOnce sync.Once
+	defaultTaskStore     TaskStore
+)
+
+// DefaultTaskStore returns the process-wide in-memory TaskStore used when no
+// store is configured via WithTaskStore.
+func DefaultTaskStore() TaskStore {
+	defaultTaskStoreOnce.Do(func() {
+		defaultTaskStore = NewInMemoryTaskStore(DefaultTaskTTL)
+	})
+	return defaultTaskStore
+}
+
+// Create implements TaskStore.
+func (s *InMemoryTaskStore) Create(_ context.Context, task *Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireLocked()
+	if task.TTL == 0 {
+		task.TTL = s.ttl.Milliseconds()
+	}
+	cp := *task
+	s.tasks[task.TaskID] = &cp
+	return nil
+}
+
+// Get implements TaskStore.
+func (s *InMemoryTaskStore) Get(_ context.Context, taskID string) (*Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireLocked()
+	t, ok := s.tasks[taskID]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	cp := *t
+	return &cp, nil
+}
+
+// List implements TaskStore.
+func (s *InMemoryTaskStore) List(_ context.Context) ([]*Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireLocked()
+	out := make([]*Task, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		cp := *t
+		out = append(out, &cp)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+// Update implements TaskStore.
+func (s *InMemoryTaskStore) Update(_ context.Context, taskID string, update func(task *Task) bool) (*Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tasks[taskID]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	cp := *t
+	if update(&cp) {
+		s.tasks[taskID] = &cp
+	}
+	out := *s.tasks[taskID]
+	return &out, nil
+}
+
+// SetResult implements TaskStore.
+func (s *InMemoryTaskStore) SetResult(_ context.Context, taskID string, result *mcp.CallToolResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[taskID]; !ok {
+		return ErrTaskNotFound
+	}
+	s.results[taskID] = result
+	return nil
+}
+
+// Result implements TaskStore.
+func (s *InMemoryTaskStore) Result(_ context.Context, taskID string) (*mcp.CallToolResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[taskID]; !ok {
+		return nil, ErrTaskNotFound
+	}
+	return s.results[taskID], nil
+}
+
+// expireLocked drops finished tasks whose TTL has elapsed. s.mu must be held.
+func (s *InMemoryTaskStore) expireLocked() {
+	now := time.Now()
+	for id, t := range s.tasks {
+		if t.Terminal() && t.TTL > 0 && now.Sub(t.LastUpdatedAt) > time.Duration(t.TTL)*time.Millisecond {
+			delete(s.tasks, id)
+			delete(s.results, id)
+		}
+	}
+}
+
+// taskCancels holds the cancel functions of tasks running in this process.
+var taskCancels sync.Map // taskID -> context.CancelFunc
+
+// TaskHandle is passed to the body of a task started with StartTask. It
+// reports progress both to the TaskStore and as MCP progress notifications.
+type TaskHandle struct {
+	ID      string
+	store   TaskStore
+	session *mcp.ServerSession
+	token   any
+}
+
+// Progress records p as the task's latest status and forwards it to the
+// client as a progress notification when a progress token was supplied.
+func (h *TaskHandle) Progress(p *mcppb.MCPProgress) error {
+	if p == nil {
+		return nil
+	}
+	ctx := context.Background()
+	_, _ = h.store.Update(ctx, h.ID, func(t *Task) bool {
+		if t.Terminal() {
+			return false
+		}
+		t.Progress = p.Progress
+		t.Total = p.GetTotal()
+		t.StatusMessage = p.Message
+		t.LastUpdatedAt = time.Now()
+		return true
+	})
+	return SendProgressFromProto(ctx, h.session, h.token, p)
+}
+
+// StartTask creates a task in cfg's TaskStore, runs fn in the background and
+// returns immediately with a tool result describing the task. The result
+// returned by fn (or the error converted via HandleError) is stored and can be
+// fetched later with the tasks-result tool. fn's context is detached from the
+// tool-call request and is cancelled by the tasks-cancel tool.
+//
+// For backward compatibility the final result is also sent as the message of
+// a last progress notification (see SendDoneProgress).
+func StartTask(ctx context.Context, req *mcp.CallToolRequest, cfg *Config, fn func(ctx context.Context, task *TaskHandle) (*mcp.CallToolResult, error)) (*mcp.CallToolResult, error) {
+	store := cfg.TaskStoreOrDefault()
+	now := time.Now()
+	task := &Task{
+		TaskID:        newTaskID(),
+		ToolName:      req.Params.Name,
+		Status:        TaskStatusWorking,
+		CreatedAt:     now,
+		LastUpdatedAt: now,
+		PollInterval:  defaultTaskPollInterval.Milliseconds(),
+		SessionID:     sessionID(req.Session),
+	}
+	if err := store.Create(ctx, task); err != nil {
+		return nil, fmt.Errorf("runtime: create task: %w", err)
+	}
+
+	taskCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	taskCancels.Store(task.TaskID, cancel)
+	h := &TaskHandle{ID: task.TaskID, store: store, session: req.Session, token: req.Params.GetProgressToken()}
+
+	go func() {
+		defer taskCancels.Delete(task.TaskID)
+		defer cancel()
+		result, err := fn(taskCtx, h)
+		if err != nil {
+			result, _ = HandleError(err)
+		}
+		result = cfg.shapeResult(taskCtx, sessionID(req.Session), req.Params.Name, result)
+		finishTask(store, h, result, taskCtx.Err() != nil)
+	}()
+
+	return taskResult(task), nil
+}
+
+// finishTask stores the final result and status of a task and sends the
+// legacy completion notification. A task cancelled meanwhile stays
+// cancelled.
+func finishTask(store TaskStore, h *TaskHandle, result *mcp.CallToolResult, cancelled bool) {
+	ctx := context.Background()
+	if result != nil {
+		// Stored first so that a terminal task always has its result.
+		if err := store.SetResult(ctx, h.ID, result); err != nil {
+			return
+		}
+	}
+	t, err := store.Update(ctx, h.ID, func(t *Task) bool {
+		if t.Terminal() {
+			return false
+		}
+		switch {
+		case cancelled:
+			t.Status = TaskStatusCancelled
+		case result != nil && result.IsError:
+			t.Status = TaskStatusFailed
+		default:
+			t.Status = TaskStatusCompleted
+		}
+		t.LastUpdatedAt = time.Now()
+		return true
+	})
+	if err != nil || t.Status == TaskStatusCancelled {
+		return
+	}
+
+	if result != nil {
+		_ = SendDoneProgress(ctx, h.session, h.token, resultText(result))
+	}
+}
+
+// CancelTask cancels a running task and marks it cancelled in the store.
+// Cancelling a finished task is a no-op.
+func CancelTask(ctx context.Context, store TaskStore, taskID string) (*Task, error) {
+	t, err := store.Update(ctx, taskID, func(t *Task) bool {
+		if t.Terminal() {
+			return false
+		}
+		t.Status = TaskStatusCancelled
+		t.LastUpdatedAt = time.Now()
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if t.Status != TaskStatusCancelled {
+		return t, nil
+	}
+	if cancel, ok := taskCancels.Load(taskID); ok {
+		cancel.(context.CancelFunc)()
+	}
+	return t, nil
+}
+
+// taskToolSets holds the companion tools registered per server.
+var taskToolSets sync.Map // *mcp.Server -> *taskTools
+
+// taskTools backs the companion tools of one server with the stores of all
+// services registered on it.
+type taskTools struct {
+	mu     sync.Mutex
+	stores []TaskStore
+}
+
+// RegisterTaskTools adds the tasks-get, tasks-result, tasks-list and
+// tasks-cancel tools backed by store. Generated handlers call this when a
+// service has task-based tools. The tools are added once per server; later
+// calls with another store add it to the stores the tools search.
+//
+// The tools only show a task to the MCP session that started it. Servers
+// without session IDs (stdio, stateless HTTP) share tasks among callers.
+func RegisterTaskTools(s *mcp.Server, store TaskStore) {
+	v, loaded := taskToolSets.LoadOrStore(s, &taskTools{stores: []TaskStore{store}})
+	tools := v.(*taskTools)
+	if loaded {
+		tools.mu.Lock()
+		defer tools.mu.Unlock()
+		for _, st := range tools.stores {
+			if st == store {
+				return
+			}
+		}
+		tools.stores = append(tools.stores, store)
+		return
+	}
+
+	idSchema := &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"taskId": {Type: "string", Description: "Task ID returned when the task was created."},
+		},
+		Required: []string{"taskId"},
+	}
+	s.AddTool(&mcp.Tool{
+		Name:        TaskGetToolName,
+		Title:       "Get Task",
+		Description: "Returns the current status of a task started by a long-running tool.",
+		InputSchema: idSchema,
+	}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		t, _, err := tools.find(ctx, req)
+		if err != nil {
+			return ErrorResult(err.Error()), nil
+		}
+		return taskResult(t), nil
+	})
+	s.AddTool(&mcp.Tool{
+		Name:        TaskResultToolName,
+		Title:       "Get Task Result",
+		Description: "Waits for a task to finish and returns the result of the tool call that started it.",
+		InputSchema: idSchema,
+	}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		t, store, err := tools.find(ctx, req)
+		if err != nil {
+			return ErrorResult(err.Error()), nil
+		}
+		return waitTaskResult(ctx, store, t.TaskID)
+	})
+	s.AddTool(&mcp.Tool{
+		Name:        TaskListToolName,
+		Title:       "List Tasks",
+		Description: "Lists known tasks and their status.",
+		InputSchema: &jsonschema.Schema{Type: "object"},
+	}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tasks, err := tools.list(ctx, sessionID(req.Session))
+		if err != nil {
+			return ErrorResult(err.Error()), nil
+		}
+		b, err := json.Marshal(map[string]any{"tasks": tasks})
+		if err != nil {
+			return nil, err
+		}
+		return TextResult(string(b)), nil
+	})
+	s.AddTool(&mcp.Tool{
+		Name:        TaskCancelToolName,
+		Title:       "Cancel Task",
+		Description: "Cancels a running task.",
+		InputSchema: idSchema,
+	}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		t, store, err := tools.find(ctx, req)
+		if err != nil {
+			return ErrorResult(err.Error()), nil
+		}
+		t, err = CancelTask(ctx, store, t.TaskID)
+		if err != nil {
+			return ErrorResult(err.Error()), nil
+		}
+		return taskResult(t), nil
+	})
+}
+
+// find returns the task named by the taskId argument of req and the store
+// holding it. Tasks of other sessions are reported as not found.
+func (tt *taskTools) find(ctx context.Context, req *mcp.CallToolRequest) (*Task, TaskStore, error) {
+	id, err := taskIDArg(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	tt.mu.Lock()
+	stores := tt.stores
+	tt.mu.Unlock()
+	for _, store := range stores {
+		t, err := store.Get(ctx, id)
+		if errors.Is(err, ErrTaskNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if t.SessionID != sessionID(req.Session) {
+			break
+		}
+		return t, store, nil
+	}
+	return nil, nil, ErrTaskNotFound
+}
+
+// list returns the tasks of session in all stores, oldest first.
+func (tt *taskTools) list(ctx context.Context, session string) ([]*Task, error) {
+	tt.mu.Lock()
+	stores := tt.stores
+	tt.mu.Unlock()
+	out := []*Task{}
+	for _, store := range stores {
+		tasks, err := store.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tasks {
+			if t.SessionID == session {
+				out = append(out, t)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+// sessionID returns the ID of session, or "" without a session.
+func sessionID(session *mcp.ServerSession) string {
+	if session == nil {
+		return ""
+	}
+	return session.ID()
+}
+
+// waitTaskResult polls store until the task is terminal or ctx is done.
+func waitTaskResult(ctx context.Context, store TaskStore, taskID string) (*mcp.CallToolResult, error) {
+	ticker := time.NewTicker(defaultTaskPollInterval)
+	defer ticker.Stop()
+	for {
+		t, err := store.Get(ctx, taskID)
+		if err != nil {
+			return ErrorResult(err.Error()), nil
+		}
+		if t.Terminal() {
+			result, err := store.Result(ctx, taskID)
+			if err != nil {
+				return ErrorResult(err.Error()), nil
+			}
+			if result == nil || t.Status == TaskStatusCancelled {
+				result = ErrorResult(fmt.Sprintf("task %s was %s", taskID, t.Status))
+			}
+			cloned := *result
+			cloned.Meta = mcp.Meta{RelatedTaskMetaKey: map[string]any{"taskId": taskID}}
+			return &cloned, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// taskResult wraps a task as a tool result: the task JSON as text plus the
+// related-task _meta entry.
+func taskResult(t *Task) *mcp.CallToolResult {
+	b, err := json.Marshal(map[string]any{"task": t})
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	res := TextResult(string(b))
+	res.Meta = mcp.Meta{RelatedTaskMetaKey: map[string]any{"taskId": t.TaskID}}
+	return res
+}
+
+func taskIDArg(req *mcp.CallToolRequest) (string, error) {
+	var args struct {
+		TaskID string `json:"taskId"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	if args.TaskID == "" {
+		return "", errors.New("taskId is required")
+	}
+	return args.TaskID, nil
+}
+
+// resultText returns the concatenated text content of a tool result.
+func resultText(r *mcp.CallToolResult) string {
+	var out string
+	for _, c := range r.Content {
+		if tc, ok := c.(*mcp.TextContent); ok {
+			out += tc.Text
+		}
+	}
+	return out
+}
+
+func newTaskID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
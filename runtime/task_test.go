@@ -0,0 +1,126 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func newTestTask(store TaskStore, id, session string) {
+	now := time.Now()
+	_ = store.Create(context.Background(), &Task{
+		TaskID: id, Status: TaskStatusWorking, CreatedAt: now, LastUpdatedAt: now, SessionID: session,
+	})
+}
+
+func taskRequest(id string) *mcp.CallToolRequest {
+	args, _ := json.Marshal(map[string]string{"taskId": id})
+	return &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Arguments: args}}
+}
+
+func TestTaskTools_SessionScope(t *testing.T) {
+	store := NewInMemoryTaskStore(0)
+	newTestTask(store, "mine", "")
+	newTestTask(store, "theirs", "other-session")
+	tools := &taskTools{stores: []TaskStore{store}}
+
+	tests := []struct {
+		id      string
+		wantErr error
+	}{
+		{"mine", nil},
+		{"theirs", ErrTaskNotFound},
+		{"missing", ErrTaskNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			task, _, err := tools.find(context.Background(), taskRequest(tt.id))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("find(%q) error = %v, want %v", tt.id, err, tt.wantErr)
+			}
+			if err == nil && task.TaskID != tt.id {
+				t.Errorf("find(%q) = %q", tt.id, task.TaskID)
+			}
+		})
+	}
+
+	tasks, err := tools.list(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].TaskID != "mine" {
+		t.Errorf("list returned %d tasks, want only %q", len(tasks), "mine")
+	}
+}
+
+func TestTask_JSONOmitsSession(t *testing.T) {
+	b, err := json.Marshal(&Task{TaskID: "t", Status: TaskStatusWorking, SessionID: "secret-session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(b, []byte("secret-session")) {
+		t.Errorf("task JSON exposes the session ID: %s", b)
+	}
+}
+
+func TestFinishTask_KeepsCancellation(t *testing.T) {
+	tests := []struct {
+		name       string
+		cancel     bool
+		result     *mcp.CallToolResult
+		wantStatus TaskStatus
+	}{
+		{"completed", false, TextResult("ok"), TaskStatusCompleted},
+		{"failed", false, ErrorResult("boom"), TaskStatusFailed},
+		{"cancelled first", true, TextResult("ok"), TaskStatusCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewInMemoryTaskStore(0)
+			newTestTask(store, "t", "")
+			if tt.cancel {
+				if _, err := CancelTask(context.Background(), store, "t"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			finishTask(store, &TaskHandle{ID: "t", store: store}, tt.result, false)
+			got, err := store.Get(context.Background(), "t")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", got.Status, tt.wantStatus)
+			}
+			res, _ := waitTaskResult(context.Background(), store, "t")
+			if res.IsError != (tt.wantStatus != TaskStatusCompleted) {
+				t.Errorf("result IsError = %v for status %s", res.IsError, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRegisterTaskTools_OncePerServer(t *testing.T) {
+	s := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	a, b := NewInMemoryTaskStore(0), NewInMemoryTaskStore(0)
+	RegisterTaskTools(s, a)
+	RegisterTaskTools(s, b)
+	RegisterTaskTools(s, a)
+
+	v, ok := taskToolSets.Load(s)
+	if !ok {
+		t.Fatal("no task tools recorded for server")
+	}
+	if n := len(v.(*taskTools).stores); n != 2 {
+		t.Errorf("stores = %d, want 2", n)
+	}
+
+	newTestTask(b, "in-b", "")
+	if _, store, err := v.(*taskTools).find(context.Background(), taskRequest("in-b")); err != nil || store != b {
+		t.Errorf("find in second store: store=%v err=%v", store, err)
+	}
+}
@@ -0,0 +1,286 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// googleTypeShorthand returns the shorthand expansion for a google.type
+// message, or nil. Expansions return the protojson object of the message.
+func googleTypeShorthand(md protoreflect.MessageDescriptor) func(any) (any, bool) {
+	switch md.FullName() {
+	case "google.type.Date":
+		return expandDate
+	case "google.type.TimeOfDay":
+		return expandTimeOfDay
+	case "google.type.Money":
+		return expandMoney
+	case "google.type.LatLng":
+		return expandLatLng
+	case "google.type.Color":
+		return expandColor
+	case "google.type.Interval":
+		return expandInterval
+	case "google.type.DateTime":
+		return expandDateTime
+	case "google.type.Decimal":
+		return expandDecimal
+	}
+	return nil
+}
+
+// dateLayouts are the date shorthands accepted for google.type.Date.
+var dateLayouts = []string{"2006-01-02", "2006/01/02", "Jan 2, 2006", "January 2, 2006", "2 Jan 2006", "2 January 2006"}
+
+// expandDate reads "2026-10-17" and the layouts in dateLayouts, or "2026-10"
+// for a year and month.
+func expandDate(v any) (any, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return v, false
+	}
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return map[string]any{"year": t.Year(), "month": int(t.Month()), "day": t.Day()}, true
+		}
+	}
+	if t, err := time.Parse("2006-01", s); err == nil {
+		return map[string]any{"year": t.Year(), "month": int(t.Month())}, true
+	}
+	return v, false
+}
+
+var clockTime = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(?::(\d{2})(?:\.(\d{1,9}))?)?\s*([aApP][mM])?$`)
+
+// expandTimeOfDay reads "14:30", "14:30:15.5", "2:30 PM" and "2pm".
+func expandTimeOfDay(v any) (any, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return v, false
+	}
+	m := clockTime.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil || (m[2] == "" && m[5] == "") {
+		return v, false
+	}
+	h, _ := strconv.Atoi(m[1])
+	min, _ := strconv.Atoi("0" + m[2])
+	sec, _ := strconv.Atoi("0" + m[3])
+	nanos := 0
+	if m[4] != "" {
+		nanos, _ = strconv.Atoi((m[4] + "00000000")[:9])
+	}
+	if m[5] != "" {
+		if h < 1 || h > 12 {
+			return v, false
+		}
+		h %= 12
+		if strings.EqualFold(m[5], "pm") {
+			h += 12
+		}
+	}
+	if h > 24 || min > 59 || sec > 60 {
+		return v, false
+	}
+	return map[string]any{"hours": h, "minutes": min, "seconds": sec, "nanos": nanos}, true
+}
+
+// currencySymbols maps common currency symbols to ISO 4217 codes.
+var currencySymbols = map[string]string{"$": "USD", "€": "EUR", "£": "GBP", "¥": "JPY", "₹": "INR"}
+
+var (
+	moneyCodeAfter  = regexp.MustCompile(`^([+-]?[0-9][0-9,]*(?:\.[0-9]+)?)\s*([A-Za-z]{3})$`)
+	moneyCodeBefore = regexp.MustCompile(`^([A-Za-z]{3})\s*([+-]?[0-9][0-9,]*(?:\.[0-9]+)?)$`)
+	moneySymbol     = regexp.MustCompile(`^([+-]?)(\$|€|£|¥|₹)\s*([0-9][0-9,]*(?:\.[0-9]+)?)$`)
+)
+
+// expandMoney reads "12.50 USD", "USD 12.50" and "$12.50".
+func expandMoney(v any) (any, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return v, false
+	}
+	s = strings.TrimSpace(s)
+	var amount, code string
+	if m := moneyCodeAfter.FindStringSubmatch(s); m != nil {
+		amount, code = m[1], m[2]
+	} else if m := moneyCodeBefore.FindStringSubmatch(s); m != nil {
+		code, amount = m[1], m[2]
+	} else if m := moneySymbol.FindStringSubmatch(s); m != nil {
+		code, amount = currencySymbols[m[2]], m[1]+m[3]
+	} else {
+		return v, false
+	}
+	units, nanos, ok := splitDecimal(strings.ReplaceAll(amount, ",", ""))
+	if !ok {
+		return v, false
+	}
+	return map[string]any{"currency_code": strings.ToUpper(code), "units": units, "nanos": nanos}, true
+}
+
+// splitDecimal splits a decimal string into whole units and nanos with the
+// same sign, as google.type.Money stores them.
+func splitDecimal(s string) (string, int64, bool) {
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
+	whole, frac, _ := strings.Cut(s, ".")
+	if len(frac) > 9 {
+		return "", 0, false
+	}
+	units, err := strconv.ParseInt("0"+whole, 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	nanos, _ := strconv.ParseInt((frac + "000000000")[:9], 10, 64)
+	if neg {
+		units, nanos = -units, -nanos
+	}
+	return strconv.FormatInt(units, 10), nanos, true
+}
+
+// expandLatLng reads "37.42, -122.08" and [37.42, -122.08].
+func expandLatLng(v any) (any, bool) {
+	var parts []string
+	switch t := v.(type) {
+	case string:
+		parts = strings.Split(t, ",")
+	case []any:
+		for _, p := range t {
+			parts = append(parts, fmt.Sprint(p))
+		}
+	default:
+		return v, false
+	}
+	if len(parts) != 2 {
+		return v, false
+	}
+	lat, err1 := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	lng, err2 := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err1 != nil || err2 != nil || math.Abs(lat) > 90 || math.Abs(lng) > 180 {
+		return v, false
+	}
+	return map[string]any{"latitude": lat, "longitude": lng}, true
+}
+
+var rgbFunc = regexp.MustCompile(`^rgba?\(\s*(\d{1,3})\s*,\s*(\d{1,3})\s*,\s*(\d{1,3})\s*(?:,\s*([0-9.]+)\s*)?\)$`)
+
+// expandColor reads "#RGB", "#RRGGBB", "#RRGGBBAA", "rgb(r, g, b)" and
+// "rgba(r, g, b, a)".
+func expandColor(v any) (any, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return v, false
+	}
+	s = strings.ToLower(strings.TrimSpace(s))
+	var rgba []float64
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if len(hex) != 6 && len(hex) != 8 {
+			return v, false
+		}
+		for i := 0; i < len(hex); i += 2 {
+			n, err := strconv.ParseUint(hex[i:i+2], 16, 8)
+			if err != nil {
+				return v, false
+			}
+			rgba = append(rgba, float64(n)/255)
+		}
+	} else if m := rgbFunc.FindStringSubmatch(s); m != nil {
+		for _, c := range m[1:4] {
+			n, _ := strconv.Atoi(c)
+			if n > 255 {
+				return v, false
+			}
+			rgba = append(rgba, float64(n)/255)
+		}
+		if m[4] != "" {
+			a, err := strconv.ParseFloat(m[4], 64)
+			if err != nil || a > 1 {
+				return v, false
+			}
+			rgba = append(rgba, a)
+		}
+	} else {
+		return v, false
+	}
+	out := map[string]any{"red": rgba[0], "green": rgba[1], "blue": rgba[2]}
+	if len(rgba) == 4 {
+		out["alpha"] = rgba[3]
+	}
+	return out, true
+}
+
+// expandInterval reads ISO 8601 "start/end" intervals; either side may be
+// empty for an open interval and may use any Timestamp shorthand.
+func expandInterval(v any) (any, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return v, false
+	}
+	start, end, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok || (start == "" && end == "") {
+		return v, false
+	}
+	out := map[string]any{}
+	for key, part := range map[string]string{"start_time": start, "end_time": end} {
+		if part == "" {
+			continue
+		}
+		ts, _ := coerceTimestamp(part)
+		if _, err := time.Parse(time.RFC3339Nano, fmt.Sprint(ts)); err != nil {
+			return v, false
+		}
+		out[key] = ts
+	}
+	return out, true
+}
+
+// expandDateTime reads "2026-10-17T14:30:00" (local time), optionally with
+// a "Z" or "+02:00" offset, or with a space instead of "T".
+func expandDateTime(v any) (any, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return v, false
+	}
+	s = strings.Replace(strings.TrimSpace(s), " ", "T", 1)
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02T15:04"} {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			continue
+		}
+		out := map[string]any{
+			"year": t.Year(), "month": int(t.Month()), "day": t.Day(),
+			"hours": t.Hour(), "minutes": t.Minute(), "seconds": t.Second(), "nanos": t.Nanosecond(),
+		}
+		if layout == time.RFC3339Nano {
+			_, offset := t.Zone()
+			out["utc_offset"] = strconv.Itoa(offset) + "s"
+		}
+		return out, true
+	}
+	return v, false
+}
+
+// expandDecimal reads a JSON number or a decimal string.
+func expandDecimal(v any) (any, bool) {
+	switch t := v.(type) {
+	case json.Number:
+		return map[string]any{"value": t.String()}, true
+	case string:
+		s := strings.TrimSpace(t)
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return v, false
+		}
+		return map[string]any{"value": s}, true
+	}
+	return v, false
+}
@@ -0,0 +1,50 @@
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGoogleTypeShorthand(t *testing.T) {
+	tests := []struct {
+		name   string
+		expand func(any) (any, bool)
+		in     any
+		want   string // JSON of the expansion; empty when it must not apply
+	}{
+		{"date", expandDate, "2026-10-17", `{"day":17,"month":10,"year":2026}`},
+		{"date words", expandDate, "Oct 17, 2026", `{"day":17,"month":10,"year":2026}`},
+		{"year and month", expandDate, "2026-10", `{"month":10,"year":2026}`},
+		{"not a date", expandDate, "tomorrow", ""},
+		{"time of day", expandTimeOfDay, "14:30", `{"hours":14,"minutes":30,"nanos":0,"seconds":0}`},
+		{"time with pm", expandTimeOfDay, "2:30 PM", `{"hours":14,"minutes":30,"nanos":0,"seconds":0}`},
+		{"bare hour", expandTimeOfDay, "14", ""},
+		{"money code after", expandMoney, "12.50 USD", `{"currency_code":"USD","nanos":500000000,"units":"12"}`},
+		{"money symbol", expandMoney, "-$1,000.25", `{"currency_code":"USD","nanos":-250000000,"units":"-1000"}`},
+		{"lat lng string", expandLatLng, "37.42, -122.08", `{"latitude":37.42,"longitude":-122.08}`},
+		{"lat lng out of range", expandLatLng, "91, 0", ""},
+		{"hex color", expandColor, "#f00", `{"blue":0,"green":0,"red":1}`},
+		{"rgba color", expandColor, "rgba(0, 0, 255, 0.5)", `{"alpha":0.5,"blue":1,"green":0,"red":0}`},
+		{"date time with offset", expandDateTime, "2026-10-17T14:30:00+02:00", `{"day":17,"hours":14,"minutes":30,"month":10,"nanos":0,"seconds":0,"utc_offset":"7200s","year":2026}`},
+		{"decimal number", expandDecimal, json.Number("1.25"), `{"value":"1.25"}`},
+		{"decimal text", expandDecimal, "abc", ""},
+		{"open interval", expandInterval, "2026-10-17/", `{"start_time":"2026-10-17T00:00:00Z"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.expand(tt.in)
+			if tt.want == "" {
+				if ok {
+					t.Errorf("expanded %v to %v", tt.in, got)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("%v not expanded", tt.in)
+			}
+			if b, _ := json.Marshal(got); string(b) != tt.want {
+				t.Errorf("got %s, want %s", b, tt.want)
+			}
+		})
+	}
+}